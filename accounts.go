@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// KrispAccount is one of several Krisp accounts synced into the same vault
+type KrispAccount struct {
+	Name  string
+	Token string
+}
+
+// krispAccounts are the accounts from KRISP_ACCOUNTS; empty for a single account (KRISP_BEARER_TOKEN)
+var krispAccounts []KrispAccount
+
+var accountEnvRegex = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// accountTokenEnv returns the environment variable holding an account's token
+// (e.g. "client-a" -> KRISP_BEARER_TOKEN_CLIENT_A)
+func accountTokenEnv(name string) string {
+	return "KRISP_BEARER_TOKEN_" + strings.Trim(accountEnvRegex.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+}
+
+// loadKrispAccounts reads KRISP_ACCOUNTS, a comma-separated list of account names, each
+// with its token in KRISP_BEARER_TOKEN_<NAME>. Returns nil when it isn't set.
+func loadKrispAccounts(requireTokens bool) ([]KrispAccount, error) {
+	value := strings.TrimSpace(os.Getenv("KRISP_ACCOUNTS"))
+	if value == "" {
+		return nil, nil
+	}
+
+	var accounts []KrispAccount
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		token := firstNonEmpty(os.Getenv(accountTokenEnv(name)), savedKrispToken(name))
+		if token == "" && requireTokens {
+			return nil, fmt.Errorf("%s not set for Krisp account %q (or run `krisp-sync login %s`)", accountTokenEnv(name), name, name)
+		}
+		accounts = append(accounts, KrispAccount{Name: name, Token: token})
+	}
+	return accounts, nil
+}
+
+// accountClient returns the client making Krisp requests as an account ("" for a single account)
+func accountClient(name string) *KrispClient {
+	if name == "" {
+		return krispAPI
+	}
+	return krispAPI.asAccount(name)
+}
+
+// findAccount returns the configured account with a name
+func findAccount(name string) (KrispAccount, bool) {
+	for _, a := range krispAccounts {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return KrispAccount{}, false
+}
+
+// meetingClient returns the client for the account a cached meeting was downloaded from.
+// Returns false if the meeting's account isn't known.
+func meetingClient(cache *Cache, meetingID string) (*KrispClient, bool) {
+	if len(krispAccounts) == 0 {
+		return krispAPI, true
+	}
+	m, err := cache.LoadMeeting(meetingID)
+	if err != nil {
+		return nil, false
+	}
+	if _, ok := findAccount(m.Account); !ok {
+		return nil, false
+	}
+	return accountClient(m.Account), true
+}
+
+// fetchAccountMeeting fetches a meeting as the account it belongs to, trying each
+// account in turn for meetings that haven't been downloaded before
+func fetchAccountMeeting(ctx context.Context, cache *Cache, meetingID string) (*Meeting, error) {
+	if isMergedMeetingID(meetingID) {
+		return nil, fmt.Errorf("%s was made by merge and isn't in Krisp", meetingID)
+	}
+	if api, ok := meetingClient(cache, meetingID); ok {
+		m, err := api.GetMeeting(ctx, meetingID)
+		if err == nil {
+			m.Account = api.account
+		}
+		return m, err
+	}
+
+	var lastErr error
+	for _, a := range krispAccounts {
+		m, err := accountClient(a.Name).GetMeeting(ctx, meetingID)
+		if err == nil {
+			m.Account = a.Name
+			return m, nil
+		}
+		// Only a missing meeting or a rejected token means another account may have it
+		if !errors.Is(err, errKrispNotFound) && !errors.Is(err, errKrispUnauthorized) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("meeting not found in any Krisp account: %w", lastErr)
+}
+
+// forEachAccount runs fn with the client of each configured account in turn (or once for
+// a single account)
+func forEachAccount(fn func(api *KrispClient) error) error {
+	if len(krispAccounts) == 0 {
+		return fn(krispAPI)
+	}
+	for _, a := range krispAccounts {
+		fmt.Printf("\n👤 Krisp account: %s\n", a.Name)
+		if err := fn(accountClient(a.Name)); err != nil {
+			return fmt.Errorf("account %s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// listCursor returns the listing cursor of an account ("" for a single account)
+func (s *SyncState) listCursor(account string) *ListCursor {
+	if account == "" {
+		return s.ListCursor
+	}
+	return s.AccountCursors[account]
+}
+
+// setListCursor stores the listing cursor of an account ("" for a single account)
+func (s *SyncState) setListCursor(account string, cursor *ListCursor) {
+	if account == "" {
+		s.ListCursor = cursor
+		return
+	}
+	if s.AccountCursors == nil {
+		s.AccountCursors = make(map[string]*ListCursor)
+	}
+	s.AccountCursors[account] = cursor
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// actionItemsHeading is the summary note section holding action item checkboxes
+const actionItemsHeading = "## Action Items"
+
+// ActionItem is a follow-up task from a meeting
+type ActionItem struct {
+	Text        string     `json:"text"`
+	Owner       string     `json:"owner,omitempty"`
+	Done        bool       `json:"done,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // when completion was first seen in the vault
+}
+
+// Label is how the item appears after the checkbox in the note; it is also used to
+// match checkboxes back to cached items
+func (a ActionItem) Label() string {
+	if a.Owner != "" {
+		return fmt.Sprintf("%s (%s)", a.Text, a.Owner)
+	}
+	return a.Text
+}
+
+// myActionItemsHeading is the daily note and planning note section listing your own action items
+const myActionItemsHeading = "## My Action Items"
+
+// myEmails returns your email addresses (MY_EMAIL, comma-separated), lowercase
+func myEmails() []string {
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("MY_EMAIL"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// myNames returns the names action items are assigned to you under: MY_NAME (comma-separated)
+// and the people directory names of your MY_EMAIL addresses
+func myNames() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("MY_NAME"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	for _, email := range myEmails() {
+		if name := preferredName(email, ""); name != "" && !containsFold(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mine reports whether the item is owned by you (matched by full or first name against
+// MY_NAME, or by email against MY_EMAIL)
+func (a ActionItem) Mine() bool {
+	if a.Owner == "" {
+		return false
+	}
+	if containsFold(myEmails(), a.Owner) {
+		return true
+	}
+	for _, name := range myNames() {
+		if strings.EqualFold(a.Owner, name) || strings.EqualFold(a.Owner, strings.Fields(name)[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// myCommitmentsDirective tells the model who you are, so it assigns what you committed to
+// to you by name
+func myCommitmentsDirective() string {
+	names := myNames()
+	if len(names) == 0 {
+		return ""
+	}
+	who := names[0]
+	if len(names) > 1 {
+		who += fmt.Sprintf(" (also called %s)", strings.Join(names[1:], ", "))
+	}
+	if emails := myEmails(); len(emails) > 0 {
+		who += fmt.Sprintf(" <%s>", strings.Join(emails, ", "))
+	}
+	return fmt.Sprintf("These notes are for %s. List everything %s committed to do, or was asked to do and accepted, as an action item with the owner %q.", who, names[0], names[0])
+}
+
+// renderMyActionItems renders action items as linked checkboxes for the My Action Items
+// section, keeping the checkboxes ticked in existing (the section's current content)
+func renderMyActionItems(items []planItem, existing string) string {
+	checked := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		if match := planItemRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			checked[actionItemKey(match[3], match[2])] = match[1] != " "
+		}
+	}
+
+	var sb strings.Builder
+	for _, p := range items {
+		done, ok := checked[actionItemKey(p.Meeting.ID, p.Item.Label())]
+		if !ok {
+			done = p.Item.Done
+		}
+		box := " "
+		if done {
+			box = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] %s ([[%s-summary|%s]])\n", box, p.Item.Label(), p.Meeting.ID, p.Title))
+	}
+	return sb.String()
+}
+
+// updateDailyMyActionItems writes the My Action Items section of a day's daily note: your
+// action items from that day's meetings, through w. Confidential meetings are left out.
+func updateDailyMyActionItems(w VaultWriter, path string, day time.Time, cache *Cache) error {
+	if len(myNames()) == 0 && len(myEmails()) == 0 {
+		return nil
+	}
+	meetings, summaries, err := daySummaries(cache, day)
+	if err != nil {
+		return err
+	}
+	var items []planItem
+	for _, m := range meetings {
+		s := summaries[m.ID]
+		for _, item := range s.ActionItems {
+			if item.Mine() {
+				items = append(items, planItem{Meeting: m, Title: firstNonEmpty(s.Title, m.Title), Item: item})
+			}
+		}
+	}
+
+	content, err := w.ReadNote(path)
+	if err != nil {
+		return err
+	}
+	existing := sectionBody(string(content), myActionItemsHeading)
+	if len(items) == 0 && existing == "" {
+		return nil
+	}
+	body := renderMyActionItems(items, existing)
+	if body == "" {
+		body = "_None_\n"
+	}
+	return w.UpsertSection(path, myActionItemsHeading, body)
+}
+
+// hasMyActionItems reports whether a summary has action items of yours
+func hasMyActionItems(summaryData *SummaryData) bool {
+	for _, item := range summaryData.ActionItems {
+		if item.Mine() {
+			return true
+		}
+	}
+	return false
+}
+
+// renderActionItems renders the "Action Items" section of a summary note, or "" if there are none
+func renderActionItems(summaryData *SummaryData) string {
+	if summaryData == nil || len(summaryData.ActionItems) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(actionItemsHeading + "\n")
+	for _, item := range summaryData.ActionItems {
+		box := " "
+		if item.Done {
+			box = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", box, item.Label()))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// parseActionItemCheckboxes returns the checked state of each checkbox in a note's
+// Action Items section, keyed by label
+func parseActionItemCheckboxes(body string) map[string]bool {
+	checkboxes := make(map[string]bool)
+	inSection := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if headingLevel(trimmed) > 0 {
+			inSection = trimmed == actionItemsHeading
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		for _, prefix := range []string{"- [ ] ", "- [x] ", "- [X] "} {
+			if strings.HasPrefix(trimmed, prefix) {
+				label := strings.TrimSpace(strings.TrimPrefix(trimmed, prefix))
+				checkboxes[label] = prefix != "- [ ] "
+				break
+			}
+		}
+	}
+	return checkboxes
+}
+
+// runActionItemsSync re-scans synced summary notes, weekly planning notes and daily notes
+// and records checked-off action items in the cache
+func runActionItemsSync(obsidianVaultPath string, syncState *SyncState, cache *Cache) error {
+	fmt.Println("\n=== Action Items: Syncing completion from vault ===")
+
+	meetingIDs := make([]string, 0, len(syncState.ObsidianSyncedMeetings))
+	for id := range syncState.ObsidianSyncedMeetings {
+		meetingIDs = append(meetingIDs, id)
+	}
+	sort.Strings(meetingIDs)
+
+	now := time.Now()
+	scanned, completed, reopened := 0, 0, 0
+	changedInNotes := make(map[string]bool)
+	// Only the daily notes of days with your action items have a My Action Items section
+	var myItemDays []time.Time
+	seenDays := make(map[string]bool)
+	for _, id := range meetingIDs {
+		summaryData, err := cache.LoadSummary(id)
+		if err != nil || len(summaryData.ActionItems) == 0 {
+			continue
+		}
+		meeting, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if day := meeting.CreatedAt.Local(); !seenDays[day.Format("2006-01-02")] && hasMyActionItems(summaryData) {
+			seenDays[day.Format("2006-01-02")] = true
+			myItemDays = append(myItemDays, day)
+		}
+
+		notePath := summaryNotePath(obsidianVaultPath, meeting)
+		if !vaultWriter.Exists(notePath) {
+			continue
+		}
+		_, body, err := parseFrontmatter(notePath)
+		if err != nil {
+			fmt.Printf("  ⚠ Error reading %s: %v\n", notePath, err)
+			continue
+		}
+		scanned++
+
+		checkboxes := parseActionItemCheckboxes(body)
+		changed := false
+		for i := range summaryData.ActionItems {
+			item := &summaryData.ActionItems[i]
+			done, ok := checkboxes[item.Label()]
+			if !ok || done == item.Done {
+				continue
+			}
+
+			item.Done = done
+			changedInNotes[actionItemKey(id, item.Label())] = true
+			if done {
+				item.CompletedAt = &now
+				completed++
+				fmt.Printf("  ✓ %s: %s\n", meeting.Title, item.Label())
+			} else {
+				item.CompletedAt = nil
+				reopened++
+				fmt.Printf("  ↺ %s: %s\n", meeting.Title, item.Label())
+			}
+			changed = true
+		}
+
+		if changed {
+			if err := cache.SaveSummary(id, summaryData); err != nil {
+				fmt.Printf("  ⚠ Error saving summary %s: %v\n", id, err)
+			}
+		}
+	}
+
+	planCompleted, planReopened, err := reconcileTaskNotes(obsidianVaultPath, cache, changedInNotes, myItemDays)
+	if err != nil {
+		return err
+	}
+	completed += planCompleted
+	reopened += planReopened
+
+	fmt.Printf("\n📊 Scanned %d note(s): %d item(s) completed, %d reopened\n", scanned, completed, reopened)
+	return nil
+}
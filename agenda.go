@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"google.golang.org/genai"
+)
+
+//go:embed agenda-prompt.md
+var agendaPromptTemplate string
+
+// AgendaSlice is the part of a meeting spent on one agenda item
+type AgendaSlice struct {
+	Item      string   `json:"item"`
+	Start     float64  `json:"start"` // seconds into the meeting; -1 when the item wasn't discussed
+	End       float64  `json:"end"`
+	SegmentID *int     `json:"segment_id,omitempty"` // first transcript segment of the slice
+	Takeaways []string `json:"takeaways,omitempty"`
+}
+
+// Discussed reports whether the meeting got to the agenda item
+func (a AgendaSlice) Discussed() bool {
+	return a.Start >= 0
+}
+
+var (
+	agendaHeadingRegex   = regexp.MustCompile(`(?i)^\s*(?:#+\s*)?\**agenda\**\s*:?\s*$`)
+	agendaItemRegex      = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+(.+?)\s*$`)
+	agendaHTMLTagRegex   = regexp.MustCompile(`<[^>]+>`)
+	agendaHTMLBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</div>`)
+)
+
+// agendaSlicesEnabled reports whether transcripts are split by agenda item (AGENDA_SLICES, default on)
+func agendaSlicesEnabled() bool {
+	return envBoolDefault("AGENDA_SLICES", true)
+}
+
+// parseAgenda returns the agenda items in a calendar event description: the list under an
+// "Agenda" heading if there is one, otherwise every list item. Fewer than two items isn't an agenda.
+func parseAgenda(description string) []string {
+	text := agendaHTMLBreakRegex.ReplaceAllString(description, "\n")
+	text = strings.ReplaceAll(text, "<li>", "\n- ")
+	text = html.UnescapeString(agendaHTMLTagRegex.ReplaceAllString(text, ""))
+	lines := strings.Split(text, "\n")
+
+	start := 0
+	for i, line := range lines {
+		if agendaHeadingRegex.MatchString(line) {
+			start = i + 1
+			break
+		}
+	}
+
+	var items []string
+	for _, line := range lines[start:] {
+		if match := agendaItemRegex.FindStringSubmatch(line); match != nil {
+			items = append(items, match[1])
+			continue
+		}
+		// The agenda list ends at the first other line after it
+		if start > 0 && len(items) > 0 && strings.TrimSpace(line) != "" {
+			break
+		}
+	}
+	if len(items) < 2 {
+		return nil
+	}
+	return items
+}
+
+// meetingAgenda returns the agenda items of a meeting's calendar event, if it has one
+func meetingAgenda(m *Meeting) []string {
+	if m.CalendarEvent == nil {
+		return nil
+	}
+	return parseAgenda(m.CalendarEvent.Description)
+}
+
+// generateAgendaSlices splits newly summarized meetings that have a calendar agenda into
+// per-item slices of the transcript, with takeaways for each item
+func generateAgendaSlices(ctx context.Context, meetingIDs []string, cache *Cache) {
+	if !agendaSlicesEnabled() {
+		return
+	}
+
+	for _, id := range meetingIDs {
+		if ctx.Err() != nil || budgetStop("agenda slices") {
+			return
+		}
+
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		items := meetingAgenda(m)
+		if len(items) == 0 {
+			continue
+		}
+		summary, err := cache.LoadSummary(id)
+		if err != nil {
+			continue
+		}
+		var segments []Segment
+		if err := json.Unmarshal([]byte(m.Resources.Transcript.Content), &segments); err != nil || len(segments) == 0 {
+			continue
+		}
+
+		fmt.Printf("📋 Matching %s to %d agenda item(s)\n", id, len(items))
+		slices, err := sliceByAgenda(ctx, m, items, segments, inferredSpeakers(summary))
+		if err != nil {
+			fmt.Printf("  ⚠ Error matching transcript to agenda: %v\n", err)
+			continue
+		}
+
+		summary.Agenda = slices
+		if err := cache.SaveSummary(id, summary); err != nil {
+			fmt.Printf("  ⚠ Error saving agenda slices: %v\n", err)
+			continue
+		}
+		discussed := 0
+		for _, s := range slices {
+			if s.Discussed() {
+				discussed++
+			}
+		}
+		fmt.Printf("  ✓ %d of %d agenda item(s) discussed\n", discussed, len(slices))
+	}
+}
+
+// sliceByAgenda asks the LLM where each agenda item starts in the transcript and what came of it
+func sliceByAgenda(ctx context.Context, m *Meeting, items []string, segments []Segment, inferred map[string]string) ([]AgendaSlice, error) {
+	var agenda, transcript strings.Builder
+	for i, item := range items {
+		agenda.WriteString(fmt.Sprintf("%d. %s\n", i+1, item))
+	}
+	for _, seg := range segments {
+		transcript.WriteString(fmt.Sprintf("[%d] %s: %s\n", int(seg.Speech.Start), speakerDisplayName(m, seg.SpeakerIndex, inferred), seg.Speech.Text))
+	}
+
+	tmpl, err := template.New("agenda").Parse(agendaPromptTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agenda template: %w", err)
+	}
+	var promptBuf bytes.Buffer
+	if err := tmpl.Execute(&promptBuf, map[string]string{
+		"Title":      m.Title,
+		"Agenda":     agenda.String(),
+		"Transcript": transcript.String(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to execute agenda template: %w", err)
+	}
+
+	schema := &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"items": {
+				Type:        genai.TypeArray,
+				Description: "One entry per agenda item, in agenda order",
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"item": {
+							Type:        genai.TypeString,
+							Description: "The agenda item, exactly as given",
+						},
+						"start_seconds": {
+							Type:        genai.TypeInteger,
+							Description: "Offset in seconds of the first transcript line about the item, or -1 if not discussed",
+						},
+						"takeaways": {
+							Type:        genai.TypeArray,
+							Description: "1-3 key takeaways, one short sentence each",
+							Items:       &genai.Schema{Type: genai.TypeString},
+						},
+					},
+					Required: []string{"item", "start_seconds"},
+				},
+			},
+		},
+		Required: []string{"items"},
+	}
+
+	response, err := generateStructured(ctx, promptBuf.String(), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Items []struct {
+			Item         string   `json:"item"`
+			StartSeconds int      `json:"start_seconds"`
+			Takeaways    []string `json:"takeaways"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(repairJSON(response)), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse agenda slices: %w", err)
+	}
+
+	// Keep the calendar's wording and order, whatever the model returned
+	slices := make([]AgendaSlice, len(items))
+	for i, item := range items {
+		slices[i] = AgendaSlice{Item: item, Start: -1, End: -1}
+	}
+	for i, got := range data.Items {
+		index := i
+		for j, item := range items {
+			if strings.EqualFold(strings.TrimSpace(got.Item), item) {
+				index = j
+				break
+			}
+		}
+		if index >= len(slices) {
+			continue
+		}
+		slices[index].Takeaways = got.Takeaways
+		if got.StartSeconds >= 0 {
+			slices[index].Start = float64(got.StartSeconds)
+		}
+	}
+
+	// Each slice runs until the next discussed item starts (or the meeting ends)
+	var discussed []int
+	for i := range slices {
+		if slices[i].Discussed() {
+			discussed = append(discussed, i)
+		}
+	}
+	sort.Slice(discussed, func(a, b int) bool { return slices[discussed[a]].Start < slices[discussed[b]].Start })
+	meetingEnd := segments[len(segments)-1].Speech.End
+	for n, i := range discussed {
+		slices[i].End = meetingEnd
+		if n+1 < len(discussed) {
+			slices[i].End = slices[discussed[n+1]].Start
+		}
+		for _, seg := range segments {
+			if seg.Speech.End > slices[i].Start {
+				id := seg.ID
+				slices[i].SegmentID = &id
+				break
+			}
+		}
+	}
+	return slices, nil
+}
+
+// renderAgenda renders the per-agenda-item section of a summary, or "" without an agenda
+func renderAgenda(summaryData *SummaryData, meetingID string, offset float64) string {
+	if summaryData == nil || len(summaryData.Agenda) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Agenda\n")
+	for _, slice := range summaryData.Agenda {
+		sb.WriteString(fmt.Sprintf("### %s\n", slice.Item))
+		if !slice.Discussed() {
+			sb.WriteString("_Not discussed_\n\n")
+			continue
+		}
+		span := formatTimestamp(slice.Start+offset) + "–" + formatTimestamp(slice.End+offset)
+		if slice.SegmentID != nil {
+			span = fmt.Sprintf("[[%s-transcript#^%s|%s]]", meetingID, transcriptBlockID(*slice.SegmentID), span)
+		}
+		sb.WriteString(fmt.Sprintf("_%s_\n", span))
+		for _, takeaway := range slice.Takeaways {
+			sb.WriteString(fmt.Sprintf("- %s\n", takeaway))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// agendaHeadings maps the first transcript segment of each discussed agenda item to the item
+func agendaHeadings(agenda []AgendaSlice) map[int]string {
+	headings := make(map[int]string)
+	for _, slice := range agenda {
+		if slice.SegmentID != nil {
+			headings[*slice.SegmentID] = slice.Item
+		}
+	}
+	return headings
+}
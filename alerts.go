@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// alertExcerptChars is the longest excerpt an alert quotes
+const alertExcerptChars = 240
+
+// alertMeToken stands for your own names (MY_NAME) in an alert keyword
+const alertMeToken = "me"
+
+// alertKeywordsFile records when each ALERT_KEYWORDS entry was first seen
+const alertKeywordsFile = "alert-keywords.json"
+
+// KeywordAlert is a keyword found in a newly summarized meeting
+type KeywordAlert struct {
+	Keyword string     `json:"keyword"`           // the ALERT_KEYWORDS entry that matched
+	Excerpt string     `json:"excerpt"`           // the transcript line or summary passage it matched in
+	SentAt  *time.Time `json:"sent_at,omitempty"` // when the notification went out
+}
+
+// alertRule is one ALERT_KEYWORDS entry: terms that must all appear in the same transcript
+// line or summary passage. A term matches any of its patterns. Only meetings after Since,
+// when the keyword was added, are alerted on.
+type alertRule struct {
+	Keyword string
+	Since   time.Time
+	terms   [][]*wordPattern
+}
+
+// alertRulesFromEnv reads ALERT_KEYWORDS, a comma-separated list of keywords or phrases.
+// Terms joined with "+" must appear together ("me+deadline"), and "me" stands for any of
+// your names (MY_NAME). Keywords seen for the first time are recorded in alert-keywords.json.
+func alertRulesFromEnv() ([]alertRule, error) {
+	v := strings.TrimSpace(os.Getenv("ALERT_KEYWORDS"))
+	if v == "" {
+		return nil, nil
+	}
+	since, err := loadAlertKeywords()
+	if err != nil {
+		return nil, err
+	}
+	added := false
+	var rules []alertRule
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, ok := since[entry]; !ok {
+			since[entry] = time.Now()
+			added = true
+		}
+		rule := alertRule{Keyword: entry, Since: since[entry]}
+		for _, term := range splitAlertTerms(entry) {
+			if term == "" {
+				return nil, fmt.Errorf("invalid ALERT_KEYWORDS %q: empty term in %q", v, entry)
+			}
+			alternatives := []string{term}
+			if strings.EqualFold(term, alertMeToken) {
+				alternatives = myNames()
+				if len(alternatives) == 0 {
+					return nil, fmt.Errorf("invalid ALERT_KEYWORDS %q: %q needs MY_NAME to be set", v, alertMeToken)
+				}
+			}
+			var patterns []*wordPattern
+			for _, alt := range alternatives {
+				patterns = append(patterns, newWordPattern(alt, true))
+			}
+			rule.terms = append(rule.terms, patterns)
+		}
+		rules = append(rules, rule)
+	}
+	if added && !dryRun {
+		if err := saveAlertKeywords(since); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// splitAlertTerms splits an ALERT_KEYWORDS entry at "+". A "+" with no term after it is
+// part of the term before it, so "C++" and "C+++deadline" work.
+func splitAlertTerms(entry string) []string {
+	var terms []string
+	for i, piece := range strings.Split(entry, "+") {
+		if piece == "" && i > 0 && terms[len(terms)-1] != "" {
+			terms[len(terms)-1] += "+"
+			continue
+		}
+		terms = append(terms, piece)
+	}
+	for i := range terms {
+		terms[i] = strings.TrimSpace(terms[i])
+	}
+	return terms
+}
+
+// loadAlertKeywords reads when each alert keyword was first seen
+func loadAlertKeywords() (map[string]time.Time, error) {
+	since := make(map[string]time.Time)
+	data, err := os.ReadFile(dataPath(alertKeywordsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return since, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", alertKeywordsFile, err)
+	}
+	if err := json.Unmarshal(data, &since); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", alertKeywordsFile, err)
+	}
+	return since, nil
+}
+
+// saveAlertKeywords writes alert-keywords.json
+func saveAlertKeywords(since map[string]time.Time) error {
+	data, err := json.MarshalIndent(since, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert keywords: %w", err)
+	}
+	return writeFileAtomic(dataPath(alertKeywordsFile), data)
+}
+
+// matches reports whether every term of the rule appears in text
+func (r alertRule) matches(text string) bool {
+	for _, alternatives := range r.terms {
+		found := false
+		for _, pattern := range alternatives {
+			if pattern.MatchString(text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchKeywordAlerts checks a meeting's transcript and summary against the alert rules,
+// returning one alert per matching rule with the first passage it matched in. The summary
+// is checked first, since its passages read better out of context. Rules added after the
+// meeting (e.g. while backfilling old meetings) are skipped.
+func matchKeywordAlerts(rules []alertRule, m *Meeting, transcript string, summaryData *SummaryData) []KeywordAlert {
+	if len(rules) == 0 {
+		return nil
+	}
+	passages := []string{summaryData.Description}
+	passages = append(passages, summaryData.Decisions...)
+	for _, item := range summaryData.ActionItems {
+		passages = append(passages, item.Label())
+	}
+	for _, detail := range summaryData.TopicDetails {
+		passages = append(passages, detail.Topic+": "+detail.Summary)
+	}
+	passages = append(passages, summaryData.Topics...)
+	passages = append(passages, strings.Split(transcript, "\n")...)
+
+	var alerts []KeywordAlert
+	for _, rule := range rules {
+		if m.CreatedAt.Before(rule.Since) {
+			continue
+		}
+		for _, passage := range passages {
+			if rule.matches(passage) {
+				alerts = append(alerts, KeywordAlert{Keyword: rule.Keyword, Excerpt: alertExcerpt(passage)})
+				break
+			}
+		}
+	}
+	return alerts
+}
+
+// alertExcerpt shortens a passage for a notification
+func alertExcerpt(passage string) string {
+	passage = strings.Join(strings.Fields(passage), " ")
+	if len(passage) <= alertExcerptChars {
+		return passage
+	}
+	cut := strings.LastIndex(passage[:alertExcerptChars], " ")
+	if cut <= 0 {
+		cut = alertExcerptChars
+	}
+	return passage[:cut] + "…"
+}
+
+// sendKeywordAlerts notifies about a meeting's alerts that haven't been sent yet, with a
+// link to its note, and marks them sent. Confidential meetings' alerts leave the excerpt
+// out of Slack. Returns whether any alert was sent.
+func sendKeywordAlerts(vaultPath, notePath string, m *Meeting, summaryData *SummaryData, confidential bool) bool {
+	link, err := obsidianURI(vaultPath, notePath)
+	if err != nil {
+		link = notePath
+	}
+	title := firstNonEmpty(summaryData.Title, m.Title)
+
+	sent := false
+	for i := range summaryData.Alerts {
+		alert := &summaryData.Alerts[i]
+		if alert.SentAt != nil {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("  🚨 Would alert on %q: %s\n", alert.Keyword, alert.Excerpt)
+			continue
+		}
+
+		heading := fmt.Sprintf("🚨 %q in %s", alert.Keyword, title)
+		fmt.Printf("  %s\n     %s\n", heading, alert.Excerpt)
+		if envBoolDefault("ALERT_DESKTOP", true) {
+			if err := notifyDesktop(heading, alert.Excerpt); err != nil {
+				fmt.Printf("  ⚠ Error sending desktop notification: %v\n", err)
+			}
+		}
+		if webhook := os.Getenv("ALERT_SLACK_WEBHOOK"); webhook != "" {
+			excerpt := alert.Excerpt
+			if confidential {
+				excerpt = "(confidential meeting, see the note)"
+			}
+			text := fmt.Sprintf("*%s* (%s)\n> %s\n%s", heading, m.CreatedAt.Local().Format("2006-01-02 15:04"), excerpt, link)
+			if err := notifySlack(webhook, text); err != nil {
+				fmt.Printf("  ⚠ Error sending Slack alert: %v\n", err)
+			}
+		}
+		now := time.Now()
+		alert.SentAt = &now
+		sent = true
+	}
+	return sent
+}
+
+// notifyDesktop shows a desktop notification (notify-send on Linux, Notification Center on
+// macOS); other systems only get the console output
+func notifyDesktop(title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		return exec.Command("notify-send", "--app-name=krisp-sync", title, body).Run()
+	default:
+		return nil
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// notifySlack posts a message to a Slack incoming webhook
+func notifySlack(webhook, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// analyticsTrendMonths is how many previous months the trend table compares against
+const analyticsTrendMonths = 3
+
+// monthTotals aggregates meeting time for one month
+type monthTotals struct {
+	Month    time.Time
+	Meetings int
+	Seconds  int
+
+	ByTag         map[string]int
+	ByProject     map[string]int
+	ByParticipant map[string]int
+}
+
+func newMonthTotals(month time.Time) *monthTotals {
+	return &monthTotals{
+		Month:         month,
+		ByTag:         make(map[string]int),
+		ByProject:     make(map[string]int),
+		ByParticipant: make(map[string]int),
+	}
+}
+
+// monthStart returns the first instant of t's month in local time
+func monthStart(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// parseMonth parses a YYYY-MM month, defaulting to the current month when empty
+func parseMonth(value string) (time.Time, error) {
+	if value == "" {
+		return monthStart(time.Now()), nil
+	}
+	t, err := time.ParseInLocation("2006-01", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q (expected YYYY-MM)", value)
+	}
+	return t, nil
+}
+
+// projectTagsFromEnv reads ANALYTICS_PROJECT_TAGS, the tags that represent projects
+func projectTagsFromEnv() map[string]bool {
+	projects := make(map[string]bool)
+	for _, tag := range splitTags(os.Getenv("ANALYTICS_PROJECT_TAGS")) {
+		projects[tag] = true
+	}
+	return projects
+}
+
+// meetingDurationSeconds returns a meeting's length, falling back to its transcript stats
+func meetingDurationSeconds(m *Meeting, cache *Cache) int {
+	if m.Duration > 0 {
+		return m.Duration
+	}
+	if stats, err := cache.LoadStats(m.ID); err == nil {
+		return stats.DurationSeconds
+	}
+	return 0
+}
+
+// collectMonthTotals aggregates cached meetings for the given month and the months before it
+func collectMonthTotals(cache *Cache, month time.Time, previous int) (map[time.Time]*monthTotals, error) {
+	meetingIDs, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	earliest := month.AddDate(0, -previous, 0)
+	end := month.AddDate(0, 1, 0)
+	projects := projectTagsFromEnv()
+
+	totals := make(map[time.Time]*monthTotals)
+	for i := 0; i <= previous; i++ {
+		m := month.AddDate(0, -i, 0)
+		totals[m] = newMonthTotals(m)
+	}
+
+	for _, id := range meetingIDs {
+		meeting, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if meeting.CreatedAt.Before(earliest) || !meeting.CreatedAt.Before(end) {
+			continue
+		}
+
+		t := totals[monthStart(meeting.CreatedAt)]
+		seconds := meetingDurationSeconds(meeting, cache)
+		t.Meetings++
+		t.Seconds += seconds
+
+		if summary, err := cache.LoadSummary(id); err == nil {
+			for _, tag := range splitTags(summary.Tags) {
+				t.ByTag[tag] += seconds
+				if projects[tag] {
+					t.ByProject[tag] += seconds
+				}
+			}
+		}
+		for _, p := range meetingParticipants(meeting) {
+			t.ByParticipant[p] += seconds
+		}
+	}
+
+	return totals, nil
+}
+
+// formatHours renders seconds as hours with one decimal
+func formatHours(seconds int) string {
+	return fmt.Sprintf("%.1f h", float64(seconds)/3600)
+}
+
+// writeHoursTable renders a "name | hours | share" table, largest first
+func writeHoursTable(sb *strings.Builder, label string, hours map[string]int, total int, limit int) {
+	type row struct {
+		Name    string
+		Seconds int
+	}
+	var rows []row
+	for name, seconds := range hours {
+		rows = append(rows, row{name, seconds})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Seconds != rows[j].Seconds {
+			return rows[i].Seconds > rows[j].Seconds
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	sb.WriteString(fmt.Sprintf("| %s | Hours | Share |\n", label))
+	sb.WriteString("|---|---:|---:|\n")
+	for _, r := range rows {
+		share := 0.0
+		if total > 0 {
+			share = float64(r.Seconds) * 100 / float64(total)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %.0f%% |\n", r.Name, formatHours(r.Seconds), share))
+	}
+	sb.WriteString("\n")
+}
+
+// renderAnalyticsReport renders the monthly meeting time report note
+func renderAnalyticsReport(month time.Time, totals map[time.Time]*monthTotals) string {
+	current := totals[month]
+
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("type: meeting-report\n")
+	sb.WriteString(fmt.Sprintf("month: %s\n", month.Format("2006-01")))
+	sb.WriteString(fmt.Sprintf("meeting_hours: %.1f\n", float64(current.Seconds)/3600))
+	sb.WriteString(fmt.Sprintf("meetings: %d\n", current.Meetings))
+	sb.WriteString("---\n\n")
+	sb.WriteString(fmt.Sprintf("# Meeting Time - %s\n\n", month.Format("January 2006")))
+
+	sb.WriteString(fmt.Sprintf("- **Total**: %s in %d meeting(s)\n", formatHours(current.Seconds), current.Meetings))
+	if current.Meetings > 0 {
+		sb.WriteString(fmt.Sprintf("- **Average length**: %s\n", formatTimestamp(float64(current.Seconds/current.Meetings))))
+	}
+	sb.WriteString("\n")
+
+	// Trend vs previous months
+	sb.WriteString("## Trend\n\n")
+	sb.WriteString("| Month | Meetings | Hours | Change |\n")
+	sb.WriteString("|---|---:|---:|---:|\n")
+	for i := analyticsTrendMonths; i >= 0; i-- {
+		m := month.AddDate(0, -i, 0)
+		t := totals[m]
+		change := ""
+		if prev, ok := totals[m.AddDate(0, -1, 0)]; ok && prev.Seconds > 0 {
+			change = fmt.Sprintf("%+.0f%%", (float64(t.Seconds)-float64(prev.Seconds))*100/float64(prev.Seconds))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n", m.Format("Jan 2006"), t.Meetings, formatHours(t.Seconds), change))
+	}
+	sb.WriteString("\n")
+
+	if len(current.ByProject) > 0 {
+		sb.WriteString("## By Project\n\n")
+		writeHoursTable(&sb, "Project", current.ByProject, current.Seconds, 0)
+	}
+	if len(current.ByTag) > 0 {
+		sb.WriteString("## By Tag\n\n")
+		sb.WriteString("_Meetings count fully towards each of their tags, so shares add up to more than 100%._\n\n")
+		writeHoursTable(&sb, "Tag", current.ByTag, current.Seconds, 20)
+	}
+	if len(current.ByParticipant) > 0 {
+		sb.WriteString("## By Participant\n\n")
+		writeHoursTable(&sb, "Participant", current.ByParticipant, current.Seconds, 20)
+	}
+
+	return sb.String()
+}
+
+// runAnalytics writes a meeting time report note for a month into that month's folder
+func runAnalytics(obsidianVaultPath string, cache *Cache, monthFlag string) error {
+	fmt.Println("\n=== Analytics: Meeting time report ===")
+
+	month, err := parseMonth(monthFlag)
+	if err != nil {
+		return err
+	}
+
+	totals, err := collectMonthTotals(cache, month, analyticsTrendMonths+1)
+	if err != nil {
+		return err
+	}
+
+	current := totals[month]
+	fmt.Printf("📊 %s: %d meeting(s), %s\n", month.Format("January 2006"), current.Meetings, formatHours(current.Seconds))
+
+	path := filepath.Join(monthDir(obsidianVaultPath, month), month.Format("2006-01")+" Meeting Report.md")
+	if err := vaultWriter.CreateNote(path, []byte(renderAnalyticsReport(month, totals))); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	fmt.Printf("✓ Wrote report: %s\n", path)
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultAnthropicBaseURL   = "https://api.anthropic.com"
+	defaultAnthropicModel     = "claude-3-5-haiku-latest"
+	defaultAnthropicMaxTokens = 8192
+	anthropicAPIVersion       = "2023-06-01"
+
+	// anthropicResponseTool is the tool Claude is made to call with a structured response
+	anthropicResponseTool = "respond"
+)
+
+// anthropicSummarizer calls Claude through the Anthropic Messages API. Structured
+// responses use a tool the model is required to call, whose input is the response.
+type anthropicSummarizer struct {
+	baseURL   string
+	apiKey    string
+	model     string
+	maxTokens int
+}
+
+// newAnthropicSummarizer configures the backend from ANTHROPIC_API_KEY, ANTHROPIC_MODEL,
+// ANTHROPIC_MAX_TOKENS and ANTHROPIC_BASE_URL
+func newAnthropicSummarizer() *anthropicSummarizer {
+	a := &anthropicSummarizer{
+		baseURL:   strings.TrimRight(firstNonEmpty(os.Getenv("ANTHROPIC_BASE_URL"), defaultAnthropicBaseURL), "/"),
+		apiKey:    os.Getenv("ANTHROPIC_API_KEY"),
+		model:     firstNonEmpty(os.Getenv("ANTHROPIC_MODEL"), defaultAnthropicModel),
+		maxTokens: defaultAnthropicMaxTokens,
+	}
+	if v := os.Getenv("ANTHROPIC_MAX_TOKENS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			a.maxTokens = n
+		} else {
+			fmt.Printf("⚠ Ignoring invalid ANTHROPIC_MAX_TOKENS %q\n", v)
+		}
+	}
+	return a
+}
+
+func (a *anthropicSummarizer) Name() string { return providerAnthropic }
+
+func (a *anthropicSummarizer) DefaultModel() string { return a.model }
+
+func (a *anthropicSummarizer) Ready() error {
+	if a.apiKey == "" {
+		return fmt.Errorf("ANTHROPIC_API_KEY not set; set it to use LLM_PROVIDER=anthropic")
+	}
+	return nil
+}
+
+// anthropicError is an error response of the Messages API
+type anthropicError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *anthropicError) Error() string {
+	return fmt.Sprintf("Anthropic API returned status %d (%s): %s", e.StatusCode, e.Type, e.Message)
+}
+
+func (a *anthropicSummarizer) Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	if err := a.Ready(); err != nil {
+		return "", err
+	}
+
+	request := map[string]interface{}{
+		"model":       model,
+		"max_tokens":  a.maxTokens,
+		"temperature": opts.Temperature,
+		"messages":    []map[string]string{{"role": "user", "content": prompt}},
+	}
+	if opts.Schema != nil {
+		request["tools"] = []map[string]interface{}{{
+			"name":         anthropicResponseTool,
+			"description":  "Give the response, following the input schema exactly",
+			"input_schema": jsonSchema(opts.Schema),
+		}}
+		request["tool_choice"] = map[string]string{"type": "tool", "name": anthropicResponseTool}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	client := llmHTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &anthropicError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		if json.Unmarshal(respBody, &parsed) == nil && parsed.Error != nil {
+			apiErr.Type, apiErr.Message = parsed.Error.Type, parsed.Error.Message
+		}
+		return "", fmt.Errorf("failed to generate content: %w", apiErr)
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	reportTokens(ctx, parsed.Usage.InputTokens, parsed.Usage.OutputTokens)
+	if parsed.StopReason == "refusal" {
+		return "", &ContentBlockedError{Reason: "REFUSAL"}
+	}
+
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		switch {
+		case block.Type == "tool_use" && block.Name == anthropicResponseTool:
+			if parsed.StopReason == "max_tokens" {
+				return "", fmt.Errorf("response cut off at ANTHROPIC_MAX_TOKENS (%d)", a.maxTokens)
+			}
+			return string(block.Input), nil
+		case block.Type == "text":
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no content generated")
+	}
+	// Without a tool call a structured response may still be JSON in the text
+	if opts.Schema != nil {
+		return repairJSON(text.String()), nil
+	}
+	return text.String(), nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultArchiveDir is where archived months go when ARCHIVE_DIR is not set
+const defaultArchiveDir = "vault:Archive"
+
+var (
+	yearFolderRegex  = regexp.MustCompile(`^\d{4}$`)
+	monthFolderRegex = regexp.MustCompile(`^(\d{2})-[A-Za-z]+$`)
+)
+
+// archiveAfterMonths reads ARCHIVE_AFTER_MONTHS; 0 disables archiving
+func archiveAfterMonths() (int, error) {
+	v := os.Getenv("ARCHIVE_AFTER_MONTHS")
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid ARCHIVE_AFTER_MONTHS %q: must be a non-negative number", v)
+	}
+	return n, nil
+}
+
+// findMonthFolders returns the vault-relative YYYY/MM-MonthName folders with their month start
+func findMonthFolders(vaultPath string) (map[string]time.Time, error) {
+	months := make(map[string]time.Time)
+
+	years, err := os.ReadDir(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, year := range years {
+		if !year.IsDir() || !yearFolderRegex.MatchString(year.Name()) {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(vaultPath, year.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			match := monthFolderRegex.FindStringSubmatch(entry.Name())
+			if !entry.IsDir() || match == nil {
+				continue
+			}
+			y, _ := strconv.Atoi(year.Name())
+			m, _ := strconv.Atoi(match[1])
+			if m < 1 || m > 12 {
+				continue
+			}
+			months[filepath.Join(year.Name(), entry.Name())] = time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.Local)
+		}
+	}
+	return months, nil
+}
+
+// rewriteArchivedLinks points path-based wikilinks, markdown links and Dataview sources
+// at a month's new location
+func rewriteArchivedLinks(content, oldRel, newRel string) string {
+	for _, prefix := range []string{"[[", "](", "\""} {
+		content = strings.ReplaceAll(content, prefix+oldRel+"/", prefix+newRel+"/")
+		content = strings.ReplaceAll(content, prefix+oldRel+"\"", prefix+newRel+"\"")
+	}
+	return content
+}
+
+// rewriteLinksInNote applies rewriteArchivedLinks to a note, reporting whether it changed
+func rewriteLinksInNote(path string, rewrites map[string]string) bool {
+	content, err := vaultWriter.ReadNote(path)
+	if err != nil {
+		fmt.Printf("  ⚠ Error reading %s: %v\n", path, err)
+		return false
+	}
+	rewritten := string(content)
+	for oldRel, newRel := range rewrites {
+		rewritten = rewriteArchivedLinks(rewritten, oldRel, newRel)
+	}
+	if rewritten == string(content) {
+		return false
+	}
+	if err := vaultWriter.CreateNote(path, []byte(rewritten)); err != nil {
+		fmt.Printf("  ⚠ Error rewriting links in %s: %v\n", path, err)
+		return false
+	}
+	return true
+}
+
+// rewriteLinksInFolder applies rewriteArchivedLinks to every markdown note under root.
+// Notes a dry run or test run has moved away are skipped.
+func rewriteLinksInFolder(root string, rewrites map[string]string) (int, error) {
+	updated := 0
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		if vaultWriter.Exists(path) && rewriteLinksInNote(path, rewrites) {
+			updated++
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// runArchive moves month folders older than ARCHIVE_AFTER_MONTHS into the archive and
+// rewrites links and Dataview sources that pointed at their old location. Moves go through
+// the vault writer, so dry runs and test runs only preview them and rollback undoes them.
+func runArchive(obsidianVaultPath string) error {
+	fmt.Println("\n=== Archive: Moving old months ===")
+
+	months, err := archiveAfterMonths()
+	if err != nil {
+		return err
+	}
+	if months == 0 {
+		fmt.Println("⚠ ARCHIVE_AFTER_MONTHS not set, nothing to archive")
+		return nil
+	}
+
+	folders, err := findMonthFolders(obsidianVaultPath)
+	if err != nil {
+		return fmt.Errorf("error scanning vault: %w", err)
+	}
+
+	now := time.Now()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -months, 0)
+
+	var toArchive []string
+	for rel, start := range folders {
+		if start.Before(cutoff) {
+			toArchive = append(toArchive, rel)
+		}
+	}
+	sort.Strings(toArchive)
+	if len(toArchive) == 0 {
+		fmt.Printf("✓ No months older than %s\n", cutoff.Format("January 2006"))
+		return nil
+	}
+
+	rewrites := make(map[string]string)
+	var moved []string // notes now in the archive
+	for _, rel := range toArchive {
+		src := filepath.Join(obsidianVaultPath, rel)
+		dest := filepath.Join(archiveDir, rel)
+
+		if err := checkVaultWrite(src, true); err != nil {
+			fmt.Printf("  ⚠ Skipping %s: %v\n", rel, err)
+			continue
+		}
+		if fileExists(dest) {
+			fmt.Printf("  ⚠ Skipping %s: %s already exists\n", rel, dest)
+			continue
+		}
+		files, _ := folderFiles(src)
+		if err := vaultWriter.MoveFolder(src, dest); err != nil {
+			fmt.Printf("  ⚠ Error moving %s: %v\n", rel, err)
+			continue
+		}
+		for _, path := range files {
+			if strings.HasSuffix(path, ".md") {
+				moved = append(moved, movedPath(path, src, dest))
+			}
+		}
+
+		rewrites[filepath.ToSlash(rel)] = vaultRelative(obsidianVaultPath, dest)
+		if dryRun {
+			fmt.Printf("  🔍 Would archive %s → %s\n", rel, vaultRelative(obsidianVaultPath, dest))
+		} else {
+			fmt.Printf("  ✓ Archived %s → %s\n", rel, vaultRelative(obsidianVaultPath, dest))
+		}
+
+		// Remove the year folder once its last month is gone
+		yearDir := filepath.Dir(src)
+		if entries, err := os.ReadDir(yearDir); err == nil && len(entries) == 0 {
+			os.Remove(yearDir)
+		}
+	}
+
+	if len(rewrites) == 0 {
+		return nil
+	}
+
+	roots := []string{obsidianVaultPath}
+	if rel, err := filepath.Rel(obsidianVaultPath, archiveDir); err != nil || strings.HasPrefix(rel, "..") {
+		roots = append(roots, archiveDir) // separate archive vault
+	}
+	updated := 0
+	for _, root := range roots {
+		n, err := rewriteLinksInFolder(root, rewrites)
+		if err != nil {
+			return fmt.Errorf("error rewriting links: %w", err)
+		}
+		updated += n
+	}
+	// Dry runs and test runs leave the moved notes where they were on disk
+	for _, path := range moved {
+		if !fileExists(path) && rewriteLinksInNote(path, rewrites) {
+			updated++
+		}
+	}
+
+	fmt.Printf("\n✅ Archived %d month(s), updated links in %d note(s)\n", len(rewrites), updated)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultAttachmentsDir is the vault folder meeting attachments are copied into
+const defaultAttachmentsDir = "attachments/krisp"
+
+// attachmentFileName returns a safe file name for an attachment, unique within its
+// meeting: its name prefixed with its ID, so attachments sharing a name don't overwrite
+// each other
+func attachmentFileName(a Attachment) string {
+	id := safeFileName(a.ID)
+	name := attachmentLabel(a)
+	if id == "" || name == id {
+		return name
+	}
+	return id + "-" + name
+}
+
+// attachmentLabel returns the name an attachment is shown with, without any directory
+func attachmentLabel(a Attachment) string {
+	return firstNonEmpty(safeFileName(a.Name), safeFileName(a.ID), "attachment")
+}
+
+// safeFileName returns the last element of a path, or "" when that isn't a usable file
+// name (".", ".." or empty)
+func safeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// parseChatMessages parses a meeting's chat content, if any
+func parseChatMessages(m *Meeting) ([]ChatMessage, error) {
+	if m.Resources.Chat.Content == "" {
+		return nil, nil
+	}
+
+	var messages []ChatMessage
+	if err := json.Unmarshal([]byte(m.Resources.Chat.Content), &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse chat: %w", err)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp < messages[j].Timestamp
+	})
+	return messages, nil
+}
+
+// downloadAttachments fetches a meeting's attachments (and recording, if enabled) into the
+// cache, skipping ones already cached
+func downloadAttachments(ctx context.Context, m *Meeting, cache *Cache) {
+	for _, a := range m.Resources.Attachments {
+		if a.URL == "" {
+			continue
+		}
+
+		name := attachmentFileName(a)
+		if cache.AttachmentExists(m.ID, name) {
+			continue
+		}
+
+		data, err := accountClient(m.Account).GetAttachment(ctx, a)
+		if err != nil {
+			fmt.Printf("  ⚠ Error fetching attachment %s: %v\n", name, err)
+			continue
+		}
+
+		if err := cache.SaveAttachment(m.ID, name, data); err != nil {
+			fmt.Printf("  ⚠ Error saving attachment %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("  📎 Cached attachment: %s\n", name)
+	}
+
+	downloadRecording(ctx, m, cache)
+}
+
+// copyAttachmentsToVault copies a meeting's cached attachments into the vault attachments folder.
+// Returns the vault-relative paths of the copied files, keyed by file name.
+func copyAttachmentsToVault(vaultPath, attachmentsDir string, m *Meeting, cache *Cache) (map[string]string, error) {
+	links := make(map[string]string)
+	if len(m.Resources.Attachments) == 0 {
+		return links, nil
+	}
+
+	relDir := filepath.Join(attachmentsDir, m.ID)
+	destDir := filepath.Join(vaultPath, relDir)
+
+	for _, a := range m.Resources.Attachments {
+		name := attachmentFileName(a)
+		if !cache.AttachmentExists(m.ID, name) {
+			continue
+		}
+
+		destPath := filepath.Join(destDir, name)
+		if !vaultWriter.Exists(destPath) {
+			data, err := os.ReadFile(cache.AttachmentPath(m.ID, name))
+			if err != nil {
+				return links, fmt.Errorf("failed to read cached attachment: %w", err)
+			}
+			if err := vaultWriter.CreateNote(destPath, data); err != nil {
+				return links, fmt.Errorf("failed to write attachment: %w", err)
+			}
+		}
+
+		links[name] = filepath.ToSlash(filepath.Join(relDir, name))
+	}
+
+	return links, nil
+}
+
+// renderChatAndAttachments renders the "Chat & Attachments" section of a summary note,
+// or "" if the meeting has neither
+func renderChatAndAttachments(m *Meeting, attachmentLinks map[string]string) string {
+	messages, err := parseChatMessages(m)
+	if err != nil {
+		fmt.Printf("  ⚠ %v\n", err)
+	}
+
+	if len(messages) == 0 && len(m.Resources.Attachments) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Chat & Attachments\n\n")
+
+	if len(m.Resources.Attachments) > 0 {
+		sb.WriteString("### Attachments\n")
+		for _, a := range m.Resources.Attachments {
+			label := attachmentLabel(a)
+			if link, ok := attachmentLinks[attachmentFileName(a)]; ok {
+				sb.WriteString(fmt.Sprintf("- [[%s|%s]]\n", link, label))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s (not downloaded)\n", label))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(messages) > 0 {
+		sb.WriteString("### Chat\n")
+		for _, msg := range messages {
+			sb.WriteString(fmt.Sprintf("- **[%s] %s**: %s\n", formatTimestamp(msg.Timestamp), msg.Author, msg.Text))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Audio link formats for transcript timestamps
+const (
+	audioLinkMediaExtended = "media-extended" // [[recording.m4a#t=83|01:23]] (Media Extended plugin)
+	audioLinkURI           = "uri"            // [01:23](file:///.../recording.m4a#t=83)
+)
+
+// audioTarget is a meeting recording copied into the vault
+type audioTarget struct {
+	VaultPath string // vault-relative path, for wikilinks
+	AbsPath   string // absolute path, for file URIs
+}
+
+// audioExtensions maps recording MIME types to file extensions
+var audioExtensions = map[string]string{
+	"audio/mp4":  ".m4a",
+	"audio/m4a":  ".m4a",
+	"audio/mpeg": ".mp3",
+	"audio/wav":  ".wav",
+	"audio/webm": ".webm",
+	"audio/ogg":  ".ogg",
+}
+
+// recordingFileName returns the cache/vault file name of a meeting's recording
+func recordingFileName(m *Meeting) string {
+	ext := audioExtensions[m.Resources.Recording.MimeType]
+	if ext == "" {
+		if u, err := url.Parse(m.Resources.Recording.URL); err == nil {
+			ext = path.Ext(u.Path)
+		}
+	}
+	if ext == "" {
+		ext = ".m4a"
+	}
+	return "recording" + ext
+}
+
+// downloadRecording fetches a meeting's audio into the cache when AUDIO_DOWNLOAD is enabled
+func downloadRecording(ctx context.Context, m *Meeting, cache *Cache) {
+	if !envBool("AUDIO_DOWNLOAD") || m.Resources.Recording.URL == "" {
+		return
+	}
+
+	name := recordingFileName(m)
+	if cache.AttachmentExists(m.ID, name) {
+		return
+	}
+
+	data, err := accountClient(m.Account).GetAttachment(ctx, Attachment{Name: name, URL: m.Resources.Recording.URL})
+	if err != nil {
+		fmt.Printf("  ⚠ Error fetching recording: %v\n", err)
+		return
+	}
+	if err := cache.SaveAttachment(m.ID, name, data); err != nil {
+		fmt.Printf("  ⚠ Error saving recording: %v\n", err)
+		return
+	}
+	fmt.Printf("  🎧 Cached recording: %s\n", name)
+}
+
+// copyRecordingToVault copies a cached recording next to the meeting's attachments.
+// Returns nil if the meeting has no cached recording.
+func copyRecordingToVault(vaultPath, attachmentsDir string, m *Meeting, cache *Cache) (*audioTarget, error) {
+	if m.Resources.Recording.URL == "" {
+		return nil, nil
+	}
+	name := recordingFileName(m)
+	if !cache.AttachmentExists(m.ID, name) {
+		return nil, nil
+	}
+
+	relPath := filepath.Join(attachmentsDir, m.ID, name)
+	absPath := filepath.Join(vaultPath, relPath)
+	if !vaultWriter.Exists(absPath) {
+		data, err := os.ReadFile(cache.AttachmentPath(m.ID, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached recording: %w", err)
+		}
+		if err := vaultWriter.CreateNote(absPath, data); err != nil {
+			return nil, fmt.Errorf("failed to write recording: %w", err)
+		}
+	}
+
+	return &audioTarget{VaultPath: filepath.ToSlash(relPath), AbsPath: absPath}, nil
+}
+
+// audioTimestampLink renders a transcript timestamp as a link that plays the recording
+// from that point (AUDIO_LINK_FORMAT: media-extended or uri)
+func audioTimestampLink(audio *audioTarget, seconds float64, label string) string {
+	offset := int(seconds)
+	if strings.ToLower(os.Getenv("AUDIO_LINK_FORMAT")) == audioLinkURI {
+		u := url.URL{Scheme: "file", Path: filepath.ToSlash(audio.AbsPath), Fragment: fmt.Sprintf("t=%d", offset)}
+		return fmt.Sprintf("[%s](%s)", label, u.String())
+	}
+	return fmt.Sprintf("[[%s#t=%d|%s]]", audio.VaultPath, offset, label)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transcript status of a meeting synced before its transcript was in
+const (
+	transcriptPending     = "pending"     // Krisp is still transcribing (or it is queued for retry)
+	transcriptUnavailable = "unavailable" // gave up waiting after TRANSCRIPT_MAX_WAIT
+)
+
+// transcriptStatusKey is the frontmatter property marking an audio-only note
+const transcriptStatusKey = "transcript_status"
+
+// transcriptStatus returns "" for a meeting with a usable transcript, or whether its
+// transcript is still pending or unavailable
+func transcriptStatus(m *Meeting, syncState *SyncState) string {
+	if transcriptReady(m) {
+		return ""
+	}
+	if item, ok := syncState.TranscriptQueue[m.ID]; ok && item.Missing {
+		return transcriptUnavailable
+	}
+	return transcriptPending
+}
+
+// MarkAudioOnly records that a meeting's note was written without a transcript
+func (s *SyncState) MarkAudioOnly(meetingID, status string) {
+	if s.AudioOnlyNotes[meetingID] == status {
+		return
+	}
+	if s.AudioOnlyNotes == nil {
+		s.AudioOnlyNotes = make(map[string]string)
+	}
+	s.AudioOnlyNotes[meetingID] = status
+	s.pending++
+	if err := s.Checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+}
+
+// ClearAudioOnly forgets an audio-only note once it has been replaced by the full note
+func (s *SyncState) ClearAudioOnly(meetingID string) {
+	if _, ok := s.AudioOnlyNotes[meetingID]; !ok {
+		return
+	}
+	delete(s.AudioOnlyNotes, meetingID)
+	s.pending++
+	if err := s.Checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+}
+
+// audioOnlyOutdated reports whether a meeting's audio-only note has to be rewritten: its
+// transcript arrived (from Krisp or filled into the cache locally) and was summarized, or
+// Krisp's transcript was given up on since the note was written
+func (s *SyncState) audioOnlyOutdated(m *Meeting, cache *Cache) bool {
+	written, ok := s.AudioOnlyNotes[m.ID]
+	if !ok {
+		return false
+	}
+	if transcriptReady(m) {
+		return cache.SummaryExists(m.ID)
+	}
+	return transcriptStatus(m, s) != written
+}
+
+// renderAudioOnlyBody renders the body of a meeting's note while it has no transcript: a
+// banner, the recording (when AUDIO_DOWNLOAD cached it) and the user sections
+func renderAudioOnlyBody(status string, audio *audioTarget) string {
+	var sb strings.Builder
+	if status == transcriptUnavailable {
+		sb.WriteString("> [!warning] Transcript unavailable\n")
+		sb.WriteString("> Krisp never produced a transcript for this meeting, so there is no summary. If it is transcribed later, this note is filled in on the next sync.\n\n")
+	} else {
+		sb.WriteString("> [!info] Transcript pending\n")
+		sb.WriteString("> Krisp hasn't finished the transcript yet. This note is replaced with the summary once it is in.\n\n")
+	}
+	if audio != nil {
+		sb.WriteString(fmt.Sprintf("**Recording**: ![[%s]]\n\n", audio.VaultPath))
+	}
+	sb.WriteString(renderUserSections())
+	return sb.String()
+}
+
+// withoutTranscriptStatus removes the audio-only marker from a note's frontmatter, so it
+// isn't carried over when the note is regenerated with its transcript
+func withoutTranscriptStatus(content []byte) []byte {
+	updated, err := editFrontmatter(content, nil, []string{transcriptStatusKey})
+	if err != nil {
+		return content
+	}
+	return updated
+}
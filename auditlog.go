@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditLogFile is the append-only log of vault writes, in the data directory
+const auditLogFile = "vault-audit.jsonl"
+
+// Audit actions beyond the manifest's created/modified/deleted
+const auditMoved = "moved"
+
+// defaultAuditLogLimit is how many entries krisp-sync log shows without --file
+const defaultAuditLogLimit = 50
+
+// auditStage names the stage of an "all" run currently writing to the vault
+var auditStage string
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	RunID  string    `json:"run_id"`
+	Step   string    `json:"step"`            // the command the run was started with
+	Stage  string    `json:"stage,omitempty"` // stage of an "all" run
+	Action string    `json:"action"`
+	Path   string    `json:"path"`           // vault-relative
+	From   string    `json:"from,omitempty"` // previous path of a moved folder
+	Hash   string    `json:"hash,omitempty"` // content written
+}
+
+// Reason describes which run and stage made the change
+func (e AuditEntry) Reason() string {
+	stage := e.Step
+	if e.Stage != "" {
+		stage += "/" + e.Stage
+	}
+	return fmt.Sprintf("%s (run %s)", stage, e.RunID)
+}
+
+// AuditVaultWriter appends every note another writer creates, changes or deletes to the
+// audit log. Writes that leave a note's content as it was aren't logged.
+type AuditVaultWriter struct {
+	inner     VaultWriter
+	vaultPath string
+	runID     string
+	step      string
+	mu        sync.Mutex
+	warned    bool
+}
+
+// newAuditVaultWriter wraps a writer, logging its changes under step
+func newAuditVaultWriter(inner VaultWriter, vaultPath, step string) *AuditVaultWriter {
+	return &AuditVaultWriter{
+		inner:     inner,
+		vaultPath: vaultPath,
+		runID:     newRunID(time.Now()),
+		step:      step,
+	}
+}
+
+func (w *AuditVaultWriter) Exists(path string) bool {
+	return w.inner.Exists(path)
+}
+
+func (w *AuditVaultWriter) ReadNote(path string) ([]byte, error) {
+	return w.inner.ReadNote(path)
+}
+
+func (w *AuditVaultWriter) CreateNote(path string, content []byte) error {
+	action, changed := w.writeAction(path, content)
+	if err := w.inner.CreateNote(path, content); err != nil {
+		return err
+	}
+	if changed {
+		w.record(AuditEntry{Action: action, Path: path, Hash: contentHash(content)})
+	}
+	return nil
+}
+
+func (w *AuditVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	actions := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if action, changed := w.writeAction(path, contents[path]); changed {
+			actions[path] = action
+		}
+	}
+	if err := createNotes(w.inner, paths, contents); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if action, ok := actions[path]; ok {
+			w.record(AuditEntry{Action: action, Path: path, Hash: contentHash(contents[path])})
+		}
+	}
+	return nil
+}
+
+func (w *AuditVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *AuditVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *AuditVaultWriter) DeleteNote(path string) error {
+	if err := w.inner.DeleteNote(path); err != nil {
+		return err
+	}
+	w.record(AuditEntry{Action: manifestDeleted, Path: path})
+	return nil
+}
+
+func (w *AuditVaultWriter) MoveFolder(src, dest string) error {
+	if err := w.inner.MoveFolder(src, dest); err != nil {
+		return err
+	}
+	w.record(AuditEntry{Action: auditMoved, Path: dest, From: src})
+	return nil
+}
+
+// writeAction returns whether writing content to path creates or modifies the note, and
+// false if the note already has exactly that content
+func (w *AuditVaultWriter) writeAction(path string, content []byte) (string, bool) {
+	if !w.inner.Exists(path) {
+		return manifestCreated, true
+	}
+	if existing, err := w.inner.ReadNote(path); err == nil && bytes.Equal(existing, content) {
+		return "", false
+	}
+	return manifestModified, true
+}
+
+// record appends an entry to the audit log. A log that can't be written is reported once
+// and doesn't fail the write it describes.
+func (w *AuditVaultWriter) record(entry AuditEntry) {
+	entry.Time = time.Now()
+	entry.RunID = w.runID
+	entry.Step = w.step
+	entry.Stage = auditStage
+	entry.Path = vaultRelative(w.vaultPath, entry.Path)
+	if entry.From != "" {
+		entry.From = vaultRelative(w.vaultPath, entry.From)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := appendAuditEntry(entry); err != nil && !w.warned {
+		w.warned = true
+		fmt.Printf("⚠ Warning: Could not write audit log: %v\n", err)
+	}
+}
+
+// appendAuditEntry appends one JSON line to the audit log
+func appendAuditEntry(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dataPath(auditLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditPathMatches reports whether an entry is about file: the same vault-relative path,
+// any path ending in file when it is only a note name, or a move of a folder holding it
+func auditPathMatches(entry AuditEntry, file string) bool {
+	for _, p := range []string{entry.Path, entry.From} {
+		if p == "" {
+			continue
+		}
+		if p == file || strings.HasSuffix(p, "/"+file) {
+			return true
+		}
+		if entry.Action == auditMoved && strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// runAuditLog prints the audit log entries for a vault file (absolute, vault-relative or
+// just its name), or the latest entries without one
+func runAuditLog(vaultPath, file string) error {
+	f, err := os.Open(dataPath(auditLogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No vault changes logged yet")
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", auditLogFile, err)
+	}
+	defer f.Close()
+
+	if file != "" {
+		if filepath.IsAbs(file) {
+			file = vaultRelative(vaultPath, file)
+		}
+		file = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+	}
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if file == "" || auditPathMatches(entry, file) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", auditLogFile, err)
+	}
+
+	if file == "" && len(entries) > defaultAuditLogLimit {
+		entries = entries[len(entries)-defaultAuditLogLimit:]
+	}
+	for _, e := range entries {
+		path := e.Path
+		if e.From != "" {
+			path = e.From + " → " + e.Path
+		}
+		fmt.Printf("%s  %-8s  %s  %s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Action, e.Reason(), path)
+	}
+	if file != "" {
+		fmt.Printf("\n📜 %d change(s) to %s\n", len(entries), file)
+	}
+	return nil
+}
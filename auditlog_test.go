@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWriterChainRecordsFolderMoves(t *testing.T) {
+	dir := t.TempDir()
+	previousWriter, previousDataDir := vaultWriter, dataDir
+	t.Cleanup(func() { vaultWriter, dataDir = previousWriter, previousDataDir })
+	dataDir = filepath.Join(dir, "data")
+
+	vault := filepath.Join(dir, "vault")
+	src := filepath.Join(vault, "2024", "01-January")
+	dest := filepath.Join(vault, "Archive", "2024", "01-January")
+	note := filepath.Join(src, "m1-summary.md")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(note, []byte("# Launch planning\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var writers *runVaultWriters
+	vaultWriter, writers = stackRunWriters(&FSVaultWriter{}, vault, "archive", false)
+	if err := vaultWriter.MoveFolder(src, dest); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(filepath.Join(dest, "m1-summary.md")) || fileExists(note) {
+		t.Fatalf("folder not moved")
+	}
+
+	data, err := os.ReadFile(dataPath(auditLogFile))
+	if err != nil {
+		t.Fatalf("no audit log written: %v", err)
+	}
+	log := string(data)
+	if !strings.Contains(log, `"action":"moved"`) || !strings.Contains(log, `"from":"2024/01-January"`) || !strings.Contains(log, writers.audit.runID) {
+		t.Errorf("move missing from the audit log of run %s:\n%s", writers.audit.runID, log)
+	}
+
+	// Rolling the run back moves the folder's notes back
+	if err := writers.manifest.Save(); err != nil {
+		t.Fatal(err)
+	}
+	vaultWriter = &FSVaultWriter{}
+	state := loadSyncState(filepath.Join(dir, "state.json"))
+	state.MarkObsidianSynced("m1")
+	if err := runRollback(writers.manifest.manifest.RunID, false, state); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(note) || fileExists(filepath.Join(dest, "m1-summary.md")) {
+		t.Errorf("rollback didn't move the note back")
+	}
+	if !state.ObsidianSyncedMeetings["m1"] {
+		t.Errorf("rollback unsynced a meeting whose note was moved back")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default daily quotas for backfilling a large account's history
+const (
+	defaultBackfillDownloadsPerDay = 50
+	defaultBackfillSummariesPerDay = 50
+)
+
+// BackfillState tracks today's backfill usage so runs spread across days
+type BackfillState struct {
+	Day        string    `json:"day"` // YYYY-MM-DD the counters below are for
+	Downloaded int       `json:"downloaded"`
+	Summarized int       `json:"summarized"`
+	StartedAt  time.Time `json:"started_at"`
+	Completed  bool      `json:"completed,omitempty"`
+}
+
+// backfillQuota reads a per-day quota from the environment
+func backfillQuota(name string, def int) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative number", name, v)
+	}
+	return n, nil
+}
+
+// runBackfill downloads and summarizes the account's history a day's quota at a time
+// (BACKFILL_DOWNLOADS_PER_DAY Krisp downloads, BACKFILL_SUMMARIES_PER_DAY LLM summaries),
+// then syncs what's ready. Run it daily (or more often) until it reports completion.
+func runBackfill(ctx context.Context, obsidianVaultPath string, syncState *SyncState, cache *Cache) error {
+	fmt.Println("\n=== Backfill ===")
+
+	downloadsPerDay, err := backfillQuota("BACKFILL_DOWNLOADS_PER_DAY", defaultBackfillDownloadsPerDay)
+	if err != nil {
+		return err
+	}
+	summariesPerDay, err := backfillQuota("BACKFILL_SUMMARIES_PER_DAY", defaultBackfillSummariesPerDay)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if syncState.Backfill == nil {
+		syncState.Backfill = &BackfillState{StartedAt: time.Now()}
+	}
+	bf := syncState.Backfill
+	if bf.Day != today {
+		bf.Day = today
+		bf.Downloaded = 0
+		bf.Summarized = 0
+	}
+	syncState.pending++
+
+	downloadsLeft := downloadsPerDay - bf.Downloaded
+	summariesLeft := summariesPerDay - bf.Summarized
+	fmt.Printf("📅 Today's quota left: %d/%d download(s), %d/%d summar(ies)\n", downloadsLeft, downloadsPerDay, summariesLeft, summariesPerDay)
+
+	// Download (a limit of 0 would mean "everything", so only run with quota left)
+	downloadsDone := false
+	if downloadsLeft > 0 {
+		before := len(syncState.SyncedMeetings)
+		if err := runDownload(ctx, downloadsLeft, syncState, false, nil, cache); err != nil {
+			return fmt.Errorf("download: %w", err)
+		}
+		n := len(syncState.SyncedMeetings) - before
+		bf.Downloaded += n
+		downloadsDone = downloadsRemaining == 0 && ctx.Err() == nil && !budgetExhausted()
+	}
+
+	summariesDone := false
+	if summariesLeft > 0 {
+		before := len(syncState.SummarizedMeetings)
+		if err := runSummarize(ctx, summariesLeft, syncState, false, nil, cache); err != nil {
+			return fmt.Errorf("summarize: %w", err)
+		}
+		n := len(syncState.SummarizedMeetings) - before
+		bf.Summarized += n
+		summariesDone = n < summariesLeft && ctx.Err() == nil && !budgetExhausted()
+	}
+
+	// Syncing cached meetings needs neither quota
+	if _, err := runSync(ctx, obsidianVaultPath, 0, syncState, false, false, false, nil, nil, cache); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+
+	fmt.Printf("\n📊 Backfill today: %d download(s), %d summar(ies); %d meeting(s) cached, %d summarized\n",
+		bf.Downloaded, bf.Summarized, len(syncState.SyncedMeetings), len(syncState.SummarizedMeetings))
+	if downloadsDone && summariesDone {
+		if !bf.Completed {
+			bf.Completed = true
+			fmt.Printf("🎉 Backfill complete (started %s) - switch to the regular sync\n", bf.StartedAt.Local().Format("2006-01-02"))
+		}
+		return nil
+	}
+	fmt.Println("⏳ More to do - the next run continues where this one stopped (quotas reset at midnight)")
+	return nil
+}
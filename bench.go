@@ -0,0 +1,515 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Benchmark defaults
+const (
+	defaultBenchMeetings   = 25
+	defaultBenchHTTPDelay  = 150 * time.Millisecond
+	defaultBenchLLMDelay   = 1500 * time.Millisecond
+	benchSegmentsPerMinute = 6
+)
+
+// Where benchmark time is spent
+const (
+	benchHTTP = "http"
+	benchLLM  = "llm"
+	benchDisk = "disk"
+)
+
+// benchStats accumulates the time spent per category while a stage runs. Concurrent
+// requests each count their full duration, so busy time can exceed wall time.
+type benchStats struct {
+	mu    sync.Mutex
+	busy  map[string]time.Duration
+	calls map[string]int
+}
+
+func newBenchStats() *benchStats {
+	return &benchStats{busy: make(map[string]time.Duration), calls: make(map[string]int)}
+}
+
+func (s *benchStats) add(category string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.busy[category] += d
+	s.calls[category]++
+}
+
+// take returns the accumulated times and starts over
+func (s *benchStats) take() (map[string]time.Duration, map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	busy, calls := s.busy, s.calls
+	s.busy, s.calls = make(map[string]time.Duration), make(map[string]int)
+	return busy, calls
+}
+
+// benchTransport times HTTP round trips, telling LLM requests apart from Krisp ones
+type benchTransport struct {
+	inner http.RoundTripper
+	stats *benchStats
+}
+
+func (t *benchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	category := benchHTTP
+	if strings.Contains(req.URL.Path, ":generateContent") || strings.Contains(req.URL.Host, "aiplatform.googleapis.com") {
+		category = benchLLM
+	}
+
+	start := time.Now()
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		t.stats.add(category, time.Since(start))
+		return nil, err
+	}
+	resp.Body = &timedBody{ReadCloser: resp.Body, done: func() { t.stats.add(category, time.Since(start)) }}
+	return resp, nil
+}
+
+// timedBody reports when a response body has been read and closed
+type timedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
+
+// benchVaultWriter times every vault operation as disk time
+type benchVaultWriter struct {
+	inner VaultWriter
+	stats *benchStats
+}
+
+func (w *benchVaultWriter) timed(start time.Time) {
+	w.stats.add(benchDisk, time.Since(start))
+}
+
+func (w *benchVaultWriter) Exists(path string) bool {
+	defer w.timed(time.Now())
+	return w.inner.Exists(path)
+}
+
+func (w *benchVaultWriter) ReadNote(path string) ([]byte, error) {
+	defer w.timed(time.Now())
+	return w.inner.ReadNote(path)
+}
+
+func (w *benchVaultWriter) CreateNote(path string, content []byte) error {
+	defer w.timed(time.Now())
+	return w.inner.CreateNote(path, content)
+}
+
+func (w *benchVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	defer w.timed(time.Now())
+	return createNotes(w.inner, paths, contents)
+}
+
+func (w *benchVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *benchVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *benchVaultWriter) DeleteNote(path string) error {
+	defer w.timed(time.Now())
+	return w.inner.DeleteNote(path)
+}
+
+func (w *benchVaultWriter) MoveFolder(src, dest string) error {
+	defer w.timed(time.Now())
+	return w.inner.MoveFolder(src, dest)
+}
+
+// benchDuration reads a latency setting such as BENCH_LLM_LATENCY (e.g. "800ms")
+func benchDuration(name string, def time.Duration) (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration like 200ms", name, v)
+	}
+	return d, nil
+}
+
+// benchMeetingCount reads BENCH_MEETINGS
+func benchMeetingCount() (int, error) {
+	v := strings.TrimSpace(os.Getenv("BENCH_MEETINGS"))
+	if v == "" {
+		return defaultBenchMeetings, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid BENCH_MEETINGS %q: must be a positive number", v)
+	}
+	return n, nil
+}
+
+// syntheticMeetings builds n meetings of 15 to 75 minutes with made-up transcripts
+func syntheticMeetings(n int) []*Meeting {
+	speakers := []string{"Alex Morgan", "Sam Lee", "Priya Shah", "Jordan Kim"}
+	phrases := []string{
+		"Let's go through the status of the migration before the release.",
+		"The latency numbers improved after we moved the cache closer to the API.",
+		"I'll follow up with the vendor about the contract renewal this week.",
+		"We agreed to push the launch by two weeks to finish the security review.",
+		"Can someone own the onboarding docs? They are out of date again.",
+	}
+
+	start := time.Now().AddDate(0, 0, -n).Truncate(24 * time.Hour).Add(9 * time.Hour)
+	meetings := make([]*Meeting, n)
+	for i := range meetings {
+		minutes := 15 + (i*17)%61
+		m := &Meeting{
+			ID:        fmt.Sprintf("bench%04d", i+1),
+			Title:     fmt.Sprintf("Benchmark meeting %d", i+1),
+			CreatedAt: start.AddDate(0, 0, i).UTC(),
+			Duration:  minutes * 60,
+		}
+		m.Speakers.Data = make(map[string]SpeakerInfo)
+		for s, name := range speakers {
+			var info SpeakerInfo
+			info.Person.FirstName, info.Person.LastName, _ = strings.Cut(name, " ")
+			m.Speakers.Data[strconv.Itoa(s+1)] = info
+		}
+
+		count := minutes * benchSegmentsPerMinute
+		segments := make([]Segment, count)
+		step := float64(m.Duration) / float64(count)
+		for s := range segments {
+			segments[s] = Segment{
+				SpeakerIndex: 1 + (s+i)%len(speakers),
+				ID:           s,
+				Speech: Speech{
+					Start: float64(s) * step,
+					End:   float64(s+1) * step,
+					Text:  phrases[(s*7+i)%len(phrases)],
+				},
+			}
+		}
+		content, _ := json.Marshal(segments)
+		m.Resources.Transcript.Status = "uploaded"
+		m.Resources.Transcript.Content = string(content)
+		meetings[i] = m
+	}
+	return meetings
+}
+
+// recordedMeetings loads up to n cached meetings from a fixtures folder (a meeting cache)
+func recordedMeetings(dir string, n int) ([]*Meeting, error) {
+	fixtures := NewCache(dir)
+	ids, err := fixtures.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	var meetings []*Meeting
+	for _, id := range ids {
+		if len(meetings) == n {
+			break
+		}
+		m, err := fixtures.LoadMeeting(id)
+		if err != nil {
+			fmt.Printf("⚠ Skipping fixture %s: %v\n", id, err)
+			continue
+		}
+		meetings = append(meetings, m)
+	}
+	if len(meetings) == 0 {
+		return nil, fmt.Errorf("no meetings found in %s", dir)
+	}
+	return meetings, nil
+}
+
+// benchSummaryResponse is what the fake LLM answers every request with
+const benchSummaryResponse = `{"title":"Benchmark meeting","description":"Synthetic meeting used for benchmarking","tags":["benchmark","planning"],"topics":["Release status","Vendor contract"],"topic_details":[{"topic":"Release status","summary":"The team reviewed the migration and agreed to push the launch by two weeks."},{"topic":"Vendor contract","summary":"The renewal needs a follow-up with the vendor this week."}],"decisions":["Push the launch by two weeks"],"action_items":[{"text":"Follow up with the vendor","owner":"Sam Lee"}],"speaker_count":4}`
+
+// benchHandler fakes the Krisp API and the LLM, serving the fixtures with the given latencies
+func benchHandler(meetings []*Meeting, httpDelay, llmDelay time.Duration) http.Handler {
+	byID := make(map[string]*Meeting, len(meetings))
+	rows := make([]MeetingSummary, len(meetings))
+	for i, m := range meetings {
+		byID[m.ID] = m
+		rows[i] = MeetingSummary{ID: m.ID, Title: m.Title, CreatedAt: m.CreatedAt, Duration: m.Duration}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].CreatedAt.Before(rows[j].CreatedAt) })
+
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":generateContent"):
+			time.Sleep(llmDelay)
+			writeJSON(w, map[string]interface{}{
+				"candidates": []interface{}{map[string]interface{}{
+					"content": map[string]interface{}{
+						"role":  "model",
+						"parts": []interface{}{map[string]string{"text": benchSummaryResponse}},
+					},
+					"finishReason": "STOP",
+				}},
+			})
+
+		case r.URL.Path == "/meetings/list":
+			time.Sleep(httpDelay)
+			var req MeetingsListRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			from := min((req.Page-1)*req.Limit, len(rows))
+			to := min(from+req.Limit, len(rows))
+			var resp MeetingsListResponse
+			resp.Data.Rows = rows[from:to]
+			resp.Data.Total = len(rows)
+			writeJSON(w, resp)
+
+		case strings.HasPrefix(r.URL.Path, "/meetings/"):
+			time.Sleep(httpDelay)
+			m, ok := byID[strings.TrimPrefix(r.URL.Path, "/meetings/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			writeJSON(w, map[string]interface{}{"code": 0, "data": m})
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// memorySampler tracks the heap high-water mark while a stage runs
+type memorySampler struct {
+	stop chan struct{}
+	done chan struct{}
+	peak uint64
+}
+
+func startMemorySampler() *memorySampler {
+	s := &memorySampler{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(25 * time.Millisecond)
+		defer ticker.Stop()
+		var ms runtime.MemStats
+		for {
+			runtime.ReadMemStats(&ms)
+			s.peak = max(s.peak, ms.HeapInuse)
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop ends sampling and returns the peak heap in use, in bytes
+func (s *memorySampler) Stop() uint64 {
+	close(s.stop)
+	<-s.done
+	return s.peak
+}
+
+// benchResult is the measurement of one pipeline stage
+type benchResult struct {
+	Stage string
+	Wall  time.Duration
+	Busy  map[string]time.Duration
+	Calls map[string]int
+	Peak  uint64
+	Err   error
+}
+
+// formatBusy formats a category's busy time with its call count and share of wall time
+func (r benchResult) formatBusy(category string) string {
+	if r.Calls[category] == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s ×%d (%.1fx)", r.Busy[category].Round(time.Millisecond), r.Calls[category], r.Busy[category].Seconds()/r.Wall.Seconds())
+}
+
+// runBench runs download, summarize and sync against fixtures in a throwaway data folder and
+// vault, with Krisp and the LLM faked by a local server (BENCH_LLM=real uses the real LLM),
+// and reports per-stage throughput, heap high-water mark and where the time went
+func runBench(ctx context.Context, fixturesDir string) error {
+	fmt.Println("\n=== Bench: Pipeline throughput ===")
+
+	count, err := benchMeetingCount()
+	if err != nil {
+		return err
+	}
+	httpDelay, err := benchDuration("BENCH_HTTP_LATENCY", defaultBenchHTTPDelay)
+	if err != nil {
+		return err
+	}
+	llmDelay, err := benchDuration("BENCH_LLM_LATENCY", defaultBenchLLMDelay)
+	if err != nil {
+		return err
+	}
+	realLLM := strings.EqualFold(strings.TrimSpace(os.Getenv("BENCH_LLM")), "real")
+
+	var meetings []*Meeting
+	if fixturesDir != "" {
+		if meetings, err = recordedMeetings(fixturesDir, count); err != nil {
+			return err
+		}
+		fmt.Printf("📼 %d recorded meeting(s) from %s\n", len(meetings), fixturesDir)
+	} else {
+		meetings = syntheticMeetings(count)
+		fmt.Printf("🧪 %d synthetic meeting(s)\n", len(meetings))
+	}
+	llmDesc := fmt.Sprintf("fake, %s per call", llmDelay)
+	if realLLM {
+		llmDesc = "real"
+	}
+	fmt.Printf("⚙️  Krisp: fake, %s per call · LLM: %s · summarize concurrency: %d\n", httpDelay, llmDesc, summarizeConcurrency())
+
+	// Everything happens in a throwaway folder
+	tmp, err := os.MkdirTemp("", "krisp-sync-bench-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	vault := filepath.Join(tmp, "vault")
+	if err := os.MkdirAll(filepath.Join(vault, obsidianConfigDir), 0755); err != nil {
+		return err
+	}
+
+	stats := newBenchStats()
+	server := httptest.NewServer(benchHandler(meetings, httpDelay, llmDelay))
+	defer server.Close()
+
+	// Point the pipeline at the sandbox, restoring everything afterwards
+	saved := struct {
+		apiBaseURL, bearerToken, dataDir, gcpProject, gcpLocation, llmBaseURL string
+		accounts                                                              []KrispAccount
+		writer                                                                VaultWriter
+		ignore                                                                *VaultIgnore
+		krispTransport                                                        http.RoundTripper
+		llmClient                                                             *http.Client
+		stdout                                                                *os.File
+	}{apiBaseURL, bearerToken, dataDir, gcpProject, gcpLocation, llmBaseURL, krispAccounts, vaultWriter, vaultIgnore, krispAPI.transport, llmHTTPClient, os.Stdout}
+	defer func() {
+		apiBaseURL, bearerToken, dataDir = saved.apiBaseURL, saved.bearerToken, saved.dataDir
+		gcpProject, gcpLocation, llmBaseURL = saved.gcpProject, saved.gcpLocation, saved.llmBaseURL
+		krispAccounts, vaultWriter, vaultIgnore = saved.accounts, saved.writer, saved.ignore
+		krispAPI.transport, llmHTTPClient, os.Stdout = saved.krispTransport, saved.llmClient, saved.stdout
+	}()
+
+	apiBaseURL, bearerToken, krispAccounts = server.URL, "bench", nil
+	dataDir = filepath.Join(tmp, "data")
+	vaultWriter = &benchVaultWriter{inner: &FSVaultWriter{}, stats: stats}
+	vaultIgnore = &VaultIgnore{root: vault}
+	withKrispTransport(&benchTransport{inner: krispAPI.baseTransport(), stats: stats})(krispAPI)
+	if !realLLM {
+		// The fake speaks the Vertex AI protocol, whatever LLM_PROVIDER is
+		if provider, ok := os.LookupEnv("LLM_PROVIDER"); ok {
+			defer os.Setenv("LLM_PROVIDER", provider)
+		} else {
+			defer os.Unsetenv("LLM_PROVIDER")
+		}
+		os.Setenv("LLM_PROVIDER", providerVertex)
+		gcpProject, gcpLocation = firstNonEmpty(gcpProject, "bench"), firstNonEmpty(gcpLocation, "bench")
+		llmBaseURL = server.URL
+		llmHTTPClient = &http.Client{Transport: &benchTransport{inner: http.DefaultTransport, stats: stats}}
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+
+	syncState := loadSyncState(filepath.Join(dataDir, syncStateFile))
+	cache := NewCache(filepath.Join(dataDir, meetingsCacheDir))
+
+	// Stage output is hidden unless BENCH_VERBOSE is set
+	var quiet *os.File
+	if !envBool("BENCH_VERBOSE") {
+		if quiet, err = os.Open(os.DevNull); err == nil {
+			defer quiet.Close()
+		}
+	}
+
+	stages := []struct {
+		name string
+		run  func() error
+	}{
+		{"download", func() error { return runDownload(ctx, 0, syncState, false, nil, cache) }},
+		{"summarize", func() error { return runSummarize(ctx, 0, syncState, false, nil, cache) }},
+		{"sync", func() error {
+			_, err := runSync(ctx, vault, 0, syncState, false, false, false, nil, nil, cache)
+			return err
+		}},
+	}
+
+	var results []benchResult
+	for _, stage := range stages {
+		fmt.Fprintf(saved.stdout, "⏱  %s...\n", stage.name)
+		if quiet != nil {
+			os.Stdout = quiet
+		}
+		runtime.GC()
+		sampler := startMemorySampler()
+		start := time.Now()
+		err := stage.run()
+		wall := time.Since(start)
+		peak := sampler.Stop()
+		os.Stdout = saved.stdout
+
+		busy, calls := stats.take()
+		results = append(results, benchResult{Stage: stage.name, Wall: wall, Busy: busy, Calls: calls, Peak: peak, Err: err})
+		if err != nil {
+			break
+		}
+	}
+
+	fmt.Printf("\n%-10s %9s %10s %12s  %-24s %-24s %-24s\n", "Stage", "Wall", "Meetings/s", "Peak heap", "HTTP", "LLM", "Disk")
+	for _, r := range results {
+		fmt.Printf("%-10s %9s %10.2f %9.1f MB  %-24s %-24s %-24s\n",
+			r.Stage, r.Wall.Round(time.Millisecond), float64(len(meetings))/r.Wall.Seconds(), float64(r.Peak)/(1<<20),
+			r.formatBusy(benchHTTP), r.formatBusy(benchLLM), r.formatBusy(benchDisk))
+		if r.Err != nil {
+			fmt.Printf("  ❌ %s failed: %v\n", r.Stage, r.Err)
+		}
+	}
+	fmt.Println("\nBusy times add up concurrent calls; (N.Nx) is busy time divided by wall time, i.e. how many calls were in flight on average.")
+
+	for _, r := range results {
+		if r.Stage != "summarize" || r.Calls[benchLLM] == 0 {
+			continue
+		}
+		inFlight := r.Busy[benchLLM].Seconds() / r.Wall.Seconds()
+		if inFlight >= float64(summarizeConcurrency())*0.8 {
+			fmt.Printf("💡 Summarize kept ~%.1f LLM calls in flight with SUMMARIZE_CONCURRENCY=%d: raising it may help if your quota allows\n", inFlight, summarizeConcurrency())
+		} else {
+			fmt.Printf("💡 Summarize kept ~%.1f LLM calls in flight with SUMMARIZE_CONCURRENCY=%d: the limit isn't the bottleneck\n", inFlight, summarizeConcurrency())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// runDeadline is when a time-boxed run (--max-runtime) stops starting new work; zero means unlimited
+var runDeadline time.Time
+
+// budgetExhausted reports whether the run's time budget is used up
+func budgetExhausted() bool {
+	return !runDeadline.IsZero() && time.Now().After(runDeadline)
+}
+
+// budgetStop reports whether a stage should stop starting new work, logging why
+func budgetStop(stage string) bool {
+	if !budgetExhausted() {
+		return false
+	}
+	fmt.Printf("\n⏱  Time budget reached - stopping %s; remaining meetings will be processed next run\n", stage)
+	return true
+}
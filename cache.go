@@ -5,13 +5,47 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // SummaryData holds the structured summary information
 type SummaryData struct {
+	Title       string `json:"title,omitempty"` // LLM-improved meeting title
 	Description string `json:"description"`
 	Tags        string `json:"tags"`
 	Summary     string `json:"summary"`
+
+	Topics       []string      `json:"topics,omitempty"`        // topics discussed, also rendered into Summary
+	TopicDetails []TopicDetail `json:"topic_details,omitempty"` // per-topic summaries, also rendered into Summary
+	Decisions    []string      `json:"decisions,omitempty"`     // decisions made in the meeting
+	Agenda       []AgendaSlice `json:"agenda,omitempty"`        // transcript split by calendar agenda item
+
+	Style string `json:"style,omitempty"` // SummaryStyle.Key() the summary was written in
+	Model string `json:"model,omitempty"` // model that produced the summary
+
+	SuggestedTags []TagSuggestion   `json:"suggested_tags,omitempty"` // co-occurrence suggestions awaiting review
+	TagOrigins    map[string]string `json:"tag_origins,omitempty"`    // tag -> llm, mapping, suggestion or user (see tagorigin.go)
+
+	PreviousMeetingID string   `json:"previous_meeting_id,omitempty"` // previous occurrence of a recurring meeting
+	SinceLastTime     []string `json:"since_last_time,omitempty"`     // what changed since the previous occurrence
+
+	ActionItems []ActionItem `json:"action_items,omitempty"` // follow-ups, with completion synced back from the vault
+	Quotes      []Quote      `json:"quotes,omitempty"`       // notable verbatim quotes
+
+	EstimatedSpeakers int               `json:"estimated_speakers,omitempty"` // LLM estimate, used when Krisp has no speaker data
+	InferredSpeakers  map[string]string `json:"inferred_speakers,omitempty"`  // speaker index -> name from self-introductions (SPEAKER_FALLBACK=llm)
+	speakerNames      []InferredSpeaker // names the LLM found by transcript label, before they are resolved to indexes
+
+	PeopleMentioned []string `json:"people_mentioned,omitempty"` // people the LLM says it named
+	UnknownNames    []string `json:"unknown_names,omitempty"`    // names not in the speaker list or allowlist
+
+	Visibility string `json:"visibility,omitempty"` // sensitivity level set on review (see visibility.go)
+
+	Omissions []TranscriptOmission `json:"omissions,omitempty"` // transcript parts left out because the content filter blocked them
+
+	Alerts []KeywordAlert `json:"alerts,omitempty"` // ALERT_KEYWORDS found when the meeting was first summarized
+
+	Usage *LLMUsage `json:"usage,omitempty"` // tokens and cost of the LLM calls that produced the summary
 }
 
 // Cache manages local storage of meetings and summaries with in-memory caching
@@ -43,8 +77,12 @@ func (c *Cache) ensureDir() error {
 	return nil
 }
 
-// SaveMeeting saves a meeting to disk and cache
+// SaveMeeting saves a meeting to disk and cache (only to memory in a dry run)
 func (c *Cache) SaveMeeting(meeting *Meeting) error {
+	if dryRun {
+		c.meetings[meeting.ID] = meeting
+		return nil
+	}
 	if err := c.ensureDir(); err != nil {
 		return err
 	}
@@ -88,7 +126,8 @@ func (c *Cache) LoadMeeting(meetingID string) (*Meeting, error) {
 	return &meeting, nil
 }
 
-// MeetingExists checks if a meeting exists in cache
+// MeetingExists checks if a meeting exists in cache. Meetings merged into another count
+// as cached, so they aren't downloaded again.
 func (c *Cache) MeetingExists(meetingID string) bool {
 	// Check memory first
 	if _, ok := c.meetings[meetingID]; ok {
@@ -97,12 +136,43 @@ func (c *Cache) MeetingExists(meetingID string) bool {
 
 	// Check disk
 	cachePath := filepath.Join(c.dir, meetingID+".json")
-	_, err := os.Stat(cachePath)
+	if _, err := os.Stat(cachePath); err == nil {
+		return true
+	}
+	_, err := os.Stat(c.tombstonePath(meetingID))
 	return err == nil
 }
 
-// SaveSummary saves a summary to disk and cache
+// isDerivedCacheFile reports whether a cache file holds derived data (summary, stats, ...)
+// rather than a meeting. Krisp meeting IDs never contain hyphens.
+func isDerivedCacheFile(filename string) bool {
+	return strings.Contains(strings.TrimSuffix(filename, ".json"), "-")
+}
+
+// MeetingIDs lists the IDs of all meetings cached on disk
+func (c *Cache) MeetingIDs() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache directory: %w", err)
+	}
+
+	var ids []string
+	for _, file := range files {
+		filename := filepath.Base(file)
+		if isDerivedCacheFile(filename) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(filename, ".json"))
+	}
+	return ids, nil
+}
+
+// SaveSummary saves a summary to disk and cache (only to memory in a dry run)
 func (c *Cache) SaveSummary(meetingID string, summary *SummaryData) error {
+	if dryRun {
+		c.summaries[meetingID] = summary
+		return nil
+	}
 	if err := c.ensureDir(); err != nil {
 		return err
 	}
@@ -158,3 +228,101 @@ func (c *Cache) SummaryExists(meetingID string) bool {
 	_, err := os.Stat(cachePath)
 	return err == nil
 }
+
+// SaveStats saves transcript stats to disk (not in a dry run)
+func (c *Cache) SaveStats(meetingID string, stats *TranscriptStats) error {
+	if dryRun {
+		return nil
+	}
+	if err := c.ensureDir(); err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcript stats: %w", err)
+	}
+
+	cachePath := filepath.Join(c.dir, meetingID+"-stats.json")
+	if err := os.WriteFile(cachePath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write transcript stats file: %w", err)
+	}
+	return nil
+}
+
+// LoadStats loads transcript stats from disk
+func (c *Cache) LoadStats(meetingID string) (*TranscriptStats, error) {
+	cachePath := filepath.Join(c.dir, meetingID+"-stats.json")
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript stats file: %w", err)
+	}
+
+	var stats TranscriptStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transcript stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// SaveSearchTerms saves a transcript's indexed word counts to disk (not in a dry run)
+func (c *Cache) SaveSearchTerms(meetingID string, terms *SearchTerms) error {
+	if dryRun {
+		return nil
+	}
+	if err := c.ensureDir(); err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(terms)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search terms: %w", err)
+	}
+
+	cachePath := filepath.Join(c.dir, meetingID+"-terms.json")
+	if err := os.WriteFile(cachePath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write search terms file: %w", err)
+	}
+	return nil
+}
+
+// LoadSearchTerms loads a transcript's indexed word counts from disk
+func (c *Cache) LoadSearchTerms(meetingID string) (*SearchTerms, error) {
+	cachePath := filepath.Join(c.dir, meetingID+"-terms.json")
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search terms file: %w", err)
+	}
+
+	var terms SearchTerms
+	if err := json.Unmarshal(data, &terms); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search terms: %w", err)
+	}
+	return &terms, nil
+}
+
+// AttachmentPath returns the cache location of a meeting attachment
+func (c *Cache) AttachmentPath(meetingID, name string) string {
+	return filepath.Join(c.dir, "attachments", meetingID, name)
+}
+
+// AttachmentExists checks if a meeting attachment is cached
+func (c *Cache) AttachmentExists(meetingID, name string) bool {
+	_, err := os.Stat(c.AttachmentPath(meetingID, name))
+	return err == nil
+}
+
+// SaveAttachment saves a meeting attachment to disk (not in a dry run)
+func (c *Cache) SaveAttachment(meetingID, name string, data []byte) error {
+	if dryRun {
+		return nil
+	}
+	path := c.AttachmentPath(meetingID, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write attachment file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultChunkTokens is the largest transcript (estimated tokens) summarized in one call
+const defaultChunkTokens = 100000
+
+// chunkTokensFromEnv reads SUMMARIZE_CHUNK_TOKENS: transcripts estimated above it are
+// summarized in chunks of at most that size. 0 turns chunking off.
+func chunkTokensFromEnv() (int, error) {
+	v := strings.TrimSpace(os.Getenv("SUMMARIZE_CHUNK_TOKENS"))
+	if v == "" {
+		return defaultChunkTokens, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid SUMMARIZE_CHUNK_TOKENS %q", v)
+	}
+	return n, nil
+}
+
+// transcriptChunk is a run of consecutive transcript lines
+type transcriptChunk struct {
+	FirstLine int // 0-based index of the chunk's first line in the transcript
+	Text      string
+}
+
+// splitTranscript splits a transcript into chunks of at most maxTokens (estimated),
+// breaking between lines so no speaker turn is cut in two. A line over the budget on its
+// own gets a chunk of its own.
+func splitTranscript(transcript string, maxTokens int) []transcriptChunk {
+	lines := strings.Split(strings.TrimRight(transcript, "\n"), "\n")
+	var chunks []transcriptChunk
+	start, tokens := 0, 0
+	for i, line := range lines {
+		n := estimateTokens(line + "\n")
+		if i > start && tokens+n > maxTokens {
+			chunks = append(chunks, transcriptChunk{FirstLine: start, Text: strings.Join(lines[start:i], "\n")})
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	return append(chunks, transcriptChunk{FirstLine: start, Text: strings.Join(lines[start:], "\n")})
+}
+
+// summarizeInChunks summarizes a transcript too long for one call: each chunk is
+// summarized on its own, then the chunk summaries are merged into one summary of the whole
+// meeting by another call (in rounds, if the chunk summaries are too long themselves).
+// Returns the summary and the model that merged it.
+func summarizeInChunks(ctx context.Context, models []string, transcript string, existingTags []string, names []string, style SummaryStyle, maxTokens int) (*SummaryData, string, error) {
+	chunks := splitTranscript(transcript, maxTokens)
+	fmt.Printf("  ✂️  Transcript is ~%d tokens, summarizing it in %d parts\n", estimateTokens(transcript), len(chunks))
+
+	parts := make([]*SummaryData, len(chunks))
+	for i, chunk := range chunks {
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		part, _, err := summarizeWithFallback(ctx, models, chunk.Text, existingTags, names, style)
+		if err != nil {
+			return nil, "", fmt.Errorf("part %d of %d: %w", i+1, len(chunks), err)
+		}
+		// Omitted lines count from the start of the whole transcript
+		for j := range part.Omissions {
+			part.Omissions[j].FromLine += chunk.FirstLine
+			part.Omissions[j].ToLine += chunk.FirstLine
+		}
+		parts[i] = part
+	}
+
+	merged, model, err := mergeChunkSummaries(ctx, models, parts, existingTags, names, style, maxTokens)
+	if err != nil {
+		return nil, model, err
+	}
+
+	// What the merge call can't see is carried over from the parts
+	var omissions []TranscriptOmission
+	var speakerNames []InferredSpeaker
+	people := make(map[string]bool)
+	for _, name := range merged.PeopleMentioned {
+		people[name] = true
+	}
+	for _, part := range parts {
+		omissions = append(omissions, part.Omissions...)
+		speakerNames = append(speakerNames, part.speakerNames...)
+		merged.EstimatedSpeakers = max(merged.EstimatedSpeakers, part.EstimatedSpeakers)
+		for _, name := range part.PeopleMentioned {
+			if !people[name] {
+				people[name] = true
+				merged.PeopleMentioned = append(merged.PeopleMentioned, name)
+			}
+		}
+	}
+	merged.Omissions = omissions
+	if len(merged.speakerNames) == 0 {
+		merged.speakerNames = speakerNames
+	}
+	return merged, model, nil
+}
+
+// mergeChunkSummaries merges the summaries of consecutive parts of a meeting into one.
+// When they don't fit in one call together, neighbouring parts are merged first.
+func mergeChunkSummaries(ctx context.Context, models []string, parts []*SummaryData, existingTags []string, names []string, style SummaryStyle, maxTokens int) (*SummaryData, string, error) {
+	for round := 1; ; round++ {
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		groups := groupChunkSummaries(parts, maxTokens)
+		merged := make([]*SummaryData, len(groups))
+		model := ""
+		for i, group := range groups {
+			if len(group) == 1 {
+				merged[i] = group[0]
+				continue
+			}
+			summary, m, err := summarizeWithFallback(ctx, models, renderChunkSummaries(group), existingTags, names, style)
+			if err != nil {
+				return nil, m, fmt.Errorf("merging the summaries of %d parts: %w", len(group), err)
+			}
+			merged[i], model = summary, m
+		}
+		if len(merged) == 1 {
+			return merged[0], model, nil
+		}
+		if len(merged) == len(parts) {
+			return nil, "", fmt.Errorf("the summaries of the transcript's parts are too long to merge within SUMMARIZE_CHUNK_TOKENS")
+		}
+		fmt.Printf("  ✂️  Merged %d part summaries into %d (round %d)\n", len(parts), len(merged), round)
+		parts = merged
+	}
+}
+
+// groupChunkSummaries groups consecutive part summaries whose rendering fits in maxTokens
+func groupChunkSummaries(parts []*SummaryData, maxTokens int) [][]*SummaryData {
+	var groups [][]*SummaryData
+	start := 0
+	for i := range parts {
+		if i > start && estimateTokens(renderChunkSummaries(parts[start:i+1])) > maxTokens {
+			groups = append(groups, parts[start:i])
+			start = i
+		}
+	}
+	return append(groups, parts[start:])
+}
+
+// renderChunkSummaries renders part summaries as the "transcript" of the merge call
+func renderChunkSummaries(parts []*SummaryData) string {
+	var b strings.Builder
+	b.WriteString("This meeting was too long to summarize at once, so consecutive parts of its transcript were summarized separately. ")
+	b.WriteString("Below are those summaries, in order, as JSON. Merge them into one summary of the whole meeting: ")
+	b.WriteString("combine topics discussed in several parts, keep every decision and action item once, and pick the most notable quotes.\n")
+	for i, part := range parts {
+		// Only what the model wrote; the bookkeeping fields mean nothing to it
+		data, _ := json.MarshalIndent(SummaryData{
+			Title:           part.Title,
+			Description:     part.Description,
+			Tags:            part.Tags,
+			Summary:         part.Summary,
+			Topics:          part.Topics,
+			TopicDetails:    part.TopicDetails,
+			Decisions:       part.Decisions,
+			ActionItems:     part.ActionItems,
+			Quotes:          part.Quotes,
+			PeopleMentioned: part.PeopleMentioned,
+		}, "", "  ")
+		fmt.Fprintf(&b, "\nPart %d of %d:\n%s\n", i+1, len(parts), data)
+	}
+	return b.String()
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// options holds the command line flags of a run. Each command only registers the flags
+// it uses; the rest keep their zero values.
+type options struct {
+	limit              int
+	overwrite          bool
+	meeting            string
+	test               bool
+	applyNormalization bool
+	updateFields       string
+	open               bool
+	restyle            bool
+	month              string
+	tag                string
+	fix                bool
+	from               string
+	dryRun             bool
+	format             string
+	run                string
+	force              bool
+	participant        string
+	since              string
+	file               string
+	interval           time.Duration
+
+	// Global flags, accepted by every command
+	dataDir    string
+	cacheDir   string
+	statePath  string
+	maxRuntime time.Duration
+	offline    bool
+	keepGoing  bool
+	token      string
+	tokenStdin bool
+
+	args   []string        // positional arguments after the flags
+	passed map[string]bool // flags given explicitly
+}
+
+// flagGroup registers related flags of a command
+type flagGroup func(fs *flag.FlagSet, o *options)
+
+// command is a krisp-sync subcommand, e.g. `krisp-sync sync`
+type command struct {
+	name          string
+	usage         string // arguments after the flags, if any
+	summary       string
+	flags         []flagGroup
+	noCredentials bool // runs without Krisp and Google Cloud credentials
+	beforeSetup   bool // runs once the credentials are read, without vault, state or cache
+	run           func(e *cmdEnv) error
+}
+
+func globalFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.dataDir, "data-dir", "", "Directory for state, cache and generated files (default: $KRISP_SYNC_DATA_DIR or XDG data dir; 'vault:' prefix for vault-relative)")
+	fs.StringVar(&o.cacheDir, "cache-dir", "", "Meeting cache directory (default: $KRISP_SYNC_CACHE_DIR or <data-dir>/meetings)")
+	fs.StringVar(&o.statePath, "state", "", "Sync state file (default: $KRISP_SYNC_STATE_PATH or <data-dir>/.krisp_sync_state.json)")
+	fs.DurationVar(&o.maxRuntime, "max-runtime", 0, "Stop starting new work after this long (e.g. 10m) and exit cleanly; 0 = unlimited")
+	fs.BoolVar(&o.offline, "offline", false, "Make no Krisp or LLM calls: sync the vault from the local cache only")
+	fs.BoolVar(&o.keepGoing, "keep-going", false, "Exit with status 0 even if some meetings failed")
+	fs.StringVar(&o.token, "token", "", "Krisp bearer token, instead of KRISP_BEARER_TOKEN (visible to other users in the process list; prefer --token-stdin)")
+	fs.BoolVar(&o.tokenStdin, "token-stdin", false, "Read the Krisp bearer token from stdin, for CI and secret managers")
+}
+
+func limitFlag(fs *flag.FlagSet, o *options) {
+	fs.IntVar(&o.limit, "limit", 1, "Number of meetings to process (0 = all; default: 1 for testing)")
+}
+
+func overwriteFlag(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.overwrite, "overwrite", false, "Force re-process meetings, ignoring state (re-summarize and re-sync)")
+}
+
+func meetingFlag(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.meeting, "meeting", "", "Process specific meeting IDs (comma-separated, combine with --overwrite to re-process)")
+}
+
+func syncOnlyFlags(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.test, "test", false, "Test mode: sync a single meeting into the sandbox (KRISP_SYNC_SANDBOX_DIR) instead of the vault, without updating state")
+	fs.BoolVar(&o.applyNormalization, "apply-normalization", false, "Apply tag normalization from normalize-result.json (for initial mass import)")
+	fs.StringVar(&o.updateFields, "update-fields", "", "Update only specific frontmatter fields in existing Obsidian files (comma-separated, e.g., 'date,time')")
+}
+
+func openFlag(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.open, "open", false, "Open the newest synced summary (or daily note) in Obsidian when sync completes (default: $OBSIDIAN_OPEN_ON_SYNC)")
+}
+
+func restyleFlag(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.restyle, "restyle", false, "Re-summarize and re-sync meetings whose summaries were written in a different style (SUMMARY_* settings)")
+}
+
+func dryRunFlag(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Show what would change without writing anything")
+}
+
+func monthFlag(usage string) flagGroup {
+	return func(fs *flag.FlagSet, o *options) { fs.StringVar(&o.month, "month", "", usage) }
+}
+
+func fromFlag(usage string) flagGroup {
+	return func(fs *flag.FlagSet, o *options) { fs.StringVar(&o.from, "from", "", usage) }
+}
+
+func formatFlag(value, usage string) flagGroup {
+	return func(fs *flag.FlagSet, o *options) { fs.StringVar(&o.format, "format", value, usage) }
+}
+
+// pipelineFlags are the flags of the stages that select meetings to process
+var pipelineFlags = []flagGroup{limitFlag, overwriteFlag, meetingFlag}
+
+// commands lists the subcommands in the order `krisp-sync help` shows them
+var commands = []command{
+	{name: "all", summary: "Run all stages in sequence (extract-tags, download, summarize, sync and the optional ones); the default", flags: append([]flagGroup{openFlag, restyleFlag, dryRunFlag}, pipelineFlags...), run: runAllCommand},
+	{name: "download", summary: "Download meetings from Krisp API to local cache", flags: append([]flagGroup{dryRunFlag}, pipelineFlags...), run: func(e *cmdEnv) error { return e.downloadStage(e.meetingIDs) }},
+	{name: "summarize", summary: "Generate AI summaries for cached meetings", flags: append([]flagGroup{dryRunFlag}, pipelineFlags...), run: func(e *cmdEnv) error { return e.summarizeStage(e.meetingIDs) }},
+	{name: "sync", summary: "Sync cached meetings and summaries to Obsidian", flags: append([]flagGroup{syncOnlyFlags, openFlag, dryRunFlag}, pipelineFlags...), run: func(e *cmdEnv) error { return e.syncStage(e.meetingIDs) }},
+	{name: "check-updates", summary: "Check Krisp API for updated meetings and sync changes to Obsidian", run: func(e *cmdEnv) error {
+		if skipOffline("check-updates", serviceKrisp) {
+			return nil
+		}
+		return runCheckUpdates(e.ctx, e.syncState, e.cache, e.vaultPath)
+	}},
+	{name: "extract-tags", summary: "Extract all existing tags from Obsidian vault to obsidian-tags.json and the Tags Report note", run: func(e *cmdEnv) error { return runExtractTags(e.vaultPath) }},
+	{name: "normalize-prompt", summary: "Generate tag normalization prompt for initial mass import", run: func(e *cmdEnv) error { return runNormalizePrompt(e.ctx, e.cache) }},
+	{name: "normalize-edit", summary: "Review the fuzzy and LLM tag mappings one by one", run: func(e *cmdEnv) error { return runNormalizeEdit() }},
+	{name: "repair", summary: "Sync filesystem state with tracking state", run: func(e *cmdEnv) error { return runRepair(e.syncState, e.cache) }},
+	{name: "action-items", summary: "Record action items checked off in the vault", run: func(e *cmdEnv) error { return runActionItemsSync(e.vaultPath, e.syncState, e.cache) }},
+	{name: "archive", summary: "Move months older than ARCHIVE_AFTER_MONTHS into the archive", flags: []flagGroup{dryRunFlag}, run: func(e *cmdEnv) error { return runArchive(e.vaultPath) }},
+	{name: "reprocess", summary: "Re-run Krisp transcription for --meeting IDs, then re-summarize and re-sync them", flags: []flagGroup{meetingFlag, limitFlag, openFlag}, run: runReprocessCommand},
+	{name: "merge", usage: "[<meeting-id>...]", summary: "Combine two or more cached meetings into one, then summarize and sync it", flags: []flagGroup{meetingFlag, openFlag}, run: runMergeCommand},
+	{name: "analytics", summary: "Write a monthly meeting time report note", flags: []flagGroup{monthFlag("Month to report on, as YYYY-MM (default: current month)")}, run: func(e *cmdEnv) error { return runAnalytics(e.vaultPath, e.cache, e.opts.month) }},
+	{name: "resync", summary: "Re-render the notes of a month and/or tag, keeping your edits", flags: []flagGroup{
+		monthFlag("Re-sync meetings of this month, as YYYY-MM"),
+		func(fs *flag.FlagSet, o *options) { fs.StringVar(&o.tag, "tag", "", "Re-sync meetings with this tag") },
+		limitFlag, openFlag,
+	}, run: runResyncCommand},
+	{name: "status", summary: "Show pipeline progress, Krisp token expiry and meetings waiting for transcripts", run: func(e *cmdEnv) error { return runStatus(e.syncState) }},
+	{name: "login", usage: "[<account>]", summary: "Save a Krisp bearer token for later runs, and a refresh token when KRISP_REFRESH_URL is set", noCredentials: true, beforeSetup: true, run: func(e *cmdEnv) error {
+		// A token in the environment isn't one to save
+		token := ""
+		if e.opts.token != "" || e.opts.tokenStdin {
+			token = bearerToken
+		}
+		return runLogin(e.ctx, firstNonEmpty(e.opts.args...), token)
+	}},
+	{name: "import-people", summary: "Import a people directory (Google Contacts/LDAP CSV or LDIF export)", flags: []flagGroup{fromFlag("CSV (Google Contacts, LDAP tools) or LDIF export to import")}, run: func(e *cmdEnv) error { return runImportPeople(e.opts.from) }},
+	{name: "rename-people", summary: "Rewrite corrected names from the people directory across synced notes", flags: []flagGroup{dryRunFlag}, run: func(e *cmdEnv) error { return runRenamePeople(e.vaultPath, e.cache, e.opts.dryRun) }},
+	{name: "eod", summary: "Write today's end-of-day wrap-up into the daily note", run: func(e *cmdEnv) error {
+		if skipOffline("eod", serviceLLM) {
+			return nil
+		}
+		return runEOD(e.ctx, e.vaultPath, e.cache)
+	}},
+	{name: "retry-failed", summary: "Resume meetings that failed in earlier runs at the stage they failed in", flags: []flagGroup{limitFlag, openFlag}, run: runRetryFailedCommand},
+	{name: "lint", summary: "Validate synced meeting notes", flags: []flagGroup{func(fs *flag.FlagSet, o *options) {
+		fs.BoolVar(&o.fix, "fix", false, "Auto-fix fixable issues")
+	}}, run: func(e *cmdEnv) error { return runLint(e.vaultPath, e.cache, e.opts.fix) }},
+	{name: "backfill", summary: "Download, summarize and sync a large history a daily quota at a time", run: func(e *cmdEnv) error {
+		if skipOffline("backfill", serviceKrisp) {
+			return nil
+		}
+		return runBackfill(e.ctx, e.vaultPath, e.syncState, e.cache)
+	}},
+	{name: "inbox", summary: "Update the meeting inbox note", run: func(e *cmdEnv) error { return runInbox(e.vaultPath, e.syncState, e.cache) }},
+	{name: "minutes", summary: "Rewrite formal minutes of board/steering meetings, optionally exported as DOCX or PDF", flags: []flagGroup{
+		meetingFlag, formatFlag("", "Also export the minutes as docx or pdf"),
+	}, run: func(e *cmdEnv) error {
+		return runMinutes(e.vaultPath, e.syncState, e.cache, e.meetingIDs, e.opts.format)
+	}},
+	{name: "share", summary: "Export shareable notes of synced meetings (no transcript or internal tags) to SHARE_DIR", noCredentials: true, flags: []flagGroup{meetingFlag, dryRunFlag}, run: func(e *cmdEnv) error { return runShare(e.vaultPath, e.syncState, e.cache, e.meetingIDs) }},
+	{name: "promote", usage: "[<path>...]", summary: "Copy files approved in the `sync --test` sandbox into the vault (without paths, lists the sandbox)", noCredentials: true, flags: []flagGroup{dryRunFlag}, run: func(e *cmdEnv) error {
+		auditStage = "promote"
+		return runPromote(e.vaultPath, e.testSandbox, e.opts.args)
+	}},
+	{name: "rollback", summary: "Undo the vault changes of one run (without --run, lists recent runs)", flags: []flagGroup{
+		func(fs *flag.FlagSet, o *options) {
+			fs.StringVar(&o.run, "run", "", "Run ID to roll back (omit to list runs)")
+			fs.BoolVar(&o.force, "force", false, "Roll back files even if they changed since the run")
+		},
+	}, run: func(e *cmdEnv) error { return runRollback(e.opts.run, e.opts.force, e.syncState) }},
+	{name: "orphans", summary: "Find transcripts whose summary you deleted, and summaries missing their transcript", flags: []flagGroup{dryRunFlag}, run: func(e *cmdEnv) error { return runOrphans(e.vaultPath, e.cache, e.opts.dryRun) }},
+	{name: "plan", summary: "Write this week's planning note with the open action items of previous weeks", run: runPlanCommand},
+	{name: "log", summary: "Show what changed a vault file, or the latest vault changes", noCredentials: true, flags: []flagGroup{
+		func(fs *flag.FlagSet, o *options) {
+			fs.StringVar(&o.file, "file", "", "Vault file to show the change history of")
+		},
+	}, run: func(e *cmdEnv) error { return runAuditLog(e.vaultPath, e.opts.file) }},
+	{name: "dashboards", summary: "Write a Dataview dashboard note per top-level tag", run: func(e *cmdEnv) error { return runTagDashboards(e.vaultPath, true) }},
+	{name: "search-index", summary: "Write a note with the key terms of every transcript, for Obsidian search when it skips transcripts", noCredentials: true, run: func(e *cmdEnv) error { return runSearchIndex(e.vaultPath, e.syncState, e.cache) }},
+	{name: "list", summary: "Print cached meetings with their date, title, sync status and vault note", noCredentials: true, flags: []flagGroup{
+		func(fs *flag.FlagSet, o *options) {
+			fs.StringVar(&o.participant, "participant", "", "Name or email of a participant to list meetings with")
+			fs.StringVar(&o.since, "since", "", "Only list meetings on or after this date, as YYYY-MM-DD")
+		},
+	}, run: func(e *cmdEnv) error {
+		return runList(e.vaultPath, e.syncState, e.cache, e.opts.participant, e.opts.since)
+	}},
+	{name: "retire-tags", summary: "Propose retiring tags no meeting has used for TAG_RETIRE_MONTHS", flags: []flagGroup{dryRunFlag}, run: func(e *cmdEnv) error { return runRetireTags(e.vaultPath, e.syncState, e.cache, e.opts.dryRun) }},
+	{name: "bench", summary: "Benchmark download, summarize and sync against fixtures in a sandbox", noCredentials: true, flags: []flagGroup{
+		fromFlag("Meeting cache to use as fixtures (default: synthetic meetings)"),
+	}, run: func(e *cmdEnv) error { return runBench(e.ctx, e.opts.from) }},
+	{name: "watch", summary: "Keep running, regenerating meetings whose notes are flagged with krisp_resync: true", run: func(e *cmdEnv) error { return runWatch(e.ctx, e.vaultPath, e.syncState, e.cache) }},
+	{name: "daemon", summary: "Keep running, downloading, summarizing and syncing new meetings on an interval", flags: []flagGroup{
+		func(fs *flag.FlagSet, o *options) {
+			fs.DurationVar(&o.interval, "interval", 0, "Time between runs (default: $DAEMON_INTERVAL or 15m)")
+			fs.IntVar(&o.limit, "limit", 0, "Number of meetings to process per run (0 = all)")
+		},
+	}, run: runDaemonCommand},
+	{name: "export", summary: "Export all cached meetings as a flat dataset for spreadsheets or DuckDB", flags: []flagGroup{formatFlag("csv", "Export format: csv, jsonl or parquet")}, run: func(e *cmdEnv) error { return runExport(e.vaultPath, e.syncState, e.cache, e.opts.format) }},
+	{name: "ics", summary: "Export synced meetings to an .ics calendar file", run: func(e *cmdEnv) error { return runExportICS(e.vaultPath, e.syncState, e.cache) }},
+	{name: "stats", summary: "Report transcript size metrics", run: func(e *cmdEnv) error { return runStats(e.cache) }},
+	{name: "costs", summary: "Report LLM tokens and cost by month and model, and what re-summarizing everything would cost", noCredentials: true, run: func(e *cmdEnv) error { return runCosts(e.cache) }},
+	{name: "plugins", summary: "List the plugins loaded from the plugins directory and the hooks they export", noCredentials: true, run: func(e *cmdEnv) error {
+		runPlugins(e.pluginDir)
+		return nil
+	}},
+}
+
+// findCommand returns the command with the given name
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// printUsage lists the commands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: krisp-sync [command] [flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-17s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nRun `krisp-sync <command> -h` for the flags of a command.")
+}
+
+// legacyStep removes a --step flag from the arguments of the old flag-only command line,
+// returning the step it named ("" when there was none)
+func legacyStep(args []string) (string, []string, error) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "step" {
+			continue
+		}
+		rest := append([]string{}, args[:i]...)
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag needs an argument: --step")
+			}
+			value = args[i+1]
+			i++
+		}
+		return value, append(rest, args[i+1:]...), nil
+	}
+	return "", args, nil
+}
+
+// errUsage is returned for command lines that were already reported with usage help
+var errUsage = errors.New("invalid command line")
+
+// errNothingToDo is returned by command handlers that found no work, to end the run early
+// without an error
+var errNothingToDo = errors.New("nothing to do")
+
+// leadingGlobalFlags splits off global flags given before the command name, as in
+// `krisp-sync --offline sync`. It returns no flags when the arguments don't start with
+// global flags followed by a command.
+func leadingGlobalFlags(args []string) ([]string, []string) {
+	fs := flag.NewFlagSet("krisp-sync", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	globalFlags(fs, &options{})
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 || findCommand(fs.Arg(0)) == nil {
+		return nil, args
+	}
+	return args[:len(args)-fs.NArg()], fs.Args()
+}
+
+// parseCommandLine parses `krisp-sync [global flags] [command] [flags] [args]`. Without a
+// command it runs "all"; the old `--step <command>` form still works.
+func parseCommandLine(args []string) (*command, *options, error) {
+	global, args := leadingGlobalFlags(args)
+	name := "all"
+	if len(args) > 0 {
+		switch arg := args[0]; {
+		case arg == "help" || arg == "-h" || arg == "-help" || arg == "--help":
+			if len(args) > 1 && findCommand(args[1]) != nil {
+				return parseCommandLine([]string{args[1], "-h"})
+			}
+			printUsage(os.Stdout)
+			return nil, nil, flag.ErrHelp
+		case !strings.HasPrefix(arg, "-"):
+			name, args = arg, args[1:]
+		default:
+			step, rest, err := legacyStep(args)
+			if err != nil {
+				return nil, nil, err
+			}
+			if step != "" {
+				fmt.Fprintf(os.Stderr, "⚠ --step is deprecated: run `krisp-sync %s` instead\n", step)
+				name, args = step, rest
+			}
+		}
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		printUsage(os.Stderr)
+		return nil, nil, fmt.Errorf("unknown command %q", name)
+	}
+
+	o := &options{passed: make(map[string]bool)}
+	fs := flag.NewFlagSet("krisp-sync "+cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: krisp-sync %s\n\n%s\n\nFlags:\n", strings.TrimSpace(cmd.name+" [flags] "+cmd.usage), cmd.summary)
+		fs.PrintDefaults()
+	}
+	globalFlags(fs, o)
+	for _, group := range cmd.flags {
+		group(fs, o)
+	}
+	if err := fs.Parse(append(append([]string{}, global...), args...)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, nil, err
+		}
+		return nil, nil, errUsage // the flag package already reported it
+	}
+	fs.Visit(func(f *flag.Flag) { o.passed[f.Name] = true })
+	o.args = fs.Args()
+	if len(o.args) > 0 && cmd.usage == "" {
+		return nil, nil, fmt.Errorf("%s: unexpected arguments %s", cmd.name, strings.Join(o.args, " "))
+	}
+	return cmd, o, nil
+}
+
+// mustParseCommandLine parses the command line, exiting on errors and after -h
+func mustParseCommandLine() (*command, *options) {
+	cmd, o, err := parseCommandLine(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		}
+		os.Exit(2)
+	}
+	return cmd, o
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"strconv"
+	"text/template"
+)
+
+//go:embed compress-prompt.md
+var compressPromptTemplate string
+
+// defaultCompressMinTokens is the transcript size above which compression kicks in
+const defaultCompressMinTokens = 20000
+
+// CompressionConfig controls the optional transcript → minutes stage before summarizing
+type CompressionConfig struct {
+	Enabled   bool
+	MinTokens int    // only transcripts estimated above this are compressed
+	Model     string // cheap model that writes the minutes
+}
+
+// compressionFromEnv reads SUMMARIZE_COMPRESS, SUMMARIZE_COMPRESS_MIN_TOKENS and COMPRESS_MODEL
+func compressionFromEnv() (CompressionConfig, error) {
+	config := CompressionConfig{
+		Enabled:   envBool("SUMMARIZE_COMPRESS"),
+		MinTokens: defaultCompressMinTokens,
+		Model:     firstNonEmpty(os.Getenv("COMPRESS_MODEL"), defaultModel()),
+	}
+	if v := os.Getenv("SUMMARIZE_COMPRESS_MIN_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return config, fmt.Errorf("invalid SUMMARIZE_COMPRESS_MIN_TOKENS %q", v)
+		}
+		config.MinTokens = n
+	}
+	return config, nil
+}
+
+// Applies reports whether a transcript should be compressed before summarizing
+func (c CompressionConfig) Applies(stats *TranscriptStats) bool {
+	return c.Enabled && stats != nil && stats.TokenEstimate > c.MinTokens
+}
+
+// buildCompressPrompt renders the prompt asking for a transcript's minutes
+func buildCompressPrompt(transcript string) (string, error) {
+	tmpl, err := template.New("compress").Parse(compressPromptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing compression prompt: %w", err)
+	}
+	var promptBuf bytes.Buffer
+	if err := tmpl.Execute(&promptBuf, map[string]string{"Transcript": transcript}); err != nil {
+		return "", fmt.Errorf("error rendering compression prompt: %w", err)
+	}
+	return promptBuf.String(), nil
+}
+
+// compressTranscript condenses a transcript into minutes, returning the original
+// transcript if compression fails or doesn't make it smaller
+func compressTranscript(ctx context.Context, config CompressionConfig, meetingID, transcript string) string {
+	prompt, err := buildCompressPrompt(transcript)
+	if err != nil {
+		fmt.Printf("  ⚠ %v\n", err)
+		return transcript
+	}
+
+	minutes, err := generateText(ctx, config.Model, prompt)
+	if err != nil {
+		fmt.Printf("  ⚠ Compression failed for %s, summarizing full transcript: %v\n", meetingID, err)
+		return transcript
+	}
+
+	before, after := estimateTokens(transcript), estimateTokens(minutes)
+	if after == 0 || after >= before {
+		return transcript
+	}
+	fmt.Printf("  🗜  Compressed %s: ~%d → ~%d tokens (%.0f%%)\n", meetingID, before, after, float64(after)*100/float64(before))
+	return "Meeting minutes (condensed from the full transcript):\n\n" + minutes
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// contentFilterChunkLines is how many transcript lines are checked against the content
+// filter at a time when looking for the part of a transcript that is blocked
+const contentFilterChunkLines = 40
+
+// TranscriptOmission is a part of the transcript left out of the summary because the
+// content filter blocked it
+type TranscriptOmission struct {
+	FromLine int      `json:"from_line"` // 1-based, inclusive
+	ToLine   int      `json:"to_line"`
+	Speakers []string `json:"speakers,omitempty"`
+	Reason   string   `json:"reason"` // block reason reported by the model
+}
+
+// relaxedSafetyKey marks a context whose LLM calls use relaxed safety settings
+type relaxedSafetyKey struct{}
+
+// withRelaxedSafety returns a context whose LLM calls only block content with a high
+// probability of harm
+func withRelaxedSafety(ctx context.Context) context.Context {
+	return context.WithValue(ctx, relaxedSafetyKey{}, true)
+}
+
+// safetySettings returns the safety settings for an LLM call, or nil for the defaults
+func safetySettings(ctx context.Context) []*genai.SafetySetting {
+	if relaxed, _ := ctx.Value(relaxedSafetyKey{}).(bool); !relaxed {
+		return nil
+	}
+	var settings []*genai.SafetySetting
+	for _, category := range []genai.HarmCategory{
+		genai.HarmCategoryHarassment,
+		genai.HarmCategoryHateSpeech,
+		genai.HarmCategorySexuallyExplicit,
+		genai.HarmCategoryDangerousContent,
+	} {
+		settings = append(settings, &genai.SafetySetting{Category: category, Threshold: genai.HarmBlockThresholdBlockOnlyHigh})
+	}
+	return settings
+}
+
+// contentFilterRetryEnabled reports whether blocked transcripts are retried (CONTENT_FILTER_RETRY,
+// on by default)
+func contentFilterRetryEnabled() bool {
+	return envBoolDefault("CONTENT_FILTER_RETRY", true)
+}
+
+// blockReason returns the reason the model gave for blocking a response ("SAFETY")
+func blockReason(err error) string {
+	var blocked *ContentBlockedError
+	if !errors.As(err, &blocked) || blocked.Reason == "" {
+		return "blocked"
+	}
+	return blocked.Reason
+}
+
+// summarizeAroundBlocks recovers a summary of a transcript the content filter blocked
+// (blocked, the error of the last attempt): first with relaxed safety settings, then with
+// the chunks of the transcript that are blocked on their own left out. The omitted lines
+// are recorded with the summary so the note can say what is missing.
+func summarizeAroundBlocks(ctx context.Context, model string, transcript string, existingTags []string, names []string, style SummaryStyle, blocked error) (*SummaryData, error) {
+	relaxed := withRelaxedSafety(ctx)
+	fmt.Printf("  🛡  %s blocked the transcript (%s), retrying with relaxed safety settings\n", model, blockReason(blocked))
+	response, err := summarizeTranscript(relaxed, model, transcript, existingTags, names, style)
+	if err == nil && validSummaryResponse(response) {
+		return parseSummaryResponse(response), nil
+	}
+	if err != nil && !errors.Is(err, errContentBlocked) {
+		return nil, err
+	}
+
+	// Find the chunks that are blocked on their own
+	lines := strings.Split(strings.TrimRight(transcript, "\n"), "\n")
+	var omissions []TranscriptOmission
+	for start := 0; start < len(lines); start += contentFilterChunkLines {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		end := start + contentFilterChunkLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		chunk := strings.Join(lines[start:end], "\n")
+		_, err := generateText(relaxed, model, "Summarize this part of a meeting transcript in one sentence:\n\n"+chunk)
+		if err == nil || !errors.Is(err, errContentBlocked) {
+			continue
+		}
+		omissions = append(omissions, TranscriptOmission{
+			FromLine: start + 1,
+			ToLine:   end,
+			Speakers: chunkSpeakers(lines[start:end]),
+			Reason:   blockReason(err),
+		})
+	}
+	if len(omissions) == 0 {
+		return nil, fmt.Errorf("%w (no single part of the transcript is blocked on its own)", blocked)
+	}
+	omitted := 0
+	for _, o := range omissions {
+		omitted += o.ToLine - o.FromLine + 1
+	}
+	if omitted == len(lines) {
+		return nil, fmt.Errorf("%w (every part of the transcript is blocked)", blocked)
+	}
+
+	fmt.Printf("  ✂️  Leaving out %d of %d transcript line(s) blocked by the content filter\n", omitted, len(lines))
+	response, err = summarizeTranscript(relaxed, model, elideTranscript(lines, omissions), existingTags, names, style)
+	if err == nil && !validSummaryResponse(response) {
+		err = errSchemaFailure
+	}
+	if err != nil {
+		return nil, err
+	}
+	summaryData := parseSummaryResponse(response)
+	summaryData.Omissions = omissions
+	return summaryData, nil
+}
+
+// elideTranscript replaces the omitted lines of a transcript with a marker, so the model
+// knows something was left out
+func elideTranscript(lines []string, omissions []TranscriptOmission) string {
+	var sb strings.Builder
+	next := 0
+	for _, o := range omissions {
+		for _, line := range lines[next : o.FromLine-1] {
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("[Part of the conversation omitted]\n")
+		next = o.ToLine
+	}
+	for _, line := range lines[next:] {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// chunkSpeakers returns the speakers of "Speaker: text" transcript lines, sorted
+func chunkSpeakers(lines []string) []string {
+	seen := make(map[string]bool)
+	var speakers []string
+	for _, line := range lines {
+		speaker, _, ok := strings.Cut(line, ": ")
+		if !ok || seen[speaker] {
+			continue
+		}
+		seen[speaker] = true
+		speakers = append(speakers, speaker)
+	}
+	sort.Strings(speakers)
+	return speakers
+}
+
+// renderOmissions renders a warning listing the parts of the transcript the summary leaves out
+func renderOmissions(summaryData *SummaryData) string {
+	if summaryData == nil || len(summaryData.Omissions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("> [!warning] Left out by the content filter\n")
+	sb.WriteString("> The model's safety filter blocked these parts of the transcript, so the summary doesn't cover them:\n")
+	for _, o := range summaryData.Omissions {
+		sb.WriteString(fmt.Sprintf("> - Lines %d–%d", o.FromLine, o.ToLine))
+		if len(o.Speakers) > 0 {
+			sb.WriteString(" (" + strings.Join(o.Speakers, ", ") + ")")
+		}
+		sb.WriteString(": " + o.Reason + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultGCPLocation is the Vertex AI region used when GOOGLE_CLOUD_LOCATION isn't set
+const defaultGCPLocation = "us-central1"
+
+// loadDotEnv loads .env when there is one. Without it, settings come from the environment.
+func loadDotEnv() error {
+	err := godotenv.Load()
+	if err == nil || errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return fmt.Errorf("error loading .env file: %w", err)
+}
+
+// readTokenFromStdin reads a Krisp bearer token piped to stdin (the first line)
+func readTokenFromStdin(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read token from stdin: %w", err)
+	}
+	token := strings.TrimSpace(line)
+	if token == "" {
+		return "", fmt.Errorf("--token-stdin: no token on stdin")
+	}
+	return token, nil
+}
+
+// krispTokenFromFlags returns the bearer token passed with --token or --token-stdin, or
+// KRISP_BEARER_TOKEN when neither is used
+func krispTokenFromFlags(flagToken string, fromStdin bool) (string, error) {
+	if flagToken != "" && fromStdin {
+		return "", fmt.Errorf("use either --token or --token-stdin, not both")
+	}
+	if fromStdin {
+		return readTokenFromStdin(os.Stdin)
+	}
+	return firstNonEmpty(flagToken, os.Getenv("KRISP_BEARER_TOKEN")), nil
+}
+
+// gcpCredentialsFile returns the Application Default Credentials file: the one in
+// GOOGLE_APPLICATION_CREDENTIALS, or the one `gcloud auth application-default login`
+// writes. Returns "" when there is neither.
+func gcpCredentialsFile() (string, error) {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		if !fileExists(path) {
+			return "", fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS points to %s, which doesn't exist", path)
+		}
+		return path, nil
+	}
+	configDir := os.Getenv("CLOUDSDK_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil
+		}
+		configDir = filepath.Join(home, ".config", "gcloud")
+	}
+	if path := filepath.Join(configDir, "application_default_credentials.json"); fileExists(path) {
+		return path, nil
+	}
+	return "", nil
+}
+
+// credentialsProject returns the project a credentials file names: a service account key's
+// project_id, or the quota_project_id of gcloud user credentials
+func credentialsProject(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Google credentials %s: %w", path, err)
+	}
+	var creds struct {
+		Type           string `json:"type"`
+		ProjectID      string `json:"project_id"`
+		QuotaProjectID string `json:"quota_project_id"`
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", fmt.Errorf("Google credentials %s are not valid JSON: %w", path, err)
+	}
+	if creds.Type == "" {
+		return "", fmt.Errorf("Google credentials %s have no type; expected a service account key or gcloud application default credentials", path)
+	}
+	return firstNonEmpty(creds.ProjectID, creds.QuotaProjectID), nil
+}
+
+// resolveGCPConfig returns the Vertex AI project and location. GOOGLE_CLOUD_PROJECT can be
+// left out when the credentials name the project, and GOOGLE_CLOUD_LOCATION defaults to
+// us-central1, so GOOGLE_APPLICATION_CREDENTIALS alone is enough.
+func resolveGCPConfig() (project, location string, err error) {
+	credsPath, err := gcpCredentialsFile()
+	if err != nil {
+		return "", "", err
+	}
+
+	project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if project == "" && credsPath != "" {
+		if project, err = credentialsProject(credsPath); err != nil {
+			return "", "", err
+		}
+		if project != "" {
+			fmt.Printf("☁️  Using Google Cloud project %s from %s\n", project, credsPath)
+		}
+	}
+	if project == "" {
+		if credsPath != "" {
+			return "", "", fmt.Errorf("GOOGLE_CLOUD_PROJECT not set, and the credentials in %s don't name a project; set GOOGLE_CLOUD_PROJECT", credsPath)
+		}
+		return "", "", fmt.Errorf("GOOGLE_CLOUD_PROJECT not set; set it, or set GOOGLE_APPLICATION_CREDENTIALS to a service account key file")
+	}
+	if credsPath == "" {
+		// Fine on Google Cloud, where the metadata server provides credentials
+		fmt.Println("⚠ No Google credentials found; run `gcloud auth application-default login` or set GOOGLE_APPLICATION_CREDENTIALS unless this runs on Google Cloud")
+	}
+
+	location = os.Getenv("GOOGLE_CLOUD_LOCATION")
+	if location == "" {
+		location = defaultGCPLocation
+	}
+	return project, location, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultDaemonInterval = 15 * time.Minute
+
+// daemonInterval returns how long the daemon waits between runs: the --interval flag, or
+// else DAEMON_INTERVAL (default 15m)
+func daemonInterval(flagValue time.Duration) (time.Duration, error) {
+	if flagValue < 0 {
+		return 0, fmt.Errorf("invalid --interval %s: must be positive", flagValue)
+	}
+	if flagValue > 0 {
+		return flagValue, nil
+	}
+	v := os.Getenv("DAEMON_INTERVAL")
+	if v == "" {
+		return defaultDaemonInterval, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid DAEMON_INTERVAL %q: must be a duration like 15m", v)
+	}
+	return d, nil
+}
+
+// quietSchedule is when the daemon and watch mode make no API calls: a daily window of
+// quiet hours (DAEMON_QUIET_HOURS, e.g. "19:00-08:00") and whole quiet days
+// (DAEMON_QUIET_DAYS, e.g. "sat,sun"), in local time
+type quietSchedule struct {
+	from, to int // quiet hours as minutes after midnight; from == to means none
+	days     map[time.Weekday]bool
+}
+
+// quietScheduleFromEnv reads DAEMON_QUIET_HOURS and DAEMON_QUIET_DAYS
+func quietScheduleFromEnv() (quietSchedule, error) {
+	var q quietSchedule
+	if v := strings.TrimSpace(os.Getenv("DAEMON_QUIET_HOURS")); v != "" {
+		from, to, ok := strings.Cut(v, "-")
+		start, err1 := time.Parse("15:04", strings.TrimSpace(from))
+		end, err2 := time.Parse("15:04", strings.TrimSpace(to))
+		if !ok || err1 != nil || err2 != nil {
+			return q, fmt.Errorf("invalid DAEMON_QUIET_HOURS %q: expected a window like 19:00-08:00", v)
+		}
+		q.from = start.Hour()*60 + start.Minute()
+		q.to = end.Hour()*60 + end.Minute()
+	}
+	if v := strings.TrimSpace(os.Getenv("DAEMON_QUIET_DAYS")); v != "" {
+		q.days = make(map[time.Weekday]bool)
+		for _, name := range strings.Split(v, ",") {
+			day, ok := parseWeekday(name)
+			if !ok {
+				return q, fmt.Errorf("invalid DAEMON_QUIET_DAYS %q: expected days like sat,sun", v)
+			}
+			q.days[day] = true
+		}
+	}
+	return q, nil
+}
+
+// parseWeekday parses a day name or its first three letters ("Sunday", "sun")
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if len(name) >= 3 && strings.HasPrefix(full, name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// enabled reports whether there are any quiet hours or days
+func (q quietSchedule) enabled() bool {
+	return q.from != q.to || len(q.days) > 0
+}
+
+// quiet reports whether t falls in the quiet hours or on a quiet day
+func (q quietSchedule) quiet(t time.Time) bool {
+	if q.days[t.Weekday()] {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if q.from < q.to {
+		return minute >= q.from && minute < q.to
+	}
+	// The window crosses midnight, e.g. 19:00-08:00
+	return q.from != q.to && (minute >= q.from || minute < q.to)
+}
+
+// nextOpen returns the first minute at or after t outside the quiet hours and days
+func (q quietSchedule) nextOpen(t time.Time) time.Time {
+	if !q.quiet(t) {
+		return t
+	}
+	open := t.Truncate(time.Minute)
+	for i := 0; i < 8*24*60 && q.quiet(open); i++ {
+		open = open.Add(time.Minute)
+	}
+	return open
+}
+
+// String describes the schedule, e.g. "19:00-08:00 and all day Sat, Sun"
+func (q quietSchedule) String() string {
+	var parts []string
+	if q.from != q.to {
+		parts = append(parts, fmt.Sprintf("%02d:%02d-%02d:%02d", q.from/60, q.from%60, q.to/60, q.to%60))
+	}
+	if len(q.days) > 0 {
+		var days []string
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if q.days[day] {
+				days = append(days, day.String()[:3])
+			}
+		}
+		parts = append(parts, "all day "+strings.Join(days, ", "))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// daemonRun is the outcome of one run of the daemon
+type daemonRun struct {
+	Started  time.Time
+	Took     time.Duration
+	Synced   int  // summary notes written
+	Failures int  // meetings that failed
+	CatchUp  bool // first run after quiet hours, without the per-run limit
+	Err      error
+}
+
+// statusLine summarizes a run and when the next one starts
+func (r daemonRun) statusLine(next time.Time) string {
+	when := fmt.Sprintf("Last run %s (%s)", r.Started.Format("2006-01-02 15:04:05"), r.Took.Round(time.Second))
+	if r.CatchUp {
+		when = "Catch-up run " + strings.TrimPrefix(when, "Last run ")
+	}
+	nextRun := next.Format("15:04:05")
+	if next.YearDay() != r.Started.YearDay() || next.Year() != r.Started.Year() {
+		nextRun = next.Format("Mon 15:04:05")
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("❌ %s failed: %v · next run %s", when, r.Err, nextRun)
+	}
+	status := fmt.Sprintf("%d note(s) synced", r.Synced)
+	if r.Failures > 0 {
+		status += fmt.Sprintf(", %d meeting failure(s)", r.Failures)
+	}
+	return fmt.Sprintf("🕒 %s: %s · next run %s", when, status, nextRun)
+}
+
+// runDaemonPass runs the stages of "all" once, as its own trace, and saves the state.
+// Returns the number of summary notes written.
+func runDaemonPass(e *cmdEnv, limit int) (int, error) {
+	ctx, span := tracer.Start(e.ctx, "krisp-sync daemon run", trace.WithNewRoot())
+	var err error
+	defer func() { endSpan(span, err) }()
+
+	opts := *e.opts
+	opts.limit = limit
+	pass := &cmdEnv{
+		ctx:           ctx,
+		opts:          &opts,
+		vaultPath:     e.vaultPath,
+		syncState:     e.syncState,
+		cache:         e.cache,
+		archiveMonths: e.archiveMonths,
+	}
+	if err = runAllStages(pass); err != nil {
+		return pass.synced, err
+	}
+
+	if !offline {
+		e.syncState.LastSyncTime = time.Now()
+	}
+	if err = e.syncState.Save(); err != nil {
+		return pass.synced, fmt.Errorf("error saving sync state: %w", err)
+	}
+	return pass.synced, nil
+}
+
+// runDaemon runs the pipeline every interval until e.ctx is cancelled (Ctrl+C or SIGTERM),
+// letting a run in progress stop at the next meeting. During quiet hours it makes no runs;
+// the first run after them catches up on everything without the per-run limit. Each run's
+// manifest is saved after it (e.nextRun).
+func runDaemon(e *cmdEnv, interval time.Duration) error {
+	ctx := e.ctx
+	quiet, err := quietScheduleFromEnv()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n=== Daemon: syncing every %s (Ctrl+C to stop) ===\n", interval)
+	if quiet.enabled() {
+		fmt.Printf("🌙 Quiet hours: %s\n", quiet)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	catchUp := false
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n🛑 Daemon stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		if now := time.Now(); quiet.quiet(now) {
+			open := quiet.nextOpen(now)
+			fmt.Printf("\n🌙 Quiet hours: next run %s\n", open.Format("Mon 15:04"))
+			timer.Reset(time.Until(open))
+			catchUp = true
+			continue
+		}
+
+		run := daemonRun{Started: time.Now(), CatchUp: catchUp}
+		runLimit := e.opts.limit
+		if catchUp {
+			fmt.Println("\n🌅 Quiet hours over: catching up")
+			runLimit = 0
+		}
+		run.Synced, run.Err = runDaemonPass(e, runLimit)
+		catchUp = false
+		run.Took = time.Since(run.Started)
+		e.nextRun()
+
+		// Failures are reported per run; the state keeps them for retry-failed
+		run.Failures = len(runFailures)
+		printFailureSummary()
+		runFailures = nil
+
+		if ctx.Err() != nil {
+			fmt.Println("\n🛑 Daemon stopped")
+			return nil
+		}
+		next := run.Started.Add(interval)
+		if next.Before(time.Now()) {
+			next = time.Now()
+		}
+		if quiet.quiet(next) {
+			next = quiet.nextOpen(next)
+			catchUp = true
+		}
+		timer.Reset(time.Until(next))
+		fmt.Printf("\n%s\n", run.statusLine(next))
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// DailyNoteThresholds decides whether a day's meetings warrant a new daily note
+type DailyNoteThresholds struct {
+	Disabled    bool          // never create daily notes (meetings only)
+	MinMeetings int           // minimum meetings on the day
+	MinDuration time.Duration // minimum total meeting time on the day
+}
+
+// dailyNoteThresholdsFromEnv reads DAILY_NOTES, DAILY_NOTE_MIN_MEETINGS and DAILY_NOTE_MIN_DURATION
+func dailyNoteThresholdsFromEnv() (DailyNoteThresholds, error) {
+	th := DailyNoteThresholds{
+		Disabled:    !envBoolDefault("DAILY_NOTES", true),
+		MinMeetings: 1,
+	}
+
+	if v := os.Getenv("DAILY_NOTE_MIN_MEETINGS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return th, fmt.Errorf("invalid DAILY_NOTE_MIN_MEETINGS %q: must be a non-negative number", v)
+		}
+		th.MinMeetings = n
+	}
+
+	if v := os.Getenv("DAILY_NOTE_MIN_DURATION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return th, fmt.Errorf("invalid DAILY_NOTE_MIN_DURATION %q: must be a duration like 10m", v)
+		}
+		th.MinDuration = d
+	}
+
+	return th, nil
+}
+
+// Allows reports whether a daily note should be created for a day's meetings,
+// with the reason when it shouldn't
+func (th DailyNoteThresholds) Allows(dayMeetings []*MeetingWithSummary, cache *Cache) (bool, string) {
+	if th.Disabled {
+		return false, "daily notes disabled"
+	}
+	if len(dayMeetings) < th.MinMeetings {
+		return false, fmt.Sprintf("%d meeting(s), minimum is %d", len(dayMeetings), th.MinMeetings)
+	}
+
+	if th.MinDuration > 0 {
+		var total time.Duration
+		for _, mws := range dayMeetings {
+			total += time.Duration(meetingDurationSeconds(mws.Meeting, cache)) * time.Second
+		}
+		if total < th.MinDuration {
+			return false, fmt.Sprintf("%s of meetings, minimum is %s", total.Round(time.Second), th.MinDuration)
+		}
+	}
+
+	return true, ""
+}
+
+// syncedDays indexes the meetings synced to the vault in earlier runs by day, so a day's
+// thresholds also count meetings that arrived in earlier syncs. Built on first use.
+type syncedDays struct {
+	state *SyncState
+	cache *Cache
+	byDay map[string][]*MeetingWithSummary
+}
+
+// withEarlier returns a day's meetings from this batch plus the ones synced before
+func (d *syncedDays) withEarlier(date string, dayMeetings []*MeetingWithSummary) []*MeetingWithSummary {
+	if d.byDay == nil {
+		d.byDay = make(map[string][]*MeetingWithSummary)
+		for id := range d.state.ObsidianSyncedMeetings {
+			meeting, err := d.cache.LoadMeeting(id)
+			if err != nil {
+				continue
+			}
+			key := meeting.CreatedAt.Local().Format("2006-01-02")
+			d.byDay[key] = append(d.byDay[key], &MeetingWithSummary{Meeting: meeting})
+		}
+	}
+
+	all := append([]*MeetingWithSummary(nil), dayMeetings...)
+	seen := make(map[string]bool, len(dayMeetings))
+	for _, mws := range dayMeetings {
+		seen[mws.Meeting.ID] = true
+	}
+	for _, mws := range d.byDay[date] {
+		if !seen[mws.Meeting.ID] {
+			all = append(all, mws)
+		}
+	}
+	return all
+}
+
+// dailyNoteData returns the daily note template data for a day
+func dailyNoteData(vaultPath string, t time.Time) map[string]string {
+	return map[string]string{
+		"Date":      t.Local().Format("2006-01-02"),
+		"YearPath":  vaultRelative(vaultPath, filepath.Dir(monthDir(vaultPath, t))),
+		"MonthPath": filepath.Base(monthFolder(t)),
+	}
+}
+
+// renderDailyNote renders a new daily note for a day
+func renderDailyNote(vaultPath string, t time.Time) ([]byte, error) {
+	tmpl, err := template.New("dailynote").Parse(dailyNoteTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse daily note template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, dailyNoteData(vaultPath, t)); err != nil {
+		return nil, fmt.Errorf("failed to render daily note template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tag dashboard defaults
+const (
+	defaultDashboardDir         = "Dashboards"
+	defaultDashboardMinMeetings = 3
+	dashboardHashKey            = "meetings_hash"
+)
+
+// tagDashboardsEnabled reports whether "all" runs keep a dashboard note per top-level tag
+// (TAG_DASHBOARDS)
+func tagDashboardsEnabled() bool {
+	return envBool("TAG_DASHBOARDS")
+}
+
+// dashboardMinMeetings reads TAG_DASHBOARD_MIN_MEETINGS, how many meetings a tag needs
+// before it gets a dashboard
+func dashboardMinMeetings() int {
+	v := strings.TrimSpace(os.Getenv("TAG_DASHBOARD_MIN_MEETINGS"))
+	if v == "" {
+		return defaultDashboardMinMeetings
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		fmt.Printf("⚠ Ignoring invalid TAG_DASHBOARD_MIN_MEETINGS %q\n", v)
+		return defaultDashboardMinMeetings
+	}
+	return n
+}
+
+// dashboardPath returns the path of a tag's dashboard note (TAG_DASHBOARD_DIR, vault-relative)
+func dashboardPath(vaultPath, tag string) string {
+	return filepath.Join(vaultPath, firstNonEmpty(os.Getenv("TAG_DASHBOARD_DIR"), defaultDashboardDir), sanitizeNoteName(tag)+".md")
+}
+
+// topLevelTag returns the first segment of a nested tag ("project/apollo" -> "project")
+func topLevelTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	top, _, _ := strings.Cut(tag, "/")
+	return top
+}
+
+// collectTagMeetings returns the meetings whose summary notes carry each top-level tag
+// (or a tag nested under it), by tag
+func collectTagMeetings(vaultPath string) (map[string][]string, error) {
+	byTag := make(map[string]map[string]bool)
+	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(info.Name(), ".md") || strings.HasSuffix(info.Name(), "-transcript.md") {
+			return nil
+		}
+		fm, _, err := parseFrontmatter(path)
+		if err != nil || (fm["meeting_id"] == nil && fm["krisp_meeting_id"] == nil) {
+			return nil
+		}
+		id := noteMeetingID(path, fm)
+		for _, tag := range frontmatterList(fm["tags"]) {
+			top := topLevelTag(tag)
+			if top == "" {
+				continue
+			}
+			if byTag[top] == nil {
+				byTag[top] = make(map[string]bool)
+			}
+			byTag[top][id] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning vault for meeting tags: %w", err)
+	}
+
+	meetings := make(map[string][]string, len(byTag))
+	for tag, ids := range byTag {
+		for id := range ids {
+			meetings[tag] = append(meetings[tag], id)
+		}
+		sort.Strings(meetings[tag])
+	}
+	return meetings, nil
+}
+
+// renderTagDashboard renders a tag's dashboard: Dataview blocks for its recent meetings,
+// open action items and the people involved. hash identifies the meeting set it was made for.
+func renderTagDashboard(tag string, meetingCount int, hash string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("type: tag-dashboard\n")
+	sb.WriteString(fmt.Sprintf("tag: \"%s\"\n", tag))
+	sb.WriteString(fmt.Sprintf("meetings: %d\n", meetingCount))
+	sb.WriteString(fmt.Sprintf("%s: \"%s\"\n", dashboardHashKey, hash))
+	sb.WriteString("---\n\n")
+	sb.WriteString(fmt.Sprintf("# %s\n\n", tag))
+	sb.WriteString(fmt.Sprintf("%d meetings tagged `#%s`. Regenerated when they change - edits will be overwritten.\n\n", meetingCount, tag))
+
+	sb.WriteString("## Recent Meetings\n\n")
+	sb.WriteString("```dataview\n")
+	sb.WriteString(fmt.Sprintf("TABLE date AS Date, description AS Description\nFROM #%s\nWHERE type = \"meeting\"\nSORT date DESC\nLIMIT 20\n", tag))
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("## Open Action Items\n\n")
+	sb.WriteString("```dataview\n")
+	sb.WriteString(fmt.Sprintf("TASK\nFROM #%s\nWHERE !completed AND type = \"meeting\"\nGROUP BY file.link\n", tag))
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("## People Involved\n\n")
+	sb.WriteString("```dataview\n")
+	sb.WriteString(fmt.Sprintf("TABLE WITHOUT ID person AS Person, length(rows) AS Meetings, max(rows.date) AS \"Last Meeting\"\nFROM #%s\nWHERE type = \"meeting\"\nFLATTEN choice(typeof(participants) = \"array\", participants, split(participants, \", \")) AS person\nGROUP BY person\nSORT length(rows) DESC\n", tag))
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// runTagDashboards writes a dashboard note for every top-level tag on at least
+// TAG_DASHBOARD_MIN_MEETINGS meetings. A dashboard is only rewritten when its tag's
+// meetings changed, unless force is set.
+func runTagDashboards(vaultPath string, force bool) error {
+	fmt.Println("\n=== Dashboards: Tag dashboards ===")
+
+	tagMeetings, err := collectTagMeetings(vaultPath)
+	if err != nil {
+		return err
+	}
+	minMeetings := dashboardMinMeetings()
+
+	tags := make([]string, 0, len(tagMeetings))
+	for tag, ids := range tagMeetings {
+		if len(ids) >= minMeetings {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	written := 0
+	for _, tag := range tags {
+		ids := tagMeetings[tag]
+		hash := contentHash([]byte(strings.Join(ids, ",")))[:16]
+		path := dashboardPath(vaultPath, tag)
+		if !force && vaultWriter.Exists(path) {
+			if fm, _, err := parseFrontmatter(path); err == nil && fmt.Sprintf("%v", fm[dashboardHashKey]) == hash {
+				continue
+			}
+		}
+		if err := vaultWriter.CreateNote(path, []byte(renderTagDashboard(tag, len(ids), hash))); err != nil {
+			fmt.Printf("  ⚠ Error writing dashboard for %s: %v\n", tag, err)
+			continue
+		}
+		fmt.Printf("  📊 %s (%d meetings)\n", vaultRelative(vaultPath, path), len(ids))
+		written++
+	}
+	fmt.Printf("✅ %d tag dashboard(s), %d updated\n", len(tags), written)
+	return nil
+}
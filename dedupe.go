@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Dedupe against notes written by hand or by other tools
+const (
+	defaultDedupeWindow = 15 * time.Minute
+	krispSectionHeading = "## Krisp Summary"
+)
+
+// existingNote is a meeting note in the vault that wasn't written by krisp-sync
+type existingNote struct {
+	Path  string
+	Date  string    // YYYY-MM-DD
+	Start time.Time // zero when the note has no time
+	Title string
+}
+
+// DedupeIndex finds existing notes for the same meeting as a Krisp recording
+type DedupeIndex struct {
+	window time.Duration
+	notes  map[string][]existingNote // date -> notes
+	merged map[string]string         // meeting ID -> note it was already merged into
+}
+
+// dedupeWindow returns how far apart a note's time and the recording's start may be (VAULT_DEDUPE_WINDOW)
+func dedupeWindow() (time.Duration, error) {
+	v := os.Getenv("VAULT_DEDUPE_WINDOW")
+	if v == "" {
+		return defaultDedupeWindow, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid VAULT_DEDUPE_WINDOW %q: must be a duration like 15m", v)
+	}
+	return d, nil
+}
+
+// frontmatterDate reads a date field, which YAML may have decoded as a time or a string
+func frontmatterDate(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format("2006-01-02")
+	case string:
+		if t, err := time.Parse("2006-01-02", strings.TrimSpace(v)); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// buildDedupeIndex indexes dated notes in the vault, skipping krisp-sync's own meeting notes
+func buildDedupeIndex(vaultPath string) (*DedupeIndex, error) {
+	window, err := dedupeWindow()
+	if err != nil {
+		return nil, err
+	}
+	index := &DedupeIndex{window: window, notes: make(map[string][]existingNote), merged: make(map[string]string)}
+
+	err = filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if !strings.HasSuffix(name, ".md") || strings.HasSuffix(name, "-summary.md") || strings.HasSuffix(name, "-transcript.md") || strings.HasSuffix(name, "-minutes.md") {
+			return nil
+		}
+
+		fm, _, err := parseFrontmatter(path)
+		if err != nil {
+			return nil
+		}
+		if id, ok := fm["krisp_meeting_id"].(string); ok && id != "" {
+			index.merged[id] = path
+			return nil
+		}
+		date := frontmatterDate(fm["date"])
+		if date == "" {
+			return nil
+		}
+
+		note := existingNote{Path: path, Date: date, Title: strings.TrimSuffix(name, ".md")}
+		if title, ok := fm["title"].(string); ok && title != "" {
+			note.Title = title
+		}
+		if clock, ok := fm["time"].(string); ok {
+			if t, err := time.ParseInLocation("2006-01-02 15:04", date+" "+strings.TrimSpace(clock), time.Local); err == nil {
+				note.Start = t
+			}
+		}
+		index.notes[date] = append(index.notes[date], note)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning vault for existing notes: %w", err)
+	}
+	return index, nil
+}
+
+var titleNoiseRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sameTitle compares titles ignoring case and punctuation; one containing the other also matches
+func sameTitle(a, b string) bool {
+	a = strings.TrimSpace(titleNoiseRegex.ReplaceAllString(strings.ToLower(a), " "))
+	b = strings.TrimSpace(titleNoiseRegex.ReplaceAllString(strings.ToLower(b), " "))
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// Match returns the existing note for a meeting, or "". A note the meeting was merged
+// into before always matches. Otherwise a note matches when it is on the same day and
+// either starts within the window or has the same title; a note whose time is outside
+// the window never matches.
+func (idx *DedupeIndex) Match(m *Meeting, titles ...string) string {
+	if idx == nil {
+		return ""
+	}
+	if path, ok := idx.merged[m.ID]; ok {
+		return path
+	}
+	start := m.CreatedAt.Local()
+	for _, note := range idx.notes[start.Format("2006-01-02")] {
+		if !note.Start.IsZero() {
+			diff := note.Start.Sub(start)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff <= idx.window {
+				return note.Path
+			}
+			continue
+		}
+		for _, title := range titles {
+			if sameTitle(note.Title, title) {
+				return note.Path
+			}
+		}
+	}
+	return ""
+}
+
+// Claim records that a meeting was merged into a note, so no other meeting matches it
+func (idx *DedupeIndex) Claim(m *Meeting, path string) {
+	idx.merged[m.ID] = path
+	for date, notes := range idx.notes {
+		for i, note := range notes {
+			if note.Path == path {
+				idx.notes[date] = append(notes[:i:i], notes[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// mergeIntoExistingNote appends the generated summary under a marked section of an existing
+// note and links the note to the meeting. Re-syncing replaces the section.
+func mergeIntoExistingNote(path string, m *Meeting, sections []summarySection, templateData map[string]interface{}) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<!-- krisp-sync: generated from Krisp meeting %s; edits here are replaced on re-sync -->\n\n", m.ID))
+	// Confidential meetings have no transcript note to link to
+	if link, _ := templateData["TranscriptLink"].(string); link != "" {
+		sb.WriteString(fmt.Sprintf("**Transcript**: [[%s-transcript|View Transcript]]\n\n", m.ID))
+	}
+	for _, section := range sections {
+		// The existing note keeps its own description; the transcript link is written above.
+		// The whole note is already yours, so it gets no empty user sections.
+		if section.Key == "description" || section.Key == "transcript" || section.Key == userSectionsKey {
+			continue
+		}
+		if text, ok := templateData[section.Var].(string); ok && text != "" {
+			// Demote section headings so they nest under the Krisp section
+			sb.WriteString(demoteHeadings(text))
+			sb.WriteString("\n")
+		}
+	}
+
+	if err := vaultWriter.UpsertSection(path, krispSectionHeading, sb.String()); err != nil {
+		return err
+	}
+	fields := map[string]interface{}{"krisp_meeting_id": m.ID}
+	if propertiesState {
+		fields[syncedVersionKey] = currentSyncedVersion
+	}
+	return vaultWriter.UpdateFrontmatter(path, fields)
+}
+
+// demoteHeadings turns "## X" headings into "### X" (and so on)
+func demoteHeadings(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if level := headingLevel(line); level > 0 && level < 6 {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
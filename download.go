@@ -2,53 +2,114 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 )
 
+// downloadsRemaining counts the meetings the last download stage found but didn't download
+// (because of the limit, a failure or stopping early), so backfill knows when it's done
+var downloadsRemaining int
+
 // Stage 1: Download meetings from Krisp API and cache them locally
 func runDownload(ctx context.Context, limit int, syncState *SyncState, overwrite bool, meetingIDs []string, cache *Cache) error {
 	fmt.Println("\n=== Stage 1: Downloading meetings ===")
 
 	// Handle specific meeting IDs mode
 	if len(meetingIDs) > 0 {
+		if dryRun {
+			fmt.Printf("🔍 Would re-download %d meeting(s): %s\n", len(meetingIDs), strings.Join(meetingIDs, ", "))
+			return nil
+		}
 		fmt.Printf("🎯 Re-downloading %d specific meeting(s) from Krisp API\n", len(meetingIDs))
 		for _, meetingID := range meetingIDs {
-			fullMeeting, err := fetchMeeting(ctx, meetingID)
+			fullMeeting, err := fetchAccountMeeting(ctx, cache, meetingID)
 			if err != nil {
 				fmt.Printf("❌ Error fetching meeting %s: %v\n", meetingID, err)
+				recordFailure(syncState, stageDownload, meetingID, err)
 				continue
 			}
 
 			// Save to cache (overwriting existing)
-			if err := cache.SaveMeeting(fullMeeting); err != nil {
+			if err := saveDownloadedMeeting(cache, fullMeeting); err != nil {
 				fmt.Printf("  ⚠ Error saving to cache: %v\n", err)
+				recordFailure(syncState, stageDownload, meetingID, err)
 				continue
 			}
 
-			syncState.SyncedMeetings[fullMeeting.ID] = true
-			fmt.Printf("  ✓ Re-downloaded and cached: %s\n", meetingID)
+			downloadAttachments(ctx, fullMeeting, cache)
 
-			// Save state
-			if err := syncState.Save(); err != nil {
-				fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+			syncState.MarkDownloaded(fullMeeting.ID)
+			if transcriptReady(fullMeeting) {
+				syncState.DequeueTranscript(fullMeeting.ID)
+				syncState.CheckTranscript(fullMeeting)
+			} else {
+				syncState.QueueTranscript(fullMeeting)
 			}
+			fmt.Printf("  ✓ Re-downloaded and cached: %s\n", meetingID)
 		}
 		fmt.Printf("\n✅ Re-downloaded %d meeting(s)\n", len(meetingIDs))
 		return nil
 	}
 
-	// Fetch all meetings from API
-	allMeetings, err := fetchAllMeetings(ctx)
+	downloadsRemaining = 0
+
+	// Pick up transcripts that weren't ready on earlier runs
+	retryQueuedTranscripts(ctx, syncState, cache)
+	retryTruncatedTranscripts(ctx, syncState, cache)
+	defer printTranscriptQueue(syncState)
+
+	// The limit is for the whole run, shared by all accounts
+	left := limit
+	return forEachAccount(func(api *KrispClient) error {
+		if limit > 0 && left <= 0 {
+			return nil
+		}
+		n, err := downloadNewMeetings(ctx, api, left, syncState, overwrite, cache)
+		left -= n
+		return err
+	})
+}
+
+// downloadNewMeetings lists an account's meetings and downloads the ones not cached yet.
+// Returns how many meetings it set out to download, which count towards the limit.
+func downloadNewMeetings(ctx context.Context, api *KrispClient, limit int, syncState *SyncState, overwrite bool, cache *Cache) (int, error) {
+	// One request tells whether anything was added since the last complete listing
+	if !overwrite && checkListing(ctx, api, syncState) {
+		return 0, nil
+	}
+
+	// Fetch meetings from API, resuming from the pagination cursor unless overwriting
+	cursor := syncState.listCursor(api.account)
+	if overwrite {
+		cursor = nil
+	} else if cursor != nil {
+		fmt.Printf("⏩ Resuming listing from page %d (meetings since %s)\n", cursor.Page, cursor.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	pages, err := api.ListMeetingsSince(ctx, cursor)
 	if err != nil {
-		return fmt.Errorf("error fetching meetings: %w", err)
+		return 0, fmt.Errorf("error fetching meetings: %w", err)
+	}
+
+	var allMeetings []MeetingSummary
+	for _, p := range pages {
+		allMeetings = append(allMeetings, p.Rows...)
 	}
 
 	fmt.Printf("📊 Total meetings fetched from API: %d\n", len(allMeetings))
 
+	// Advance the cursor once this run's downloads are done, and fingerprint the listing
+	// if every meeting in it is now cached
+	defer func() {
+		syncState.setListCursor(api.account, advanceListCursor(syncState.listCursor(api.account), pages, cache))
+		syncState.setListingFingerprint(api.account, fingerprintListing(pages, cache))
+	}()
+
 	// Filter to only meetings not yet downloaded (unless overwrite is set)
 	var toDownload []MeetingSummary
 	for _, m := range allMeetings {
-		if overwrite || !cache.MeetingExists(m.ID) {
+		if (overwrite && cache.MergedInto(m.ID) == "") || !cache.MeetingExists(m.ID) {
 			toDownload = append(toDownload, m)
 		}
 	}
@@ -59,10 +120,11 @@ func runDownload(ctx context.Context, limit int, syncState *SyncState, overwrite
 
 	if len(toDownload) == 0 {
 		fmt.Println("✅ All meetings already cached!")
-		return nil
+		return 0, nil
 	}
 
 	fmt.Printf("Found %d meeting(s) to download\n", len(toDownload))
+	downloadsRemaining += len(toDownload)
 
 	// Apply limit
 	if limit > 0 && len(toDownload) > limit {
@@ -70,37 +132,77 @@ func runDownload(ctx context.Context, limit int, syncState *SyncState, overwrite
 		toDownload = toDownload[:limit]
 	}
 
+	if dryRun {
+		fmt.Println("🔍 Dry run: meetings that would be downloaded (not cached yet, so summarize and sync leave them out)")
+		for _, m := range toDownload {
+			fmt.Printf("  %s  %s (%s)\n", m.CreatedAt.Local().Format("2006-01-02 15:04"), m.Title, m.ID)
+		}
+		return len(toDownload), nil
+	}
+
 	// Download and cache each meeting
+	downloaded := 0
 	for i, meetingSummary := range toDownload {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
 			fmt.Printf("\n⚠ Download cancelled\n")
-			return ctx.Err()
+			return len(toDownload), ctx.Err()
+		}
+		if budgetStop("download") {
+			break
 		}
 
 		fmt.Printf("[%d/%d] Downloading: %s\n", i+1, len(toDownload), meetingSummary.Title)
+		meetingCtx, span := startMeetingSpan(ctx, "download", meetingSummary.ID)
 
-		fullMeeting, err := fetchMeeting(ctx, meetingSummary.ID)
+		fullMeeting, err := api.GetMeeting(meetingCtx, meetingSummary.ID)
+		if errors.Is(err, errKrispUnauthorized) {
+			// Every other meeting would fail the same way
+			endSpan(span, err)
+			return len(toDownload), fmt.Errorf("error fetching meeting: %w", err)
+		}
+		if errors.Is(err, errKrispRateLimited) {
+			fmt.Println("  ⚠ Krisp is rate limiting requests, stopping; the rest download on the next run")
+			endSpan(span, err)
+			break
+		}
 		if err != nil {
 			fmt.Printf("  ⚠ Error fetching meeting: %v\n", err)
+			recordFailure(syncState, stageDownload, meetingSummary.ID, err)
+			endSpan(span, err)
 			continue
 		}
+		fullMeeting.Account = api.account
 
 		// Save to cache
-		if err := cache.SaveMeeting(fullMeeting); err != nil {
+		if err := saveDownloadedMeeting(cache, fullMeeting); err != nil {
 			fmt.Printf("  ⚠ Error saving to cache: %v\n", err)
+			recordFailure(syncState, stageDownload, meetingSummary.ID, err)
+			endSpan(span, err)
 			continue
 		}
 
-		syncState.SyncedMeetings[fullMeeting.ID] = true
-		fmt.Printf("  ✓ Cached: meetings/%s.json\n", fullMeeting.ID)
+		downloadAttachments(meetingCtx, fullMeeting, cache)
+
+		syncState.MarkDownloaded(fullMeeting.ID)
+		downloadsRemaining--
+		downloaded++
+		fmt.Printf("  ✓ Cached: %s\n", filepath.Join(cache.dir, fullMeeting.ID+".json"))
 
-		// Save state after each download
-		if err := syncState.Save(); err != nil {
-			fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+		// Transcripts still processing are retried on later runs
+		if !transcriptReady(fullMeeting) {
+			syncState.QueueTranscript(fullMeeting)
+			fmt.Printf("  ⏳ Transcript %s, queued for retry\n", fullMeeting.Resources.Transcript.Status)
+		} else {
+			syncState.CheckTranscript(fullMeeting)
 		}
+		span.End()
 	}
 
-	fmt.Printf("\n✅ Downloaded %d meeting(s)\n", len(toDownload))
-	return nil
+	if downloaded < len(toDownload) {
+		fmt.Printf("\n⚠ Downloaded %d of %d meeting(s)\n", downloaded, len(toDownload))
+	} else {
+		fmt.Printf("\n✅ Downloaded %d meeting(s)\n", downloaded)
+	}
+	return len(toDownload), nil
 }
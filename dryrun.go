@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// dryRun is set by --dry-run: download, summarize and sync report what they would do
+// without fetching meetings, calling the LLM, or writing the cache, the state or the vault
+var dryRun bool
+
+// DryRunVaultWriter keeps writes in memory on top of the vault, so a dry run reads back
+// what it would have written, and reports them instead of writing
+type DryRunVaultWriter struct {
+	inner     VaultWriter
+	vaultPath string
+	mu        sync.Mutex
+	pending   map[string][]byte // path -> content the run would leave; nil when deleted
+}
+
+// newDryRunVaultWriter wraps a writer, keeping every write in memory
+func newDryRunVaultWriter(inner VaultWriter, vaultPath string) *DryRunVaultWriter {
+	return &DryRunVaultWriter{inner: inner, vaultPath: vaultPath, pending: make(map[string][]byte)}
+}
+
+func (w *DryRunVaultWriter) Exists(path string) bool {
+	w.mu.Lock()
+	content, ok := w.pending[filepath.Clean(path)]
+	w.mu.Unlock()
+	if ok {
+		return content != nil
+	}
+	return w.inner.Exists(path)
+}
+
+func (w *DryRunVaultWriter) ReadNote(path string) ([]byte, error) {
+	w.mu.Lock()
+	content, ok := w.pending[filepath.Clean(path)]
+	w.mu.Unlock()
+	if !ok {
+		return w.inner.ReadNote(path)
+	}
+	if content == nil {
+		return nil, fmt.Errorf("open %s: %w", path, os.ErrNotExist)
+	}
+	return append([]byte(nil), content...), nil
+}
+
+func (w *DryRunVaultWriter) CreateNote(path string, content []byte) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[filepath.Clean(path)] = append([]byte{}, content...)
+	return nil
+}
+
+func (w *DryRunVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	for _, path := range paths {
+		if err := w.CreateNote(path, contents[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *DryRunVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *DryRunVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *DryRunVaultWriter) DeleteNote(path string) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	if !w.Exists(path) {
+		return os.ErrNotExist
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[filepath.Clean(path)] = nil
+	return nil
+}
+
+// MoveFolder stages a folder's move as its files created under dest and deleted from src
+func (w *DryRunVaultWriter) MoveFolder(src, dest string) error {
+	if err := checkVaultWrite(src, true); err != nil {
+		return err
+	}
+	files, err := folderFiles(src)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	prefix := filepath.Clean(src) + string(filepath.Separator)
+	w.mu.Lock()
+	for path, content := range w.pending {
+		if content != nil && strings.HasPrefix(path, prefix) && !w.inner.Exists(path) {
+			files = append(files, path)
+		}
+	}
+	w.mu.Unlock()
+
+	var existing []string
+	for _, path := range files {
+		if w.Exists(path) {
+			existing = append(existing, path)
+		}
+	}
+	if len(existing) == 0 {
+		return os.ErrNotExist
+	}
+	return moveFolderFiles(w, existing, src, dest)
+}
+
+// Report lists the vault files the run would have created, modified and deleted
+func (w *DryRunVaultWriter) Report() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var created, modified, deleted []string
+	for path, content := range w.pending {
+		rel := vaultRelative(w.vaultPath, path)
+		switch {
+		case !w.inner.Exists(path):
+			if content != nil {
+				created = append(created, rel)
+			}
+		case content == nil:
+			deleted = append(deleted, rel)
+		default:
+			if existing, err := w.inner.ReadNote(path); err != nil || !sameNoteContent(existing, content) {
+				modified = append(modified, fmt.Sprintf("%s (%s)", rel, lineChanges(existing, content)))
+			}
+		}
+	}
+
+	fmt.Println("\n=== Dry run: vault changes ===")
+	if len(created)+len(modified)+len(deleted) == 0 {
+		fmt.Println("✅ No vault files would change")
+		return
+	}
+	for _, group := range []struct {
+		icon, action string
+		paths        []string
+	}{{"📝", "create", created}, {"✏️ ", "modify", modified}, {"🗑 ", "delete", deleted}} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		sort.Strings(group.paths)
+		fmt.Printf("%s Would %s %d file(s):\n", group.icon, group.action, len(group.paths))
+		for _, path := range group.paths {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+}
+
+// lineChanges summarizes how many lines a change adds and removes, e.g. "+3 -1 lines"
+func lineChanges(before, after []byte) string {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(string(before), "\n") {
+		counts[line]++
+	}
+	added := 0
+	for _, line := range strings.Split(string(after), "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	removed := 0
+	for _, n := range counts {
+		removed += n
+	}
+	if added == 0 && removed == 0 && !bytes.Equal(before, after) {
+		return "reordered lines"
+	}
+	return fmt.Sprintf("+%d -%d lines", added, removed)
+}
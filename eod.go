@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+//go:embed eod-prompt.md
+var eodPromptTemplate string
+
+// eodHeading is the daily note section holding the end-of-day wrap-up
+const eodHeading = "## Meeting Wrap"
+
+// daySummaries returns a day's cached meetings that have summaries, oldest first. Confidential
+// meetings are left out of the wrap-up.
+func daySummaries(cache *Cache, day time.Time) ([]*Meeting, map[string]*SummaryData, error) {
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	date := day.Local().Format("2006-01-02")
+	var meetings []*Meeting
+	summaries := make(map[string]*SummaryData)
+	for _, id := range ids {
+		if !cache.SummaryExists(id) {
+			continue
+		}
+		m, err := cache.LoadMeeting(id)
+		if err != nil || m.CreatedAt.Local().Format("2006-01-02") != date {
+			continue
+		}
+		summary, err := cache.LoadSummary(id)
+		if err != nil || isConfidential(m, summary) {
+			continue
+		}
+		meetings = append(meetings, m)
+		summaries[id] = summary
+	}
+
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].CreatedAt.Before(meetings[j].CreatedAt)
+	})
+	return meetings, summaries, nil
+}
+
+// wrapUp asks the LLM for the day's key outcomes and tomorrow's follow-ups
+func wrapUp(ctx context.Context, day time.Time, meetings []*Meeting, summaries map[string]*SummaryData) ([]string, []string, error) {
+	var sb strings.Builder
+	for _, m := range meetings {
+		s := summaries[m.ID]
+		sb.WriteString(fmt.Sprintf("### %s (%s)\n%s\n\n%s\n\n", firstNonEmpty(s.Title, m.Title), m.CreatedAt.Local().Format("15:04"), s.Description, s.Summary))
+	}
+
+	tmpl, err := template.New("eod").Parse(eodPromptTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse eod template: %w", err)
+	}
+	var promptBuf bytes.Buffer
+	if err := tmpl.Execute(&promptBuf, map[string]string{
+		"Date":     day.Local().Format("2006-01-02"),
+		"Meetings": sb.String(),
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to execute eod template: %w", err)
+	}
+
+	schema := &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"outcomes": {
+				Type:        genai.TypeArray,
+				Description: "Key decisions and results of the day, one short sentence each",
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+			"follow_ups": {
+				Type:        genai.TypeArray,
+				Description: "What to pick up tomorrow, one short sentence each",
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+		},
+		Required: []string{"outcomes", "follow_ups"},
+	}
+
+	response, err := generateStructured(ctx, promptBuf.String(), schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var data struct {
+		Outcomes  []string `json:"outcomes"`
+		FollowUps []string `json:"follow_ups"`
+	}
+	if err := json.Unmarshal([]byte(repairJSON(response)), &data); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse wrap-up: %w", err)
+	}
+	return data.Outcomes, data.FollowUps, nil
+}
+
+// renderWrapUp renders the body of the daily note's wrap-up section
+func renderWrapUp(meetings []*Meeting, summaries map[string]*SummaryData, outcomes, followUps []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("### Key Outcomes\n")
+	if len(outcomes) == 0 {
+		sb.WriteString("- _None_\n")
+	}
+	for _, o := range outcomes {
+		sb.WriteString(fmt.Sprintf("- %s\n", o))
+	}
+
+	sb.WriteString("\n### My Action Items\n")
+	mine := 0
+	for _, m := range meetings {
+		s := summaries[m.ID]
+		for _, item := range s.ActionItems {
+			if !item.Mine() {
+				continue
+			}
+			box := " "
+			if item.Done {
+				box = "x"
+			}
+			sb.WriteString(fmt.Sprintf("- [%s] %s ([[%s-summary|%s]])\n", box, item.Text, m.ID, firstNonEmpty(s.Title, m.Title)))
+			mine++
+		}
+	}
+	if mine == 0 {
+		if len(myNames()) == 0 {
+			sb.WriteString("- _Set MY_NAME to list action items assigned to you_\n")
+		} else {
+			sb.WriteString("- _None_\n")
+		}
+	}
+
+	sb.WriteString("\n### Follow-ups for Tomorrow\n")
+	if len(followUps) == 0 {
+		sb.WriteString("- _None_\n")
+	}
+	for _, f := range followUps {
+		sb.WriteString(fmt.Sprintf("- [ ] %s\n", f))
+	}
+
+	return sb.String()
+}
+
+// runEOD writes today's end-of-day wrap-up into the daily note, creating the note if needed
+func runEOD(ctx context.Context, vaultPath string, cache *Cache) error {
+	fmt.Println("\n=== End-of-day wrap-up ===")
+
+	today := time.Now()
+	meetings, summaries, err := daySummaries(cache, today)
+	if err != nil {
+		return err
+	}
+	if len(meetings) == 0 {
+		fmt.Println("⚠ No summarized meetings today")
+		return nil
+	}
+	fmt.Printf("📋 Wrapping up %d meeting(s)\n", len(meetings))
+
+	outcomes, followUps, err := wrapUp(ctx, today, meetings, summaries)
+	if err != nil {
+		return fmt.Errorf("failed to generate wrap-up: %w", err)
+	}
+
+	path := dailyNotePath(vaultPath, today)
+	if !vaultWriter.Exists(path) {
+		content, err := renderDailyNote(vaultPath, today)
+		if err != nil {
+			return err
+		}
+		if err := vaultWriter.CreateNote(path, content); err != nil {
+			return fmt.Errorf("failed to create daily note: %w", err)
+		}
+		fmt.Printf("  ✓ Created daily note: %s\n", dailyNoteFileName(today))
+	}
+
+	if err := vaultWriter.UpsertSection(path, eodHeading, renderWrapUp(meetings, summaries, outcomes, followUps)); err != nil {
+		return fmt.Errorf("failed to update daily note: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %s to %s (%d outcome(s), %d follow-up(s))\n", strings.TrimPrefix(eodHeading, "## "), dailyNoteFileName(today), len(outcomes), len(followUps))
+	return nil
+}
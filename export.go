@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Export formats for krisp-sync export
+const (
+	exportCSV     = "csv"
+	exportJSONL   = "jsonl"
+	exportParquet = "parquet"
+)
+
+// exportRow is one meeting in the flat export dataset
+type exportRow struct {
+	ID               string   `json:"id"`
+	Date             string   `json:"date"`
+	Time             string   `json:"time"`
+	Start            string   `json:"start"`
+	DurationSeconds  int      `json:"duration_seconds"`
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	Participants     []string `json:"participants"`
+	ParticipantCount int      `json:"participant_count"`
+	Teams            []string `json:"teams"`
+	Tags             []string `json:"tags"`
+	ActionItems      int      `json:"action_items"`
+	ActionItemsDone  int      `json:"action_items_done"`
+	Summarized       bool     `json:"summarized"`
+	Synced           bool     `json:"synced"`
+	Account          string   `json:"account"`
+}
+
+// exportColumns are the CSV header, in exportRow order
+var exportColumns = []string{"id", "date", "time", "start", "duration_seconds", "title", "description", "participants", "participant_count", "teams", "tags", "action_items", "action_items_done", "summarized", "synced", "account"}
+
+// csvRecord flattens a row for CSV; list fields are joined with "; "
+func (r exportRow) csvRecord() []string {
+	return []string{
+		r.ID, r.Date, r.Time, r.Start, strconv.Itoa(r.DurationSeconds), r.Title, r.Description,
+		strings.Join(r.Participants, "; "), strconv.Itoa(r.ParticipantCount), strings.Join(r.Teams, "; "),
+		strings.Join(r.Tags, "; "), strconv.Itoa(r.ActionItems), strconv.Itoa(r.ActionItemsDone),
+		strconv.FormatBool(r.Summarized), strconv.FormatBool(r.Synced), r.Account,
+	}
+}
+
+// buildExportRows collects every cached meeting as a flat row, oldest first
+func buildExportRows(syncState *SyncState, cache *Cache) ([]exportRow, error) {
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []exportRow
+	confidential := 0
+	for _, id := range ids {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			fmt.Printf("⚠ Error loading meeting %s: %v\n", id, err)
+			continue
+		}
+		var summary *SummaryData
+		if cache.SummaryExists(m.ID) {
+			summary, _ = cache.LoadSummary(m.ID)
+		}
+		if isConfidential(m, summary) {
+			confidential++
+			continue
+		}
+
+		participants := meetingParticipants(m)
+		start := m.CreatedAt.Local()
+		row := exportRow{
+			ID:               m.ID,
+			Date:             start.Format("2006-01-02"),
+			Time:             start.Format("15:04"),
+			Start:            start.Format("2006-01-02T15:04:05Z07:00"),
+			DurationSeconds:  meetingDurationSeconds(m, cache),
+			Title:            m.Title,
+			Account:          m.Account,
+			Participants:     participants,
+			ParticipantCount: len(participants),
+			Teams:            meetingTeams(m),
+			Tags:             []string{},
+			Synced:           syncState.ObsidianSyncedMeetings[m.ID],
+		}
+
+		if summary != nil {
+			row.Summarized = true
+			row.Title = firstNonEmpty(summary.Title, m.Title)
+			row.Description = summary.Description
+			for _, tag := range strings.Split(summary.Tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					row.Tags = append(row.Tags, tag)
+				}
+			}
+			row.ActionItems = len(summary.ActionItems)
+			for _, item := range summary.ActionItems {
+				if item.Done {
+					row.ActionItemsDone++
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if confidential > 0 {
+		fmt.Printf("🔒 Left out %d confidential meeting(s)\n", confidential)
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Start < rows[j].Start })
+	return rows, nil
+}
+
+// writeExportCSV writes rows as CSV with a header
+func writeExportCSV(path string, rows []exportRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := w.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeExportJSONL writes rows as newline-delimited JSON
+func writeExportJSONL(path string, rows []exportRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeExportParquet converts a JSONL export to Parquet with the DuckDB CLI
+func writeExportParquet(path string, rows []exportRow) error {
+	duckdb, err := exec.LookPath("duckdb")
+	if err != nil {
+		return fmt.Errorf("parquet export needs the duckdb CLI on PATH (or export jsonl and convert it yourself)")
+	}
+
+	// DuckDB reads the rows from a temporary JSONL file, removed once converted
+	tmp, err := os.CreateTemp("", "krisp-export-*.jsonl")
+	if err != nil {
+		return err
+	}
+	jsonlPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(jsonlPath)
+	if err := writeExportJSONL(jsonlPath, rows); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("COPY (SELECT * FROM read_json_auto('%s')) TO '%s' (FORMAT PARQUET)",
+		strings.ReplaceAll(jsonlPath, "'", "''"), strings.ReplaceAll(path, "'", "''"))
+	if output, err := exec.Command(duckdb, "-c", query).CombinedOutput(); err != nil {
+		return fmt.Errorf("duckdb failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// runExport writes a flat dataset of all cached meetings for spreadsheets, DuckDB and other BI tools
+func runExport(obsidianVaultPath string, syncState *SyncState, cache *Cache, format string) error {
+	fmt.Println("\n=== Exporting meetings dataset ===")
+
+	format = strings.ToLower(firstNonEmpty(format, exportCSV))
+	writers := map[string]func(string, []exportRow) error{
+		exportCSV:     writeExportCSV,
+		exportJSONL:   writeExportJSONL,
+		exportParquet: writeExportParquet,
+	}
+	write, ok := writers[format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q (expected csv, jsonl or parquet)", format)
+	}
+
+	outputDir := dataDir
+	if configured := os.Getenv("EXPORT_OUTPUT_DIR"); configured != "" {
+		dir, err := resolvePath(configured, obsidianVaultPath)
+		if err != nil {
+			return err
+		}
+		outputDir = dir
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create export output directory: %w", err)
+	}
+
+	rows, err := buildExportRows(syncState, cache)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		fmt.Println("⚠ No cached meetings to export. Run download step first.")
+		return nil
+	}
+
+	path := filepath.Join(outputDir, "krisp-meetings."+format)
+	if err := write(path, rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("✅ Exported %d meeting(s) to %s\n", len(rows), path)
+	return nil
+}
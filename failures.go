@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Stages a meeting can fail in
+const (
+	stageDownload  = "download"
+	stageSummarize = "summarize"
+	stageSync      = "sync"
+)
+
+// MeetingFailure records the last error a meeting hit, kept in state for krisp-sync retry-failed
+type MeetingFailure struct {
+	Stage    string    `json:"stage"`
+	Error    string    `json:"error"`
+	At       time.Time `json:"at"`
+	Attempts int       `json:"attempts"`
+}
+
+// runFailure is a per-meeting error from the current run
+type runFailure struct {
+	Stage     string
+	MeetingID string
+	Err       error
+}
+
+// runFailures collects this run's per-meeting errors for the failure summary
+var runFailures []runFailure
+
+// recordFailure notes that a meeting failed in a stage without stopping the stage
+func recordFailure(syncState *SyncState, stage, meetingID string, err error) {
+	syncState.mu.Lock()
+	defer syncState.mu.Unlock()
+	runFailures = append(runFailures, runFailure{Stage: stage, MeetingID: meetingID, Err: err})
+
+	if syncState.FailedMeetings == nil {
+		syncState.FailedMeetings = make(map[string]*MeetingFailure)
+	}
+	failure, ok := syncState.FailedMeetings[meetingID]
+	if !ok {
+		failure = &MeetingFailure{}
+		syncState.FailedMeetings[meetingID] = failure
+	}
+	failure.Stage = stage
+	failure.Error = err.Error()
+	failure.At = time.Now()
+	failure.Attempts++
+	syncState.pending++
+}
+
+// clearFailure forgets a meeting's failure once it gets through the stage it failed in.
+// The caller holds s.mu.
+func (s *SyncState) clearFailure(meetingID, stage string) {
+	failure, ok := s.FailedMeetings[meetingID]
+	if !ok || failure.Stage != stage {
+		return
+	}
+	delete(s.FailedMeetings, meetingID)
+	s.pending++
+}
+
+// failedMeetingIDs returns the IDs of meetings with a recorded failure, sorted
+func (s *SyncState) failedMeetingIDs() []string {
+	ids := make([]string, 0, len(s.FailedMeetings))
+	for id := range s.FailedMeetings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// stageOrder lists the stages in the order the pipeline runs them
+var stageOrder = []string{stageDownload, stageSummarize, stageSync}
+
+// retryMeetingIDs returns, per stage, the failed meetings that need to go through it again:
+// each meeting resumes at the stage it failed in and runs through the later ones
+func (s *SyncState) retryMeetingIDs() map[string][]string {
+	retry := make(map[string][]string)
+	for _, id := range s.failedMeetingIDs() {
+		resume := false
+		for _, stage := range stageOrder {
+			if stage == s.FailedMeetings[id].Stage {
+				resume = true
+			}
+			if resume {
+				retry[stage] = append(retry[stage], id)
+			}
+		}
+	}
+	return retry
+}
+
+// printFailureSummary prints a table of this run's per-meeting failures
+func printFailureSummary() {
+	if len(runFailures) == 0 {
+		return
+	}
+
+	fmt.Printf("\n❌ %d meeting failure(s):\n\n", len(runFailures))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tMEETING\tERROR")
+	for _, f := range runFailures {
+		msg := strings.ReplaceAll(f.Err.Error(), "\n", " ")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Stage, f.MeetingID, msg)
+	}
+	w.Flush()
+	fmt.Println("\nRetry with krisp-sync retry-failed")
+}
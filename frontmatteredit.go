@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterLines splits a note into its frontmatter lines (between the "---" delimiters,
+// each keeping its newline) and everything from the closing delimiter on
+func frontmatterLines(content []byte) ([]string, string, error) {
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return nil, "", fmt.Errorf("file does not have YAML frontmatter")
+	}
+	rest := string(content[4:])
+	if strings.HasPrefix(rest, "---\n") {
+		return nil, rest, nil
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		return nil, "", fmt.Errorf("malformed YAML frontmatter")
+	}
+	lines := strings.SplitAfter(rest[:end+1], "\n")
+	return lines[:len(lines)-1], rest[end+1:], nil
+}
+
+// frontmatterKeyLines maps each top-level frontmatter key to the lines it spans (start
+// inclusive, end exclusive). Comments and blank lines between two keys stay with the
+// following key, so rewriting a key never drops them.
+func frontmatterKeyLines(lines []string) (map[string][2]int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "")), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	spans := make(map[string][2]int)
+	if len(doc.Content) == 0 {
+		return spans, nil
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("frontmatter is not a mapping")
+	}
+
+	for i := 0; i < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		start, end := key.Line-1, len(lines)
+		if i+2 < len(mapping.Content) {
+			end = mapping.Content[i+2].Line - 1
+		}
+		// Leave trailing comments and blank lines to whatever follows
+		for end > start+1 {
+			line := lines[end-1]
+			if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "#") {
+				break
+			}
+			end--
+		}
+		spans[key.Value] = [2]int{start, end}
+	}
+	return spans, nil
+}
+
+// editFrontmatter sets and removes frontmatter properties of a note in place. Only the
+// lines of the properties it changes are rewritten; every other line, including comments,
+// multi-line strings and nested maps, is kept exactly as it was. New properties are added
+// at the end of the frontmatter.
+func editFrontmatter(content []byte, set map[string]interface{}, remove []string) ([]byte, error) {
+	lines, rest, err := frontmatterLines(content)
+	if err != nil {
+		return nil, err
+	}
+	spans, err := frontmatterKeyLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make(map[int]string) // start line -> rendered property ("" to drop it)
+	skip := make([]bool, len(lines))
+	edit := func(key, rendered string) bool {
+		span, ok := spans[key]
+		if !ok {
+			return false
+		}
+		replaced[span[0]] = rendered
+		for i := span[0]; i < span[1]; i++ {
+			skip[i] = true
+		}
+		return true
+	}
+
+	for _, key := range remove {
+		edit(key, "")
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var added bytes.Buffer
+	for _, key := range keys {
+		var buf bytes.Buffer
+		writeFrontmatterField(&buf, key, set[key])
+		if !edit(key, buf.String()) {
+			added.Write(buf.Bytes())
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	for i, line := range lines {
+		if rendered, ok := replaced[i]; ok {
+			sb.WriteString(rendered)
+		}
+		if !skip[i] {
+			sb.WriteString(line)
+		}
+	}
+	sb.Write(added.Bytes())
+	sb.WriteString(rest)
+	return []byte(sb.String()), nil
+}
+
+// editFrontmatterFile sets and removes frontmatter properties of a vault note in place
+func editFrontmatterFile(path string, set map[string]interface{}, remove []string) error {
+	content, err := vaultWriter.ReadNote(path)
+	if err != nil {
+		return err
+	}
+	updated, err := editFrontmatter(content, set, remove)
+	if err != nil {
+		return err
+	}
+	return vaultWriter.CreateNote(path, updated)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// fuzzyThresholds controls which tags fuzzy pre-processing treats as typos of each other
+type fuzzyThresholds struct {
+	MaxDistance   int     // Levenshtein distance at most this (FUZZY_MAX_DISTANCE)
+	MaxLengthDiff float64 // length difference at most this fraction of the longer tag (FUZZY_MAX_LENGTH_DIFF)
+	MinLength     int     // tags shorter than this are never typo-matched (FUZZY_MIN_LENGTH)
+}
+
+// defaultFuzzyThresholds are the thresholds fuzzy pre-processing has always used
+var defaultFuzzyThresholds = fuzzyThresholds{MaxDistance: 2, MaxLengthDiff: 0.2, MinLength: 4}
+
+// fuzzyThresholdsFromEnv reads FUZZY_MAX_DISTANCE, FUZZY_MAX_LENGTH_DIFF and FUZZY_MIN_LENGTH
+func fuzzyThresholdsFromEnv() (fuzzyThresholds, error) {
+	t := defaultFuzzyThresholds
+	if v := os.Getenv("FUZZY_MAX_DISTANCE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return t, fmt.Errorf("invalid FUZZY_MAX_DISTANCE %q: expected a number of edits (0 or more)", v)
+		}
+		t.MaxDistance = n
+	}
+	if v := os.Getenv("FUZZY_MAX_LENGTH_DIFF"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			return t, fmt.Errorf("invalid FUZZY_MAX_LENGTH_DIFF %q: expected a fraction between 0 and 1", v)
+		}
+		t.MaxLengthDiff = f
+	}
+	if v := os.Getenv("FUZZY_MIN_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return t, fmt.Errorf("invalid FUZZY_MIN_LENGTH %q: expected a length of 1 or more", v)
+		}
+		t.MinLength = n
+	}
+	return t, nil
+}
+
+// fuzzyNormalize returns the form tags are compared in: lowercase, without hyphens
+func fuzzyNormalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "-", ""))
+}
+
+// fuzzyTagsMatch reports whether tag a (normalized) is an obvious duplicate of tag b:
+// the same, a typo of it, its plural, a verb form of it, or it with a common suffix
+func fuzzyTagsMatch(a, b string, t fuzzyThresholds) bool {
+	if a == b {
+		return true
+	}
+
+	la, lb := len([]rune(a)), len([]rune(b))
+	if la >= t.MinLength && abs(la-lb) <= t.MaxDistance &&
+		float64(abs(la-lb))/float64(maxInt(la, lb)) <= t.MaxLengthDiff &&
+		fuzzy.LevenshteinDistance(a, b) <= t.MaxDistance {
+		return true
+	}
+
+	if isSingularPlural(a, b) || isVerbNounVariation(a, b) {
+		return true
+	}
+
+	// One is the other with a common suffix, e.g. "api" vs "apis"
+	if len(a) > 3 && len(b) > 3 {
+		shorter, longer := a, b
+		if len(b) < len(a) {
+			shorter, longer = b, a
+		}
+		if strings.HasPrefix(longer, shorter) && isCommonSuffix(strings.TrimPrefix(longer, shorter)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyStem returns the part of a tag its plural and verb forms start with ("query" for
+// "queries" is "quer")
+func fuzzyStem(s string) string {
+	return strings.TrimSuffix(s, "y")
+}
+
+// trigrams counts the trigrams of s, padded so its first and last letters are in three
+// trigrams like the rest
+func trigrams(s string) map[string]int {
+	runes := []rune("\x00\x00" + s + "\x00\x00")
+	grams := make(map[string]int)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])]++
+	}
+	return grams
+}
+
+// trigramPosting is a tag containing a trigram, and how often
+type trigramPosting struct {
+	tag   int
+	count int
+}
+
+// fuzzyTagIndex finds the tags that could match a tag without comparing it to every tag:
+// tags of a similar length sharing enough trigrams to be within the edit distance, and
+// tags one of which starts with the other's stem.
+type fuzzyTagIndex struct {
+	norms    []string
+	lengths  []int
+	grams    []map[string]int
+	postings map[string][]trigramPosting
+	byLength map[int][]int
+	byStem   map[string][]int
+	sorted   []int // tag indexes sorted by normalized form
+}
+
+// newFuzzyTagIndex indexes normalized tags
+func newFuzzyTagIndex(norms []string) *fuzzyTagIndex {
+	idx := &fuzzyTagIndex{
+		norms:    norms,
+		lengths:  make([]int, len(norms)),
+		grams:    make([]map[string]int, len(norms)),
+		postings: make(map[string][]trigramPosting),
+		byLength: make(map[int][]int),
+		byStem:   make(map[string][]int),
+		sorted:   make([]int, len(norms)),
+	}
+	for i, norm := range norms {
+		idx.lengths[i] = len([]rune(norm))
+		idx.grams[i] = trigrams(norm)
+		for gram, count := range idx.grams[i] {
+			idx.postings[gram] = append(idx.postings[gram], trigramPosting{tag: i, count: count})
+		}
+		idx.byLength[idx.lengths[i]] = append(idx.byLength[idx.lengths[i]], i)
+		idx.byStem[fuzzyStem(norm)] = append(idx.byStem[fuzzyStem(norm)], i)
+		idx.sorted[i] = i
+	}
+	sort.Slice(idx.sorted, func(a, b int) bool { return norms[idx.sorted[a]] < norms[idx.sorted[b]] })
+	return idx
+}
+
+// fuzzyCandidates collects the candidates of one tag at a time. Its scratch space is
+// indexed by tag, so each worker has its own.
+type fuzzyCandidates struct {
+	idx     *fuzzyTagIndex
+	t       fuzzyThresholds
+	seenBy  []int // tag+1 whose candidates last included a tag
+	shared  []int // trigrams shared with the current tag
+	touched []int // tags with shared trigrams, to reset
+	found   []int
+}
+
+func newFuzzyCandidates(idx *fuzzyTagIndex, t fuzzyThresholds) *fuzzyCandidates {
+	return &fuzzyCandidates{idx: idx, t: t, seenBy: make([]int, len(idx.norms)), shared: make([]int, len(idx.norms))}
+}
+
+// add adds tag j to the candidates of tag i once, if it comes before it
+func (c *fuzzyCandidates) add(i, j int) {
+	if j < i && c.seenBy[j] != i+1 {
+		c.seenBy[j] = i + 1
+		c.found = append(c.found, j)
+	}
+}
+
+// of returns the tags before tag i that could match it, in no particular order. The
+// slice is reused by the next call.
+func (c *fuzzyCandidates) of(i int) []int {
+	idx, t := c.idx, c.t
+	norm, length := idx.norms[i], idx.lengths[i]
+	c.found = c.found[:0]
+
+	// Typos: a string within d edits of another of length L shares at least
+	// L+2-3d of its padded trigrams (the q-gram lemma)
+	if length >= t.MinLength {
+		for gram, count := range idx.grams[i] {
+			for _, p := range idx.postings[gram] {
+				if p.tag < i && abs(idx.lengths[p.tag]-length) <= t.MaxDistance {
+					if c.shared[p.tag] == 0 {
+						c.touched = append(c.touched, p.tag)
+					}
+					c.shared[p.tag] += min(count, p.count)
+				}
+			}
+		}
+		for l := length - t.MaxDistance; l <= length+t.MaxDistance; l++ {
+			if maxInt(length, l)+2-3*t.MaxDistance > 0 {
+				continue
+			}
+			// Too short for trigrams to rule anything out: consider every tag of this length
+			for _, j := range idx.byLength[l] {
+				c.add(i, j)
+			}
+		}
+		for _, j := range c.touched {
+			if c.shared[j] >= maxInt(length, idx.lengths[j])+2-3*t.MaxDistance {
+				c.add(i, j)
+			}
+			c.shared[j] = 0
+		}
+		c.touched = c.touched[:0]
+	}
+
+	// Plurals, verb forms and suffixes: the shorter tag's stem starts the longer one.
+	// Tags whose stem starts this one...
+	runes := []rune(norm)
+	for k := 0; k <= len(runes); k++ {
+		for _, j := range idx.byStem[string(runes[:k])] {
+			c.add(i, j)
+		}
+	}
+	// ...and tags starting with this one's stem
+	stem := fuzzyStem(norm)
+	from := sort.Search(len(idx.sorted), func(k int) bool { return idx.norms[idx.sorted[k]] >= stem })
+	for k := from; k < len(idx.sorted) && strings.HasPrefix(idx.norms[idx.sorted[k]], stem); k++ {
+		c.add(i, idx.sorted[k])
+	}
+	return c.found
+}
+
+// fuzzyPreProcess consolidates tags using fuzzy matching for obvious duplicates. Tags are
+// taken in order, so the first (most used) tag of a group becomes its canonical tag.
+// Returns consolidated tag list and mappings (canonical -> [originals])
+func fuzzyPreProcess(tags []tagInfo, t fuzzyThresholds) ([]tagInfo, map[string][]string) {
+	norms := make([]string, len(tags))
+	for i, tag := range tags {
+		norms[i] = fuzzyNormalize(tag.Tag)
+	}
+	idx := newFuzzyTagIndex(norms)
+
+	// Find the earlier tags each tag matches, in parallel
+	matches := make([][]int, len(tags))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			candidates := newFuzzyCandidates(idx, t)
+			for i := range next {
+				for _, j := range candidates.of(i) {
+					if fuzzyTagsMatch(norms[i], norms[j], t) {
+						matches[i] = append(matches[i], j)
+					}
+				}
+				sort.Ints(matches[i])
+			}
+		}()
+	}
+	for i := range tags {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	// Each tag joins the group of the first canonical tag it matches, or starts its own
+	groupOf := make([]int, len(tags))
+	for i := range tags {
+		groupOf[i] = i
+		for _, j := range matches[i] {
+			if groupOf[j] == j {
+				groupOf[i] = j
+				break
+			}
+		}
+	}
+
+	counts := make(map[int]int)
+	mappings := make(map[string][]string)
+	for i, tag := range tags {
+		g := groupOf[i]
+		counts[g] += tag.Count
+		if g != i {
+			mappings[tags[g].Tag] = append(mappings[tags[g].Tag], tag.Tag)
+		}
+	}
+
+	consolidated := make([]tagInfo, 0, len(counts))
+	for g, count := range counts {
+		consolidated = append(consolidated, tagInfo{Tag: tags[g].Tag, Count: count})
+	}
+	// Sort by frequency
+	sort.Slice(consolidated, func(i, j int) bool {
+		if consolidated[i].Count != consolidated[j].Count {
+			return consolidated[i].Count > consolidated[j].Count
+		}
+		return consolidated[i].Tag < consolidated[j].Tag
+	})
+	return consolidated, mappings
+}
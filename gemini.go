@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// geminiSummarizer calls Gemini through the Gemini API with an API key from Google AI
+// Studio, for users without a Google Cloud project
+type geminiSummarizer struct {
+	apiKey string
+	model  string
+}
+
+// newGeminiSummarizer reads GEMINI_API_KEY and GEMINI_MODEL (default: the Vertex AI model)
+func newGeminiSummarizer() *geminiSummarizer {
+	g := &geminiSummarizer{
+		apiKey: strings.TrimSpace(os.Getenv("GEMINI_API_KEY")),
+		model:  strings.TrimSpace(os.Getenv("GEMINI_MODEL")),
+	}
+	if g.model == "" {
+		g.model = geminiModel
+	}
+	return g
+}
+
+func (g *geminiSummarizer) Name() string { return providerGemini }
+
+func (g *geminiSummarizer) DefaultModel() string { return g.model }
+
+func (g *geminiSummarizer) Ready() error {
+	if g.apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY not set; create a key at https://aistudio.google.com/apikey")
+	}
+	return nil
+}
+
+func (g *geminiSummarizer) Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	if err := g.Ready(); err != nil {
+		return "", err
+	}
+	return genaiGenerate(ctx, &genai.ClientConfig{
+		APIKey:     g.apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: llmHTTPClient,
+	}, model, prompt, opts)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// icsTimeFormat is the UTC date-time format used in iCalendar files
+const icsTimeFormat = "20060102T150405Z"
+
+// runExportICS writes an .ics calendar of synced meetings with links back to their notes.
+// With ICS_PER_MONTH=true one file per month is written instead of a single file.
+func runExportICS(obsidianVaultPath string, syncState *SyncState, cache *Cache) error {
+	fmt.Println("\n=== Exporting meetings to iCalendar ===")
+
+	outputDir := dataDir
+	if configured := os.Getenv("ICS_OUTPUT_DIR"); configured != "" {
+		dir, err := resolvePath(configured, obsidianVaultPath)
+		if err != nil {
+			return err
+		}
+		outputDir = dir
+	}
+
+	// Collect synced meetings, oldest first
+	var meetings []*MeetingWithSummary
+	for meetingID := range syncState.ObsidianSyncedMeetings {
+		meeting, err := cache.LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("⚠ Error loading meeting %s: %v\n", meetingID, err)
+			continue
+		}
+		var summaryData *SummaryData
+		if cache.SummaryExists(meetingID) {
+			summaryData, _ = cache.LoadSummary(meetingID)
+		}
+		if isConfidential(meeting, summaryData) {
+			continue
+		}
+		meetings = append(meetings, &MeetingWithSummary{Meeting: meeting, SummaryData: summaryData})
+	}
+
+	if len(meetings) == 0 {
+		fmt.Println("⚠ No synced meetings to export. Run sync step first.")
+		return nil
+	}
+
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].Meeting.CreatedAt.Before(meetings[j].Meeting.CreatedAt)
+	})
+
+	// Group into files
+	files := make(map[string][]*MeetingWithSummary)
+	for _, mws := range meetings {
+		name := "krisp-meetings.ics"
+		if envBool("ICS_PER_MONTH") {
+			name = fmt.Sprintf("krisp-meetings-%s.ics", mws.Meeting.CreatedAt.Local().Format("2006-01"))
+		}
+		files[name] = append(files[name], mws)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content := renderICS(obsidianVaultPath, files[name])
+		path := filepath.Join(outputDir, name)
+		if err := vaultWriter.CreateNote(path, []byte(content)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("  ✓ Wrote %d event(s) to %s\n", len(files[name]), path)
+	}
+
+	fmt.Printf("\n✅ Exported %d meeting(s) to %d calendar file(s)\n", len(meetings), len(names))
+	return nil
+}
+
+// renderICS renders meetings as an iCalendar document
+func renderICS(obsidianVaultPath string, meetings []*MeetingWithSummary) string {
+	var sb strings.Builder
+	writeICSLine(&sb, "BEGIN:VCALENDAR")
+	writeICSLine(&sb, "VERSION:2.0")
+	writeICSLine(&sb, "PRODID:-//krisp-sync//Meeting Notes//EN")
+	writeICSLine(&sb, "CALSCALE:GREGORIAN")
+	writeICSLine(&sb, "X-WR-CALNAME:Krisp Meetings")
+
+	stamp := time.Now().UTC().Format(icsTimeFormat)
+	for _, mws := range meetings {
+		m := mws.Meeting
+		start := m.CreatedAt.UTC()
+		end := start.Add(time.Duration(m.Duration) * time.Second)
+
+		description := ""
+		if mws.SummaryData != nil {
+			description = mws.SummaryData.Description
+		}
+
+		uri, err := obsidianURI(obsidianVaultPath, summaryNotePath(obsidianVaultPath, m))
+		if err == nil {
+			if description != "" {
+				description += "\n\n"
+			}
+			description += "Notes: " + uri
+		}
+
+		writeICSLine(&sb, "BEGIN:VEVENT")
+		writeICSLine(&sb, "UID:"+m.ID+"@krisp-sync")
+		writeICSLine(&sb, "DTSTAMP:"+stamp)
+		writeICSLine(&sb, "DTSTART:"+start.Format(icsTimeFormat))
+		writeICSLine(&sb, "DTEND:"+end.Format(icsTimeFormat))
+		writeICSLine(&sb, "SUMMARY:"+escapeICSText(m.Title))
+		if description != "" {
+			writeICSLine(&sb, "DESCRIPTION:"+escapeICSText(description))
+		}
+		if uri != "" {
+			writeICSLine(&sb, "URL:"+uri)
+		}
+		writeICSLine(&sb, "END:VEVENT")
+	}
+
+	writeICSLine(&sb, "END:VCALENDAR")
+	return sb.String()
+}
+
+// escapeICSText escapes text values per RFC 5545
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(s)
+}
+
+// writeICSLine writes a content line, folding it at 75 octets as required by RFC 5545
+func writeICSLine(sb *strings.Builder, line string) {
+	const maxLen = 75
+	for len(line) > maxLen {
+		// Don't split in the middle of a UTF-8 sequence
+		cut := maxLen
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	sb.WriteString(line + "\r\n")
+}
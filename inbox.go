@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the meeting inbox
+const (
+	defaultInboxNote     = "Meeting Inbox.md"
+	defaultInboxMinScore = 5
+	defaultInboxDays     = 14
+)
+
+// defaultSeniorRoles are role keywords that mark a participant as senior
+var defaultSeniorRoles = []string{"ceo", "cto", "cfo", "coo", "chief", "founder", "president", "vp", "vice president", "director", "head of", "partner"}
+
+// inboxLineRegex matches an inbox entry and captures its checkbox and meeting ID
+var inboxLineRegex = regexp.MustCompile(`^\s*- \[([ xX])\] .*<!-- krisp:([^ ]+) -->\s*$`)
+
+// InboxSettings configures which meetings land in the inbox
+type InboxSettings struct {
+	Path        string   // inbox note, relative to the vault
+	MinScore    int      // importance a meeting needs to be listed
+	Days        int      // only meetings from the last N days are listed
+	SeniorRoles []string // lowercase role keywords that count as senior
+
+	seniorPatterns []*wordPattern // SeniorRoles, compiled
+}
+
+// inboxSettingsFromEnv reads INBOX_NOTE, INBOX_MIN_SCORE, INBOX_DAYS and SENIOR_ROLES
+func inboxSettingsFromEnv() (InboxSettings, error) {
+	settings := InboxSettings{
+		Path:        defaultInboxNote,
+		MinScore:    defaultInboxMinScore,
+		Days:        defaultInboxDays,
+		SeniorRoles: defaultSeniorRoles,
+	}
+	if v := strings.TrimSpace(os.Getenv("INBOX_NOTE")); v != "" {
+		settings.Path = v
+		if !strings.HasSuffix(settings.Path, ".md") {
+			settings.Path += ".md"
+		}
+	}
+	if v := os.Getenv("INBOX_MIN_SCORE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return settings, fmt.Errorf("invalid INBOX_MIN_SCORE %q: must be a non-negative integer", v)
+		}
+		settings.MinScore = n
+	}
+	if v := os.Getenv("INBOX_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return settings, fmt.Errorf("invalid INBOX_DAYS %q: must be a positive integer", v)
+		}
+		settings.Days = n
+	}
+	if v := os.Getenv("SENIOR_ROLES"); v != "" {
+		settings.SeniorRoles = nil
+		for _, role := range strings.Split(v, ",") {
+			if role = strings.ToLower(strings.TrimSpace(role)); role != "" {
+				settings.SeniorRoles = append(settings.SeniorRoles, role)
+			}
+		}
+	}
+	for _, keyword := range settings.SeniorRoles {
+		settings.seniorPatterns = append(settings.seniorPatterns, newWordPattern(keyword, false))
+	}
+	return settings, nil
+}
+
+// isSenior reports whether a role matches one of the senior role keywords
+func (s InboxSettings) isSenior(role string) bool {
+	role = strings.ToLower(role)
+	for _, pattern := range s.seniorPatterns {
+		if pattern.MatchString(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Importance is a meeting's importance score and what it's made of
+type Importance struct {
+	Score   int
+	Reasons []string
+}
+
+// meetingImportance scores a meeting: 2 points per senior participant (from the people
+// directory's roles), 2 per decision (up to 3) and 3 per action item assigned to me
+func meetingImportance(m *Meeting, summaryData *SummaryData, settings InboxSettings) Importance {
+	var imp Importance
+
+	var senior []string
+	for _, p := range meetingPeople(m) {
+		if p.Role != "" && settings.isSenior(p.Role) {
+			senior = append(senior, p.Name)
+		}
+	}
+	if len(senior) > 0 {
+		imp.Score += 2 * len(senior)
+		imp.Reasons = append(imp.Reasons, "with "+strings.Join(senior, ", "))
+	}
+
+	if summaryData == nil {
+		return imp
+	}
+	if n := len(summaryData.Decisions); n > 0 {
+		imp.Score += 2 * min(n, 3)
+		imp.Reasons = append(imp.Reasons, pluralize(n, "decision"))
+	}
+	mine := 0
+	for _, item := range summaryData.ActionItems {
+		if item.Mine() && !item.Done {
+			mine++
+		}
+	}
+	if mine > 0 {
+		imp.Score += 3 * mine
+		imp.Reasons = append(imp.Reasons, pluralize(mine, "action item")+" for me")
+	}
+	return imp
+}
+
+// pluralize formats a count with a noun, adding "s" when needed
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// parseInboxChecks returns the meetings checked off in an inbox note
+func parseInboxChecks(body string) []string {
+	var checked []string
+	for _, line := range strings.Split(body, "\n") {
+		if match := inboxLineRegex.FindStringSubmatch(line); match != nil && match[1] != " " {
+			checked = append(checked, match[2])
+		}
+	}
+	return checked
+}
+
+// runInbox marks meetings checked off in the inbox note as reviewed, then rewrites the
+// note with the unreviewed high-importance meetings
+func runInbox(obsidianVaultPath string, syncState *SyncState, cache *Cache) error {
+	fmt.Println("\n=== Inbox: Listing important meetings to review ===")
+
+	settings, err := inboxSettingsFromEnv()
+	if err != nil {
+		return err
+	}
+	inboxPath := filepath.Join(obsidianVaultPath, settings.Path)
+
+	// Record what was checked off since the last run
+	if vaultWriter.Exists(inboxPath) {
+		content, err := vaultWriter.ReadNote(inboxPath)
+		if err != nil {
+			return fmt.Errorf("error reading inbox note: %w", err)
+		}
+		for _, id := range parseInboxChecks(string(content)) {
+			if !syncState.ReviewedMeetings[id] {
+				syncState.MarkReviewed(id)
+				fmt.Printf("  ✓ Reviewed: %s\n", id)
+			}
+		}
+	}
+
+	type entry struct {
+		meeting    *Meeting
+		importance Importance
+	}
+	var entries []entry
+	cutoff := time.Now().AddDate(0, 0, -settings.Days)
+	for id := range syncState.ObsidianSyncedMeetings {
+		if syncState.ReviewedMeetings[id] {
+			continue
+		}
+		m, err := cache.LoadMeeting(id)
+		if err != nil || m.CreatedAt.Before(cutoff) {
+			continue
+		}
+		summaryData, _ := cache.LoadSummary(id)
+		imp := meetingImportance(m, summaryData, settings)
+		if imp.Score >= settings.MinScore {
+			entries = append(entries, entry{meeting: m, importance: imp})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].importance.Score != entries[j].importance.Score {
+			return entries[i].importance.Score > entries[j].importance.Score
+		}
+		return entries[i].meeting.CreatedAt.After(entries[j].meeting.CreatedAt)
+	})
+
+	var sb strings.Builder
+	sb.WriteString("---\ntype: meeting-inbox\n---\n\n# Meeting Inbox\n\n")
+	sb.WriteString("Important meetings you haven't reviewed yet. Check one off once you have - it's dropped on the next sync.\n\n")
+	if len(entries) == 0 {
+		sb.WriteString("_Nothing to review._\n")
+	}
+	for _, e := range entries {
+		m := e.meeting
+		sb.WriteString(fmt.Sprintf("- [ ] [[%s-summary|%s]] · %s · score %d (%s) <!-- krisp:%s -->\n",
+			m.ID, m.Title, m.CreatedAt.Local().Format("2006-01-02"), e.importance.Score, strings.Join(e.importance.Reasons, ", "), m.ID))
+	}
+
+	if err := vaultWriter.CreateNote(inboxPath, []byte(sb.String())); err != nil {
+		return fmt.Errorf("error writing inbox note: %w", err)
+	}
+	fmt.Printf("✅ %d meeting(s) to review in %s\n", len(entries), settings.Path)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// repairJSON tries to turn a sloppy LLM response into parseable JSON:
+// strips markdown code fences, extracts the largest JSON object and removes trailing commas
+func repairJSON(response string) string {
+	s := stripCodeFences(response)
+	if obj := extractLargestJSONObject(s); obj != "" {
+		s = obj
+	}
+	return removeTrailingCommas(s)
+}
+
+// stripCodeFences removes ```json ... ``` style fences around a response
+func stripCodeFences(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+
+	// Drop the opening fence line (which may carry a language tag)
+	if idx := strings.Index(s, "\n"); idx != -1 {
+		s = s[idx+1:]
+	} else {
+		return ""
+	}
+
+	if idx := strings.LastIndex(s, "```"); idx != -1 {
+		s = s[:idx]
+	}
+	return strings.TrimSpace(s)
+}
+
+// extractLargestJSONObject returns the longest balanced {...} span in s, ignoring braces inside strings
+func extractLargestJSONObject(s string) string {
+	best := ""
+	depth := 0
+	start := -1
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			if depth > 0 {
+				inString = true
+			}
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 && i+1-start > len(best) {
+				best = s[start : i+1]
+			}
+		}
+	}
+
+	return best
+}
+
+// removeTrailingCommas drops commas directly before a closing } or ], ignoring string contents
+func removeTrailingCommas(s string) string {
+	var sb strings.Builder
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			sb.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		}
+
+		if c == ',' {
+			// Look ahead past whitespace for a closing bracket
+			j := i + 1
+			for j < len(s) && strings.ContainsRune(" \t\r\n", rune(s[j])) {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+var (
+	salvageDescriptionRegex = regexp.MustCompile(`(?i)"?description"?\s*[:=]\s*"((?:[^"\\]|\\.)*)"`)
+	salvageTagsArrayRegex   = regexp.MustCompile(`(?is)"?tags"?\s*[:=]\s*\[(.*?)\]`)
+	salvageTagsLineRegex    = regexp.MustCompile(`(?im)^\W*tags\W*:\s*(.+)$`)
+	salvageQuotedRegex      = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+)
+
+// salvageSummaryFields heuristically extracts the description and tags from a response
+// that could not be parsed as JSON, e.g. one truncated mid-object
+func salvageSummaryFields(response string) (string, []string) {
+	description := ""
+	if m := salvageDescriptionRegex.FindStringSubmatch(response); m != nil {
+		description = strings.TrimSpace(strings.ReplaceAll(m[1], `\"`, `"`))
+	}
+
+	var tags []string
+	if m := salvageTagsArrayRegex.FindStringSubmatch(response); m != nil {
+		for _, q := range salvageQuotedRegex.FindAllStringSubmatch(m[1], -1) {
+			if tag := strings.TrimSpace(q[1]); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	} else if m := salvageTagsLineRegex.FindStringSubmatch(response); m != nil {
+		for _, tag := range strings.Split(m[1], ",") {
+			tag = strings.Trim(strings.TrimSpace(tag), `"'#`)
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return description, uniqueStrings(tags)
+}
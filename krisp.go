@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -52,15 +53,35 @@ type Meeting struct {
 	Speakers  struct {
 		Data map[string]SpeakerInfo `json:"data"` // "1", "2", etc. -> speaker info
 	} `json:"speakers"`
-	Resources struct {
+	Participants  []Speaker      `json:"participants,omitempty"`   // invitees/attendees, when Krisp knows them
+	CalendarEvent *CalendarEvent `json:"calendar_event,omitempty"` // linked calendar event, when Krisp has one
+	Resources     struct {
 		Transcript struct {
 			Status  string `json:"status"`
 			Content string `json:"content"` // JSON string containing transcript data
 		} `json:"transcript"`
 		MeetingNotes map[string]interface{} `json:"meeting_notes"`
+		Chat         struct {
+			Status  string `json:"status"`
+			Content string `json:"content"` // JSON string containing chat messages
+		} `json:"chat"`
+		Attachments []Attachment `json:"attachments"`
+		Recording   struct {
+			Status    string     `json:"status"`
+			URL       string     `json:"url"`
+			MimeType  string     `json:"mime_type"`
+			StartedAt *time.Time `json:"started_at,omitempty"` // when the recording started, when Krisp reports it
+		} `json:"recording"`
 	} `json:"resources"`
-	Summary string `json:"summary"` // We'll populate this ourselves
-	Notes   string `json:"notes"`   // We'll populate this ourselves
+	Account string `json:"account,omitempty"` // Krisp account the meeting was downloaded from (KRISP_ACCOUNTS)
+	Summary string `json:"summary"`           // We'll populate this ourselves
+	Notes   string `json:"notes"`             // We'll populate this ourselves
+}
+
+// CalendarEvent is the calendar event a meeting was recorded for
+type CalendarEvent struct {
+	Title       string `json:"title"`
+	Description string `json:"description"` // may be HTML; often holds the agenda
 }
 
 type SpeakerInfo struct {
@@ -72,6 +93,22 @@ type SpeakerInfo struct {
 	} `json:"person"`
 }
 
+// ChatMessage is a message from the in-meeting chat
+type ChatMessage struct {
+	Author    string  `json:"author"`
+	Text      string  `json:"text"`
+	Timestamp float64 `json:"timestamp"` // seconds from meeting start
+}
+
+// Attachment is a file shared during or attached to a meeting
+type Attachment struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
 type Segment struct {
 	SpeakerIndex int    `json:"speakerIndex"`
 	ID           int    `json:"id"`
@@ -82,90 +119,156 @@ type Speech struct {
 	Start float64 `json:"start"`
 	End   float64 `json:"end"`
 	Text  string  `json:"text"`
+	// Confidence and Words are only present when Krisp returns detailed timing
+	Confidence *float64 `json:"confidence,omitempty"`
+	Words      []Word   `json:"words,omitempty"`
 }
 
-// Krisp API functions
-func fetchAllMeetings(ctx context.Context) ([]MeetingSummary, error) {
+// Word is a single word with its own timing inside a speech segment
+type Word struct {
+	Start      float64  `json:"start"`
+	End        float64  `json:"end"`
+	Text       string   `json:"text"`
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+// meetingsPageSize is the number of meetings requested per /meetings/list page
+const meetingsPageSize = 100
+
+// MeetingsPage is one page of the meetings listing (oldest first)
+type MeetingsPage struct {
+	Page int
+	Rows []MeetingSummary
+	ETag string // validator Krisp sent with the page, if any
+	Hash string // hash of the page's rows, for when Krisp sends no ETag
+}
+
+// Full reports whether the page was completely filled
+func (p MeetingsPage) Full() bool {
+	return len(p.Rows) >= meetingsPageSize
+}
+
+// ListMeetings lists every meeting, oldest first
+func (c *KrispClient) ListMeetings(ctx context.Context) ([]MeetingSummary, error) {
+	pages, err := c.ListMeetingPages(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
 	var allMeetings []MeetingSummary
-	page := 1
-	limit := 100
+	for _, p := range pages {
+		allMeetings = append(allMeetings, p.Rows...)
+	}
+	return allMeetings, nil
+}
+
+// ListMeetingPages lists meetings oldest first starting at startPage until a partial page is returned
+func (c *KrispClient) ListMeetingPages(ctx context.Context, startPage int) ([]MeetingsPage, error) {
+	var pages []MeetingsPage
 
-	for {
+	for page := startPage; ; page++ {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
 
-		requestBody := MeetingsListRequest{
-			Sort:    "asc", // Get oldest first
-			SortKey: "created_at",
-			Page:    page,
-			Limit:   limit,
-			Starred: false,
-		}
-
-		jsonData, err := json.Marshal(requestBody)
-		if err != nil {
-			return nil, err
-		}
-
-		req, err := http.NewRequestWithContext(ctx, "POST", apiBaseURL+"/meetings/list", bytes.NewBuffer(jsonData))
+		p, _, err := c.ListMeetingsPage(ctx, page, "")
 		if err != nil {
 			return nil, err
 		}
 
-		setHeaders(req)
+		pages = append(pages, p)
 
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, err
+		// Continue if we got a full page of results
+		if !p.Full() {
+			break
 		}
-		defer resp.Body.Close()
+	}
 
-		body, _ := io.ReadAll(resp.Body)
+	return pages, nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-		}
+// ListMeetingsSince lists only meetings on or after the pagination cursor.
+// It re-lists the cursor's page and verifies its last row still matches; if the
+// history shifted (e.g. meetings were deleted) it falls back to a full listing.
+func (c *KrispClient) ListMeetingsSince(ctx context.Context, cursor *ListCursor) ([]MeetingsPage, error) {
+	if cursor == nil || cursor.Page < 1 {
+		return c.ListMeetingPages(ctx, 1)
+	}
 
-		var listResp MeetingsListResponse
-		if err := json.Unmarshal(body, &listResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
+	pages, err := c.ListMeetingPages(ctx, cursor.Page)
+	if err != nil {
+		return nil, err
+	}
 
-		allMeetings = append(allMeetings, listResp.Data.Rows...)
+	first := pages[0]
+	if !first.Full() || !first.Rows[len(first.Rows)-1].CreatedAt.Equal(cursor.CreatedAt) {
+		fmt.Println("⚠ Meeting history changed since last listing, re-listing from the start")
+		return c.ListMeetingPages(ctx, 1)
+	}
 
-		// Continue if we got a full page of results
-		if len(listResp.Data.Rows) < limit {
-			break
-		}
+	return pages, nil
+}
 
-		page++
+// ListMeetingsPage fetches a single page of the meetings listing. With an etag the request is
+// conditional, and notModified reports that Krisp answered 304 Not Modified.
+func (c *KrispClient) ListMeetingsPage(ctx context.Context, page int, etag string) (p MeetingsPage, notModified bool, err error) {
+	requestBody := MeetingsListRequest{
+		Sort:    "asc", // Get oldest first
+		SortKey: "created_at",
+		Page:    page,
+		Limit:   meetingsPageSize,
+		Starred: false,
 	}
 
-	return allMeetings, nil
-}
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return p, false, err
+	}
 
-func fetchMeeting(ctx context.Context, meetingID string) (*Meeting, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/meetings/"+meetingID, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url("/meetings/list"), bytes.NewReader(jsonData))
 	if err != nil {
-		return nil, err
+		return p, false, err
+	}
+	expected := []int{http.StatusOK}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+		expected = append(expected, http.StatusNotModified)
 	}
 
-	setHeaders(req)
+	resp, body, err := c.do(ctx, c.timeout, req, expected...)
+	if err != nil {
+		return p, false, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return MeetingsPage{Page: page, ETag: etag}, true, nil
+	}
+
+	var listResp MeetingsListResponse
+	if err := json.Unmarshal(body, &listResp); err != nil {
+		return p, false, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	// Hash the decoded rows rather than the body, so volatile response fields don't count as changes
+	rows, _ := json.Marshal(listResp.Data.Rows)
+	return MeetingsPage{
+		Page: page,
+		Rows: listResp.Data.Rows,
+		ETag: resp.Header.Get("ETag"),
+		Hash: contentHash(rows),
+	}, false, nil
+}
+
+// GetMeeting fetches a meeting with its transcript and resources
+func (c *KrispClient) GetMeeting(ctx context.Context, meetingID string) (*Meeting, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url("/meetings/"+meetingID), nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	_, body, err := c.do(ctx, c.timeout, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// The API wraps the meeting in a data object
@@ -181,14 +284,33 @@ func fetchMeeting(ctx context.Context, meetingID string) (*Meeting, error) {
 	return &response.Data, nil
 }
 
-func setHeaders(req *http.Request) {
-	req.Header.Set("Accept", "application/json, text/plain, */*")
-	req.Header.Set("Authorization", "Bearer "+bearerToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("krisp_header_app", "web")
-	req.Header.Set("krisp_header_web_project", "note")
-	// Dynamically set timezone based on system's local timezone
-	req.Header.Set("krisp_origin_timezone", time.Now().Format("-07:00"))
-	req.Header.Set("Origin", "https://app.krisp.ai")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)")
+// GetAttachment downloads the content of a meeting attachment
+func (c *KrispClient) GetAttachment(ctx context.Context, attachment Attachment) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", attachment.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	_, body, err := c.do(ctx, krispDownloadTimeout, req)
+	if err != nil {
+		var apiErr *KrispAPIError
+		if errors.As(err, &apiErr) {
+			// Attachment error bodies are storage service pages, not worth printing
+			apiErr.Body = ""
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
+// Reprocess asks Krisp to re-run transcription and speaker diarization for a meeting
+func (c *KrispClient) Reprocess(ctx context.Context, meetingID string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url("/meetings/"+meetingID+"/reprocess"), strings.NewReader("{}"))
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.do(ctx, c.timeout, req, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	return err
 }
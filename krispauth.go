@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// krispCredentialsFile is where `krisp-sync login` saves tokens, in the user config directory
+	krispCredentialsFile = "krisp-credentials.json"
+	// defaultCredentialsAccount keys the tokens of a single account (KRISP_BEARER_TOKEN)
+	defaultCredentialsAccount = "default"
+	// krispRefreshMargin is how long before its expiry a token is renewed
+	krispRefreshMargin = 5 * time.Minute
+)
+
+// KrispCredentials are an account's tokens saved by `krisp-sync login`
+type KrispCredentials struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// krispAuthMu serializes token refreshes and credentials file writes
+var krispAuthMu sync.Mutex
+
+var (
+	// krispCredsMu guards krispCreds, the credentials file as last read or written, so
+	// requests don't parse the file every time
+	krispCredsMu  sync.Mutex
+	krispCreds    map[string]*KrispCredentials
+	krispCredsErr error
+)
+
+// krispCredentialsPath returns the credentials file (KRISP_CREDENTIALS_FILE, default
+// krisp-credentials.json in the user config directory, e.g. ~/.config/krisp-sync)
+func krispCredentialsPath() (string, error) {
+	if v := strings.TrimSpace(os.Getenv("KRISP_CREDENTIALS_FILE")); v != "" {
+		return resolvePath(v, "")
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine config directory: %w", err)
+	}
+	return filepath.Join(configDir, "krisp-sync", krispCredentialsFile), nil
+}
+
+// credentialsKey returns the credentials file key of an account ("" for a single account)
+func credentialsKey(account string) string {
+	return firstNonEmpty(account, defaultCredentialsAccount)
+}
+
+// loadKrispCredentials returns the saved credentials by account, reading the file once
+func loadKrispCredentials() (map[string]*KrispCredentials, error) {
+	krispCredsMu.Lock()
+	defer krispCredsMu.Unlock()
+	if krispCreds == nil && krispCredsErr == nil {
+		krispCreds, krispCredsErr = readKrispCredentials()
+	}
+	return krispCreds, krispCredsErr
+}
+
+// readKrispCredentials reads the saved credentials by account from the file. A missing
+// file means none.
+func readKrispCredentials() (map[string]*KrispCredentials, error) {
+	path, err := krispCredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	creds := make(map[string]*KrispCredentials)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Krisp credentials: %w", err)
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("Krisp credentials %s are not valid JSON: %w", path, err)
+	}
+	return creds, nil
+}
+
+// saveKrispCredentials stores an account's tokens, readable by the user only. The
+// file is read again first, so accounts saved by another run are kept.
+func saveKrispCredentials(account string, c *KrispCredentials) error {
+	creds, err := readKrispCredentials()
+	if err != nil {
+		return err
+	}
+	path, err := krispCredentialsPath()
+	if err != nil {
+		return err
+	}
+	c.UpdatedAt = time.Now()
+	creds[credentialsKey(account)] = c
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tempPath := path + ".new"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write Krisp credentials: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to write Krisp credentials: %w", err)
+	}
+
+	krispCredsMu.Lock()
+	krispCreds, krispCredsErr = creds, nil
+	krispCredsMu.Unlock()
+	return nil
+}
+
+// savedKrispToken returns the access token `krisp-sync login` saved for an account, or ""
+func savedKrispToken(account string) string {
+	creds, err := loadKrispCredentials()
+	if err != nil {
+		fmt.Printf("⚠ %v\n", err)
+		return ""
+	}
+	if c := creds[credentialsKey(account)]; c != nil {
+		return c.AccessToken
+	}
+	return ""
+}
+
+// krispRefreshURL returns KRISP_REFRESH_URL, a token service of your own that exchanges a
+// refresh token for a new bearer token. Krisp doesn't document how its tokens are renewed,
+// so krisp-sync can't renew them with Krisp itself.
+func krispRefreshURL() string {
+	return strings.TrimSpace(os.Getenv("KRISP_REFRESH_URL"))
+}
+
+// canRefreshKrispToken reports whether an account's token can be renewed: a refresh
+// token was saved by `krisp-sync login` and KRISP_REFRESH_URL is set
+func canRefreshKrispToken(account string) bool {
+	if krispRefreshURL() == "" {
+		return false
+	}
+	creds, err := loadKrispCredentials()
+	if err != nil {
+		return false
+	}
+	c := creds[credentialsKey(account)]
+	return c != nil && c.RefreshToken != ""
+}
+
+// refreshKrispToken exchanges an account's refresh token for a new bearer token, saves
+// both, and makes requests as the account use it. A token renewed by another request or
+// run in the meantime (newer than stale) is used as it is, so the file is read again.
+func refreshKrispToken(ctx context.Context, account, stale string) (string, error) {
+	krispAuthMu.Lock()
+	defer krispAuthMu.Unlock()
+
+	creds, err := readKrispCredentials()
+	if err != nil {
+		return "", err
+	}
+	c := creds[credentialsKey(account)]
+	if c == nil || c.RefreshToken == "" {
+		return "", fmt.Errorf("no Krisp refresh token saved; run `krisp-sync login`")
+	}
+	if c.AccessToken != "" && c.AccessToken != stale && !tokenExpiresWithin(c.AccessToken, krispRefreshMargin) {
+		useRefreshedToken(account, c.AccessToken)
+		return c.AccessToken, nil
+	}
+
+	access, refresh, err := requestTokenRefresh(ctx, krispRefreshURL(), c.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	if err := saveKrispCredentials(account, &KrispCredentials{AccessToken: access, RefreshToken: firstNonEmpty(refresh, c.RefreshToken)}); err != nil {
+		fmt.Printf("⚠ Warning: Could not save the renewed Krisp token: %v\n", err)
+	}
+	useRefreshedToken(account, access)
+	if expiry, ok := tokenExpiry(access); ok {
+		fmt.Printf("🔑 Renewed the Krisp token of %s (%s)\n", tokenLabel(KrispAccount{Name: account}), describeExpiry(expiry))
+	} else {
+		fmt.Printf("🔑 Renewed the Krisp token of %s\n", tokenLabel(KrispAccount{Name: account}))
+	}
+	return access, nil
+}
+
+// useRefreshedToken makes requests as an account use a renewed token
+func useRefreshedToken(account, token string) {
+	for i := range krispAccounts {
+		if krispAccounts[i].Name == account {
+			krispAccounts[i].Token = token
+		}
+	}
+	if account == "" {
+		bearerToken = token
+	}
+}
+
+// tokenExpiresWithin reports whether a JWT token expires within d. Tokens without an
+// expiry never do.
+func tokenExpiresWithin(token string, d time.Duration) bool {
+	expiry, ok := tokenExpiry(token)
+	return ok && time.Until(expiry) <= d
+}
+
+// requestTokenRefresh posts {"refresh_token": "..."} to the KRISP_REFRESH_URL token
+// service and reads the new bearer token from "access_token" of its JSON response, and the
+// rotated refresh token from "refresh_token" if there is one
+func requestTokenRefresh(ctx context.Context, url, refreshToken string) (access, refresh string, err error) {
+	body, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	ctx, cancel := context.WithTimeout(ctx, krispRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to renew the Krisp token: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to renew the Krisp token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("failed to renew the Krisp token: %w; run `krisp-sync login` again", &KrispAPIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))})
+	}
+
+	var parsed struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", "", fmt.Errorf("failed to parse the KRISP_REFRESH_URL response: %w", err)
+	}
+	if parsed.AccessToken == "" {
+		return "", "", fmt.Errorf("the KRISP_REFRESH_URL response has no access_token")
+	}
+	return parsed.AccessToken, parsed.RefreshToken, nil
+}
+
+// refreshExpiringTokens renews the tokens of every account that has expired or expires
+// within krispRefreshMargin, where a refresh token allows it
+func refreshExpiringTokens(ctx context.Context) {
+	for _, a := range krispTokens() {
+		if !tokenExpiresWithin(a.Token, krispRefreshMargin) || !canRefreshKrispToken(a.Name) {
+			continue
+		}
+		if _, err := refreshKrispToken(ctx, a.Name, a.Token); err != nil {
+			fmt.Printf("⚠ %v\n", err)
+		}
+	}
+}
+
+// runLogin saves Krisp tokens for later runs: the bearer token (from --token,
+// --token-stdin or a prompt) and, when KRISP_REFRESH_URL is set, the refresh token
+// that renews it. Tokens typed at a terminal aren't echoed.
+func runLogin(ctx context.Context, account, token string) error {
+	fmt.Printf("\n=== Login: Krisp credentials for %s ===\n", tokenLabel(KrispAccount{Name: account}))
+	in := bufio.NewReader(os.Stdin)
+	prompt := func(label string) (string, error) {
+		fmt.Print(label)
+		if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+			line, err := term.ReadPassword(fd)
+			fmt.Println()
+			return strings.TrimSpace(string(line)), err
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	refreshURL := krispRefreshURL()
+	var err error
+	if token == "" {
+		label := "Bearer token (the Authorization header of app.krisp.ai requests, without \"Bearer \"): "
+		if refreshURL != "" {
+			label = "Bearer token (the Authorization header of app.krisp.ai requests, without \"Bearer \"; empty to get one with the refresh token): "
+		}
+		if token, err = prompt(label); err != nil {
+			return err
+		}
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+	// Krisp doesn't document how its tokens are renewed, so a refresh token is only
+	// useful with a token service of your own
+	refresh := ""
+	if refreshURL != "" {
+		if refresh, err = prompt("Refresh token for KRISP_REFRESH_URL (empty to skip automatic renewal): "); err != nil {
+			return err
+		}
+	}
+	if token == "" && refresh == "" {
+		return fmt.Errorf("no token given")
+	}
+
+	if token == "" {
+		var rotated string
+		if token, rotated, err = requestTokenRefresh(ctx, refreshURL, refresh); err != nil {
+			return err
+		}
+		refresh = firstNonEmpty(rotated, refresh)
+	}
+	if err := saveKrispCredentials(account, &KrispCredentials{AccessToken: token, RefreshToken: refresh}); err != nil {
+		return err
+	}
+
+	path, _ := krispCredentialsPath()
+	fmt.Printf("✅ Saved to %s\n", path)
+	if expiry, ok := tokenExpiry(token); ok {
+		fmt.Printf("   Bearer token %s\n", describeExpiry(expiry))
+	}
+	if refresh != "" {
+		fmt.Println("   It will be renewed through KRISP_REFRESH_URL when it expires")
+	} else {
+		fmt.Println("   Krisp tokens can't be renewed automatically; run `krisp-sync login` again when it expires")
+	}
+	return nil
+}
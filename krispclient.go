@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// krispRequestTimeout bounds each attempt of a Krisp API call
+	krispRequestTimeout = 30 * time.Second
+	// krispDownloadTimeout bounds each attempt of an attachment or recording download
+	krispDownloadTimeout = 5 * time.Minute
+	// defaultKrispRetries is how many times a request failing with a network error, rate
+	// limiting or a gateway status is retried
+	defaultKrispRetries = 3
+	// krispRetryDelay is the wait before the first retry; it doubles with each retry
+	krispRetryDelay = 2 * time.Second
+	// krispRetryMaxDelay caps the wait between retries
+	krispRetryMaxDelay = time.Minute
+	// krispMaxRetryAfter is the longest Retry-After waited for; a rate-limited request
+	// asking for longer fails at once
+	krispMaxRetryAfter = 5 * time.Minute
+)
+
+// Failures of Krisp API calls, for errors.Is on a *KrispAPIError
+var (
+	errKrispUnauthorized = errors.New("Krisp rejected the bearer token")
+	errKrispTokenExpired = errors.New("Krisp bearer token expired")
+	errKrispNotFound     = errors.New("not found in Krisp")
+	errKrispRateLimited  = errors.New("rate limited by Krisp")
+)
+
+// KrispAPIError is a Krisp API response with an unexpected status
+type KrispAPIError struct {
+	StatusCode int
+	Body       string
+	// TokenExpiry is when the rejected bearer token expired, for a 401 with an expired token
+	TokenExpiry time.Time
+}
+
+func (e *KrispAPIError) Error() string {
+	if !e.TokenExpiry.IsZero() {
+		return fmt.Sprintf("Krisp bearer token %s (API returned status %d): run `krisp-sync login` or replace it with a new token from app.krisp.ai", describeExpiry(e.TokenExpiry), e.StatusCode)
+	}
+	if e.Body == "" {
+		return fmt.Sprintf("API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// Is matches the error classes callers handle: a rejected or expired token, a missing
+// meeting and rate limiting
+func (e *KrispAPIError) Is(target error) bool {
+	switch target {
+	case errKrispUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case errKrispTokenExpired:
+		return e.StatusCode == http.StatusUnauthorized && !e.TokenExpiry.IsZero()
+	case errKrispNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case errKrispRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// krispMiddleware wraps the transport of every Krisp request
+type krispMiddleware func(http.RoundTripper) http.RoundTripper
+
+// KrispClient calls the Krisp API. Requests go through its middleware: retrying failed
+// requests with backoff, logging them when KRISP_DEBUG is set, and authenticating them.
+type KrispClient struct {
+	baseURL    string // "" for apiBaseURL, read at each request
+	token      string // "" for the account's token, read at each request so renewed tokens apply
+	account    string // KRISP_ACCOUNTS account requests are made as; "" for KRISP_BEARER_TOKEN
+	transport  http.RoundTripper
+	middleware []krispMiddleware
+	timeout    time.Duration
+	retries    int
+}
+
+// krispOption configures a KrispClient
+type krispOption func(*KrispClient)
+
+// withKrispBaseURL sends requests to another API base URL
+func withKrispBaseURL(url string) krispOption {
+	return func(c *KrispClient) { c.baseURL = strings.TrimRight(url, "/") }
+}
+
+// withKrispToken authenticates with a fixed token instead of the current account's
+func withKrispToken(token string) krispOption {
+	return func(c *KrispClient) { c.token = token }
+}
+
+// withKrispTransport sends requests through rt instead of http.DefaultTransport
+func withKrispTransport(rt http.RoundTripper) krispOption {
+	return func(c *KrispClient) { c.transport = rt }
+}
+
+// withKrispMiddleware adds middleware, run after retries and before authentication,
+// in the order given
+func withKrispMiddleware(mw ...krispMiddleware) krispOption {
+	return func(c *KrispClient) { c.middleware = append(c.middleware, mw...) }
+}
+
+// withKrispTimeout bounds each attempt of an API call (attachment downloads keep their own,
+// longer bound)
+func withKrispTimeout(d time.Duration) krispOption {
+	return func(c *KrispClient) { c.timeout = d }
+}
+
+// withKrispRetries sets how many times failed requests are retried
+func withKrispRetries(n int) krispOption {
+	return func(c *KrispClient) { c.retries = n }
+}
+
+// newKrispClient creates a client for the single account, retrying KRISP_RETRIES times
+func newKrispClient(opts ...krispOption) *KrispClient {
+	c := &KrispClient{timeout: krispRequestTimeout, retries: krispRetries()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// krispAPI is the client the pipeline stages use
+var krispAPI = newKrispClient()
+
+// krispRetries reads KRISP_RETRIES (default 3)
+func krispRetries() int {
+	v := strings.TrimSpace(os.Getenv("KRISP_RETRIES"))
+	if v == "" {
+		return defaultKrispRetries
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		fmt.Printf("⚠ Ignoring invalid KRISP_RETRIES %q\n", v)
+		return defaultKrispRetries
+	}
+	return n
+}
+
+// url returns the API URL of path
+func (c *KrispClient) url(path string) string {
+	if c.baseURL != "" {
+		return c.baseURL + path
+	}
+	return apiBaseURL + path
+}
+
+// asAccount returns a copy of the client making requests as a KRISP_ACCOUNTS account
+func (c *KrispClient) asAccount(name string) *KrispClient {
+	account := *c
+	account.account = name
+	return &account
+}
+
+// currentToken returns the bearer token requests are authenticated with
+func (c *KrispClient) currentToken() string {
+	if c.token != "" {
+		return c.token
+	}
+	if c.account != "" {
+		a, _ := findAccount(c.account)
+		return a.Token
+	}
+	return bearerToken
+}
+
+// baseTransport returns the transport requests are sent through, below the middleware
+func (c *KrispClient) baseTransport() http.RoundTripper {
+	if c.transport != nil {
+		return c.transport
+	}
+	return http.DefaultTransport
+}
+
+// roundTripper assembles the middleware chain, each attempt bounded by timeout
+func (c *KrispClient) roundTripper(timeout time.Duration) http.RoundTripper {
+	rt := c.baseTransport()
+	rt = &krispAuthTransport{inner: rt, token: c.currentToken(), apiHost: urlHost(c.url(""))}
+	if envBool("KRISP_DEBUG") {
+		rt = &krispLogTransport{inner: rt}
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	return &krispRetryTransport{inner: rt, retries: c.retries, timeout: timeout}
+}
+
+// do sends a request, each attempt bounded by timeout, and reads the whole response.
+// Statuses other than the expected ones are returned as a *KrispAPIError. The current
+// account's token is renewed when it is about to expire or Krisp rejects it, if
+// `krisp-sync login` saved a refresh token.
+func (c *KrispClient) do(ctx context.Context, timeout time.Duration, req *http.Request, expected ...int) (*http.Response, []byte, error) {
+	refreshable := c.token == "" && canRefreshKrispToken(c.account)
+	if refreshable && tokenExpiresWithin(c.currentToken(), krispRefreshMargin) {
+		if _, err := refreshKrispToken(ctx, c.account, c.currentToken()); err != nil {
+			fmt.Printf("⚠ %v\n", err)
+		}
+	}
+
+	stale := c.currentToken()
+	resp, body, err := c.send(ctx, timeout, req, expected...)
+	if !refreshable || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, body, err
+	}
+	if _, refreshErr := refreshKrispToken(ctx, c.account, stale); refreshErr != nil {
+		fmt.Printf("⚠ %v\n", refreshErr)
+		return resp, body, err
+	}
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, body, err
+		}
+		reqBody, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, body, err
+		}
+		req = req.Clone(ctx)
+		req.Body = reqBody
+	}
+	return c.send(ctx, timeout, req, expected...)
+}
+
+// send makes one request through the middleware chain, following redirects, and checks
+// its status
+func (c *KrispClient) send(ctx context.Context, timeout time.Duration, req *http.Request, expected ...int) (*http.Response, []byte, error) {
+	client := &http.Client{Transport: c.roundTripper(timeout), CheckRedirect: checkKrispRedirect}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(expected) == 0 {
+		expected = []int{http.StatusOK}
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, body, nil
+		}
+	}
+	apiErr := &KrispAPIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	if resp.StatusCode == http.StatusUnauthorized {
+		if expiry, ok := tokenExpiry(c.currentToken()); ok && time.Now().After(expiry) {
+			apiErr.TokenExpiry = expiry
+		}
+	}
+	return resp, body, apiErr
+}
+
+// maxKrispRedirects is how many redirects a Krisp request follows
+const maxKrispRedirects = 10
+
+// checkKrispRedirect follows up to maxKrispRedirects redirects. A redirect to another host
+// (e.g. signed storage for an attachment) goes without credentials: the bearer token is
+// only ever added for the API host (krispAuthTransport), and any other credentials copied
+// from the original request are dropped here.
+func checkKrispRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxKrispRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxKrispRedirects)
+	}
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+	}
+	return nil
+}
+
+// krispAuthTransport adds the bearer token and the headers the Krisp web app sends to
+// requests to the Krisp API. Requests to other hosts, such as attachment URLs on signed
+// storage, go out without them so the token never leaves Krisp.
+type krispAuthTransport struct {
+	inner   http.RoundTripper
+	token   string
+	apiHost string
+}
+
+// urlHost returns the host (and port) of a URL, or "" if it doesn't parse
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+func (t *krispAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.apiHost == "" || strings.ToLower(req.URL.Host) != t.apiHost {
+		return t.inner.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json, text/plain, */*")
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("krisp_header_app", "web")
+	req.Header.Set("krisp_header_web_project", "note")
+	// Dynamically set timezone based on system's local timezone
+	req.Header.Set("krisp_origin_timezone", time.Now().Format("-07:00"))
+	req.Header.Set("Origin", "https://app.krisp.ai")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)")
+	return t.inner.RoundTrip(req)
+}
+
+// krispLogTransport prints each request's method, path, status and time (KRISP_DEBUG).
+// Query strings are left out, since attachment URLs carry signatures.
+type krispLogTransport struct {
+	inner http.RoundTripper
+}
+
+func (t *krispLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.inner.RoundTrip(req)
+	took := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Printf("  🔎 Krisp %s %s: %v (%s)\n", req.Method, req.URL.Path, err, took)
+		return nil, err
+	}
+	fmt.Printf("  🔎 Krisp %s %s: %s (%s)\n", req.Method, req.URL.Path, resp.Status, took)
+	return resp, nil
+}
+
+// krispRetryTransport retries requests that failed with a network error, rate limiting
+// or a gateway status, which are usually transient, waiting Retry-After when Krisp sends
+// it and backing off exponentially otherwise. Other statuses are returned as they are.
+// Each attempt is bounded by timeout.
+type krispRetryTransport struct {
+	inner   http.RoundTripper
+	retries int
+	timeout time.Duration
+}
+
+// retryableStatus reports whether a status is worth retrying
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// retryAfter reads a response's Retry-After header, in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+func (t *krispRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		attemptReq := req
+		if retry > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry %s %s: request body can't be re-read", req.Method, req.URL.Path)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+		resp, err := t.inner.RoundTrip(attemptReq.WithContext(ctx))
+		if err == nil {
+			// The attempt's deadline covers reading the body too
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+		if retry >= t.retries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		wait := backoffDelay(retry+1, krispRetryDelay, krispRetryMaxDelay)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if after, ok := retryAfter(resp); ok {
+				if after > krispMaxRetryAfter {
+					// Too long to wait now; the caller stops and the next run picks up
+					return resp, nil
+				}
+				wait = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		fmt.Printf("  ⏳ Krisp %s %s: %s, retrying in %s (%d/%d)\n", req.Method, req.URL.Path, reason, wait.Round(time.Second), retry+1, t.retries)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// cancelOnClose releases an attempt's context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Vault layout: YYYY/MM-MonthName/YYYY-MM-DD-DayName.md daily notes with
+// meeting notes in YYYY/MM-MonthName/meetings/. Old months may be moved under
+// archiveDir with the same YYYY/MM-MonthName structure.
+
+// archiveDir is the absolute folder archived months are moved into (inside the vault or a separate vault)
+var archiveDir string
+
+// monthFolder returns the vault-relative folder for a month (YYYY/MM-MonthName)
+func monthFolder(t time.Time) string {
+	t = t.Local()
+	return filepath.Join(t.Format("2006"), t.Format("01")+"-"+t.Format("January"))
+}
+
+// meetingsFolder returns the vault-relative folder holding a month's meeting notes
+func meetingsFolder(t time.Time) string {
+	return filepath.Join(monthFolder(t), "meetings")
+}
+
+// dailyNoteFileName returns the file name of the daily note for a date (YYYY-MM-DD-DayName.md)
+func dailyNoteFileName(t time.Time) string {
+	t = t.Local()
+	return t.Format("2006-01-02") + "-" + t.Format("Monday") + ".md"
+}
+
+// monthDir returns the absolute folder for a month, which is the archived copy if the
+// month has been moved to the archive
+func monthDir(vaultPath string, t time.Time) string {
+	active := filepath.Join(vaultPath, monthFolder(t))
+	if archiveDir != "" && !fileExists(active) {
+		if archived := filepath.Join(archiveDir, monthFolder(t)); fileExists(archived) {
+			return archived
+		}
+	}
+	return active
+}
+
+// meetingsDir returns the absolute folder holding a month's meeting notes
+func meetingsDir(vaultPath string, t time.Time) string {
+	return filepath.Join(monthDir(vaultPath, t), "meetings")
+}
+
+// vaultRelative returns path relative to the vault it lives in: the main vault, or the
+// archive when archiving to a separate vault
+func vaultRelative(vaultPath, path string) string {
+	for _, root := range []string{vaultPath, archiveDir} {
+		if root == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// dailyNotePath returns the absolute path of the daily note for a date
+func dailyNotePath(vaultPath string, t time.Time) string {
+	return filepath.Join(monthDir(vaultPath, t), dailyNoteFileName(t))
+}
+
+// summaryNotePath returns the absolute path of a meeting's summary note
+func summaryNotePath(vaultPath string, m *Meeting) string {
+	return filepath.Join(meetingsDir(vaultPath, m.CreatedAt), m.ID+"-summary.md")
+}
+
+// transcriptNotePath returns the absolute path of a meeting's transcript note
+func transcriptNotePath(vaultPath string, m *Meeting) string {
+	return filepath.Join(meetingsDir(vaultPath, m.CreatedAt), m.ID+"-transcript.md")
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// requiredNoteFields are frontmatter fields every meeting summary note must have
+var requiredNoteFields = []string{"date", "time", "type", "title", "meeting_id"}
+
+var (
+	tagPolicyRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*(/[a-z0-9]+(-[a-z0-9]+)*)*$`)
+	wikilinkRegex  = regexp.MustCompile(`!?\[\[([^\]|#^]+)(?:[#^][^\]|]*)?(?:\|[^\]]*)?\]\]`)
+	nonKebabRegex  = regexp.MustCompile(`[^a-z0-9/]+`)
+)
+
+// LintIssue is a problem found in a meeting note
+type LintIssue struct {
+	File    string
+	Message string
+	Fixed   bool
+}
+
+// toKebabCase converts a tag to the kebab-case tag policy (nested tags keep their "/")
+func toKebabCase(tag string) string {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	tag = nonKebabRegex.ReplaceAllString(tag, "-")
+	parts := strings.Split(tag, "/")
+	for i, p := range parts {
+		parts[i] = strings.Trim(p, "-")
+	}
+	return strings.Join(parts, "/")
+}
+
+// findSummaryNotes returns every meeting summary note in the vault
+func findSummaryNotes(vaultPath string) ([]string, error) {
+	var notes []string
+	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(info.Name(), "-summary.md") && filepath.Base(filepath.Dir(path)) == "meetings" {
+			notes = append(notes, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning vault: %w", err)
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
+
+// buildLinkIndex indexes vault files by name and vault-relative path (without .md) for wikilink resolution
+func buildLinkIndex(vaultPath string) (map[string]bool, error) {
+	index := make(map[string]bool)
+	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(vaultPath, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		index[strings.ToLower(rel)] = true
+		index[strings.ToLower(strings.TrimSuffix(rel, ".md"))] = true
+		index[strings.ToLower(info.Name())] = true
+		index[strings.ToLower(strings.TrimSuffix(info.Name(), ".md"))] = true
+		return nil
+	})
+	return index, err
+}
+
+// resolveWikilink reports whether a link target exists, trying Obsidian's lookups:
+// by name, vault-relative path, or path relative to the linking note
+func resolveWikilink(index map[string]bool, vaultPath, notePath, target string) bool {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if index[target] || index[filepath.Base(target)] {
+		return true
+	}
+	if rel, err := filepath.Rel(vaultPath, filepath.Join(filepath.Dir(notePath), target)); err == nil {
+		if index[strings.ToLower(filepath.ToSlash(rel))] {
+			return true
+		}
+	}
+	// Links like "meetings/<id>-transcript" resolve against any folder ending in that path
+	for key := range index {
+		if strings.HasSuffix(key, "/"+target) {
+			return true
+		}
+	}
+	return false
+}
+
+// frontmatterTags returns the tags field as a string slice
+func frontmatterTags(frontmatter map[string]interface{}) []string {
+	var tags []string
+	switch v := frontmatter["tags"].(type) {
+	case []interface{}:
+		for _, item := range v {
+			tags = append(tags, fmt.Sprintf("%v", item))
+		}
+	case string:
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// lintNote validates one summary note, fixing what it can when fix is set
+func lintNote(vaultPath, notePath string, index map[string]bool, cache *Cache, fix bool) []LintIssue {
+	var issues []LintIssue
+	rel, _ := filepath.Rel(vaultPath, notePath)
+	report := func(message string, fixed bool) {
+		issues = append(issues, LintIssue{File: rel, Message: message, Fixed: fixed})
+	}
+
+	frontmatter, body, err := parseFrontmatter(notePath)
+	if err != nil {
+		report(fmt.Sprintf("frontmatter: %v", err), false)
+		return issues
+	}
+	if frontmatter == nil {
+		frontmatter = make(map[string]interface{})
+	}
+
+	// Fixed properties are written back on their own, leaving the rest of the note as it was
+	fixes := make(map[string]interface{})
+	setField := func(key string, value interface{}) {
+		frontmatter[key] = value
+		fixes[key] = value
+	}
+	fileID := strings.TrimSuffix(filepath.Base(notePath), "-summary.md")
+
+	// meeting_id must match the filename and a cached meeting
+	if id, ok := frontmatter["meeting_id"]; !ok || fmt.Sprintf("%v", id) != fileID {
+		if ok {
+			report(fmt.Sprintf("meeting_id %v does not match filename (%s)", id, fileID), fix)
+		}
+		if fix {
+			setField("meeting_id", fileID)
+		}
+	}
+	meeting, meetingErr := cache.LoadMeeting(fileID)
+	if meetingErr != nil {
+		report("meeting not found in cache", false)
+	}
+
+	// Required fields, restored from the cache when possible
+	for _, field := range requiredNoteFields {
+		if _, ok := frontmatter[field]; ok {
+			continue
+		}
+		fixable := fix && (field == "meeting_id" || field == "type" || meeting != nil)
+		report(fmt.Sprintf("missing required field %q", field), fixable)
+		if !fixable {
+			continue
+		}
+		switch field {
+		case "date":
+			setField(field, meeting.CreatedAt.Local().Format("2006-01-02"))
+		case "time":
+			setField(field, meeting.CreatedAt.Local().Format("15:04"))
+		case "type":
+			setField(field, "meeting")
+		case "title":
+			setField(field, meeting.Title)
+		case "meeting_id":
+			setField(field, fileID)
+		}
+	}
+
+	if t, ok := frontmatter["type"]; ok && fmt.Sprintf("%v", t) != "meeting" {
+		report(fmt.Sprintf("type is %q, expected \"meeting\"", t), fix)
+		if fix {
+			setField("type", "meeting")
+		}
+	}
+
+	// Tags must be kebab-case
+	tags := frontmatterTags(frontmatter)
+	var fixedTags []string
+	tagsChanged := false
+	for _, tag := range tags {
+		if tagPolicyRegex.MatchString(tag) {
+			fixedTags = append(fixedTags, tag)
+			continue
+		}
+		kebab := toKebabCase(tag)
+		report(fmt.Sprintf("tag %q violates kebab-case policy (→ %q)", tag, kebab), fix && kebab != "")
+		if kebab != "" {
+			fixedTags = append(fixedTags, kebab)
+		}
+		tagsChanged = true
+	}
+	if fix && tagsChanged {
+		setField("tags", uniqueStrings(fixedTags))
+	}
+
+	// Wikilinks must resolve
+	for _, match := range wikilinkRegex.FindAllStringSubmatch(body, -1) {
+		if !resolveWikilink(index, vaultPath, notePath, match[1]) {
+			report(fmt.Sprintf("unresolved link [[%s]]", match[1]), false)
+		}
+	}
+
+	if len(fixes) > 0 {
+		if err := editFrontmatterFile(notePath, fixes, nil); err != nil {
+			report(fmt.Sprintf("failed to write fixes: %v", err), false)
+		}
+	}
+
+	return issues
+}
+
+// runLint validates all synced meeting notes in the vault
+func runLint(obsidianVaultPath string, cache *Cache, fix bool) error {
+	fmt.Println("\n=== Lint: Validating meeting notes ===")
+	if fix {
+		fmt.Println("🔧 Fix mode: fixable issues will be corrected in place")
+	}
+
+	notes, err := findSummaryNotes(obsidianVaultPath)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		fmt.Println("⚠ No meeting notes found in vault")
+		return nil
+	}
+
+	index, err := buildLinkIndex(obsidianVaultPath)
+	if err != nil {
+		return fmt.Errorf("error indexing vault: %w", err)
+	}
+
+	total, fixed, badFiles := 0, 0, 0
+	for _, note := range notes {
+		issues := lintNote(obsidianVaultPath, note, index, cache, fix)
+		if len(issues) == 0 {
+			continue
+		}
+
+		badFiles++
+		fmt.Printf("\n%s\n", issues[0].File)
+		for _, issue := range issues {
+			total++
+			if issue.Fixed {
+				fixed++
+				fmt.Printf("  ✓ fixed: %s\n", issue.Message)
+			} else {
+				fmt.Printf("  ✗ %s\n", issue.Message)
+			}
+		}
+	}
+
+	fmt.Printf("\nChecked %d note(s): %d issue(s) in %d file(s)", len(notes), total, badFiles)
+	if fix {
+		fmt.Printf(", %d fixed", fixed)
+	}
+	fmt.Println()
+
+	if total == 0 {
+		fmt.Println("✅ All meeting notes are healthy")
+	} else if !fix {
+		fmt.Println("Tip: run with --fix to correct fixable issues")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// matchesParticipant reports whether anyone in a meeting matches query (case-insensitive,
+// against names and emails)
+func matchesParticipant(m *Meeting, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	candidates := append(meetingParticipants(m), participantEmails(m)...)
+	for _, speakerInfo := range m.Speakers.Data {
+		candidates = append(candidates, speakerInfo.Person.FirstName+" "+speakerInfo.Person.LastName)
+	}
+	for _, p := range m.Participants {
+		candidates = append(candidates, p.FirstName+" "+p.LastName)
+	}
+	for _, c := range candidates {
+		if strings.Contains(strings.ToLower(c), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// meetingSyncStatus describes how far a meeting got through the pipeline
+func meetingSyncStatus(m *Meeting, syncState *SyncState) string {
+	switch {
+	case syncState.ObsidianSyncedMeetings[m.ID]:
+		if status, ok := syncState.AudioOnlyNotes[m.ID]; ok {
+			return "synced (transcript " + status + ")"
+		}
+		return "synced"
+	case syncState.SummarizedMeetings[m.ID]:
+		return "summarized"
+	case syncState.FailedMeetings[m.ID] != nil:
+		return "failed (" + syncState.FailedMeetings[m.ID].Stage + ")"
+	default:
+		return "downloaded"
+	}
+}
+
+// runList prints the cached meetings with a participant matching participant since a date,
+// newest first, with their sync status and vault note
+func runList(vaultPath string, syncState *SyncState, cache *Cache, participant, since string) error {
+	var sinceTime time.Time
+	if since != "" {
+		t, err := time.ParseInLocation("2006-01-02", since, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: expected YYYY-MM-DD", since)
+		}
+		sinceTime = t
+	}
+
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return err
+	}
+	var matches []*Meeting
+	for _, id := range ids {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if m.CreatedAt.Before(sinceTime) || !matchesParticipant(m, participant) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+
+	for _, m := range matches {
+		title := m.Title
+		if summaryData, err := cache.LoadSummary(m.ID); err == nil && summaryData.Title != "" {
+			title = summaryData.Title
+		}
+		note := "-"
+		if path := summaryNotePath(vaultPath, m); fileExists(path) {
+			note = vaultRelative(vaultPath, path)
+		}
+		fmt.Printf("%s  %s\n", m.CreatedAt.Local().Format("2006-01-02 15:04"), title)
+		fmt.Printf("    %s · %s · %s\n", m.ID, meetingSyncStatus(m, syncState), note)
+	}
+	fmt.Printf("\n📋 %d meeting(s)\n", len(matches))
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListingFingerprint identifies the last page of a fully downloaded meetings listing. The
+// listing is sorted oldest first, so new meetings always land on that page (or after it,
+// when it was full): if it hasn't changed, there is nothing new to download.
+type ListingFingerprint struct {
+	Page      int       `json:"page"`
+	ETag      string    `json:"etag,omitempty"`
+	Hash      string    `json:"hash"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// listingCacheEnabled reports whether unchanged listings short-circuit the download
+// (LISTING_CACHE, default true)
+func listingCacheEnabled() bool {
+	return envBoolDefault("LISTING_CACHE", true)
+}
+
+// listingFingerprint returns the fingerprint of an account's last complete listing
+func (s *SyncState) listingFingerprint(account string) *ListingFingerprint {
+	return s.ListingFingerprints[account]
+}
+
+// setListingFingerprint stores (or with nil, forgets) the current account's listing fingerprint
+func (s *SyncState) setListingFingerprint(account string, fp *ListingFingerprint) {
+	if fp == nil {
+		delete(s.ListingFingerprints, account)
+		return
+	}
+	if s.ListingFingerprints == nil {
+		s.ListingFingerprints = make(map[string]*ListingFingerprint)
+	}
+	s.ListingFingerprints[account] = fp
+}
+
+// fingerprintListing fingerprints a listing once every meeting in it is cached. Listings
+// ending on a full page aren't fingerprinted, since a new meeting would start the next page.
+func fingerprintListing(pages []MeetingsPage, cache *Cache) *ListingFingerprint {
+	if len(pages) == 0 {
+		return nil
+	}
+	last := pages[len(pages)-1]
+	if last.Full() {
+		return nil
+	}
+	for _, p := range pages {
+		for _, m := range p.Rows {
+			if !cache.MeetingExists(m.ID) {
+				return nil
+			}
+		}
+	}
+	return &ListingFingerprint{Page: last.Page, ETag: last.ETag, Hash: last.Hash, CheckedAt: time.Now()}
+}
+
+// listingUnchanged re-requests the fingerprinted page (conditionally, when Krisp sent an
+// ETag) and reports whether it is the same as last time. Errors count as changed, so the
+// full listing runs and reports them.
+func listingUnchanged(ctx context.Context, api *KrispClient, fp *ListingFingerprint) bool {
+	p, notModified, err := api.ListMeetingsPage(ctx, fp.Page, fp.ETag)
+	if err != nil {
+		return false
+	}
+	if notModified {
+		return true
+	}
+	return !p.Full() && p.Hash == fp.Hash
+}
+
+// checkListing reports whether an account's listing is unchanged since the last complete
+// download, updating the fingerprint's check time when it is
+func checkListing(ctx context.Context, api *KrispClient, syncState *SyncState) bool {
+	fp := syncState.listingFingerprint(api.account)
+	if fp == nil || !listingCacheEnabled() {
+		return false
+	}
+	if !listingUnchanged(ctx, api, fp) {
+		return false
+	}
+	fmt.Printf("✅ Meeting listing unchanged since %s, nothing new to download\n", fp.CheckedAt.Local().Format("2006-01-02 15:04"))
+	fp.CheckedAt = time.Now()
+	return true
+}
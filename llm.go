@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+)
+
+// LLM providers, selected with LLM_PROVIDER or per model with a "provider:" prefix
+const (
+	providerVertex    = "vertex"
+	providerGemini    = "gemini"
+	providerOpenAI    = "openai"
+	providerOllama    = "ollama"
+	providerAnthropic = "anthropic"
+)
+
+// llmProviders lists the supported providers, for error messages
+var llmProviders = []string{providerVertex, providerGemini, providerOpenAI, providerOllama, providerAnthropic}
+
+// generateOptions are the settings of one LLM request
+type generateOptions struct {
+	Temperature float32
+	Schema      *genai.Schema // JSON schema the response must follow; nil for plain text
+}
+
+// Summarizer is an LLM backend. Every LLM call of the pipeline (summaries, compression,
+// agenda slices, series diffs, wrap-ups) goes through one.
+type Summarizer interface {
+	// Name is the provider name, as in LLM_PROVIDER
+	Name() string
+	// DefaultModel is the model used when none is configured
+	DefaultModel() string
+	// Ready returns an error saying what to set when the backend isn't configured
+	Ready() error
+	// Generate sends a single prompt and returns the response text
+	Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error)
+}
+
+var (
+	summarizersMu sync.Mutex
+	summarizers   = make(map[string]Summarizer)
+)
+
+// summarizerFor returns the backend of a provider, creating it on first use
+func summarizerFor(provider string) (Summarizer, error) {
+	summarizersMu.Lock()
+	defer summarizersMu.Unlock()
+	if s, ok := summarizers[provider]; ok {
+		return s, nil
+	}
+	var s Summarizer
+	switch provider {
+	case providerVertex:
+		s = &vertexSummarizer{}
+	case providerGemini:
+		s = newGeminiSummarizer()
+	case providerOpenAI:
+		s = newOpenAISummarizer()
+	case providerOllama:
+		s = newOllamaSummarizer()
+	case providerAnthropic:
+		s = newAnthropicSummarizer()
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (supported: %s)", provider, strings.Join(llmProviders, ", "))
+	}
+	summarizers[provider] = s
+	return s, nil
+}
+
+// llmProvider returns the default provider: LLM_PROVIDER, or else gemini when only a
+// Gemini API key is set (no Google Cloud project or credentials), and vertex otherwise
+func llmProvider() (string, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_PROVIDER")))
+	if provider == "" {
+		if os.Getenv("GEMINI_API_KEY") != "" && os.Getenv("GOOGLE_CLOUD_PROJECT") == "" && os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") == "" {
+			return providerGemini, nil
+		}
+		return providerVertex, nil
+	}
+	for _, p := range llmProviders {
+		if provider == p {
+			return provider, nil
+		}
+	}
+	return "", fmt.Errorf("invalid LLM_PROVIDER %q (supported: %s)", provider, strings.Join(llmProviders, ", "))
+}
+
+// defaultSummarizer returns the backend of the default provider
+func defaultSummarizer() (Summarizer, error) {
+	provider, err := llmProvider()
+	if err != nil {
+		return nil, err
+	}
+	return summarizerFor(provider)
+}
+
+// defaultModel returns the default provider's default model, for LLM calls with no model
+// configured
+func defaultModel() string {
+	s, err := defaultSummarizer()
+	if err != nil {
+		return geminiModel
+	}
+	return s.DefaultModel()
+}
+
+// resolveModel splits a model into its backend and the model name the backend knows it
+// by: "openai:gpt-4o-mini" uses the OpenAI backend, a model without a prefix the default
+// provider
+func resolveModel(model string) (Summarizer, string, error) {
+	if provider, name, ok := strings.Cut(model, ":"); ok && isLLMProvider(provider) {
+		s, err := summarizerFor(provider)
+		return s, name, err
+	}
+	s, err := defaultSummarizer()
+	return s, model, err
+}
+
+// isLLMProvider reports whether name is a supported provider
+func isLLMProvider(name string) bool {
+	for _, p := range llmProviders {
+		if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLLMReady returns an error if a backend of the models isn't configured, so a
+// summarize run stops before its first meeting instead of failing every one of them
+func checkLLMReady(models []string) error {
+	for _, model := range models {
+		s, _, err := resolveModel(model)
+		if err != nil {
+			return err
+		}
+		if err := s.Ready(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vertexSummarizer calls Gemini on Vertex AI. The project and location are resolved on
+// first use, so commands that make no LLM calls don't need Google Cloud set up.
+type vertexSummarizer struct {
+	once sync.Once
+	err  error
+}
+
+func (v *vertexSummarizer) Name() string { return providerVertex }
+
+func (v *vertexSummarizer) DefaultModel() string { return geminiModel }
+
+func (v *vertexSummarizer) Ready() error {
+	v.once.Do(func() {
+		// Already set when the bench step points Vertex AI at its fake
+		if gcpProject != "" && gcpLocation != "" {
+			return
+		}
+		gcpProject, gcpLocation, v.err = resolveGCPConfig()
+	})
+	return v.err
+}
+
+func (v *vertexSummarizer) Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	if err := v.Ready(); err != nil {
+		return "", err
+	}
+	return genaiGenerate(ctx, &genai.ClientConfig{
+		Project:     gcpProject,
+		Location:    gcpLocation,
+		Backend:     genai.BackendVertexAI,
+		HTTPClient:  llmHTTPClient,
+		HTTPOptions: genai.HTTPOptions{BaseURL: llmBaseURL},
+	}, model, prompt, opts)
+}
+
+// genaiGenerate sends a prompt through the genai SDK, for both Vertex AI and the Gemini API
+func genaiGenerate(ctx context.Context, clientConfig *genai.ClientConfig, model string, prompt string, opts generateOptions) (string, error) {
+	config := &genai.GenerateContentConfig{
+		Temperature:    &opts.Temperature,
+		SafetySettings: safetySettings(ctx),
+	}
+	if opts.Schema != nil {
+		config.ResponseMIMEType = "application/json"
+		config.ResponseSchema = opts.Schema
+	}
+
+	client, err := genai.NewClient(ctx, clientConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	resp, err := client.Models.GenerateContent(ctx, model, []*genai.Content{
+		{
+			Role: "user",
+			Parts: []*genai.Part{
+				genai.NewPartFromText(prompt),
+			},
+		},
+	}, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	if resp.UsageMetadata != nil {
+		// Thinking models bill their thoughts as output
+		reportTokens(ctx, int(resp.UsageMetadata.PromptTokenCount), int(resp.UsageMetadata.CandidatesTokenCount+resp.UsageMetadata.ThoughtsTokenCount))
+	}
+	if err := checkBlocked(resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no content generated")
+	}
+
+	return resp.Candidates[0].Content.Parts[0].Text, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// LLM retry defaults
+const (
+	// defaultLLMRetries is how many times an LLM call failing with a transient error is retried
+	defaultLLMRetries = 3
+	// defaultLLMRetryDelay is the wait before the first retry; it doubles with each retry
+	defaultLLMRetryDelay = 2 * time.Second
+	// llmRetryMaxDelay caps the wait between retries
+	llmRetryMaxDelay = time.Minute
+)
+
+// llmRetrySettings is LLM_RETRIES and LLM_RETRY_DELAY, read once
+var (
+	llmRetryOnce    sync.Once
+	llmRetryCount   int
+	llmRetryBackoff time.Duration
+)
+
+// llmRetries returns how many times transient LLM failures are retried (LLM_RETRIES,
+// default 3) and the wait before the first retry (LLM_RETRY_DELAY, default 2s)
+func llmRetries() (int, time.Duration) {
+	llmRetryOnce.Do(func() {
+		llmRetryCount, llmRetryBackoff = defaultLLMRetries, defaultLLMRetryDelay
+		if v := strings.TrimSpace(os.Getenv("LLM_RETRIES")); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				llmRetryCount = n
+			} else {
+				fmt.Printf("⚠ Ignoring invalid LLM_RETRIES %q\n", v)
+			}
+		}
+		if v := strings.TrimSpace(os.Getenv("LLM_RETRY_DELAY")); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d > 0 {
+				llmRetryBackoff = d
+			} else {
+				fmt.Printf("⚠ Ignoring invalid LLM_RETRY_DELAY %q\n", v)
+			}
+		}
+	})
+	return llmRetryCount, llmRetryBackoff
+}
+
+// backoffDelay returns the wait before the given retry (1-based): the base delay doubled
+// per retry, capped at maxDelay, with the upper half jittered so parallel requests don't
+// retry in step
+func backoffDelay(retry int, base, maxDelay time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < retry && wait < maxDelay; i++ {
+		wait *= 2
+	}
+	if wait > maxDelay {
+		wait = maxDelay
+	}
+	half := wait / 2
+	return half + rand.N(half+1)
+}
+
+// llmErrorStatus returns the HTTP status of an LLM API error, or 0 when err has none
+func llmErrorStatus(err error) int {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	var openAIErr *openAIError
+	if errors.As(err, &openAIErr) {
+		return openAIErr.StatusCode
+	}
+	var anthropicErr *anthropicError
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode
+	}
+	var ollamaErr *ollamaError
+	if errors.As(err, &ollamaErr) {
+		return ollamaErr.StatusCode
+	}
+	return 0
+}
+
+// isTransientLLMError reports whether an LLM call failed in a way that may succeed when
+// tried again: rate limits and quota, overloaded or unavailable servers, and dropped
+// connections. Bad requests, rejected credentials, content-filter blocks and schema
+// failures fail the same way every time.
+func isTransientLLMError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, errContentBlocked) || errors.Is(err, errSchemaFailure) {
+		return false
+	}
+	if isQuotaError(err) {
+		return true
+	}
+	switch status := llmErrorStatus(err); {
+	case status == 408 || status == 429 || status >= 500:
+		return true
+	case status != 0:
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// withLLMRetries runs an LLM call, retrying transient failures with exponential backoff.
+// The last error is returned once the retries are used up, or at once if it is permanent.
+func withLLMRetries(ctx context.Context, model string, call func() (string, error)) (string, error) {
+	retries, base := llmRetries()
+	for retry := 1; ; retry++ {
+		text, err := call()
+		if err == nil || !isTransientLLMError(err) || ctx.Err() != nil {
+			return text, err
+		}
+		if retry > retries {
+			if retries == 0 {
+				return "", err
+			}
+			return "", fmt.Errorf("%w (gave up after %d retries)", err, retries)
+		}
+
+		wait := backoffDelay(retry, base, llmRetryMaxDelay)
+		fmt.Printf("  ⏳ %s: %v, retrying in %s (%d/%d)\n", model, err, wait.Round(100*time.Millisecond), retry, retries)
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(wait):
+		}
+	}
+}
@@ -2,46 +2,53 @@ package main
 
 import (
 	"context"
-	"flag"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
-
-	"github.com/joho/godotenv"
 )
 
 const (
-	apiBaseURL       = "https://api.krisp.ai/v2"
 	syncStateFile    = ".krisp_sync_state.json"
 	meetingsCacheDir = "meetings"
 )
 
 var (
+	apiBaseURL  = "https://api.krisp.ai/v2"
 	bearerToken string
 	gcpProject  string
 	gcpLocation string
 )
 
 func main() {
-	// Parse command-line flags
-	limitFlag := flag.Int("limit", 1, "Number of meetings to process (default: 1 for testing)")
-	stepFlag := flag.String("step", "all", "Step to run: download, summarize, sync, check-updates, normalize-prompt, extract-tags, repair, or all (default: all)")
-	overwriteFlag := flag.Bool("overwrite", false, "Force re-process meetings, ignoring state (re-summarize and re-sync)")
-	testFlag := flag.Bool("test", false, "Test mode: create a single test file without updating state (sync stage only)")
-	applyNormalizationFlag := flag.Bool("apply-normalization", false, "Apply tag normalization from normalize-result.json during sync (for initial mass import)")
-	meetingIDFlag := flag.String("meeting", "", "Process specific meeting IDs (comma-separated, combine with --overwrite to re-process)")
-	updateFieldsFlag := flag.String("update-fields", "", "Update only specific frontmatter fields in existing Obsidian files (comma-separated, e.g., 'date,time')")
-	flag.Parse()
+	// Parse the command line: `krisp-sync [command] [flags]`
+	cmd, opts := mustParseCommandLine()
+
+	// Summarize per-meeting failures and exit non-zero on errors and partial failure.
+	// Deferred first so it runs after everything else, including the final state flush;
+	// errors once the state is loaded set exitCode and return rather than log.Fatal,
+	// which would skip that flush.
+	exitCode := 0
+	defer func() {
+		printFailureSummary()
+		if len(runFailures) > 0 && !opts.keepGoing {
+			exitCode = 1
+		}
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
 
 	// Parse meeting IDs if provided
 	var meetingIDs []string
-	if *meetingIDFlag != "" {
-		meetingIDs = strings.Split(*meetingIDFlag, ",")
+	if opts.meeting != "" {
+		meetingIDs = strings.Split(opts.meeting, ",")
 		for i := range meetingIDs {
 			meetingIDs[i] = strings.TrimSpace(meetingIDs[i])
 		}
@@ -49,43 +56,230 @@ func main() {
 
 	// Parse update fields if provided
 	var updateFields []string
-	if *updateFieldsFlag != "" {
-		updateFields = strings.Split(*updateFieldsFlag, ",")
+	if opts.updateFields != "" {
+		updateFields = strings.Split(opts.updateFields, ",")
 		for i := range updateFields {
 			updateFields[i] = strings.TrimSpace(updateFields[i])
 		}
 	}
 
-	// Load environment variables from .env file
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+	// Load environment variables from .env file, if there is one
+	if err := loadDotEnv(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Export traces of the run when an OTLP endpoint or trace file is configured
+	shutdownTracing, err := setupTracing()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer shutdownTracing()
+
+	// Offline runs never talk to Krisp or the LLM, and the bench step fakes them, so their
+	// credentials are optional
+	offline = opts.offline
+	if offline {
+		fmt.Println("✈️  Offline mode: syncing from the local cache only")
+	}
+	credentialsOptional := offline || cmd.noCredentials
+
+	// Dry runs report what download, summarize and sync would do without doing it
+	dryRun = opts.dryRun
+	if dryRun {
+		fmt.Println("🔍 Dry run: nothing will be downloaded, summarized or written")
+	}
+	rewriteMinutes = opts.overwrite || opts.test
+
+	// Several Krisp accounts (KRISP_ACCOUNTS) replace the single KRISP_BEARER_TOKEN
+	accounts, err := loadKrispAccounts(!credentialsOptional)
+	if err != nil {
+		log.Fatal(err)
+	}
+	krispAccounts = accounts
+	bearerToken, err = krispTokenFromFlags(opts.token, opts.tokenStdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Commands like login only need the credentials read so far
+	if cmd.beforeSetup {
+		if err := cmd.run(&cmdEnv{ctx: context.Background(), opts: opts}); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Fall back to the token saved by `krisp-sync login`
+	if bearerToken == "" && len(krispAccounts) == 0 {
+		bearerToken = savedKrispToken("")
+	}
+	if bearerToken == "" && len(krispAccounts) == 0 && !credentialsOptional {
+		log.Fatal("KRISP_BEARER_TOKEN not set (in the environment or .env), no --token or --token-stdin given, and no token saved by `krisp-sync login`")
+	}
+	if !offline && !credentialsOptional {
+		// Renew tokens that expired since the last run, when a refresh token allows it
+		refreshExpiringTokens(context.Background())
+	}
+	if !offline {
+		warnTokenExpiry()
+	}
+
+	// The LLM backend (LLM_PROVIDER) is set up on the first LLM call, so commands that make
+	// none, like sync, need no Google Cloud or OpenAI credentials
+	if _, err := llmProvider(); err != nil {
+		log.Fatal(err)
+	}
+
+	style, err := loadSummaryStyle()
+	if err != nil {
+		log.Fatal(err)
+	}
+	summaryStyle = style
+
+	obsidianVaultPath, err := resolveVaultPath(os.Getenv("OBSIDIAN_VAULT_PATH"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// --open overrides OBSIDIAN_OPEN_ON_SYNC when given explicitly
+	openOnSync := envBool("OBSIDIAN_OPEN_ON_SYNC")
+	if opts.passed["open"] {
+		openOnSync = opts.open
+	}
+	openTarget, err := openTargetFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Load folders the tool must never write to
+	vaultIgnore, err = loadVaultIgnore(obsidianVaultPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Resolve where old months are archived
+	archiveDir, err = resolvePath(firstNonEmpty(os.Getenv("ARCHIVE_DIR"), defaultArchiveDir), obsidianVaultPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	archiveMonths, err := archiveAfterMonths()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, _, err := parseTranscriptTimeOffset(os.Getenv("TRANSCRIPT_TIME_OFFSET")); err != nil {
+		log.Fatal(err)
+	}
+
+	// Resolve where state, cache and generated artifacts live
+	resolvedDataDir, cacheDir, syncStatePath, err := resolveStoragePaths(opts.dataDir, opts.cacheDir, opts.statePath, obsidianVaultPath)
+	if err != nil {
+		log.Fatalf("Error resolving storage paths: %v", err)
 	}
+	dataDir = resolvedDataDir
+	fmt.Printf("📁 Data directory: %s\n", dataDir)
 
-	bearerToken = os.Getenv("KRISP_BEARER_TOKEN")
-	if bearerToken == "" {
-		log.Fatal("KRISP_BEARER_TOKEN not set in .env file")
+	// Bring state and cache written by older versions up to the current format
+	if err := migrateStorage(storagePaths{DataDir: dataDir, CacheDir: cacheDir, StatePath: syncStatePath}); err != nil {
+		log.Fatal(err)
 	}
 
-	gcpProject = os.Getenv("GOOGLE_CLOUD_PROJECT")
-	if gcpProject == "" {
-		log.Fatal("GOOGLE_CLOUD_PROJECT not set in .env file")
+	// Test mode writes to a sandbox mirroring the vault instead of the vault itself;
+	// `promote` copies approved files over
+	var testSandbox string
+	if opts.test || cmd.name == "promote" {
+		testSandbox, err = sandboxDir(obsidianVaultPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if opts.test {
+		vaultWriter = newSandboxVaultWriter(vaultWriter, obsidianVaultPath, testSandbox)
+		fmt.Printf("🧪 Test mode: writing to the sandbox %s, not the vault\n", testSandbox)
 	}
 
-	gcpLocation = os.Getenv("GOOGLE_CLOUD_LOCATION")
-	if gcpLocation == "" {
-		log.Fatal("GOOGLE_CLOUD_LOCATION not set in .env file")
+	// Log vault changes to the audit log and the run manifest, keep a dry run's writes in
+	// memory, and leave notes alone when a write wouldn't change them
+	var writers *runVaultWriters
+	vaultWriter, writers = stackRunWriters(vaultWriter, obsidianVaultPath, cmd.name, opts.test)
+
+	// Record each daemon run's vault changes separately
+	nextRun := func() {}
+	if manifestWriter := writers.manifest; manifestWriter != nil {
+		nextRun = func() {
+			runID, err := manifestWriter.Rotate()
+			if err != nil {
+				fmt.Printf("⚠ Warning: Could not save run manifest: %v\n", err)
+			}
+			writers.audit.runID = runID
+		}
+		defer func() {
+			if err := manifestWriter.Save(); err != nil {
+				fmt.Printf("⚠ Warning: Could not save run manifest: %v\n", err)
+			}
+		}()
+	}
+	if writers.dryRun != nil {
+		defer writers.dryRun.Report()
 	}
+	defer writers.unchanged.Report()
 
-	obsidianVaultPath := os.Getenv("OBSIDIAN_VAULT_PATH")
-	if obsidianVaultPath == "" {
-		log.Fatal("OBSIDIAN_VAULT_PATH not set in .env file")
+	// Load the people directory used for preferred names, teams and roles
+	people, err = loadPeople(dataPath(peopleFile))
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Store sync state in application directory
-	syncStatePath := filepath.Join(".", syncStateFile)
+	// Load the plugins that transform meetings and summaries between stages
+	pluginDir, err := pluginsDir(obsidianVaultPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := loadPlugins(pluginDir); err != nil {
+		log.Fatal(err)
+	}
 
-	// Load sync state
-	syncState := loadSyncState(syncStatePath)
+	// Load sync state, from the vault when several machines share it
+	var syncState *SyncState
+	switch store := firstNonEmpty(os.Getenv("KRISP_SYNC_STATE_STORE"), stateStoreFile); store {
+	case stateStoreFile:
+		syncState = loadSyncState(syncStatePath)
+	case stateStoreProperties:
+		// Sync status comes from the vault once the cache is open; the file keeps the rest
+		syncState = loadSyncState(syncStatePath)
+		propertiesState = true
+	case stateStoreVault:
+		stateDir, err := resolvePath(firstNonEmpty(os.Getenv("KRISP_SYNC_STATE_DIR"), defaultVaultStateDir), obsidianVaultPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if rel, err := filepath.Rel(obsidianVaultPath, cacheDir); err != nil || strings.HasPrefix(rel, "..") {
+			fmt.Println("⚠ State is shared through the vault but the meeting cache is not; set KRISP_SYNC_CACHE_DIR=vault:.krisp-sync/meetings so other machines can use downloaded meetings")
+		}
+		syncState = loadSyncStateDir(stateDir, syncStatePath)
+		fmt.Printf("🗂  State store: %s (machine %s)\n", stateDir, syncState.machine)
+	default:
+		log.Fatalf("Invalid KRISP_SYNC_STATE_STORE %q (expected %q, %q or %q)", store, stateStoreFile, stateStoreVault, stateStoreProperties)
+	}
+	if v := os.Getenv("KRISP_SYNC_SAVE_EVERY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Invalid KRISP_SYNC_SAVE_EVERY %q: %v", v, err)
+		}
+		syncState.SaveEvery = n
+	}
+	if v := os.Getenv("KRISP_SYNC_SAVE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid KRISP_SYNC_SAVE_INTERVAL %q: %v", v, err)
+		}
+		syncState.SaveInterval = d
+	}
+	// Flush batched state changes however we exit
+	defer func() {
+		if err := syncState.Flush(); err != nil {
+			fmt.Printf("⚠ Warning: Could not save sync state: %v\n", err)
+		}
+	}()
 	isFirstSync := syncState.LastSyncTime.IsZero()
 
 	if isFirstSync {
@@ -95,86 +289,403 @@ func main() {
 	}
 
 	// Create cache instance
-	cache := NewCache(meetingsCacheDir)
+	cache := NewCache(cacheDir)
+	if propertiesState {
+		if err := applyPropertiesState(syncState, cache); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exitCode = 1
+			return
+		}
+	}
 
 	// Create context that cancels on Ctrl+C (SIGINT) or SIGTERM
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	// Determine which steps to run
-	step := *stepFlag
-	runAll := step == "all"
+	// Time-boxed run: stop starting new work at the deadline, and hard-cancel
+	// anything still in flight after a grace period so scheduled runs never overlap
+	if opts.maxRuntime > 0 {
+		runDeadline = time.Now().Add(opts.maxRuntime)
+		grace := opts.maxRuntime / 10
+		if grace < time.Minute {
+			grace = time.Minute
+		}
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithDeadline(ctx, runDeadline.Add(grace))
+		defer cancelTimeout()
+		fmt.Printf("⏱  Time budget: %s (until %s)\n", opts.maxRuntime, runDeadline.Format("15:04:05"))
+	}
+
+	// One trace per run, with a span per stage below it
+	ctx, runSpan := tracer.Start(ctx, "krisp-sync "+cmd.name)
+	defer runSpan.End()
 
-	// Stage 0: Extract tags from Obsidian (runs automatically in "all" workflow)
-	if runAll {
-		if err := runExtractTags(obsidianVaultPath); err != nil {
-			fmt.Printf("❌ Error extracting tags: %v\n", err)
-			return
+	env := &cmdEnv{
+		ctx:           ctx,
+		opts:          opts,
+		vaultPath:     obsidianVaultPath,
+		syncState:     syncState,
+		cache:         cache,
+		meetingIDs:    meetingIDs,
+		updateFields:  updateFields,
+		overwrite:     opts.overwrite,
+		openOnSync:    openOnSync,
+		openTarget:    openTarget,
+		archiveMonths: archiveMonths,
+		testSandbox:   testSandbox,
+		pluginDir:     pluginDir,
+		nextRun:       nextRun,
+	}
+	if err := cmd.run(env); err != nil {
+		if !errors.Is(err, errNothingToDo) {
+			fmt.Printf("❌ Error in %s: %v\n", cmd.name, err)
+			exitCode = 1
 		}
+		return
 	}
 
-	// Stage 1: Download
-	if runAll || step == "download" {
-		if err := runDownload(ctx, *limitFlag, syncState, *overwriteFlag, meetingIDs, cache); err != nil {
-			fmt.Printf("❌ Error in download stage: %v\n", err)
-			return
+	printOfflineReport(cache)
+
+	// Update sync state (an offline run hasn't heard from Krisp)
+	if !offline {
+		syncState.LastSyncTime = time.Now()
+	}
+	if err := syncState.Save(); err != nil {
+		fmt.Printf("⚠ Warning: Could not save sync state: %v\n", err)
+	}
+
+	if budgetExhausted() {
+		fmt.Println("\n⏱  Time budget used - progress saved, remaining work continues next run")
+		return
+	}
+
+	if len(runFailures) > 0 {
+		fmt.Printf("\n⚠ Requested stages completed with %d meeting failure(s)\n", len(runFailures))
+		return
+	}
+
+	fmt.Println("\n✅ All requested stages completed!")
+}
+
+// runVaultWriters are the writers a run stacks on the vault writer
+type runVaultWriters struct {
+	audit     *AuditVaultWriter
+	manifest  *ManifestVaultWriter // nil in dry runs, test runs and rollbacks
+	dryRun    *DryRunVaultWriter   // nil unless this is a dry run
+	unchanged *UnchangedVaultWriter
+}
+
+// stackRunWriters wraps a run's vault writer. The audit log records every vault change and
+// the run manifest records them so the run can be rolled back; neither sees sandbox writes
+// or dry runs, and a rollback isn't itself recorded for rollback. A dry run keeps its
+// writes in memory, and on top, writes that wouldn't change a note are dropped.
+func stackRunWriters(w VaultWriter, vaultPath, step string, test bool) (VaultWriter, *runVaultWriters) {
+	writers := &runVaultWriters{audit: newAuditVaultWriter(w, vaultPath, step)}
+	if !dryRun && !test {
+		w = writers.audit
+		if step != "rollback" {
+			writers.manifest = newManifestVaultWriter(w, step)
+			writers.audit.runID = writers.manifest.manifest.RunID
+			w = writers.manifest
 		}
 	}
+	if dryRun {
+		writers.dryRun = newDryRunVaultWriter(w, vaultPath)
+		w = writers.dryRun
+	}
+	writers.unchanged = newUnchangedVaultWriter(w)
+	return writers.unchanged, writers
+}
 
-	// Check for updates from Krisp API
-	if step == "check-updates" {
-		if err := runCheckUpdates(ctx, syncState, cache, obsidianVaultPath); err != nil {
-			fmt.Printf("❌ Error in check-updates stage: %v\n", err)
-			return
+// cmdEnv is what main sets up for a command's handler
+type cmdEnv struct {
+	ctx           context.Context
+	opts          *options
+	vaultPath     string
+	syncState     *SyncState
+	cache         *Cache
+	meetingIDs    []string // --meeting
+	updateFields  []string // --update-fields
+	overwrite     bool
+	openOnSync    bool
+	openTarget    string
+	archiveMonths int
+	testSandbox   string
+	pluginDir     string
+	nextRun       func() // saves the run manifest and starts a new one (daemon)
+	synced        int    // summary notes written by the sync stage
+}
+
+// downloadStage runs Stage 1 on meetingIDs (all new meetings when empty)
+func (e *cmdEnv) downloadStage(meetingIDs []string) error {
+	if skipOffline("download", serviceKrisp) {
+		return nil
+	}
+	stageCtx, endStage := startStageSpan(e.ctx, "download")
+	err := runDownload(stageCtx, e.opts.limit, e.syncState, e.overwrite, meetingIDs, e.cache)
+	endStage(err)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	return nil
+}
+
+// summarizeStage runs Stage 2 on meetingIDs (all unsummarized meetings when empty)
+func (e *cmdEnv) summarizeStage(meetingIDs []string) error {
+	if skipOffline("summarize", serviceLLM) {
+		return nil
+	}
+	stageCtx, endStage := startStageSpan(e.ctx, "summarize")
+	err := runSummarize(stageCtx, e.opts.limit, e.syncState, e.overwrite, meetingIDs, e.cache)
+	endStage(err)
+	if err != nil {
+		return fmt.Errorf("summarize: %w", err)
+	}
+	return nil
+}
+
+// syncStage runs Stage 3 on meetingIDs (all unsynced meetings when empty), then opens
+// the result in Obsidian when asked to
+func (e *cmdEnv) syncStage(meetingIDs []string) error {
+	stageCtx, endStage := startStageSpan(e.ctx, "sync")
+	result, err := runSync(stageCtx, e.vaultPath, e.opts.limit, e.syncState, e.overwrite, e.opts.test, e.opts.applyNormalization, meetingIDs, e.updateFields, e.cache)
+	endStage(err)
+	if err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	e.synced += len(result.SummaryNotes)
+	if e.openOnSync && !dryRun {
+		if err := openSyncResult(e.vaultPath, result, e.openTarget); err != nil {
+			fmt.Printf("⚠ Warning: Could not open note in Obsidian: %v\n", err)
 		}
 	}
+	return nil
+}
 
-	// Stage 2: Summarize
-	if runAll || step == "summarize" {
-		if err := runSummarize(ctx, *limitFlag, syncState, *overwriteFlag, meetingIDs, cache); err != nil {
-			fmt.Printf("❌ Error in summarize stage: %v\n", err)
-			return
+// runAllCommand runs every stage in sequence. A dry run previews download, summarize and
+// sync only.
+func runAllCommand(e *cmdEnv) error {
+	// Restyle: regenerate summaries written in an older style
+	if e.opts.restyle && !skipOffline("restyle", serviceLLM) {
+		e.meetingIDs = findRestyleMeetings(e.syncState, e.cache)
+		if len(e.meetingIDs) == 0 {
+			fmt.Println("✅ All summaries already use the configured style")
+			return errNothingToDo
 		}
+		fmt.Printf("🎨 Restyling %d meeting(s) with style %q\n", len(e.meetingIDs), summaryStyle.Key())
+		e.overwrite = true
 	}
 
-	// Stage 3: Sync
-	if runAll || step == "sync" {
-		if err := runSync(ctx, obsidianVaultPath, *limitFlag, syncState, *overwriteFlag, *testFlag, *applyNormalizationFlag, meetingIDs, updateFields, cache); err != nil {
-			fmt.Printf("❌ Error in sync stage: %v\n", err)
-			return
+	if dryRun {
+		if !e.opts.restyle {
+			if err := e.downloadStage(e.meetingIDs); err != nil {
+				return err
+			}
+		}
+		if err := e.summarizeStage(e.meetingIDs); err != nil {
+			return err
 		}
+		return e.syncStage(e.meetingIDs)
 	}
+	return runAllStages(e)
+}
 
-	// Stage 4: Normalize tags (manual workflow for initial mass import)
-	if step == "normalize-prompt" {
-		// Generate normalization prompt from existing meeting summaries
-		if err := runNormalizePrompt(ctx, cache); err != nil {
-			fmt.Printf("❌ Error generating normalization prompt: %v\n", err)
-			return
+// runAllStages runs the stages of "all" in order; each daemon pass runs them too
+func runAllStages(e *cmdEnv) error {
+	// Stage 0: Extract tags from Obsidian
+	auditStage = "extract-tags"
+	if err := runExtractTags(e.vaultPath); err != nil {
+		return fmt.Errorf("error extracting tags: %w", err)
+	}
+
+	// Pick up action items checked off in the vault before notes are regenerated
+	auditStage = "action-items"
+	if err := runActionItemsSync(e.vaultPath, e.syncState, e.cache); err != nil {
+		return fmt.Errorf("error syncing action items: %w", err)
+	}
+
+	// Regenerate meetings flagged with krisp_resync: true in Obsidian
+	if _, err := runFlaggedResync(e.ctx, e.vaultPath, e.syncState, e.cache); err != nil {
+		return fmt.Errorf("error regenerating flagged meetings: %w", err)
+	}
+
+	// Restyling only needs cached transcripts
+	if !e.opts.restyle {
+		if err := e.downloadStage(e.meetingIDs); err != nil {
+			return err
 		}
 	}
+	if err := e.summarizeStage(e.meetingIDs); err != nil {
+		return err
+	}
+	auditStage = "sync"
+	if err := e.syncStage(e.meetingIDs); err != nil {
+		return err
+	}
 
-	// Extract tags from Obsidian vault
-	if step == "extract-tags" {
-		if err := runExtractTags(obsidianVaultPath); err != nil {
-			fmt.Printf("❌ Error extracting tags: %v\n", err)
-			return
+	// Archive old months when ARCHIVE_AFTER_MONTHS is set
+	if e.archiveMonths > 0 {
+		auditStage = "archive"
+		if err := runArchive(e.vaultPath); err != nil {
+			return fmt.Errorf("archive: %w", err)
 		}
 	}
 
-	// Repair: Ensure all cached meetings are in sync state
-	if step == "repair" {
-		if err := runRepair(syncState, cache); err != nil {
-			fmt.Printf("❌ Error in repair stage: %v\n", err)
-			return
+	// Inbox: record reviewed meetings and list the important ones still to review
+	auditStage = "inbox"
+	if err := runInbox(e.vaultPath, e.syncState, e.cache); err != nil {
+		return fmt.Errorf("inbox: %w", err)
+	}
+
+	// Search index: when SEARCH_INDEX is on, or the transcripts are in Obsidian's excluded files
+	if searchIndexWanted(e.vaultPath, e.syncState, e.cache) {
+		auditStage = "search-index"
+		if err := runSearchIndex(e.vaultPath, e.syncState, e.cache); err != nil {
+			return fmt.Errorf("search-index: %w", err)
 		}
 	}
 
-	// Update sync state
-	syncState.LastSyncTime = time.Now()
-	if err := syncState.Save(); err != nil {
-		fmt.Printf("⚠ Warning: Could not save sync state: %v\n", err)
+	// Plan: weekly planning note with open action items, when WEEKLY_PLAN is set
+	if weeklyPlanEnabled() {
+		auditStage = "plan"
+		if err := runWeeklyPlan(e.vaultPath, e.syncState, e.cache, false); err != nil {
+			return fmt.Errorf("plan: %w", err)
+		}
 	}
 
-	fmt.Println("\n✅ All requested stages completed!")
+	// Dashboards: a hub note per top-level tag, when TAG_DASHBOARDS is set
+	if tagDashboardsEnabled() {
+		auditStage = "dashboards"
+		if err := runTagDashboards(e.vaultPath, false); err != nil {
+			return fmt.Errorf("dashboards: %w", err)
+		}
+	}
+	return nil
+}
+
+// runReprocessCommand re-runs Krisp transcription, then cascades into re-summarize and re-sync
+func runReprocessCommand(e *cmdEnv) error {
+	if skipOffline("reprocess", serviceKrisp) {
+		return errNothingToDo
+	}
+	reprocessed, err := runReprocess(e.ctx, e.meetingIDs, e.syncState, e.cache)
+	if err != nil {
+		return err
+	}
+	if len(reprocessed) == 0 {
+		return errNothingToDo
+	}
+	e.overwrite = true
+	if err := e.summarizeStage(reprocessed); err != nil {
+		return err
+	}
+	return e.syncStage(reprocessed)
+}
+
+// runMergeCommand combines meetings into one and summarizes and syncs it. The originals
+// are only retired once the merged meeting's note is in the vault.
+func runMergeCommand(e *cmdEnv) error {
+	mergedID, originals, err := runMerge(e.syncState, e.cache, append(e.meetingIDs, e.opts.args...))
+	if err != nil {
+		return err
+	}
+	e.overwrite = true
+	if err := e.summarizeStage([]string{mergedID}); err != nil {
+		return err
+	}
+	if err := e.syncStage([]string{mergedID}); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	if !e.syncState.ObsidianSyncedMeetings[mergedID] {
+		return fmt.Errorf("%s has no note yet, so the original meetings were kept - run the merge again once it can be summarized and synced", mergedID)
+	}
+	return retireMergedMeetings(e.vaultPath, e.syncState, e.cache, mergedID, originals)
+}
+
+// runResyncCommand re-renders a month's or a tag's notes, keeping user edits
+func runResyncCommand(e *cmdEnv) error {
+	// Record checked-off action items before their notes are re-rendered
+	if err := runActionItemsSync(e.vaultPath, e.syncState, e.cache); err != nil {
+		return fmt.Errorf("error syncing action items: %w", err)
+	}
+	ids, err := findResyncMeetings(e.syncState, e.cache, e.opts.month, e.opts.tag)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("⚠ No synced meetings match")
+		return errNothingToDo
+	}
+	fmt.Printf("🔁 Re-syncing %d meeting(s)\n", len(ids))
+	e.overwrite = true
+	mergeOnOverwrite = true
+	return e.syncStage(ids)
+}
+
+// runRetryFailedCommand resumes meetings that failed in an earlier run at the stage they
+// failed in
+func runRetryFailedCommand(e *cmdEnv) error {
+	ids := e.syncState.failedMeetingIDs()
+	if len(ids) == 0 {
+		fmt.Println("✅ No failed meetings to retry")
+		return errNothingToDo
+	}
+	fmt.Printf("🔁 Retrying %d failed meeting(s)\n", len(ids))
+	retryIDs := e.syncState.retryMeetingIDs()
+	if len(retryIDs[stageDownload]) > 0 {
+		if err := e.downloadStage(retryIDs[stageDownload]); err != nil {
+			return err
+		}
+	}
+	if len(retryIDs[stageSummarize]) > 0 {
+		if err := e.summarizeStage(retryIDs[stageSummarize]); err != nil {
+			return err
+		}
+	}
+	if len(retryIDs[stageSync]) > 0 {
+		return e.syncStage(retryIDs[stageSync])
+	}
+	return nil
+}
+
+// runPlanCommand writes this week's planning note, first recording items checked off in
+// the vault so they aren't carried over
+func runPlanCommand(e *cmdEnv) error {
+	if err := runActionItemsSync(e.vaultPath, e.syncState, e.cache); err != nil {
+		return fmt.Errorf("error syncing action items: %w", err)
+	}
+	return runWeeklyPlan(e.vaultPath, e.syncState, e.cache, true)
+}
+
+// runDaemonCommand runs the pipeline on an interval until stopped
+func runDaemonCommand(e *cmdEnv) error {
+	interval, err := daemonInterval(e.opts.interval)
+	if err != nil {
+		return err
+	}
+	return runDaemon(e, interval)
+}
+
+// envBool reports whether an environment variable is set to a true value (1, true, yes, on)
+func envBool(name string) bool {
+	return envBoolDefault(name, false)
+}
+
+// envBoolDefault reads an on/off environment variable: 1, true, yes or on, and 0, false,
+// no or off. Unset, it is def; other values are reported and ignored.
+func envBoolDefault(name string, def bool) bool {
+	switch v := strings.ToLower(strings.TrimSpace(os.Getenv(name))); v {
+	case "":
+		return def
+	case "1", "true", "yes", "on":
+		return true
+	case "0", "false", "no", "off":
+		return false
+	default:
+		fmt.Printf("⚠ Ignoring invalid %s %q (expected true or false)\n", name, os.Getenv(name))
+		return def
+	}
 }
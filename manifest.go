@@ -0,0 +1,391 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manifest actions: what a run did to a vault file
+const (
+	manifestCreated  = "created"
+	manifestModified = "modified"
+	manifestDeleted  = "deleted"
+)
+
+const (
+	runsDir             = "runs"
+	defaultManifestKeep = 30
+)
+
+// ManifestEntry records one vault file touched by a run
+type ManifestEntry struct {
+	Path       string `json:"path"`
+	Action     string `json:"action"`
+	BeforeHash string `json:"before_hash,omitempty"` // content before the run (modified/deleted)
+	AfterHash  string `json:"after_hash,omitempty"`  // content the run left behind (created/modified)
+	Backup     string `json:"backup,omitempty"`      // copy of the original, relative to the run's backup folder
+}
+
+// SyncManifest lists the vault changes of one run, so the run can be rolled back
+type SyncManifest struct {
+	RunID     string           `json:"run_id"`
+	Step      string           `json:"step"`
+	StartedAt time.Time        `json:"started_at"`
+	Entries   []*ManifestEntry `json:"entries"`
+}
+
+// contentHash returns the SHA-256 of content as hex
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestPath returns where a run's manifest is stored
+func manifestPath(runID string) string {
+	return dataPath(filepath.Join(runsDir, runID+".json"))
+}
+
+// manifestBackupDir returns where a run's backups of original files are stored
+func manifestBackupDir(runID string) string {
+	return dataPath(filepath.Join(runsDir, runID))
+}
+
+// newRunID returns an ID for a run started at t. Millisecond precision keeps runs started
+// in the same second (e.g. by a daemon and a manual sync) apart; IDs sort by start time.
+func newRunID(t time.Time) string {
+	return t.Format("20060102-150405.000")
+}
+
+// ManifestVaultWriter records every change made through another writer in a manifest,
+// backing up files before their first modification
+type ManifestVaultWriter struct {
+	inner    VaultWriter
+	manifest *SyncManifest
+	byPath   map[string]*ManifestEntry
+	mu       sync.Mutex
+}
+
+// newManifestVaultWriter wraps a writer, recording changes under a new run ID
+func newManifestVaultWriter(inner VaultWriter, step string) *ManifestVaultWriter {
+	now := time.Now()
+	return &ManifestVaultWriter{
+		inner: inner,
+		manifest: &SyncManifest{
+			RunID:     newRunID(now),
+			Step:      step,
+			StartedAt: now,
+		},
+		byPath: make(map[string]*ManifestEntry),
+	}
+}
+
+func (w *ManifestVaultWriter) Exists(path string) bool {
+	return w.inner.Exists(path)
+}
+
+func (w *ManifestVaultWriter) ReadNote(path string) ([]byte, error) {
+	return w.inner.ReadNote(path)
+}
+
+func (w *ManifestVaultWriter) CreateNote(path string, content []byte) error {
+	if err := w.track(path, manifestModified); err != nil {
+		return err
+	}
+	if err := w.inner.CreateNote(path, content); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.byPath[filepath.Clean(path)].AfterHash = contentHash(content)
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *ManifestVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *ManifestVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *ManifestVaultWriter) DeleteNote(path string) error {
+	if err := w.track(path, manifestDeleted); err != nil {
+		return err
+	}
+	if err := w.inner.DeleteNote(path); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	entry := w.byPath[filepath.Clean(path)]
+	if entry.Action != manifestCreated {
+		entry.Action = manifestDeleted
+	}
+	entry.AfterHash = ""
+	w.mu.Unlock()
+	return nil
+}
+
+// MoveFolder records a moved folder as its files deleted from src (backed up) and created
+// under dest, so rolling the run back moves them back
+func (w *ManifestVaultWriter) MoveFolder(src, dest string) error {
+	files, err := folderFiles(src)
+	if err != nil {
+		return err
+	}
+	hashes := make(map[string]string, len(files))
+	for _, path := range files {
+		content, err := w.inner.ReadNote(path)
+		if err != nil {
+			return fmt.Errorf("error backing up %s: %w", path, err)
+		}
+		hashes[path] = contentHash(content)
+		if err := w.track(path, manifestDeleted); err != nil {
+			return err
+		}
+		if err := w.track(movedPath(path, src, dest), manifestModified); err != nil {
+			return err
+		}
+	}
+	if err := w.inner.MoveFolder(src, dest); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for path, hash := range hashes {
+		entry := w.byPath[filepath.Clean(path)]
+		if entry.Action != manifestCreated {
+			entry.Action = manifestDeleted
+		}
+		entry.AfterHash = ""
+		w.byPath[filepath.Clean(movedPath(path, src, dest))].AfterHash = hash
+	}
+	return nil
+}
+
+// track adds a manifest entry the first time a run touches a file, backing up its original content
+func (w *ManifestVaultWriter) track(path, action string) error {
+	path = filepath.Clean(path)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.byPath[path]; ok {
+		return nil
+	}
+
+	entry := &ManifestEntry{Path: path, Action: manifestCreated}
+	if w.inner.Exists(path) {
+		original, err := w.inner.ReadNote(path)
+		if err != nil {
+			return fmt.Errorf("error backing up %s: %w", path, err)
+		}
+		entry.Action = action
+		entry.BeforeHash = contentHash(original)
+		entry.Backup = entry.BeforeHash + filepath.Ext(path)
+		backupPath := filepath.Join(manifestBackupDir(w.manifest.RunID), entry.Backup)
+		if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+			return fmt.Errorf("error backing up %s: %w", path, err)
+		}
+		if err := os.WriteFile(backupPath, original, 0644); err != nil {
+			return fmt.Errorf("error backing up %s: %w", path, err)
+		}
+	}
+	w.byPath[path] = entry
+	w.manifest.Entries = append(w.manifest.Entries, entry)
+	return nil
+}
+
+// Save writes the manifest if the run changed anything, then prunes old runs (MANIFEST_KEEP, default 30)
+func (w *ManifestVaultWriter) Save() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.manifest.Entries) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataPath(runsDir), 0755); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+	if err := writeFileAtomic(manifestPath(w.manifest.RunID), data); err != nil {
+		return err
+	}
+	fmt.Printf("🧾 Run %s changed %d vault file(s) (undo with krisp-sync rollback --run %s)\n", w.manifest.RunID, len(w.manifest.Entries), w.manifest.RunID)
+	return pruneManifests()
+}
+
+// Rotate saves the manifest and records further changes under a new run ID, so each run of
+// a long-running command can be rolled back on its own. Returns the new run ID.
+func (w *ManifestVaultWriter) Rotate() (string, error) {
+	err := w.Save()
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.manifest = &SyncManifest{
+		RunID:     newRunID(now),
+		Step:      w.manifest.Step,
+		StartedAt: now,
+	}
+	w.byPath = make(map[string]*ManifestEntry)
+	return w.manifest.RunID, err
+}
+
+// listManifests returns the saved run IDs, newest first
+func listManifests() ([]string, error) {
+	files, err := filepath.Glob(dataPath(filepath.Join(runsDir, "*.json")))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// pruneManifests removes all but the newest MANIFEST_KEEP runs and their backups
+func pruneManifests() error {
+	keep := defaultManifestKeep
+	if v := os.Getenv("MANIFEST_KEEP"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid MANIFEST_KEEP %q: must be a positive integer", v)
+		}
+		keep = n
+	}
+
+	ids, err := listManifests()
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+	for _, id := range ids[keep:] {
+		os.Remove(manifestPath(id))
+		os.RemoveAll(manifestBackupDir(id))
+	}
+	return nil
+}
+
+// loadManifest reads a run's manifest
+func loadManifest(runID string) (*SyncManifest, error) {
+	data, err := os.ReadFile(manifestPath(runID))
+	if err != nil {
+		return nil, fmt.Errorf("no manifest for run %q: %w", runID, err)
+	}
+	var manifest SyncManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest for run %q: %w", runID, err)
+	}
+	return &manifest, nil
+}
+
+// runRollback undoes one run's vault changes: created files are deleted and modified or
+// deleted files are restored from backup. Files changed since the run are left alone unless
+// force is set. Meetings whose summary note is deleted, and not restored elsewhere (as when
+// an archived month is moved back), are marked unsynced so a later sync recreates them. Without a run ID, lists the runs that can be rolled back.
+func runRollback(runID string, force bool, syncState *SyncState) error {
+	if runID == "" {
+		ids, err := listManifests()
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			fmt.Println("No runs to roll back")
+			return nil
+		}
+		fmt.Println("\n=== Runs that can be rolled back (newest first) ===")
+		for _, id := range ids {
+			if m, err := loadManifest(id); err == nil {
+				fmt.Printf("  %s  %-14s %d file(s)\n", m.RunID, m.Step, len(m.Entries))
+			}
+		}
+		fmt.Println("\nRoll one back with krisp-sync rollback --run <id>")
+		return nil
+	}
+
+	manifest, err := loadManifest(runID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n=== Rolling back run %s (%s, %d file(s)) ===\n", manifest.RunID, manifest.Step, len(manifest.Entries))
+
+	restored, deleted, skipped := 0, 0, 0
+	removedNotes := make(map[string]bool) // meeting ID -> its summary note was deleted
+	restoredNotes := make(map[string]bool)
+	for i := len(manifest.Entries) - 1; i >= 0; i-- {
+		entry := manifest.Entries[i]
+
+		// Don't clobber edits made after the run
+		current, err := vaultWriter.ReadNote(entry.Path)
+		exists := err == nil
+		if !force && exists && contentHash(current) != entry.AfterHash {
+			fmt.Printf("  ⚠ Changed since the run, skipping: %s\n", entry.Path)
+			skipped++
+			continue
+		}
+
+		switch entry.Action {
+		case manifestCreated:
+			if !exists {
+				continue
+			}
+			if err := vaultWriter.DeleteNote(entry.Path); err != nil {
+				fmt.Printf("  ⚠ Error deleting %s: %v\n", entry.Path, err)
+				skipped++
+				continue
+			}
+			fmt.Printf("  🗑  Deleted: %s\n", entry.Path)
+			deleted++
+			if id, ok := strings.CutSuffix(filepath.Base(entry.Path), "-summary.md"); ok {
+				removedNotes[id] = true
+			}
+		case manifestModified, manifestDeleted:
+			original, err := os.ReadFile(filepath.Join(manifestBackupDir(manifest.RunID), entry.Backup))
+			if err != nil {
+				fmt.Printf("  ⚠ Backup of %s missing: %v\n", entry.Path, err)
+				skipped++
+				continue
+			}
+			if err := vaultWriter.CreateNote(entry.Path, original); err != nil {
+				fmt.Printf("  ⚠ Error restoring %s: %v\n", entry.Path, err)
+				skipped++
+				continue
+			}
+			fmt.Printf("  ↺ Restored: %s\n", entry.Path)
+			restored++
+			if id, ok := strings.CutSuffix(filepath.Base(entry.Path), "-summary.md"); ok {
+				restoredNotes[id] = true
+			}
+		}
+	}
+
+	// Meetings whose summary note was deleted are synced again by the next run
+	unsynced := 0
+	for id := range removedNotes {
+		if !restoredNotes[id] {
+			syncState.UnmarkObsidianSynced(id)
+			unsynced++
+		}
+	}
+	if unsynced > 0 {
+		if err := syncState.Save(); err != nil {
+			return fmt.Errorf("error saving sync state: %w", err)
+		}
+	}
+
+	fmt.Printf("\n✅ Restored %d file(s), deleted %d, skipped %d\n", restored, deleted, skipped)
+	if skipped > 0 && !force {
+		fmt.Println("   Use --force to roll back files changed since the run")
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MeetingTombstone replaces the cache file of a meeting merged into another. It keeps the
+// original meeting, and stops it from being downloaded, summarized or synced again.
+type MeetingTombstone struct {
+	MergedInto string    `json:"merged_into"`
+	MergedAt   time.Time `json:"merged_at"`
+	Meeting    *Meeting  `json:"meeting"`
+}
+
+// tombstonePath returns where a merged meeting's tombstone is cached
+func (c *Cache) tombstonePath(meetingID string) string {
+	return filepath.Join(c.dir, meetingID+"-merged.json")
+}
+
+// MergedInto returns the meeting a meeting was merged into, or "" if it wasn't merged
+func (c *Cache) MergedInto(meetingID string) string {
+	data, err := os.ReadFile(c.tombstonePath(meetingID))
+	if err != nil {
+		return ""
+	}
+	var tombstone MeetingTombstone
+	if err := json.Unmarshal(data, &tombstone); err != nil {
+		return ""
+	}
+	return tombstone.MergedInto
+}
+
+// SaveTombstone replaces a meeting's cache file with a tombstone pointing at mergedID
+func (c *Cache) SaveTombstone(meeting *Meeting, mergedID string) error {
+	data, err := json.MarshalIndent(MeetingTombstone{MergedInto: mergedID, MergedAt: time.Now(), Meeting: meeting}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	if err := writeFileAtomic(c.tombstonePath(meeting.ID), data); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(c.dir, meeting.ID+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(c.meetings, meeting.ID)
+	return nil
+}
+
+// mergedMeetingID derives the ID of the meeting merging ids. Like Krisp IDs it has no
+// hyphens, so it isn't mistaken for a derived cache file.
+func mergedMeetingID(ids []string) string {
+	return "merged" + contentHash([]byte(strings.Join(ids, ",")))[:24]
+}
+
+// speakerKey identifies a named speaker across meetings, by email or else by name
+func speakerKey(info SpeakerInfo) string {
+	if email := strings.ToLower(strings.TrimSpace(info.Person.Email)); email != "" {
+		return email
+	}
+	return strings.ToLower(strings.TrimSpace(info.Person.FirstName + " " + info.Person.LastName))
+}
+
+// mergeMeetings combines meetings, oldest first, into one meeting with the given ID. Each
+// transcript is shifted by its start time relative to the first meeting; named speakers
+// are matched across meetings by email or name, and unnamed ones kept apart.
+func mergeMeetings(id string, meetings []*Meeting) (*Meeting, error) {
+	first, last := meetings[0], meetings[len(meetings)-1]
+	merged := &Meeting{
+		ID:            id,
+		Title:         first.Title,
+		CreatedAt:     first.CreatedAt,
+		Duration:      int(last.CreatedAt.Sub(first.CreatedAt).Seconds()) + last.Duration,
+		CalendarEvent: first.CalendarEvent,
+		Account:       first.Account,
+	}
+	merged.Speakers.Data = make(map[string]SpeakerInfo)
+	merged.Resources.Transcript.Status = "uploaded"
+
+	var segments []Segment
+	var chat []ChatMessage
+	indexByKey := make(map[string]int)
+	nextIndex := 0
+	participants := make(map[string]bool)
+
+	for n, m := range meetings {
+		if m.Resources.Transcript.Status != "uploaded" || m.Resources.Transcript.Content == "" {
+			return nil, fmt.Errorf("meeting %s has no transcript yet (status: %s)", m.ID, m.Resources.Transcript.Status)
+		}
+		var meetingSegments []Segment
+		if err := json.Unmarshal([]byte(m.Resources.Transcript.Content), &meetingSegments); err != nil {
+			return nil, fmt.Errorf("error parsing transcript of %s: %w", m.ID, err)
+		}
+		offset := m.CreatedAt.Sub(first.CreatedAt).Seconds()
+
+		// Speaker indexes of this meeting in the merged one
+		indexes := make(map[int]int)
+		for _, seg := range meetingSegments {
+			if _, ok := indexes[seg.SpeakerIndex]; ok {
+				continue
+			}
+			info, named := m.Speakers.Data[strconv.Itoa(seg.SpeakerIndex)]
+			key := ""
+			if named {
+				key = speakerKey(info)
+			}
+			switch {
+			case n == 0:
+				indexes[seg.SpeakerIndex] = seg.SpeakerIndex
+			case key != "" && indexByKey[key] != 0:
+				indexes[seg.SpeakerIndex] = indexByKey[key] - 1
+			default:
+				indexes[seg.SpeakerIndex] = nextIndex
+			}
+			index := indexes[seg.SpeakerIndex]
+			if index >= nextIndex {
+				nextIndex = index + 1
+			}
+			if named {
+				merged.Speakers.Data[strconv.Itoa(index)] = info
+				if key != "" && indexByKey[key] == 0 {
+					indexByKey[key] = index + 1 // 0 means unseen
+				}
+			}
+		}
+
+		for _, seg := range meetingSegments {
+			seg.ID = len(segments)
+			seg.SpeakerIndex = indexes[seg.SpeakerIndex]
+			seg.Speech.Start += offset
+			seg.Speech.End += offset
+			for i := range seg.Speech.Words {
+				seg.Speech.Words[i].Start += offset
+				seg.Speech.Words[i].End += offset
+			}
+			segments = append(segments, seg)
+		}
+
+		messages, err := parseChatMessages(m)
+		if err != nil {
+			fmt.Printf("  ⚠ Leaving out the chat of %s: %v\n", m.ID, err)
+		}
+		for _, msg := range messages {
+			msg.Timestamp += offset
+			chat = append(chat, msg)
+		}
+
+		for _, p := range m.Participants {
+			key := strings.ToLower(firstNonEmpty(p.Email, p.FirstName+" "+p.LastName))
+			if !participants[key] {
+				participants[key] = true
+				merged.Participants = append(merged.Participants, p)
+			}
+		}
+		merged.Resources.Attachments = append(merged.Resources.Attachments, m.Resources.Attachments...)
+		if merged.CalendarEvent == nil {
+			merged.CalendarEvent = m.CalendarEvent
+		}
+	}
+
+	content, err := json.Marshal(segments)
+	if err != nil {
+		return nil, err
+	}
+	merged.Resources.Transcript.Content = string(content)
+	if len(chat) > 0 {
+		content, err := json.Marshal(chat)
+		if err != nil {
+			return nil, err
+		}
+		merged.Resources.Chat.Status = "uploaded"
+		merged.Resources.Chat.Content = string(content)
+	}
+	return merged, nil
+}
+
+// runMerge combines cached meetings (e.g. a call that dropped and was reconnected) into one
+// logical meeting and caches it. The originals are left alone until the merged meeting's note
+// has been written (see retireMergedMeetings), so a failed summary or sync loses nothing and
+// the merge can simply be run again. Returns the merged meeting's ID, to be summarized and
+// synced, and the IDs of the originals.
+func runMerge(syncState *SyncState, cache *Cache, meetingIDs []string) (string, []string, error) {
+	fmt.Println("\n=== Merge: Combining meetings ===")
+	if len(meetingIDs) < 2 {
+		return "", nil, fmt.Errorf("merge needs at least two meeting IDs (krisp-sync merge <id1> <id2>, or --meeting <id1>,<id2>)")
+	}
+
+	var meetings []*Meeting
+	for _, id := range uniqueStrings(meetingIDs) {
+		if into := cache.MergedInto(id); into != "" {
+			return "", nil, fmt.Errorf("meeting %s was already merged into %s", id, into)
+		}
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			return "", nil, fmt.Errorf("meeting %s is not cached: %w", id, err)
+		}
+		meetings = append(meetings, m)
+	}
+	if len(meetings) < 2 {
+		return "", nil, fmt.Errorf("merge needs at least two different meetings")
+	}
+	sort.Slice(meetings, func(i, j int) bool { return meetings[i].CreatedAt.Before(meetings[j].CreatedAt) })
+
+	ids := make([]string, len(meetings))
+	for i, m := range meetings {
+		ids[i] = m.ID
+	}
+	merged, err := mergeMeetings(mergedMeetingID(ids), meetings)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := cache.SaveMeeting(merged); err != nil {
+		return "", nil, err
+	}
+
+	// Attachments are cached per meeting
+	for _, m := range meetings {
+		for _, a := range m.Resources.Attachments {
+			name := attachmentFileName(a)
+			if !cache.AttachmentExists(m.ID, name) || cache.AttachmentExists(merged.ID, name) {
+				continue
+			}
+			data, err := os.ReadFile(cache.AttachmentPath(m.ID, name))
+			if err == nil {
+				err = cache.SaveAttachment(merged.ID, name, data)
+			}
+			if err != nil {
+				fmt.Printf("  ⚠ Error copying attachment %s: %v\n", name, err)
+			}
+		}
+	}
+	syncState.MarkDownloaded(merged.ID)
+	if err := syncState.Save(); err != nil {
+		return "", nil, err
+	}
+
+	fmt.Printf("✓ Merged %d meetings into %s (%s, %s)\n", len(meetings), merged.ID, merged.Title, formatTimestamp(float64(merged.Duration)))
+	return merged.ID, ids, nil
+}
+
+// retireMergedMeetings removes the notes of meetings merged into mergedID and replaces their
+// cache files with tombstones. Run once the merged meeting's note has been written.
+func retireMergedMeetings(vaultPath string, syncState *SyncState, cache *Cache, mergedID string, originalIDs []string) error {
+	for _, id := range originalIDs {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			return fmt.Errorf("meeting %s is not cached: %w", id, err)
+		}
+		for _, path := range []string{summaryNotePath(vaultPath, m), transcriptNotePath(vaultPath, m)} {
+			if !vaultWriter.Exists(path) {
+				continue
+			}
+			if err := vaultWriter.DeleteNote(path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", vaultRelative(vaultPath, path), err)
+			}
+			fmt.Printf("  🗑  Removed %s\n", vaultRelative(vaultPath, path))
+		}
+		if err := cache.SaveTombstone(m, mergedID); err != nil {
+			return fmt.Errorf("failed to write tombstone for %s: %w", m.ID, err)
+		}
+		syncState.Forget(m.ID)
+		fmt.Printf("  🪦 %s (%s) merged into %s\n", m.ID, m.CreatedAt.Local().Format("2006-01-02 15:04"), mergedID)
+	}
+	return syncState.Save()
+}
+
+// isMergedMeetingID reports whether an ID was made by mergedMeetingID. Such meetings only
+// exist locally, so there is nothing to fetch or reprocess in Krisp.
+func isMergedMeetingID(id string) bool {
+	return strings.HasPrefix(id, "merged")
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// formatVersionFile records which format version the data directory is in
+const formatVersionFile = "format-version.json"
+
+// migrationBackupsDir holds copies of what each migration changed, under the data directory
+const migrationBackupsDir = "migration-backups"
+
+// storagePaths are the files and folders a migration may change
+type storagePaths struct {
+	DataDir   string
+	CacheDir  string
+	StatePath string
+}
+
+// migration upgrades the data directory from the previous format version to Version
+type migration struct {
+	Version     int
+	Description string
+	// Backup lists the files and folders copied aside before the migration runs
+	Backup func(p storagePaths) []string
+	Apply  func(p storagePaths) error
+}
+
+// migrations are applied in order; append new ones with the next version. The last
+// version is the format this build writes.
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "add the tracking maps missing from sync state written by early versions",
+		Backup:      func(p storagePaths) []string { return []string{p.StatePath} },
+		Apply:       migrateStateMaps,
+	},
+}
+
+// currentFormatVersion is the format version this build reads and writes
+func currentFormatVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// formatVersion is the content of the format version file
+type formatVersion struct {
+	Version    int       `json:"version"`
+	MigratedAt time.Time `json:"migrated_at"`
+}
+
+// migrateStorage brings the data directory up to the current format version on startup,
+// backing up what each migration changes. Data written by a newer krisp-sync is refused
+// rather than misread.
+func migrateStorage(p storagePaths) error {
+	versionPath := filepath.Join(p.DataDir, formatVersionFile)
+	latest := currentFormatVersion()
+
+	version := 0
+	if data, err := os.ReadFile(versionPath); err == nil {
+		var recorded formatVersion
+		if err := json.Unmarshal(data, &recorded); err != nil {
+			return fmt.Errorf("invalid %s: %w", versionPath, err)
+		}
+		version = recorded.Version
+	} else if !fileExists(p.StatePath) && !fileExists(p.CacheDir) {
+		// Nothing written yet: start out in the current format
+		return writeFormatVersion(versionPath, latest)
+	}
+
+	if version > latest {
+		return fmt.Errorf("%s is in format version %d, but this krisp-sync only knows up to version %d; upgrade krisp-sync", p.DataDir, version, latest)
+	}
+
+	var pending []migration
+	for _, m := range migrations {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if dryRun {
+		return fmt.Errorf("%s needs %d migration(s) to format version %d; run once without --dry-run to apply them", p.DataDir, len(pending), latest)
+	}
+
+	fmt.Printf("\n=== Migrating data from format version %d to %d ===\n", version, latest)
+	for _, m := range pending {
+		backup := filepath.Join(p.DataDir, migrationBackupsDir, fmt.Sprintf("%s-v%d", time.Now().Format("20060102-150405"), m.Version))
+		for _, path := range m.Backup(p) {
+			if err := backupPath(path, backup); err != nil {
+				return fmt.Errorf("migration %d: backing up %s: %w", m.Version, path, err)
+			}
+		}
+		if err := m.Apply(p); err != nil {
+			return fmt.Errorf("migration %d (%s) failed, backup in %s: %w", m.Version, m.Description, backup, err)
+		}
+		if err := writeFormatVersion(versionPath, m.Version); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %d: %s\n", m.Version, m.Description)
+	}
+	fmt.Printf("📦 Backups in %s\n", filepath.Join(p.DataDir, migrationBackupsDir))
+	return nil
+}
+
+// writeFormatVersion records the data directory's format version
+func writeFormatVersion(path string, version int) error {
+	data, err := json.MarshalIndent(formatVersion{Version: version, MigratedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to record format version: %w", err)
+	}
+	return nil
+}
+
+// backupPath copies a file or folder into the backup folder; missing paths are skipped
+func backupPath(path, backupDir string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(backupDir, filepath.Base(path))
+	if !info.IsDir() {
+		return copyFileTo(path, target)
+	}
+	return filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		return copyFileTo(file, filepath.Join(target, rel))
+	})
+}
+
+// copyFileTo copies a file, creating the target's folder
+func copyFileTo(from, to string) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0644)
+}
+
+// migrateStateMaps adds the tracking maps early versions left out of the sync state file
+// (or wrote as null), so loading it can rely on them
+func migrateStateMaps(p storagePaths) error {
+	data, err := os.ReadFile(p.StatePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("could not parse sync state: %w", err)
+	}
+	for _, key := range []string{"synced_meetings", "summarized_meetings", "obsidian_synced_meetings"} {
+		if raw, ok := fields[key]; !ok || string(raw) == "null" {
+			fields[key] = json.RawMessage("{}")
+		}
+	}
+	migrated, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p.StatePath, migrated)
+}
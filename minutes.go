@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Minutes export formats (converted from the markdown note with pandoc)
+const (
+	minutesDOCX = "docx"
+	minutesPDF  = "pdf"
+)
+
+// rewriteMinutes lets syncs replace existing minutes: set by an explicit --overwrite, and in
+// test runs, which only write to the sandbox. Resyncs, merges and reprocessing keep them.
+var rewriteMinutes bool
+
+// defaultMinutesTags are the tags that mark a meeting as needing formal minutes
+var defaultMinutesTags = []string{"board", "board-meeting", "steering", "steering-committee"}
+
+// minutesTags returns the tags that mark a meeting as needing formal minutes (MINUTES_TAGS)
+func minutesTags() []string {
+	value := os.Getenv("MINUTES_TAGS")
+	if strings.TrimSpace(value) == "" {
+		return defaultMinutesTags
+	}
+	return splitTags(strings.ToLower(value))
+}
+
+// needsMinutes reports whether a meeting is tagged as a board or steering meeting
+func needsMinutes(summaryData *SummaryData) bool {
+	if summaryData == nil || !envBoolDefault("MINUTES", true) {
+		return false
+	}
+	wanted := minutesTags()
+	for _, tag := range splitTags(strings.ToLower(summaryData.Tags)) {
+		if contains(wanted, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// minutesNotePath returns the absolute path of a meeting's minutes note
+func minutesNotePath(vaultPath string, m *Meeting) string {
+	return filepath.Join(meetingsDir(vaultPath, m.CreatedAt), m.ID+"-minutes.md")
+}
+
+// meetingAttendance splits a meeting's invitees into those who spoke and those who didn't
+// (apologies). Without an invitee list everyone who spoke is an attendee.
+func meetingAttendance(m *Meeting) ([]string, []string) {
+	var attendees []string
+	spoke := make(map[string]bool)
+	for _, speakerInfo := range m.Speakers.Data {
+		name := preferredName(speakerInfo.Person.Email, strings.TrimSpace(speakerInfo.Person.FirstName+" "+speakerInfo.Person.LastName))
+		if name != "" {
+			attendees = append(attendees, name)
+		}
+		if speakerInfo.Person.Email != "" {
+			spoke[strings.ToLower(speakerInfo.Person.Email)] = true
+		}
+	}
+
+	var apologies []string
+	for _, p := range m.Participants {
+		if p.Email != "" && spoke[strings.ToLower(p.Email)] {
+			continue
+		}
+		name := preferredName(p.Email, strings.TrimSpace(p.FirstName+" "+p.LastName))
+		if name == "" {
+			name = p.Email
+		}
+		if name != "" && !contains(attendees, name) {
+			apologies = append(apologies, name)
+		}
+	}
+
+	attendees = uniqueStrings(attendees)
+	apologies = uniqueStrings(apologies)
+	sort.Strings(attendees)
+	sort.Strings(apologies)
+	return attendees, apologies
+}
+
+// renderMinutes renders formal minutes: attendees, apologies, agenda, resolutions and the action register
+func renderMinutes(m *Meeting, summaryData *SummaryData) string {
+	var sb strings.Builder
+	title := firstNonEmpty(summaryData.Title, m.Title)
+	start := m.CreatedAt.Local()
+	end := start.Add(time.Duration(m.Duration) * time.Second)
+
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("date: %s\n", start.Format("2006-01-02")))
+	sb.WriteString("type: minutes\n")
+	sb.WriteString(fmt.Sprintf("title: %q\n", "Minutes: "+title))
+	sb.WriteString(fmt.Sprintf("meeting_id: %s\n", m.ID))
+	sb.WriteString("---\n\n")
+
+	sb.WriteString(fmt.Sprintf("# Minutes of %s\n\n", title))
+	sb.WriteString(fmt.Sprintf("**Date**: %s  \n", start.Format("Monday, 2 January 2006")))
+	sb.WriteString(fmt.Sprintf("**Time**: %s – %s  \n", start.Format("15:04"), end.Format("15:04")))
+	sb.WriteString(fmt.Sprintf("**Summary**: [[%s-summary|%s]]\n\n", m.ID, title))
+
+	attendees, apologies := meetingAttendance(m)
+	sb.WriteString("## 1. Attendees\n")
+	if len(attendees) == 0 {
+		sb.WriteString("_Not recorded_\n")
+	}
+	for _, name := range attendees {
+		sb.WriteString(fmt.Sprintf("- %s\n", name))
+	}
+	sb.WriteString("\n## 2. Apologies\n")
+	if len(apologies) == 0 {
+		sb.WriteString("_None_\n")
+	}
+	for _, name := range apologies {
+		sb.WriteString(fmt.Sprintf("- %s\n", name))
+	}
+
+	sb.WriteString("\n## 3. Agenda\n")
+	if len(summaryData.Agenda) > 0 {
+		for i, slice := range summaryData.Agenda {
+			sb.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, slice.Item))
+			if !slice.Discussed() {
+				sb.WriteString("   - Not discussed; deferred\n")
+			}
+			for _, takeaway := range slice.Takeaways {
+				sb.WriteString(fmt.Sprintf("   - %s\n", takeaway))
+			}
+		}
+	} else if len(summaryData.TopicDetails) > 0 {
+		for i, detail := range summaryData.TopicDetails {
+			sb.WriteString(fmt.Sprintf("%d. **%s**  \n   %s\n", i+1, detail.Topic, detail.Summary))
+		}
+	} else {
+		for i, topic := range summaryData.Topics {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, topic))
+		}
+	}
+
+	sb.WriteString("\n## 4. Resolutions\n")
+	if len(summaryData.Decisions) == 0 {
+		sb.WriteString("_No resolutions recorded_\n")
+	}
+	for i, decision := range summaryData.Decisions {
+		sb.WriteString(fmt.Sprintf("%d. **Resolved** that %s\n", i+1, lowerFirst(strings.TrimSuffix(decision, "."))+"."))
+	}
+
+	sb.WriteString("\n## 5. Action Register\n")
+	if len(summaryData.ActionItems) == 0 {
+		sb.WriteString("_No actions recorded_\n")
+	} else {
+		sb.WriteString("| # | Action | Owner | Status |\n|---|---|---|---|\n")
+		for i, item := range summaryData.ActionItems {
+			status := "Open"
+			if item.Done {
+				status = "Complete"
+			}
+			sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n", i+1, escapeTableCell(item.Text), escapeTableCell(firstNonEmpty(item.Owner, "—")), status))
+		}
+	}
+	// Attendee emails of confidential meetings are redacted
+	if isConfidential(m, summaryData) {
+		return string(redactEmails([]byte(sb.String()), m))
+	}
+	return sb.String()
+}
+
+// lowerFirst lowercases the first letter of a sentence, unless it starts a name or acronym
+func lowerFirst(s string) string {
+	if len(s) < 2 || strings.ToUpper(s[:2]) == s[:2] {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// escapeTableCell keeps text from breaking a markdown table row
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
+
+// writeMinutesNote writes the minutes note of a board or steering meeting. Existing
+// minutes are only replaced when overwrite is set, since they're often edited before approval.
+func writeMinutesNote(vaultPath string, m *Meeting, summaryData *SummaryData, overwrite bool) (string, bool, error) {
+	if !needsMinutes(summaryData) {
+		return "", false, nil
+	}
+	path := minutesNotePath(vaultPath, m)
+	if vaultWriter.Exists(path) && !overwrite {
+		return path, false, nil
+	}
+	if err := vaultWriter.CreateNote(path, []byte(renderMinutes(m, summaryData))); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// exportMinutes converts a minutes note to DOCX or PDF with pandoc
+func exportMinutes(notePath, outputDir, format string) (string, error) {
+	pandoc, err := exec.LookPath("pandoc")
+	if err != nil {
+		return "", fmt.Errorf("%s export needs the pandoc CLI on PATH", format)
+	}
+	out := filepath.Join(outputDir, strings.TrimSuffix(filepath.Base(notePath), ".md")+"."+format)
+	if output, err := exec.Command(pandoc, notePath, "--from", "markdown-yaml_metadata_block", "-o", out).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("pandoc failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return out, nil
+}
+
+// runMinutes (re)writes the minutes notes of synced board and steering meetings (or the
+// given meetings) and optionally exports them as DOCX or PDF
+func runMinutes(obsidianVaultPath string, syncState *SyncState, cache *Cache, meetingIDs []string, format string) error {
+	fmt.Println("\n=== Minutes: Formal minutes for board and steering meetings ===")
+
+	format = strings.ToLower(format)
+	if format != "" && format != minutesDOCX && format != minutesPDF {
+		return fmt.Errorf("unknown minutes format %q (expected docx or pdf)", format)
+	}
+	outputDir := dataDir
+	if configured := os.Getenv("MINUTES_OUTPUT_DIR"); configured != "" {
+		dir, err := resolvePath(configured, obsidianVaultPath)
+		if err != nil {
+			return err
+		}
+		outputDir = dir
+	}
+	if format != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create minutes output directory: %w", err)
+		}
+	}
+
+	ids := meetingIDs
+	if len(ids) == 0 {
+		for id := range syncState.ObsidianSyncedMeetings {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+
+	written := 0
+	for _, id := range ids {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		summaryData, err := cache.LoadSummary(id)
+		if err != nil {
+			continue
+		}
+		// Explicitly requested meetings get minutes whatever their tags
+		if len(meetingIDs) == 0 && !needsMinutes(summaryData) {
+			continue
+		}
+
+		path := minutesNotePath(obsidianVaultPath, m)
+		if err := vaultWriter.CreateNote(path, []byte(renderMinutes(m, summaryData))); err != nil {
+			fmt.Printf("  ⚠ Error writing minutes for %s: %v\n", id, err)
+			continue
+		}
+		written++
+		fmt.Printf("  ✓ %s\n", vaultRelative(obsidianVaultPath, path))
+
+		if format != "" {
+			out, err := exportMinutes(path, outputDir, format)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("    📄 %s\n", out)
+		}
+	}
+
+	fmt.Printf("\n✅ Wrote minutes for %d meeting(s)\n", written)
+	return nil
+}
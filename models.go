@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+var (
+	// errContentBlocked is returned when the model refuses to answer because of safety filters
+	errContentBlocked = errors.New("response blocked by content filter")
+	// errSchemaFailure is returned when a response can't be read as a summary
+	errSchemaFailure = errors.New("response does not match the summary schema")
+)
+
+// ContentBlockedError is a response the model's content filter blocked, for errors.Is
+// with errContentBlocked
+type ContentBlockedError struct {
+	Reason string // block reason reported by the model, e.g. "SAFETY"
+}
+
+func (e *ContentBlockedError) Error() string {
+	return fmt.Sprintf("%v: %s", errContentBlocked, e.Reason)
+}
+
+func (e *ContentBlockedError) Is(target error) bool {
+	return target == errContentBlocked
+}
+
+// ollamaTagRegex matches the tag of an Ollama model, e.g. "8b" or "70b-instruct-q4_K_M"
+var ollamaTagRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// summaryModelsFromEnv reads SUMMARY_MODELS, an ordered, comma-separated list of models
+// to try for summaries (e.g. "gemini-2.0-flash-lite,gemini-2.5-pro"). Entries may be
+// prefixed with their backend ("vertex:gemini-2.5-pro", "openai:gpt-4o"); entries without
+// one use LLM_PROVIDER. Ollama model names have colons of their own ("llama3.1:8b"): with
+// LLM_PROVIDER=ollama, an entry whose part before the colon isn't a backend is a model
+// when the part after it looks like a tag. Any other unknown prefix is an error.
+func summaryModelsFromEnv() ([]string, error) {
+	v := os.Getenv("SUMMARY_MODELS")
+	if strings.TrimSpace(v) == "" {
+		return []string{defaultModel()}, nil
+	}
+	defaultProvider, err := llmProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if backend, model, ok := strings.Cut(entry, ":"); ok {
+			switch {
+			case backend == defaultProvider:
+				// The default provider's models are recorded without the prefix
+				entry = model
+			case isLLMProvider(backend):
+			case defaultProvider == providerOllama && ollamaTagRegex.MatchString(model):
+			default:
+				return nil, fmt.Errorf("invalid SUMMARY_MODELS entry %q: unknown backend %q (supported: %s)", entry, backend, strings.Join(llmProviders, ", "))
+			}
+		}
+		models = append(models, entry)
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("SUMMARY_MODELS has no models")
+	}
+	return models, nil
+}
+
+// blockedFinishReasons are finish reasons meaning the candidate was filtered
+var blockedFinishReasons = map[genai.FinishReason]bool{
+	genai.FinishReasonSafety:            true,
+	genai.FinishReasonRecitation:        true,
+	genai.FinishReasonBlocklist:         true,
+	genai.FinishReasonProhibitedContent: true,
+	genai.FinishReasonSPII:              true,
+}
+
+// checkBlocked returns a *ContentBlockedError if the prompt or response was filtered
+func checkBlocked(resp *genai.GenerateContentResponse) error {
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+		return &ContentBlockedError{Reason: string(resp.PromptFeedback.BlockReason)}
+	}
+	if len(resp.Candidates) > 0 && blockedFinishReasons[resp.Candidates[0].FinishReason] {
+		return &ContentBlockedError{Reason: string(resp.Candidates[0].FinishReason)}
+	}
+	return nil
+}
+
+// isQuotaError reports whether err is a rate-limit or quota error from the API
+func isQuotaError(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 429 || strings.Contains(apiErr.Status, "RESOURCE_EXHAUSTED")
+	}
+	var openAIErr *openAIError
+	if errors.As(err, &openAIErr) {
+		return openAIErr.StatusCode == 429
+	}
+	var anthropicErr *anthropicError
+	if errors.As(err, &anthropicErr) {
+		// 529: the API is overloaded
+		return anthropicErr.StatusCode == 429 || anthropicErr.StatusCode == 529
+	}
+	return false
+}
+
+// shouldFallBack reports whether a failure is worth retrying on the next model in the chain
+func shouldFallBack(err error) bool {
+	return isTransientLLMError(err) || errors.Is(err, errContentBlocked) || errors.Is(err, errSchemaFailure)
+}
+
+// validSummaryResponse reports whether a response (after repair) has the required summary fields
+func validSummaryResponse(response string) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(response), &fields); err != nil {
+		if err := json.Unmarshal([]byte(repairJSON(response)), &fields); err != nil {
+			return false
+		}
+	}
+	for _, key := range []string{"description", "tags", "topics", "topic_details"} {
+		if _, ok := fields[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Name guard modes (NAME_GUARD): what to do with people named in a summary who
+// aren't speakers in the meeting or on the allowlist
+const (
+	nameGuardOff   = "off"
+	nameGuardFlag  = "flag"  // highlight unknown names and list them in a warning (default)
+	nameGuardStrip = "strip" // replace unknown names with "someone"
+)
+
+// nameGuardMode returns the configured name guard mode
+func nameGuardMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("NAME_GUARD"))); mode {
+	case nameGuardOff, nameGuardStrip:
+		return mode
+	default:
+		return nameGuardFlag
+	}
+}
+
+// allowedNames returns the names a meeting's summary may mention: its speakers and
+// participants plus the NAME_ALLOWLIST (comma-separated). Returns nil when nobody in the
+// meeting is known by name, since there's nothing to check against.
+func allowedNames(m *Meeting) []string {
+	names := meetingParticipants(m)
+	if len(names) == 0 {
+		return nil
+	}
+	for _, speakerInfo := range m.Speakers.Data {
+		names = append(names, strings.TrimSpace(speakerInfo.Person.FirstName+" "+speakerInfo.Person.LastName))
+	}
+	for _, name := range strings.Split(os.Getenv("NAME_ALLOWLIST"), ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+
+	var result []string
+	for _, name := range uniqueStrings(names) {
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// nameGuardDirective tells the model which names it may use
+func nameGuardDirective(names []string) string {
+	if nameGuardMode() == nameGuardOff || len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Only refer to people by these names: %s. Refer to anyone else by their role (e.g. \"a customer\", \"their manager\") and never guess a name. List every person you name in people_mentioned.", strings.Join(names, ", "))
+}
+
+// nameAllowed reports whether a name matches an allowed name, either exactly or by
+// its parts (so "Sam" and "Sam Lee" both match "Sam Lee")
+func nameAllowed(name string, allowed []string) bool {
+	if strings.HasPrefix(name, "Unknown Speaker") || strings.HasPrefix(name, "Speaker ") {
+		return true
+	}
+
+	tokens := make(map[string]bool)
+	for _, a := range allowed {
+		if strings.EqualFold(a, name) {
+			return true
+		}
+		for _, t := range strings.Fields(strings.ToLower(a)) {
+			tokens[t] = true
+		}
+	}
+
+	parts := strings.Fields(strings.ToLower(name))
+	if len(parts) == 0 {
+		return true
+	}
+	for _, p := range parts {
+		if !tokens[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// guardNames checks the people a summary mentions against the allowed names and flags
+// or strips the unknown ones. Returns the unknown names.
+func guardNames(data *SummaryData, allowed []string) []string {
+	mode := nameGuardMode()
+	if mode == nameGuardOff || len(allowed) == 0 {
+		return nil
+	}
+
+	mentioned := append([]string{}, data.PeopleMentioned...)
+	for _, item := range data.ActionItems {
+		if item.Owner != "" {
+			mentioned = append(mentioned, item.Owner)
+		}
+	}
+
+	var unknown []string
+	for _, name := range uniqueStrings(mentioned) {
+		if name = strings.TrimSpace(name); name != "" && !nameAllowed(name, allowed) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	// Longest first so "Sam Lee" is replaced before "Sam"
+	ordered := append([]string{}, unknown...)
+	sort.Slice(ordered, func(i, j int) bool { return len(ordered[i]) > len(ordered[j]) })
+	patterns := make([]*wordPattern, len(ordered))
+	for i, name := range ordered {
+		patterns[i] = newWordPattern(name, false)
+	}
+	replace := func(text string) string {
+		for i, name := range ordered {
+			if mode == nameGuardStrip {
+				text = patterns[i].ReplaceAllString(text, "someone")
+			} else {
+				text = patterns[i].ReplaceAllString(text, "=="+name+"==")
+			}
+		}
+		return text
+	}
+
+	data.Summary = replace(data.Summary)
+	data.Description = replace(data.Description)
+	for i := range data.Topics {
+		data.Topics[i] = replace(data.Topics[i])
+	}
+	for i := range data.TopicDetails {
+		data.TopicDetails[i].Topic = replace(data.TopicDetails[i].Topic)
+		data.TopicDetails[i].Summary = replace(data.TopicDetails[i].Summary)
+	}
+	for i := range data.Decisions {
+		data.Decisions[i] = replace(data.Decisions[i])
+	}
+	for i := range data.ActionItems {
+		data.ActionItems[i].Text = replace(data.ActionItems[i].Text)
+		if !nameAllowed(data.ActionItems[i].Owner, allowed) {
+			if mode == nameGuardStrip {
+				data.ActionItems[i].Owner = ""
+			} else {
+				data.ActionItems[i].Owner = replace(data.ActionItems[i].Owner)
+			}
+		}
+	}
+
+	if mode == nameGuardFlag {
+		data.Summary = unverifiedNamesCallout(unknown) + data.Summary
+	}
+	data.UnknownNames = unknown
+	return unknown
+}
+
+// unverifiedNamesCallout renders the warning listing names that couldn't be verified
+func unverifiedNamesCallout(unknown []string) string {
+	return fmt.Sprintf("> [!warning] Unverified names\n> %s %s not in this meeting's speaker list - check them against the transcript.\n\n",
+		strings.Join(unknown, ", "), pluralVerb(len(unknown)))
+}
+
+// pluralVerb returns "is" or "are" for a count
+func pluralVerb(n int) string {
+	if n == 1 {
+		return "is"
+	}
+	return "are"
+}
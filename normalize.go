@@ -11,8 +11,6 @@ import (
 	"sort"
 	"strings"
 	"text/template"
-
-	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
 //go:embed normalize-prompt.md
@@ -29,7 +27,7 @@ func runNormalizePrompt(ctx context.Context, cache *Cache) error {
 	fmt.Println("\n=== Stage 4.1: Generate Normalization Prompt ===")
 
 	// Get all cached summary files
-	files, err := filepath.Glob(filepath.Join(meetingsCacheDir, "*-summary.json"))
+	files, err := filepath.Glob(filepath.Join(cache.dir, "*-summary.json"))
 	if err != nil {
 		return fmt.Errorf("error reading cache directory: %w", err)
 	}
@@ -95,12 +93,19 @@ func runNormalizePrompt(ctx context.Context, cache *Cache) error {
 		tagList = append(tagList, tagInfo{Tag: tag, Count: count})
 	}
 	sort.Slice(tagList, func(i, j int) bool {
-		return tagList[i].Count > tagList[j].Count
+		if tagList[i].Count != tagList[j].Count {
+			return tagList[i].Count > tagList[j].Count
+		}
+		return tagList[i].Tag < tagList[j].Tag
 	})
 
 	// Pre-process with fuzzy matching to consolidate obvious duplicates
+	thresholds, err := fuzzyThresholdsFromEnv()
+	if err != nil {
+		return err
+	}
 	fmt.Println("\n🔍 Pre-processing with fuzzy matching...")
-	tagList, preMappings := fuzzyPreProcess(tagList)
+	tagList, preMappings := fuzzyPreProcess(tagList, thresholds)
 	fmt.Printf("✓ Fuzzy matching reduced %d tags to %d (%.1f%% reduction)\n",
 		len(tagCounts), len(tagList), (1-float64(len(tagList))/float64(len(tagCounts)))*100)
 
@@ -109,7 +114,7 @@ func runNormalizePrompt(ctx context.Context, cache *Cache) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal pre-mappings: %w", err)
 	}
-	if err := os.WriteFile("normalize-premappings.json", preMappingsData, 0644); err != nil {
+	if err := os.WriteFile(dataPath("normalize-premappings.json"), preMappingsData, 0644); err != nil {
 		return fmt.Errorf("failed to write pre-mappings: %w", err)
 	}
 
@@ -120,15 +125,15 @@ func runNormalizePrompt(ctx context.Context, cache *Cache) error {
 		return fmt.Errorf("failed to generate prompt: %w", err)
 	}
 
-	if err := os.WriteFile("normalize-prompt-generated.txt", []byte(prompt), 0644); err != nil {
+	if err := os.WriteFile(dataPath("normalize-prompt-generated.txt"), []byte(prompt), 0644); err != nil {
 		return fmt.Errorf("failed to write prompt file: %w", err)
 	}
 
 	fmt.Println("\n✅ Normalization prompt generated!")
-	fmt.Printf("   - Pre-mappings saved to: normalize-premappings.json\n")
-	fmt.Printf("   - Prompt saved to: normalize-prompt-generated.txt\n")
+	fmt.Printf("   - Pre-mappings saved to: %s\n", dataPath("normalize-premappings.json"))
+	fmt.Printf("   - Prompt saved to: %s\n", dataPath("normalize-prompt-generated.txt"))
 	fmt.Printf("   - %d tags to consolidate\n", len(tagList))
-	fmt.Println("\nNext: Run your LLM on the prompt and save result to normalize-result.json")
+	fmt.Printf("\nNext: Run your LLM on the prompt and save result to %s\n", dataPath("normalize-result.json"))
 
 	return nil
 }
@@ -344,120 +349,6 @@ for {
 */
 // END OLD CODE
 
-// fuzzyPreProcess consolidates tags using fuzzy matching for obvious duplicates
-// Returns consolidated tag list and mappings (canonical -> [originals])
-func fuzzyPreProcess(tags []tagInfo) ([]tagInfo, map[string][]string) {
-	type tagGroup struct {
-		canonical  string
-		tags       []tagInfo
-		totalCount int
-	}
-
-	groups := make(map[string]*tagGroup)
-	mappings := make(map[string][]string)
-
-	for _, tag := range tags {
-		normalized := strings.ToLower(strings.ReplaceAll(tag.Tag, "-", ""))
-
-		// Check for exact match on normalized form (catches case and hyphen variations)
-		var matchedGroup *tagGroup
-		for _, group := range groups {
-			groupNorm := strings.ToLower(strings.ReplaceAll(group.canonical, "-", ""))
-
-			// Exact normalized match
-			if normalized == groupNorm {
-				matchedGroup = group
-				break
-			}
-
-			// Fuzzy match with high threshold (Levenshtein distance <= 2 for similar length)
-			if len(normalized) > 3 && fuzzy.LevenshteinDistance(normalized, groupNorm) <= 2 {
-				// Only match if they're similar length (within 20%)
-				lenDiff := abs(len(normalized) - len(groupNorm))
-				maxLen := maxInt(len(normalized), len(groupNorm))
-				if float64(lenDiff)/float64(maxLen) <= 0.2 {
-					matchedGroup = group
-					break
-				}
-			}
-
-			// Enhanced singular/plural matching
-			if isSingularPlural(normalized, groupNorm) {
-				matchedGroup = group
-				break
-			}
-
-			// Check for common verb/noun variations (e.g., "planning" vs "plan", "testing" vs "test")
-			if isVerbNounVariation(normalized, groupNorm) {
-				matchedGroup = group
-				break
-			}
-
-			// Check if one is a substring of the other (with constraints)
-			// e.g., "api" vs "api-integration", but only if one is significantly shorter
-			if len(normalized) > 3 && len(groupNorm) > 3 {
-				shorter := normalized
-				longer := groupNorm
-				if len(groupNorm) < len(normalized) {
-					shorter = groupNorm
-					longer = normalized
-				}
-				// If shorter is at least 4 chars and appears at start of longer
-				if len(shorter) >= 4 && strings.HasPrefix(longer, shorter) {
-					// Only match if the extension is common (like "ing", "s", "tion", etc)
-					suffix := strings.TrimPrefix(longer, shorter)
-					if isCommonSuffix(suffix) {
-						matchedGroup = group
-						break
-					}
-				}
-			}
-		}
-
-		if matchedGroup != nil {
-			// Add to existing group
-			matchedGroup.tags = append(matchedGroup.tags, tag)
-			matchedGroup.totalCount += tag.Count
-		} else {
-			// Create new group
-			groups[tag.Tag] = &tagGroup{
-				canonical:  tag.Tag,
-				tags:       []tagInfo{tag},
-				totalCount: tag.Count,
-			}
-		}
-	}
-
-	// Build consolidated list and mappings
-	var consolidated []tagInfo
-	for _, group := range groups {
-		consolidated = append(consolidated, tagInfo{
-			Tag:   group.canonical,
-			Count: group.totalCount,
-		})
-
-		// Only create mapping if we actually consolidated multiple tags
-		if len(group.tags) > 1 {
-			var originals []string
-			for _, t := range group.tags {
-				if t.Tag != group.canonical {
-					originals = append(originals, t.Tag)
-				}
-			}
-			if len(originals) > 0 {
-				mappings[group.canonical] = originals
-			}
-		}
-	}
-
-	// Sort by frequency
-	sort.Slice(consolidated, func(i, j int) bool {
-		return consolidated[i].Count > consolidated[j].Count
-	})
-
-	return consolidated, mappings
-}
-
 func abs(n int) int {
 	if n < 0 {
 		return -n
@@ -554,7 +445,7 @@ type NormalizePremappings struct {
 
 // loadNormalizeResult loads normalize-result.json (LLM output)
 func loadNormalizeResult() (*NormalizeResult, error) {
-	data, err := os.ReadFile("normalize-result.json")
+	data, err := os.ReadFile(dataPath("normalize-result.json"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read normalize-result.json: %w", err)
 	}
@@ -589,7 +480,7 @@ func loadNormalizeResult() (*NormalizeResult, error) {
 
 // loadNormalizePremappings loads normalize-premappings.json (fuzzy pre-processing)
 func loadNormalizePremappings() (*NormalizePremappings, error) {
-	data, err := os.ReadFile("normalize-premappings.json")
+	data, err := os.ReadFile(dataPath("normalize-premappings.json"))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &NormalizePremappings{Mappings: make(map[string][]string)}, nil
@@ -597,14 +488,24 @@ func loadNormalizePremappings() (*NormalizePremappings, error) {
 		return nil, fmt.Errorf("failed to read normalize-premappings.json: %w", err)
 	}
 
-	// The file format is an array of {canonical_tag, old_tags}
+	// The file format is an array of {canonical_tag, old_tags}; normalize-prompt writes
+	// a canonical tag -> old tags object, which is accepted too
 	var preMappingsArray []struct {
 		CanonicalTag string   `json:"canonical_tag"`
 		OldTags      []string `json:"old_tags"`
 	}
 
 	if err := json.Unmarshal(data, &preMappingsArray); err != nil {
-		return nil, fmt.Errorf("failed to parse normalize-premappings.json: %w", err)
+		var preMappingsObject map[string][]string
+		if objErr := json.Unmarshal(data, &preMappingsObject); objErr != nil {
+			return nil, fmt.Errorf("failed to parse normalize-premappings.json: %w", err)
+		}
+		for canonical, oldTags := range preMappingsObject {
+			preMappingsArray = append(preMappingsArray, struct {
+				CanonicalTag string   `json:"canonical_tag"`
+				OldTags      []string `json:"old_tags"`
+			}{canonical, oldTags})
+		}
 	}
 
 	// Convert to map format
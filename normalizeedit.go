@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Sources of a tag mapping
+const (
+	mappingFuzzy = "fuzzy" // normalize-premappings.json
+	mappingLLM   = "llm"   // normalize-result.json
+)
+
+// tagMapping is one old tag -> canonical tag proposal under review
+type tagMapping struct {
+	Old       string
+	Canonical string
+	Source    string
+}
+
+// mappingEntry is the on-disk format of normalize-result.json and normalize-premappings.json
+type mappingEntry struct {
+	CanonicalTag string   `json:"canonical_tag"`
+	OldTags      []string `json:"old_tags"`
+}
+
+// flattenMappings turns canonical -> old tags into sorted one-per-old-tag proposals
+func flattenMappings(mappings map[string][]string, source string) []tagMapping {
+	var result []tagMapping
+	for canonical, oldTags := range mappings {
+		for _, old := range oldTags {
+			result = append(result, tagMapping{Old: old, Canonical: canonical, Source: source})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Canonical != result[j].Canonical {
+			return result[i].Canonical < result[j].Canonical
+		}
+		return result[i].Old < result[j].Old
+	})
+	return result
+}
+
+// writeMappingsFile writes mappings in the canonical_tag/old_tags array format, keeping a .bak of the previous file
+func writeMappingsFile(name string, mappings []tagMapping) error {
+	grouped := make(map[string][]string)
+	for _, m := range mappings {
+		grouped[m.Canonical] = append(grouped[m.Canonical], m.Old)
+	}
+	entries := make([]mappingEntry, 0, len(grouped))
+	for canonical, oldTags := range grouped {
+		sort.Strings(oldTags)
+		entries = append(entries, mappingEntry{CanonicalTag: canonical, OldTags: oldTags})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CanonicalTag < entries[j].CanonicalTag })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := dataPath(name)
+	if existing, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(path+".bak", existing, 0644); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+	}
+	return writeFileAtomic(path, data)
+}
+
+// reviewMappings walks through proposals, reading one keyboard command per proposal.
+// Returns the curated mappings and whether anything changed.
+func reviewMappings(in io.Reader, proposals []tagMapping) ([]tagMapping, bool) {
+	reader := bufio.NewReader(in)
+	var curated []tagMapping
+	changed := false
+
+	fmt.Println("Commands: [a]ccept  [r]eject  [e]dit target  [s]kip (keep as is)  [A]ccept all remaining  [q]uit (keep the rest as is)")
+	for i := 0; i < len(proposals); i++ {
+		p := proposals[i]
+		fmt.Printf("\n[%d/%d] %s → %s  (%s)\n> ", i+1, len(proposals), p.Old, p.Canonical, p.Source)
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			// End of input: keep everything not reviewed yet
+			fmt.Println()
+			return append(curated, proposals[i:]...), changed
+		}
+
+		switch cmd := strings.TrimSpace(line); cmd {
+		case "a", "", "s":
+			curated = append(curated, p)
+		case "r":
+			fmt.Printf("  ✗ %s stays as is\n", p.Old)
+			changed = true
+		case "e":
+			fmt.Printf("  New target for %s: ", p.Old)
+			target, _ := reader.ReadString('\n')
+			target = toKebabCase(strings.TrimSpace(target))
+			switch target {
+			case "":
+				fmt.Println("  Empty target, keeping the proposal")
+				curated = append(curated, p)
+			case p.Old:
+				fmt.Printf("  ✗ %s stays as is\n", p.Old)
+				changed = true
+			default:
+				p.Canonical = target
+				curated = append(curated, p)
+				fmt.Printf("  ↪ %s → %s\n", p.Old, target)
+				changed = true
+			}
+		case "A":
+			return append(curated, proposals[i:]...), changed
+		case "q":
+			return append(curated, proposals[i:]...), changed
+		default:
+			fmt.Printf("  Unknown command %q\n", cmd)
+			i--
+		}
+	}
+	return curated, changed
+}
+
+// runNormalizeEdit lets you review the fuzzy premappings and LLM mappings one by one and
+// writes the curated result back to normalize-premappings.json and normalize-result.json
+func runNormalizeEdit() error {
+	fmt.Println("\n=== Reviewing tag normalization mappings ===")
+
+	premappings, err := loadNormalizePremappings()
+	if err != nil {
+		return err
+	}
+	llm := &NormalizeResult{Mappings: make(map[string][]string)}
+	if fileExists(dataPath("normalize-result.json")) {
+		if llm, err = loadNormalizeResult(); err != nil {
+			return err
+		}
+	}
+
+	// Premappings win over LLM mappings of the same tag during sync, so only review the ones that apply
+	proposals := flattenMappings(premappings.Mappings, mappingFuzzy)
+	premapped := make(map[string]bool)
+	for _, p := range proposals {
+		premapped[p.Old] = true
+	}
+	var overridden []tagMapping // LLM mappings hidden by a premapping
+	for _, p := range flattenMappings(llm.Mappings, mappingLLM) {
+		if premapped[p.Old] {
+			overridden = append(overridden, p)
+		} else {
+			proposals = append(proposals, p)
+		}
+	}
+
+	if len(proposals) == 0 {
+		fmt.Println("⚠ No mappings to review. Run krisp-sync normalize-prompt first.")
+		return nil
+	}
+	fmt.Printf("📝 %d mapping(s) to review\n", len(proposals))
+
+	curated, changed := reviewMappings(os.Stdin, proposals)
+	if !changed {
+		fmt.Println("\n✅ No changes")
+		return nil
+	}
+
+	var fuzzy, llmMappings []tagMapping
+	kept := make(map[string]bool)
+	for _, m := range curated {
+		if m.Source == mappingFuzzy {
+			fuzzy = append(fuzzy, m)
+			kept[m.Old] = true
+		} else {
+			llmMappings = append(llmMappings, m)
+		}
+	}
+	// A rejected premapping means the tag stays as is, so the LLM mapping it hid goes too
+	for _, m := range overridden {
+		if kept[m.Old] {
+			llmMappings = append(llmMappings, m)
+		}
+	}
+	if err := writeMappingsFile("normalize-premappings.json", fuzzy); err != nil {
+		return fmt.Errorf("failed to write premappings: %w", err)
+	}
+	if err := writeMappingsFile("normalize-result.json", llmMappings); err != nil {
+		return fmt.Errorf("failed to write LLM mappings: %w", err)
+	}
+
+	fmt.Printf("\n✅ Saved %d of %d mapping(s) (previous files kept as .bak)\n", len(curated), len(proposals))
+	fmt.Println("Apply them with: krisp-sync sync --apply-normalization --overwrite --limit 0")
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Services a stage can need the network for
+const (
+	serviceKrisp = "Krisp API"
+	serviceLLM   = "LLM"
+)
+
+// offline is set by --offline: no Krisp or LLM calls, only what can be done from the cache
+var offline bool
+
+// offlineSkipped records the stages skipped because of --offline, for the end-of-run report
+var offlineSkipped []string
+
+// skipOffline reports whether a stage has to be skipped because it needs the network
+func skipOffline(stage, service string) bool {
+	if !offline {
+		return false
+	}
+	fmt.Printf("\n⏭  Offline: skipping %s (needs %s)\n", stage, service)
+	if skipped := fmt.Sprintf("%s (%s)", stage, service); !slices.Contains(offlineSkipped, skipped) {
+		offlineSkipped = append(offlineSkipped, skipped)
+	}
+	return true
+}
+
+// printOfflineReport summarizes what an offline run couldn't do
+func printOfflineReport(cache *Cache) {
+	if !offline {
+		return
+	}
+
+	fmt.Println("\n=== Offline run ===")
+	for _, stage := range offlineSkipped {
+		fmt.Printf("  ⏭  Skipped %s\n", stage)
+	}
+
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		fmt.Printf("  ⚠ Could not list cached meetings: %v\n", err)
+		return
+	}
+	unsummarized := 0
+	for _, id := range ids {
+		if !cache.SummaryExists(id) {
+			unsummarized++
+		}
+	}
+	fmt.Printf("  📦 %d cached meeting(s), %d without a summary\n", len(ids), unsummarized)
+	fmt.Println("  New meetings from Krisp and missing summaries are picked up by the next online run")
+}
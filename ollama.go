@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+const (
+	defaultOllamaHost  = "http://localhost:11434"
+	defaultOllamaModel = "llama3.1:8b"
+
+	// ollamaContextHeadroom is the context left for the response on top of the prompt
+	ollamaContextHeadroom = 4096
+	// ollamaMinContext is the smallest context window requested; Ollama's own default of
+	// 2048 tokens silently cuts most transcripts short
+	ollamaMinContext = 8192
+)
+
+// ollamaSummarizer calls a local model through Ollama's HTTP API, so transcripts never
+// leave the machine (OLLAMA_HOST, OLLAMA_MODEL)
+type ollamaSummarizer struct {
+	host       string
+	model      string
+	contextLen int // OLLAMA_NUM_CTX; 0 sizes the context to each prompt
+}
+
+// newOllamaSummarizer configures the backend from OLLAMA_HOST, OLLAMA_MODEL and
+// OLLAMA_NUM_CTX
+func newOllamaSummarizer() *ollamaSummarizer {
+	host := firstNonEmpty(os.Getenv("OLLAMA_HOST"), defaultOllamaHost)
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	o := &ollamaSummarizer{
+		host:  strings.TrimRight(host, "/"),
+		model: firstNonEmpty(os.Getenv("OLLAMA_MODEL"), defaultOllamaModel),
+	}
+	if v := os.Getenv("OLLAMA_NUM_CTX"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			o.contextLen = n
+		} else {
+			fmt.Printf("⚠ Ignoring invalid OLLAMA_NUM_CTX %q\n", v)
+		}
+	}
+	return o
+}
+
+func (o *ollamaSummarizer) Name() string { return providerOllama }
+
+func (o *ollamaSummarizer) DefaultModel() string { return o.model }
+
+func (o *ollamaSummarizer) Ready() error { return nil }
+
+// ollamaError is an error response of the Ollama chat API
+type ollamaError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ollamaError) Error() string {
+	return fmt.Sprintf("Ollama returned status %d: %s", e.StatusCode, e.Message)
+}
+
+func (o *ollamaSummarizer) Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	return generateSchemaless(prompt, opts, func(prompt string) (string, error) {
+		return o.chat(ctx, model, prompt, opts)
+	})
+}
+
+// chat sends one prompt to Ollama's chat API and returns the reply
+func (o *ollamaSummarizer) chat(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	contextLen := o.contextLen
+	if contextLen == 0 {
+		contextLen = max(estimateTokens(prompt)+ollamaContextHeadroom, ollamaMinContext)
+	}
+	request := map[string]interface{}{
+		"model":    model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+		"stream":   false,
+		"options": map[string]interface{}{
+			"temperature": opts.Temperature,
+			"num_ctx":     contextLen,
+		},
+	}
+	if opts.Schema != nil {
+		// Constrains the reply to valid JSON, though not to the schema
+		request["format"] = "json"
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.host+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := llmHTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Ollama at %s (is `ollama serve` running?): %w", o.host, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+		Error           string `json:"error"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &parsed) == nil && parsed.Error != "" {
+			message = parsed.Error
+		}
+		return "", &ollamaError{StatusCode: resp.StatusCode, Message: message}
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	reportTokens(ctx, parsed.PromptEvalCount, parsed.EvalCount)
+	if parsed.Message.Content == "" {
+		return "", fmt.Errorf("no content generated")
+	}
+	return parsed.Message.Content, nil
+}
+
+// generateSchemaless runs a request on a model without structured output: the schema is
+// spelled out in the prompt, and the JSON is extracted from the reply and repaired. A reply
+// with no usable JSON is sent back once to be fixed.
+func generateSchemaless(prompt string, opts generateOptions, send func(prompt string) (string, error)) (string, error) {
+	if opts.Schema == nil {
+		return send(prompt)
+	}
+
+	prompt += "\n\n" + schemaInstructions(opts.Schema)
+	response, err := send(prompt)
+	if err != nil {
+		return "", err
+	}
+	if repaired := repairJSON(response); json.Valid([]byte(repaired)) {
+		return repaired, nil
+	}
+
+	retry := prompt + "\n\nYour previous reply was not valid JSON:\n\n" + response + "\n\nReply again with only the JSON object."
+	fixed, err := send(retry)
+	if err != nil {
+		return "", err
+	}
+	if repaired := repairJSON(fixed); json.Valid([]byte(repaired)) {
+		return repaired, nil
+	}
+	// Left to the caller's salvage
+	return fixed, nil
+}
+
+// schemaInstructions describes a response schema in the prompt, for models that can't be
+// given one: an example of the JSON shape followed by what each field holds
+func schemaInstructions(schema *genai.Schema) string {
+	var sb strings.Builder
+	sb.WriteString("Reply with only a JSON object, without any other text or code fences, in this shape:\n\n")
+	sb.WriteString(schemaExample(schema, ""))
+	sb.WriteString("\n\nFields:\n")
+	describeSchemaFields(&sb, schema, "")
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// schemaExample renders an example value of a schema, indented by indent
+func schemaExample(s *genai.Schema, indent string) string {
+	switch s.Type {
+	case genai.TypeObject:
+		inner := indent + "  "
+		var fields []string
+		for _, name := range schemaFieldOrder(s) {
+			fields = append(fields, fmt.Sprintf("%s%q: %s", inner, name, schemaExample(s.Properties[name], inner)))
+		}
+		return "{\n" + strings.Join(fields, ",\n") + "\n" + indent + "}"
+	case genai.TypeArray:
+		if s.Items == nil {
+			return "[]"
+		}
+		return "[" + schemaExample(s.Items, indent) + "]"
+	case genai.TypeInteger, genai.TypeNumber:
+		return "0"
+	case genai.TypeBoolean:
+		return "false"
+	default:
+		return `"..."`
+	}
+}
+
+// describeSchemaFields lists each field of a schema with its description, and whether it
+// is required
+func describeSchemaFields(sb *strings.Builder, s *genai.Schema, prefix string) {
+	if s.Type == genai.TypeArray && s.Items != nil {
+		describeSchemaFields(sb, s.Items, prefix+"[]")
+		return
+	}
+	if s.Type != genai.TypeObject {
+		return
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	for _, name := range schemaFieldOrder(s) {
+		field := s.Properties[name]
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		line := "- " + path
+		if field.Description != "" {
+			line += ": " + field.Description
+		}
+		if required[name] {
+			line += " (required)"
+		}
+		sb.WriteString(line + "\n")
+		describeSchemaFields(sb, field, path)
+	}
+}
+
+// schemaFieldOrder returns an object's fields, required ones first in their listed order,
+// then the rest alphabetically
+func schemaFieldOrder(s *genai.Schema) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	var rest []string
+	for name := range s.Properties {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
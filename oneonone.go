@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// oneOnOneLogName is the name of the running 1:1 log note in each person's People folder
+const oneOnOneLogName = "1-1 Log.md"
+
+// oneOnOneLogsEnabled reports whether 1:1 meetings are logged per person (ONE_ON_ONE_LOGS)
+func oneOnOneLogsEnabled() bool {
+	return envBool("ONE_ON_ONE_LOGS")
+}
+
+// isMe reports whether an invitee is you: one of your MY_EMAIL addresses, or a full name
+// in MY_NAME
+func isMe(p Speaker) bool {
+	if p.Email != "" && containsFold(myEmails(), p.Email) {
+		return true
+	}
+	name := strings.TrimSpace(p.FirstName + " " + p.LastName)
+	return name != "" && containsFold(myNames(), name)
+}
+
+// oneOnOnePartner returns the other person of a 1:1: a meeting with exactly two invitees,
+// one of them you. Returns "" for any other meeting.
+func oneOnOnePartner(m *Meeting) string {
+	if len(m.Participants) != 2 {
+		return ""
+	}
+	var partner Speaker
+	switch a, b := m.Participants[0], m.Participants[1]; {
+	case isMe(a) && !isMe(b):
+		partner = b
+	case isMe(b) && !isMe(a):
+		partner = a
+	default:
+		return ""
+	}
+	return firstNonEmpty(preferredName(partner.Email, strings.TrimSpace(partner.FirstName+" "+partner.LastName)), partner.Email)
+}
+
+// oneOnOneLogPath returns the path of a person's 1:1 log (People/Jane/1-1 Log.md)
+func oneOnOneLogPath(vaultPath, name string) string {
+	return filepath.Join(filepath.Dir(peopleNotePath(vaultPath, name)), sanitizeNoteName(name), oneOnOneLogName)
+}
+
+// renderOneOnOneEntry renders a meeting's entry in a 1:1 log: date, topics and action items
+func renderOneOnOneEntry(m *Meeting, summaryData *SummaryData) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## %s · [[%s-summary|%s]]\n\n", m.CreatedAt.Local().Format("2006-01-02"), m.ID, firstNonEmpty(summaryData.Title, m.Title)))
+	if summaryData.Description != "" {
+		sb.WriteString(summaryData.Description + "\n\n")
+	}
+	if len(summaryData.Topics) > 0 {
+		sb.WriteString("**Topics**\n")
+		for _, topic := range summaryData.Topics {
+			sb.WriteString(fmt.Sprintf("- %s\n", topic))
+		}
+		sb.WriteString("\n")
+	}
+	if len(summaryData.ActionItems) > 0 {
+		sb.WriteString("**Action items**\n")
+		for _, item := range summaryData.ActionItems {
+			if item.Done {
+				sb.WriteString(fmt.Sprintf("- ~~%s~~\n", item.Label()))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s\n", item.Label()))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// upsertOneOnOneEntry adds a meeting's entry to a 1:1 log, or replaces the one already
+// there for that meeting. Entries run from one "## " heading to the next.
+func upsertOneOnOneEntry(content, meetingID, entry string) string {
+	lines := strings.Split(content, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if start >= 0 {
+			end = i
+			break
+		}
+		if strings.Contains(line, "[["+meetingID+"-summary") {
+			start = i
+		}
+	}
+	if start < 0 {
+		return strings.TrimRight(content, "\n") + "\n\n" + strings.TrimRight(entry, "\n") + "\n"
+	}
+	replaced := append(append(lines[:start:start], strings.Split(strings.TrimRight(entry, "\n"), "\n")...), "")
+	return strings.Join(append(replaced, lines[end:]...), "\n")
+}
+
+// writeOneOnOneLog records a 1:1 meeting in the running log of the other person
+// (ONE_ON_ONE_LOGS). Confidential meetings aren't logged, and test syncs leave the logs alone.
+func writeOneOnOneLog(vaultPath string, m *Meeting, summaryData *SummaryData, testMode bool) error {
+	if !oneOnOneLogsEnabled() || testMode || summaryData == nil || isConfidential(m, summaryData) {
+		return nil
+	}
+	partner := oneOnOnePartner(m)
+	if partner == "" {
+		return nil
+	}
+
+	path := oneOnOneLogPath(vaultPath, partner)
+	person, err := yaml.Marshal(partner)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf("---\ntype: one-on-one-log\nperson: %s---\n\n# 1:1s with %s\n", person, partner)
+	if vaultWriter.Exists(path) {
+		existing, err := vaultWriter.ReadNote(path)
+		if err != nil {
+			return err
+		}
+		content = string(existing)
+	}
+	updated := upsertOneOnOneEntry(content, m.ID, renderOneOnOneEntry(m, summaryData))
+	if updated == content {
+		return nil
+	}
+	if err := vaultWriter.CreateNote(path, []byte(updated)); err != nil {
+		return err
+	}
+	fmt.Printf("  🤝 Logged 1:1 with %s\n", partner)
+	return nil
+}
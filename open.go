@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Targets for opening notes in Obsidian after a sync
+const (
+	openTargetSummary = "summary"
+	openTargetDaily   = "daily"
+)
+
+// obsidianURI builds an obsidian://open URI for a file inside the vault
+func obsidianURI(vaultPath, filePath string) (string, error) {
+	rel, err := filepath.Rel(vaultPath, filePath)
+	if err != nil {
+		return "", fmt.Errorf("file %s is not inside vault: %w", filePath, err)
+	}
+
+	// Obsidian resolves files by vault-relative path without the .md extension
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+
+	// url.Values encodes spaces as "+", which Obsidian doesn't decode
+	query := "vault=" + url.PathEscape(filepath.Base(vaultPath)) + "&file=" + url.PathEscape(rel)
+	return "obsidian://open?" + query, nil
+}
+
+// openURI hands a URI to the operating system's default handler
+func openURI(uri string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", uri)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", uri)
+	default:
+		cmd = exec.Command("xdg-open", uri)
+	}
+	return cmd.Start()
+}
+
+// openSyncResult opens the newest synced summary (or daily note) in Obsidian
+func openSyncResult(vaultPath string, result *SyncResult, target string) error {
+	if result == nil {
+		return nil
+	}
+
+	var filePath string
+	switch target {
+	case openTargetDaily:
+		if len(result.DailyNotes) > 0 {
+			filePath = result.DailyNotes[len(result.DailyNotes)-1]
+		}
+	default:
+		if len(result.SummaryNotes) > 0 {
+			filePath = result.SummaryNotes[len(result.SummaryNotes)-1]
+		} else if len(result.DailyNotes) > 0 {
+			filePath = result.DailyNotes[len(result.DailyNotes)-1]
+		}
+	}
+
+	if filePath == "" {
+		fmt.Println("📭 Nothing new to open in Obsidian")
+		return nil
+	}
+
+	uri, err := obsidianURI(vaultPath, filePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📖 Opening in Obsidian: %s\n", uri)
+	return openURI(uri)
+}
+
+// openTargetFromEnv returns the configured OBSIDIAN_OPEN_TARGET (summary or daily)
+func openTargetFromEnv() (string, error) {
+	target := strings.ToLower(strings.TrimSpace(os.Getenv("OBSIDIAN_OPEN_TARGET")))
+	switch target {
+	case "":
+		return openTargetSummary, nil
+	case openTargetSummary, openTargetDaily:
+		return target, nil
+	default:
+		return "", fmt.Errorf("OBSIDIAN_OPEN_TARGET must be 'summary' or 'daily', got %q", target)
+	}
+}
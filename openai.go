@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+)
+
+// openAISummarizer calls an OpenAI-compatible chat completions API: OpenAI itself, Azure
+// OpenAI or any server speaking the same protocol (OPENAI_BASE_URL)
+type openAISummarizer struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	structured bool // the server takes a json_schema response format
+}
+
+// newOpenAISummarizer configures the backend from OPENAI_API_KEY, OPENAI_BASE_URL,
+// OPENAI_MODEL and OPENAI_STRUCTURED_OUTPUT
+func newOpenAISummarizer() *openAISummarizer {
+	return &openAISummarizer{
+		baseURL:    strings.TrimRight(firstNonEmpty(os.Getenv("OPENAI_BASE_URL"), defaultOpenAIBaseURL), "/"),
+		apiKey:     os.Getenv("OPENAI_API_KEY"),
+		model:      firstNonEmpty(os.Getenv("OPENAI_MODEL"), defaultOpenAIModel),
+		structured: envBoolDefault("OPENAI_STRUCTURED_OUTPUT", true),
+	}
+}
+
+func (o *openAISummarizer) Name() string { return providerOpenAI }
+
+func (o *openAISummarizer) DefaultModel() string { return o.model }
+
+func (o *openAISummarizer) Ready() error {
+	// Self-hosted servers usually need no key
+	if o.apiKey == "" && o.baseURL == defaultOpenAIBaseURL {
+		return fmt.Errorf("OPENAI_API_KEY not set; set it, or set OPENAI_BASE_URL to a server that needs no key")
+	}
+	return nil
+}
+
+// openAIError is an error response of the chat completions API
+type openAIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *openAIError) Error() string {
+	return fmt.Sprintf("OpenAI API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+func (o *openAISummarizer) Generate(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	if err := o.Ready(); err != nil {
+		return "", err
+	}
+	if o.structured {
+		return o.complete(ctx, model, prompt, opts)
+	}
+	// Servers without structured output (e.g. llama.cpp's) get the schema in the prompt
+	return generateSchemaless(prompt, opts, func(prompt string) (string, error) {
+		return o.complete(ctx, model, prompt, generateOptions{Temperature: opts.Temperature})
+	})
+}
+
+// complete sends one prompt to the chat completions API and returns the reply
+func (o *openAISummarizer) complete(ctx context.Context, model string, prompt string, opts generateOptions) (string, error) {
+	request := map[string]interface{}{
+		"model":       model,
+		"messages":    []map[string]string{{"role": "user", "content": prompt}},
+		"temperature": opts.Temperature,
+	}
+	if opts.Schema != nil {
+		request["response_format"] = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "response",
+				"schema": jsonSchema(opts.Schema),
+			},
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	client := llmHTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+				Refusal string `json:"refusal"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &parsed) == nil && parsed.Error != nil {
+			message = parsed.Error.Message
+		}
+		return "", fmt.Errorf("failed to generate content: %w", &openAIError{StatusCode: resp.StatusCode, Message: message})
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	reportTokens(ctx, parsed.Usage.PromptTokens, parsed.Usage.CompletionTokens)
+
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("no content generated")
+	}
+	choice := parsed.Choices[0]
+	if choice.FinishReason == "content_filter" {
+		return "", &ContentBlockedError{Reason: "CONTENT_FILTER"}
+	}
+	if choice.Message.Refusal != "" {
+		return "", &ContentBlockedError{Reason: choice.Message.Refusal}
+	}
+	if choice.Message.Content == "" {
+		return "", fmt.Errorf("no content generated")
+	}
+	return choice.Message.Content, nil
+}
+
+// jsonSchema converts a Gemini response schema into the JSON Schema other APIs take
+func jsonSchema(s *genai.Schema) map[string]interface{} {
+	out := map[string]interface{}{"type": strings.ToLower(string(s.Type))}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Items != nil {
+		out["items"] = jsonSchema(s.Items)
+	}
+	if len(s.Properties) > 0 {
+		properties := make(map[string]interface{}, len(s.Properties))
+		for name, property := range s.Properties {
+			properties[name] = jsonSchema(property)
+		}
+		out["properties"] = properties
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	return out
+}
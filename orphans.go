@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// orphanArchiveFolder is where archived orphan transcripts go, inside the archive
+const orphanArchiveFolder = "Orphaned Transcripts"
+
+// orphanScan is the result of checking the vault's summary/transcript pairs
+type orphanScan struct {
+	Orphans            []string          // transcripts whose meeting has no summary note
+	MissingTranscripts map[string]string // meeting ID -> summary note without a transcript
+}
+
+// scanOrphans pairs every meeting transcript with its summary. A meeting whose summary was
+// merged into a note of your own (VAULT_DEDUPE) counts as having one.
+func scanOrphans(vaultPath string) (*orphanScan, error) {
+	transcripts := make(map[string]string)
+	summaries := make(map[string]string)
+	hasNote := make(map[string]bool)
+
+	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			// An archive inside the vault holds the transcripts already archived as orphans
+			if archiveDir != "" && path == filepath.Clean(archiveDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if !strings.HasSuffix(name, ".md") {
+			return nil
+		}
+		inMeetings := filepath.Base(filepath.Dir(path)) == "meetings"
+		if id, ok := strings.CutSuffix(name, "-transcript.md"); ok && inMeetings {
+			transcripts[id] = path
+			return nil
+		}
+		if id, ok := strings.CutSuffix(name, "-summary.md"); ok && inMeetings {
+			summaries[id] = path
+			hasNote[id] = true
+			return nil
+		}
+		if strings.HasSuffix(name, "-minutes.md") {
+			return nil
+		}
+		if fm, _, err := parseFrontmatter(path); err == nil {
+			if id := noteMeetingID(path, fm); id != "" {
+				hasNote[id] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning vault: %w", err)
+	}
+
+	scan := &orphanScan{MissingTranscripts: make(map[string]string)}
+	for id, path := range transcripts {
+		if !hasNote[id] {
+			scan.Orphans = append(scan.Orphans, path)
+		}
+	}
+	sort.Strings(scan.Orphans)
+	for id, path := range summaries {
+		if _, ok := transcripts[id]; !ok {
+			scan.MissingTranscripts[id] = path
+		}
+	}
+	return scan, nil
+}
+
+// archiveOrphan moves a transcript into the archive's orphan folder, keeping its vault path
+func archiveOrphan(vaultPath, path string) (string, error) {
+	dest := filepath.Join(archiveDir, orphanArchiveFolder, filepath.FromSlash(vaultRelative(vaultPath, path)))
+	if vaultWriter.Exists(dest) {
+		return "", fmt.Errorf("%s already exists", dest)
+	}
+	content, err := vaultWriter.ReadNote(path)
+	if err != nil {
+		return "", err
+	}
+	if err := vaultWriter.CreateNote(dest, content); err != nil {
+		return "", err
+	}
+	return dest, vaultWriter.DeleteNote(path)
+}
+
+// reviewOrphans asks what to do with each orphaned transcript: delete, archive or keep it
+func reviewOrphans(reader *bufio.Reader, vaultPath string, orphans []string) (deleted, archived int) {
+	all := ""
+
+	fmt.Println("Commands: [d]elete  [a]rchive  [s]kip (keep)  [D]elete all  [A]rchive all  [q]uit (keep the rest)")
+	for i, path := range orphans {
+		cmd := all
+		if cmd == "" {
+			fmt.Printf("\n[%d/%d] %s\n> ", i+1, len(orphans), vaultRelative(vaultPath, path))
+			line, err := reader.ReadString('\n')
+			if err != nil && line == "" {
+				// End of input: keep everything not reviewed yet
+				fmt.Println()
+				return deleted, archived
+			}
+			cmd = strings.TrimSpace(line)
+		}
+
+		switch cmd {
+		case "D":
+			all = "d"
+			fallthrough
+		case "d":
+			if err := vaultWriter.DeleteNote(path); err != nil {
+				fmt.Printf("  ⚠ Error deleting %s: %v\n", vaultRelative(vaultPath, path), err)
+				continue
+			}
+			fmt.Printf("  🗑  Deleted %s\n", vaultRelative(vaultPath, path))
+			deleted++
+		case "A":
+			all = "a"
+			fallthrough
+		case "a":
+			dest, err := archiveOrphan(vaultPath, path)
+			if err != nil {
+				fmt.Printf("  ⚠ Error archiving %s: %v\n", vaultRelative(vaultPath, path), err)
+				continue
+			}
+			fmt.Printf("  📦 Archived %s → %s\n", vaultRelative(vaultPath, path), vaultRelative(vaultPath, dest))
+			archived++
+		case "q":
+			return deleted, archived
+		default:
+			fmt.Printf("  ⏭  Keeping %s\n", vaultRelative(vaultPath, path))
+		}
+	}
+	return deleted, archived
+}
+
+// askYesNo prints a question and reports whether the answer was yes (default no)
+func askYesNo(reader *bufio.Reader, question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	line, _ := reader.ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+// runOrphans finds transcripts whose summary note was deleted and offers to delete or
+// archive them, then offers to regenerate missing transcripts for summaries that exist
+func runOrphans(obsidianVaultPath string, cache *Cache, dryRun bool) error {
+	fmt.Println("\n=== Orphans: Checking summary/transcript pairs ===")
+
+	scan, err := scanOrphans(obsidianVaultPath)
+	if err != nil {
+		return err
+	}
+
+	// Confidential meetings have no transcript note on purpose
+	missing := make([]string, 0, len(scan.MissingTranscripts))
+	for id := range scan.MissingTranscripts {
+		if m, err := cache.LoadMeeting(id); err == nil {
+			summaryData, _ := cache.LoadSummary(id)
+			if isConfidential(m, summaryData) {
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	sort.Strings(missing)
+
+	fmt.Printf("📊 %d orphaned transcript(s), %d summary note(s) without a transcript\n", len(scan.Orphans), len(missing))
+	if dryRun {
+		for _, path := range scan.Orphans {
+			fmt.Printf("  🔗 No summary: %s\n", vaultRelative(obsidianVaultPath, path))
+		}
+		for _, id := range missing {
+			fmt.Printf("  📄 No transcript: %s\n", vaultRelative(obsidianVaultPath, scan.MissingTranscripts[id]))
+		}
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	deleted, archived := 0, 0
+	if len(scan.Orphans) > 0 {
+		deleted, archived = reviewOrphans(reader, obsidianVaultPath, scan.Orphans)
+	}
+
+	regenerated := 0
+	if len(missing) > 0 && askYesNo(reader, fmt.Sprintf("\nRegenerate %d missing transcript(s) from the cache?", len(missing))) {
+		attachmentsDir := vaultAttachmentsDir()
+		for _, id := range missing {
+			m, err := cache.LoadMeeting(id)
+			if err != nil {
+				fmt.Printf("  ⚠ %s is not cached, skipping\n", id)
+				continue
+			}
+			if !transcriptReady(m) {
+				fmt.Printf("  ⚠ %s has no transcript yet, skipping\n", id)
+				continue
+			}
+			summaryData, _ := cache.LoadSummary(id)
+			if isConfidential(m, summaryData) {
+				continue
+			}
+
+			path := filepath.Join(filepath.Dir(scan.MissingTranscripts[id]), id+"-transcript.md")
+			if err := vaultWriter.CreateNote(path, renderTranscriptNote(obsidianVaultPath, attachmentsDir, m, summaryData, cache)); err != nil {
+				fmt.Printf("  ⚠ Error writing %s: %v\n", vaultRelative(obsidianVaultPath, path), err)
+				continue
+			}
+			fmt.Printf("  ✓ Regenerated %s\n", vaultRelative(obsidianVaultPath, path))
+			regenerated++
+		}
+	}
+
+	fmt.Printf("\n✅ Deleted %d, archived %d orphaned transcript(s); regenerated %d transcript(s)\n", deleted, archived, regenerated)
+	return nil
+}
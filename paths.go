@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// vaultPathPrefix marks a configured path as relative to the Obsidian vault
+const vaultPathPrefix = "vault:"
+
+// dataDir is the directory holding state, the meeting cache and generated JSON artifacts
+var dataDir string
+
+// dataPath returns the location of a generated artifact (e.g. obsidian-tags.json) in the data directory
+func dataPath(name string) string {
+	return filepath.Join(dataDir, name)
+}
+
+// defaultDataDir returns the XDG data directory for krisp-sync
+// ($XDG_DATA_HOME/krisp-sync, falling back to ~/.local/share/krisp-sync)
+func defaultDataDir() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "krisp-sync"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "krisp-sync"), nil
+}
+
+// resolvePath expands a configured path:
+//   - "~/..." is expanded to the user's home directory
+//   - "vault:..." is resolved relative to the Obsidian vault
+//   - other relative paths are made absolute against the working directory
+func resolvePath(p string, vaultPath string) (string, error) {
+	if strings.HasPrefix(p, vaultPathPrefix) {
+		if vaultPath == "" {
+			return "", fmt.Errorf("path %q is vault-relative but OBSIDIAN_VAULT_PATH is not set", p)
+		}
+		return filepath.Join(vaultPath, strings.TrimPrefix(p, vaultPathPrefix)), nil
+	}
+
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not expand %q: %w", p, err)
+		}
+		p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+	}
+
+	return filepath.Abs(p)
+}
+
+// obsidianConfigDir is the folder Obsidian keeps in the root of every vault
+const obsidianConfigDir = ".obsidian"
+
+// isVaultRoot reports whether dir is the root of an Obsidian vault
+func isVaultRoot(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, obsidianConfigDir))
+	return err == nil && info.IsDir()
+}
+
+// findVaultRoot returns the closest directory at or above dir that is a vault root, or ""
+func findVaultRoot(dir string) string {
+	for {
+		if isVaultRoot(dir) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolveVaultPath expands OBSIDIAN_VAULT_PATH ("~" and relative paths are allowed) and
+// checks that it is the root of an Obsidian vault. When it isn't set, the vault containing
+// the working directory is used. OBSIDIAN_VAULT_CHECK=false skips the check for vaults
+// that haven't been opened in Obsidian yet.
+func resolveVaultPath(configured string) (string, error) {
+	if configured == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("OBSIDIAN_VAULT_PATH not set in .env file")
+		}
+		root := findVaultRoot(cwd)
+		if root == "" {
+			return "", fmt.Errorf("OBSIDIAN_VAULT_PATH not set in .env file, and %s is not inside an Obsidian vault", cwd)
+		}
+		fmt.Printf("🔎 Detected Obsidian vault: %s\n", root)
+		return root, nil
+	}
+
+	if strings.HasPrefix(configured, vaultPathPrefix) {
+		return "", fmt.Errorf("OBSIDIAN_VAULT_PATH %q can't be relative to the vault itself", configured)
+	}
+	path, err := resolvePath(configured, "")
+	if err != nil {
+		return "", fmt.Errorf("invalid OBSIDIAN_VAULT_PATH: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("OBSIDIAN_VAULT_PATH %s does not exist", path)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("OBSIDIAN_VAULT_PATH %s is a file, not a vault folder", path)
+	}
+
+	if isVaultRoot(path) || !envBoolDefault("OBSIDIAN_VAULT_CHECK", true) {
+		return path, nil
+	}
+	if root := findVaultRoot(filepath.Dir(path)); root != "" {
+		rel, _ := filepath.Rel(root, path)
+		return "", fmt.Errorf("OBSIDIAN_VAULT_PATH %s is the %q folder inside the vault %s - set it to the vault root", path, filepath.ToSlash(rel), root)
+	}
+	return "", fmt.Errorf("OBSIDIAN_VAULT_PATH %s is not an Obsidian vault (no %s folder) - open it as a vault in Obsidian first, or set OBSIDIAN_VAULT_CHECK=false", path, obsidianConfigDir)
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolveStoragePaths determines the data directory, meeting cache directory and
+// sync state path from flags, environment variables and defaults (in that order).
+// For backwards compatibility, an existing state file in the working directory keeps
+// the old CWD-relative layout when nothing is configured explicitly.
+func resolveStoragePaths(dataDirFlag, cacheDirFlag, statePathFlag, vaultPath string) (string, string, string, error) {
+	configuredDataDir := firstNonEmpty(dataDirFlag, os.Getenv("KRISP_SYNC_DATA_DIR"))
+
+	var dir string
+	var err error
+	if configuredDataDir != "" {
+		dir, err = resolvePath(configuredDataDir, vaultPath)
+		if err != nil {
+			return "", "", "", err
+		}
+	} else if fileExists(syncStateFile) {
+		fmt.Printf("⚠ Using legacy state in working directory; set KRISP_SYNC_DATA_DIR to move it\n")
+		dir, err = filepath.Abs(".")
+		if err != nil {
+			return "", "", "", err
+		}
+	} else {
+		dir, err = defaultDataDir()
+		if err != nil {
+			return "", "", "", err
+		}
+	}
+
+	cache := filepath.Join(dir, meetingsCacheDir)
+	if configured := firstNonEmpty(cacheDirFlag, os.Getenv("KRISP_SYNC_CACHE_DIR")); configured != "" {
+		cache, err = resolvePath(configured, vaultPath)
+		if err != nil {
+			return "", "", "", err
+		}
+	}
+
+	state := filepath.Join(dir, syncStateFile)
+	if configured := firstNonEmpty(statePathFlag, os.Getenv("KRISP_SYNC_STATE_PATH")); configured != "" {
+		state, err = resolvePath(configured, vaultPath)
+		if err != nil {
+			return "", "", "", err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(state), 0755); err != nil {
+		return "", "", "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return dir, cache, state, nil
+}
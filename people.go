@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	peopleFile       = "people.json"
+	defaultPeopleDir = "People"
+)
+
+// Person is a directory entry mapping a speaker's email to how they should appear in notes
+type Person struct {
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Team   string `json:"team,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Source string `json:"source,omitempty"` // file the entry was imported from
+
+	SyncedName string `json:"synced_name,omitempty"` // name last written to the vault, for rename-people
+}
+
+// PeopleRegistry holds known people keyed by lowercase email
+type PeopleRegistry struct {
+	People map[string]*Person `json:"people"`
+}
+
+// people is the registry loaded at startup (empty until imported)
+var people = &PeopleRegistry{People: make(map[string]*Person)}
+
+// loadPeople reads the people registry, returning an empty one if it doesn't exist yet
+func loadPeople(path string) (*PeopleRegistry, error) {
+	registry := &PeopleRegistry{People: make(map[string]*Person)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read people registry: %w", err)
+	}
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("failed to parse people registry %s: %w", path, err)
+	}
+	if registry.People == nil {
+		registry.People = make(map[string]*Person)
+	}
+	return registry, nil
+}
+
+// Save writes the registry to path
+func (r *PeopleRegistry) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Lookup returns the person registered for an email, or nil
+func (r *PeopleRegistry) Lookup(email string) *Person {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil
+	}
+	return r.People[email]
+}
+
+// Merge adds or updates a person; fields left empty keep their registered value.
+// Returns true if the person wasn't registered before.
+func (r *PeopleRegistry) Merge(p Person) bool {
+	key := strings.ToLower(strings.TrimSpace(p.Email))
+	existing, ok := r.People[key]
+	if !ok {
+		p.Email = key
+		r.People[key] = &p
+		return true
+	}
+	if p.Name != "" {
+		existing.Name = p.Name
+	}
+	if p.Team != "" {
+		existing.Team = p.Team
+	}
+	if p.Role != "" {
+		existing.Role = p.Role
+	}
+	existing.Source = p.Source
+	return false
+}
+
+// preferredName returns the registered name for an email, or fallback
+func preferredName(email, fallback string) string {
+	if p := people.Lookup(email); p != nil && p.Name != "" {
+		return p.Name
+	}
+	return fallback
+}
+
+// meetingPeople returns the registered people among a meeting's speakers and participants
+func meetingPeople(m *Meeting) []*Person {
+	var emails []string
+	for _, speakerInfo := range m.Speakers.Data {
+		emails = append(emails, speakerInfo.Person.Email)
+	}
+	for _, p := range m.Participants {
+		emails = append(emails, p.Email)
+	}
+
+	seen := make(map[*Person]bool)
+	var result []*Person
+	for _, email := range emails {
+		if p := people.Lookup(email); p != nil && !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// meetingTeams returns the teams of a meeting's registered people, sorted
+func meetingTeams(m *Meeting) []string {
+	var teams []string
+	for _, p := range meetingPeople(m) {
+		if p.Team != "" {
+			teams = append(teams, p.Team)
+		}
+	}
+	teams = uniqueStrings(teams)
+	sort.Strings(teams)
+	return teams
+}
+
+// Column names recognized in CSV exports (Google Contacts, LDAP tools, hand-made sheets)
+var (
+	csvEmailColumns = []string{"email", "mail", "e-mail 1 - value", "e-mail address", "email address"}
+	csvNameColumns  = []string{"name", "display name", "displayname", "full name", "cn"}
+	csvFirstColumns = []string{"first name", "given name", "givenname"}
+	csvLastColumns  = []string{"last name", "family name", "sn", "surname"}
+	csvTeamColumns  = []string{"team", "department", "organization department", "organization 1 - department", "ou"}
+	csvRoleColumns  = []string{"role", "title", "job title", "organization title", "organization 1 - title"}
+)
+
+// parsePeopleCSV reads people from a CSV export with a header row
+func parsePeopleCSV(r io.Reader, source string) ([]Person, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, h := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	column := func(row []string, names []string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(row) && strings.TrimSpace(row[i]) != "" {
+				return strings.TrimSpace(row[i])
+			}
+		}
+		return ""
+	}
+	if _, ok := firstColumn(columns, csvEmailColumns); !ok {
+		return nil, fmt.Errorf("no email column found (expected one of %s)", strings.Join(csvEmailColumns, ", "))
+	}
+
+	var result []Person
+	for _, row := range rows[1:] {
+		p := Person{
+			Email:  column(row, csvEmailColumns),
+			Name:   column(row, csvNameColumns),
+			Team:   column(row, csvTeamColumns),
+			Role:   column(row, csvRoleColumns),
+			Source: source,
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSpace(column(row, csvFirstColumns) + " " + column(row, csvLastColumns))
+		}
+		if p.Email != "" {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// firstColumn returns the index of the first of names present in columns
+func firstColumn(columns map[string]int, names []string) (int, bool) {
+	for _, name := range names {
+		if i, ok := columns[name]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parsePeopleLDIF reads people from an LDAP export (e.g. ldapsearch -LLL output)
+func parsePeopleLDIF(r io.Reader, source string) ([]Person, error) {
+	var result []Person
+	entry := make(map[string]string)
+
+	flush := func() {
+		p := Person{
+			Email:  entry["mail"],
+			Name:   firstNonEmpty(entry["displayname"], entry["cn"], strings.TrimSpace(entry["givenname"]+" "+entry["sn"])),
+			Team:   firstNonEmpty(entry["department"], entry["ou"]),
+			Role:   entry["title"],
+			Source: source,
+		}
+		if p.Email != "" {
+			result = append(result, p)
+		}
+		entry = make(map[string]string)
+	}
+
+	// Unfold continuation lines (starting with a single space) before parsing attributes
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LDIF: %w", err)
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		attr, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		attr = strings.ToLower(attr)
+		if strings.HasPrefix(value, ":") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		// Keep the first value of multi-valued attributes
+		if _, seen := entry[attr]; !seen {
+			entry[attr] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+
+	return result, nil
+}
+
+// runImportPeople seeds the people registry from a Google Contacts/LDAP CSV export or an LDIF file
+func runImportPeople(path string) error {
+	fmt.Println("\n=== Importing people directory ===")
+
+	if path == "" {
+		return fmt.Errorf("--from is required (a CSV or LDIF export)")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	source := filepath.Base(path)
+	var imported []Person
+	if strings.EqualFold(filepath.Ext(path), ".ldif") {
+		imported, err = parsePeopleLDIF(f, source)
+	} else {
+		imported, err = parsePeopleCSV(f, source)
+	}
+	if err != nil {
+		return err
+	}
+
+	added, updated := 0, 0
+	for _, p := range imported {
+		if people.Merge(p) {
+			added++
+		} else {
+			updated++
+		}
+	}
+
+	if err := people.Save(dataPath(peopleFile)); err != nil {
+		return fmt.Errorf("failed to save people registry: %w", err)
+	}
+
+	fmt.Printf("✅ Imported %d people from %s (%d new, %d updated, %d total)\n", len(imported), source, added, updated, len(people.People))
+	return nil
+}
+
+// writePeopleNotes creates a People note for each registered person in a meeting who
+// doesn't have one yet. Existing notes are left alone so they can be edited freely.
+func writePeopleNotes(vaultPath string, m *Meeting) {
+	changed := false
+	for _, p := range meetingPeople(m) {
+		if p.Name == "" {
+			continue
+		}
+		notePath := peopleNotePath(vaultPath, p.Name)
+		if p.SyncedName == "" {
+			p.SyncedName = p.Name
+			changed = true
+		}
+		if vaultWriter.Exists(notePath) {
+			continue
+		}
+
+		frontmatter := map[string]interface{}{
+			"type":  "person",
+			"email": p.Email,
+		}
+		if p.Team != "" {
+			frontmatter["team"] = p.Team
+		}
+		if p.Role != "" {
+			frontmatter["role"] = p.Role
+		}
+		if err := writeFrontmatterFile(notePath, frontmatter, peopleNoteBody(p.Name)); err != nil {
+			fmt.Printf("  ⚠ Error writing People note for %s: %v\n", p.Name, err)
+			continue
+		}
+		fmt.Printf("  👤 Created People note: %s\n", p.Name)
+	}
+
+	// Remember which names are in the vault so later renames can be propagated (a dry run
+	// wrote no notes, so it leaves the registry alone)
+	if changed && !dryRun {
+		if err := people.Save(dataPath(peopleFile)); err != nil {
+			fmt.Printf("  ⚠ Error saving people registry: %v\n", err)
+		}
+	}
+}
+
+// peopleNotePath returns the path of a person's note
+func peopleNotePath(vaultPath, name string) string {
+	return filepath.Join(vaultPath, firstNonEmpty(os.Getenv("PEOPLE_DIR"), defaultPeopleDir), sanitizeNoteName(name)+".md")
+}
+
+// peopleNoteBody renders the body of a new People note
+func peopleNoteBody(name string) string {
+	return fmt.Sprintf("\n# %s\n\n```dataview\nLIST FROM \"\" WHERE type = \"meeting\" AND contains(participants, \"%s\") SORT date DESC\n```\n", name, name)
+}
+
+// sanitizeNoteName removes characters Obsidian doesn't allow in note names
+func sanitizeNoteName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|#^[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Weekly planning note defaults
+const (
+	defaultPlanningWeeks = 4
+	weeklyPlanSuffix     = " Weekly Plan.md"
+	noProjectGroup       = "No project"
+)
+
+// planItemRegex matches a planning note checkbox: "- [ ] <label> ([[<id>-summary|<title>]])"
+var planItemRegex = regexp.MustCompile(`^- \[([ xX])\] (.+) \(\[\[([^|\]]+)-summary(?:\|[^\]]*)?\]\]\)$`)
+
+// weeklyPlanEnabled reports whether "all" runs create the week's planning note (WEEKLY_PLAN)
+func weeklyPlanEnabled() bool {
+	return envBool("WEEKLY_PLAN")
+}
+
+// planningWeeks reads PLANNING_WEEKS, how many weeks back open items are carried over from
+// (0 for all of them)
+func planningWeeks() (int, error) {
+	v := strings.TrimSpace(os.Getenv("PLANNING_WEEKS"))
+	if v == "" {
+		return defaultPlanningWeeks, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid PLANNING_WEEKS %q: must be a non-negative number of weeks", v)
+	}
+	return n, nil
+}
+
+// weekStart returns the Monday starting the week of t, at midnight local time
+func weekStart(t time.Time) time.Time {
+	t = t.Local()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// weeklyPlanPath returns the absolute path of the planning note for the week starting monday
+func weeklyPlanPath(vaultPath string, monday time.Time) string {
+	return filepath.Join(monthDir(vaultPath, monday), monday.Format("2006-01-02")+weeklyPlanSuffix)
+}
+
+// meetingProject returns the project a meeting belongs to: its first tag listed in
+// ANALYTICS_PROJECT_TAGS
+func meetingProject(summaryData *SummaryData, projects map[string]bool) string {
+	for _, tag := range splitTags(summaryData.Tags) {
+		if projects[tag] {
+			return tag
+		}
+	}
+	return noProjectGroup
+}
+
+// planItem is an open action item carried into a planning note
+type planItem struct {
+	Meeting *Meeting
+	Title   string
+	Item    ActionItem
+}
+
+// collectOpenActionItems returns the open action items of synced meetings held between
+// since and before, grouped by project. Confidential meetings are left out.
+func collectOpenActionItems(syncState *SyncState, cache *Cache, since, before time.Time) (map[string][]planItem, error) {
+	meetingIDs, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+	projects := projectTagsFromEnv()
+
+	groups := make(map[string][]planItem)
+	for _, id := range meetingIDs {
+		if !syncState.ObsidianSyncedMeetings[id] {
+			continue
+		}
+		meeting, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if meeting.CreatedAt.Before(since) || !meeting.CreatedAt.Before(before) {
+			continue
+		}
+		summaryData, err := cache.LoadSummary(id)
+		if err != nil || isConfidential(meeting, summaryData) {
+			continue
+		}
+		project := meetingProject(summaryData, projects)
+		for _, item := range summaryData.ActionItems {
+			if !item.Done {
+				groups[project] = append(groups[project], planItem{Meeting: meeting, Title: firstNonEmpty(summaryData.Title, meeting.Title), Item: item})
+			}
+		}
+	}
+
+	for _, items := range groups {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Meeting.CreatedAt.Before(items[j].Meeting.CreatedAt) })
+	}
+	return groups, nil
+}
+
+// renderWeeklyPlan renders the planning note for the week starting monday
+func renderWeeklyPlan(monday, since time.Time, groups map[string][]planItem) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("type: weekly-plan\n")
+	sb.WriteString(fmt.Sprintf("week: %s\n", monday.Format("2006-01-02")))
+	sb.WriteString("---\n\n")
+	sb.WriteString(fmt.Sprintf("# Week of %s\n\n", monday.Format("January 2, 2006")))
+	if since.IsZero() {
+		sb.WriteString("Open action items from earlier meetings. ")
+	} else {
+		sb.WriteString(fmt.Sprintf("Open action items from meetings since %s. ", since.Format("January 2")))
+	}
+	sb.WriteString("Check them off here or in the meeting note; either is recorded on the next run.\n\n")
+
+	if len(groups) == 0 {
+		sb.WriteString("_No open action items_ 🎉\n")
+		return sb.String()
+	}
+
+	// Your own items come first, each listed once so checking it off can't conflict
+	var mine []planItem
+	others := make(map[string][]planItem)
+	for name, items := range groups {
+		for _, p := range items {
+			if p.Item.Mine() {
+				mine = append(mine, p)
+			} else {
+				others[name] = append(others[name], p)
+			}
+		}
+	}
+	if len(mine) > 0 {
+		sort.SliceStable(mine, func(i, j int) bool { return mine[i].Meeting.CreatedAt.Before(mine[j].Meeting.CreatedAt) })
+		sb.WriteString(myActionItemsHeading + "\n\n")
+		sb.WriteString(renderMyActionItems(mine, ""))
+		sb.WriteString("\n")
+	}
+
+	names := make([]string, 0, len(others))
+	for name := range others {
+		if name != noProjectGroup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := others[noProjectGroup]; ok {
+		names = append(names, noProjectGroup)
+	}
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", name))
+		for _, p := range others[name] {
+			sb.WriteString(fmt.Sprintf("- [ ] %s ([[%s-summary|%s]])\n", p.Item.Label(), p.Meeting.ID, p.Title))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// runWeeklyPlan writes the planning note for the current week, listing the open action items
+// of the previous PLANNING_WEEKS weeks. An existing note is only replaced when force is set.
+func runWeeklyPlan(vaultPath string, syncState *SyncState, cache *Cache, force bool) error {
+	monday := weekStart(time.Now())
+	path := weeklyPlanPath(vaultPath, monday)
+	if !force && vaultWriter.Exists(path) {
+		return nil
+	}
+	fmt.Println("\n=== Plan: Weekly planning note ===")
+
+	weeks, err := planningWeeks()
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if weeks > 0 {
+		since = monday.AddDate(0, 0, -7*weeks)
+	}
+	groups, err := collectOpenActionItems(syncState, cache, since, monday)
+	if err != nil {
+		return err
+	}
+
+	open := 0
+	for _, items := range groups {
+		open += len(items)
+	}
+	if err := vaultWriter.CreateNote(path, []byte(renderWeeklyPlan(monday, since, groups))); err != nil {
+		return fmt.Errorf("failed to write planning note: %w", err)
+	}
+	fmt.Printf("✓ Wrote planning note with %d open action item(s): %s\n", open, vaultRelative(vaultPath, path))
+	return nil
+}
+
+// actionItemKey identifies an action item across notes
+func actionItemKey(meetingID, label string) string {
+	return meetingID + "\x00" + label
+}
+
+// withCheckbox returns a "- [ ] " checkbox line checked or unchecked, keeping its indentation
+func withCheckbox(line string, done bool) string {
+	i := strings.Index(line, "- [")
+	if i < 0 || len(line) < i+5 {
+		return line
+	}
+	box := " "
+	if done {
+		box = "x"
+	}
+	return line[:i+3] + box + line[i+4:]
+}
+
+// setActionItemCheckbox checks or unchecks the item with label in a note's Action Items
+// section. Returns false if the note has no such item.
+func setActionItemCheckbox(content, label string, done bool) (string, bool) {
+	lines := strings.Split(content, "\n")
+	inSection := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if headingLevel(trimmed) > 0 {
+			inSection = trimmed == actionItemsHeading
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		for _, prefix := range []string{"- [ ] ", "- [x] ", "- [X] "} {
+			if strings.HasPrefix(trimmed, prefix) && strings.TrimSpace(strings.TrimPrefix(trimmed, prefix)) == label {
+				lines[i] = withCheckbox(line, done)
+				return strings.Join(lines, "\n"), true
+			}
+		}
+	}
+	return content, false
+}
+
+// dailyNoteNameRegex matches daily note file names (see dailyNoteFileName)
+var dailyNoteNameRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-[A-Z][a-z]+day\.md$`)
+
+// findTaskNotes returns the notes listing action items from meeting notes: the weekly
+// planning notes in the month folders of the vault and a separate archive, and the daily
+// notes of days (their My Action Items section) that exist
+func findTaskNotes(vaultPath string, days []time.Time) ([]string, error) {
+	roots := []string{vaultPath}
+	if rel, err := filepath.Rel(vaultPath, archiveDir); archiveDir != "" && (err != nil || strings.HasPrefix(rel, "..")) && fileExists(archiveDir) {
+		roots = append(roots, archiveDir)
+	}
+	var notes []string
+	for _, root := range roots {
+		plans, err := filepath.Glob(filepath.Join(root, "*", "*", "*"+weeklyPlanSuffix))
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, plans...)
+	}
+	for _, day := range days {
+		if path := dailyNotePath(vaultPath, day); vaultWriter.Exists(path) {
+			notes = append(notes, path)
+		}
+	}
+	return notes, nil
+}
+
+// reconcileTaskNotes records action items checked off (or reopened) in planning notes and
+// the daily notes of days in the cache and ticks them in their meeting note. Items whose
+// meeting note changed in this run (changed) win instead. Every planning and daily note
+// checkbox is then brought in line, so an item checked off in one of them is checked in the
+// others too.
+func reconcileTaskNotes(vaultPath string, cache *Cache, changed map[string]bool, days []time.Time) (completed, reopened int, err error) {
+	paths, err := findTaskNotes(vaultPath, days)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error scanning vault for planning and daily notes: %w", err)
+	}
+
+	// Find the checkboxes that differ from the cache
+	notes := make(map[string][]string)
+	decided := make(map[string]bool)
+	for _, path := range paths {
+		content, err := vaultWriter.ReadNote(path)
+		if err != nil {
+			fmt.Printf("  ⚠ Error reading %s: %v\n", vaultRelative(vaultPath, path), err)
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		notes[path] = lines
+		for _, line := range lines {
+			match := planItemRegex.FindStringSubmatch(strings.TrimSpace(line))
+			if match == nil {
+				continue
+			}
+			checked, label, id := match[1] != " ", match[2], match[3]
+			key := actionItemKey(id, label)
+			if _, ok := decided[key]; ok || changed[key] {
+				continue
+			}
+			if item := findActionItem(cache, id, label); item != nil && item.Done != checked {
+				decided[key] = checked
+				if checked {
+					completed++
+					fmt.Printf("  ✓ %s: %s\n", filepath.Base(path), label)
+				} else {
+					reopened++
+					fmt.Printf("  ↺ %s: %s\n", filepath.Base(path), label)
+				}
+			}
+		}
+	}
+
+	// Record them in the cache and the meeting notes
+	now := time.Now()
+	for key, checked := range decided {
+		id, label, _ := strings.Cut(key, "\x00")
+		summaryData, err := cache.LoadSummary(id)
+		if err != nil {
+			continue
+		}
+		for j := range summaryData.ActionItems {
+			item := &summaryData.ActionItems[j]
+			if item.Label() != label {
+				continue
+			}
+			item.Done = checked
+			item.CompletedAt = nil
+			if checked {
+				item.CompletedAt = &now
+			}
+		}
+		if err := cache.SaveSummary(id, summaryData); err != nil {
+			fmt.Printf("  ⚠ Error saving summary %s: %v\n", id, err)
+			continue
+		}
+		if meeting, err := cache.LoadMeeting(id); err == nil {
+			notePath := summaryNotePath(vaultPath, meeting)
+			if note, err := vaultWriter.ReadNote(notePath); err == nil {
+				if updated, ok := setActionItemCheckbox(string(note), label, checked); ok {
+					if err := vaultWriter.CreateNote(notePath, []byte(updated)); err != nil {
+						fmt.Printf("  ⚠ Error updating %s: %v\n", vaultRelative(vaultPath, notePath), err)
+					}
+				}
+			}
+		}
+	}
+
+	// Bring every planning and daily note checkbox in line with the cache
+	for _, path := range paths {
+		lines, ok := notes[path]
+		if !ok {
+			continue
+		}
+		noteChanged := false
+		for i, line := range lines {
+			match := planItemRegex.FindStringSubmatch(strings.TrimSpace(line))
+			if match == nil {
+				continue
+			}
+			if item := findActionItem(cache, match[3], match[2]); item != nil && item.Done != (match[1] != " ") {
+				lines[i] = withCheckbox(line, item.Done)
+				noteChanged = true
+			}
+		}
+		if noteChanged {
+			if err := vaultWriter.CreateNote(path, []byte(strings.Join(lines, "\n"))); err != nil {
+				fmt.Printf("  ⚠ Error updating %s: %v\n", vaultRelative(vaultPath, path), err)
+			}
+		}
+	}
+	return completed, reopened, nil
+}
+
+// findActionItem returns a meeting's cached action item with label, or nil
+func findActionItem(cache *Cache, meetingID, label string) *ActionItem {
+	summaryData, err := cache.LoadSummary(meetingID)
+	if err != nil {
+		return nil
+	}
+	for i := range summaryData.ActionItems {
+		if summaryData.ActionItems[i].Label() == label {
+			return &summaryData.ActionItems[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+)
+
+// defaultPluginsDir is where plugins are loaded from, in the data directory
+const defaultPluginsDir = "plugins"
+
+// Hooks a plugin can export. Meetings and summaries cross the plugin boundary as the JSON
+// they are cached as, so plugins don't depend on this package's types.
+const (
+	// hookAfterDownload is func(meeting []byte) ([]byte, error), run on each downloaded
+	// meeting before it is cached, e.g. to redact its transcript
+	hookAfterDownload = "AfterDownload"
+	// hookAfterSummarize is func(meeting, summary []byte) ([]byte, error), run on each new
+	// summary before it is saved, e.g. to apply tag rules
+	hookAfterSummarize = "AfterSummarize"
+)
+
+// stagePlugin is a loaded plugin and the hooks it exports
+type stagePlugin struct {
+	Name           string
+	AfterDownload  func(meeting []byte) ([]byte, error)
+	AfterSummarize func(meeting, summary []byte) ([]byte, error)
+}
+
+// Hooks returns the names of the hooks the plugin exports
+func (p *stagePlugin) Hooks() []string {
+	var hooks []string
+	if p.AfterDownload != nil {
+		hooks = append(hooks, hookAfterDownload)
+	}
+	if p.AfterSummarize != nil {
+		hooks = append(hooks, hookAfterSummarize)
+	}
+	return hooks
+}
+
+// stagePlugins are the plugins loaded at startup, in file name order
+var stagePlugins []*stagePlugin
+
+// pluginsDir returns the directory plugins are loaded from (KRISP_SYNC_PLUGINS_DIR)
+func pluginsDir(vaultPath string) (string, error) {
+	if v := strings.TrimSpace(os.Getenv("KRISP_SYNC_PLUGINS_DIR")); v != "" {
+		return resolvePath(v, vaultPath)
+	}
+	return dataPath(defaultPluginsDir), nil
+}
+
+// loadPlugins opens every Go plugin (.so, built with -buildmode=plugin) in dir. A missing
+// directory means no plugins; a plugin that fails to load, or exports a hook with the
+// wrong signature, is an error, so a run never goes ahead without a redaction it relies on.
+func loadPlugins(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		p, err := openPlugin(path)
+		if err != nil {
+			return fmt.Errorf("failed to load plugin %s: %w", path, err)
+		}
+		if len(p.Hooks()) == 0 {
+			fmt.Printf("⚠ Plugin %s exports no hooks (%s or %s), ignoring it\n", p.Name, hookAfterDownload, hookAfterSummarize)
+			continue
+		}
+		stagePlugins = append(stagePlugins, p)
+		fmt.Printf("🔌 Plugin %s: %s\n", p.Name, strings.Join(p.Hooks(), ", "))
+	}
+	return nil
+}
+
+// openPlugin opens a plugin file and looks up its hooks
+func openPlugin(path string) (*stagePlugin, error) {
+	lib, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &stagePlugin{Name: strings.TrimSuffix(filepath.Base(path), ".so")}
+	if sym, err := lib.Lookup(hookAfterDownload); err == nil {
+		fn, ok := sym.(func([]byte) ([]byte, error))
+		if !ok {
+			return nil, fmt.Errorf("%s has type %T, expected func([]byte) ([]byte, error)", hookAfterDownload, sym)
+		}
+		p.AfterDownload = fn
+	}
+	if sym, err := lib.Lookup(hookAfterSummarize); err == nil {
+		fn, ok := sym.(func([]byte, []byte) ([]byte, error))
+		if !ok {
+			return nil, fmt.Errorf("%s has type %T, expected func([]byte, []byte) ([]byte, error)", hookAfterSummarize, sym)
+		}
+		p.AfterSummarize = fn
+	}
+	return p, nil
+}
+
+// runAfterDownload passes a downloaded meeting through the plugins' AfterDownload hooks,
+// updating it in place
+func runAfterDownload(meeting *Meeting) error {
+	for _, p := range stagePlugins {
+		if p.AfterDownload == nil {
+			continue
+		}
+		data, err := json.Marshal(meeting)
+		if err != nil {
+			return err
+		}
+		out, err := p.AfterDownload(data)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
+		var transformed Meeting
+		if err := json.Unmarshal(out, &transformed); err != nil {
+			return fmt.Errorf("plugin %s returned an invalid meeting: %w", p.Name, err)
+		}
+		if transformed.ID != meeting.ID {
+			return fmt.Errorf("plugin %s changed the meeting ID from %s to %q", p.Name, meeting.ID, transformed.ID)
+		}
+		*meeting = transformed
+	}
+	return nil
+}
+
+// runAfterSummarize passes a new summary through the plugins' AfterSummarize hooks,
+// updating it in place
+func runAfterSummarize(meeting *Meeting, summary *SummaryData) error {
+	for _, p := range stagePlugins {
+		if p.AfterSummarize == nil {
+			continue
+		}
+		meetingData, err := json.Marshal(meeting)
+		if err != nil {
+			return err
+		}
+		summaryData, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		out, err := p.AfterSummarize(meetingData, summaryData)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
+		var transformed SummaryData
+		if err := json.Unmarshal(out, &transformed); err != nil {
+			return fmt.Errorf("plugin %s returned an invalid summary: %w", p.Name, err)
+		}
+		*summary = transformed
+	}
+	return nil
+}
+
+// saveDownloadedMeeting caches a meeting fresh from Krisp, after the plugins' hooks
+func saveDownloadedMeeting(cache *Cache, meeting *Meeting) error {
+	if err := runAfterDownload(meeting); err != nil {
+		return err
+	}
+	return cache.SaveMeeting(meeting)
+}
+
+// runPlugins lists the plugins found and the hooks each exports
+func runPlugins(dir string) {
+	fmt.Printf("\n=== Plugins: %s ===\n", dir)
+	if len(stagePlugins) == 0 {
+		fmt.Println("No plugins loaded")
+		return
+	}
+	for _, p := range stagePlugins {
+		fmt.Printf("  %s: %s\n", p.Name, strings.Join(p.Hooks(), ", "))
+	}
+}
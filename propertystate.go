@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// stateStoreProperties reads which meetings are in the vault from the notes themselves
+// instead of the state file, so deleting a note re-syncs its meeting
+const stateStoreProperties = "properties"
+
+// syncedVersionKey is the frontmatter property stamped on notes written in the properties
+// store; currentSyncedVersion is bumped when notes need to be regenerated
+const (
+	syncedVersionKey     = "synced_version"
+	currentSyncedVersion = 1
+)
+
+// propertiesState is set when the vault notes are the source of truth for sync status
+var propertiesState bool
+
+// vaultNoteVersions holds the synced_version of each meeting's note found in the vault;
+// nil until the sync stage has read them
+var vaultNoteVersions map[string]int
+
+// noteSyncedVersion reads the meeting ID and synced_version of a summary note. Notes
+// without the property have version 0.
+func noteSyncedVersion(path string) (string, int, bool) {
+	content, err := vaultWriter.ReadNote(path)
+	if err != nil || !bytes.HasPrefix(content, []byte("---\n")) {
+		return "", 0, false
+	}
+	fm, _, err := splitFrontmatter(content)
+	if err != nil || (fm["meeting_id"] == nil && fm["krisp_meeting_id"] == nil) {
+		return "", 0, false
+	}
+	id := noteMeetingID(path, fm)
+	if id == "" {
+		return "", 0, false
+	}
+	version, _ := strconv.Atoi(fmt.Sprintf("%v", fm[syncedVersionKey]))
+	return id, version, true
+}
+
+// scanSyncedVersions returns the synced_version of the summary notes in the meeting folders
+// (YYYY/MM-MonthName/meetings) of the vault and a separate archive vault, and of your own
+// notes summaries were added to (VAULT_DEDUPE), by meeting ID
+func scanSyncedVersions(vaultPath string) (map[string]int, error) {
+	versions := make(map[string]int)
+	add := func(path string) {
+		id, version, ok := noteSyncedVersion(path)
+		if !ok {
+			return
+		}
+		if v, seen := versions[id]; !seen || version > v {
+			versions[id] = version
+		}
+	}
+
+	roots := []string{vaultPath}
+	if rel, err := filepath.Rel(vaultPath, archiveDir); archiveDir != "" && (err != nil || strings.HasPrefix(rel, "..")) && fileExists(archiveDir) {
+		roots = append(roots, archiveDir)
+	}
+	for _, root := range roots {
+		paths, err := filepath.Glob(filepath.Join(root, "*", "*", "meetings", "*-summary.md"))
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s for synced notes: %w", root, err)
+		}
+		for _, path := range paths {
+			add(path)
+		}
+	}
+
+	// Summaries added to your own notes can be anywhere in the vault
+	if envBool("VAULT_DEDUPE") {
+		index, err := buildDedupeIndex(vaultPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range index.merged {
+			add(path)
+		}
+	}
+	return versions, nil
+}
+
+// applyPropertiesState replaces the download and summary status in the state file with
+// what the cache holds: meetings are downloaded when cached and summarized when their
+// summary is cached. Which meetings are in Obsidian is read from the vault when the sync
+// stage runs (see loadVaultSyncStatus).
+func applyPropertiesState(state *SyncState, cache *Cache) error {
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return err
+	}
+
+	state.SyncedMeetings = make(map[string]bool, len(ids))
+	state.SummarizedMeetings = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		state.SyncedMeetings[id] = true
+		if cache.SummaryExists(id) {
+			state.SummarizedMeetings[id] = true
+		}
+	}
+	return nil
+}
+
+// loadVaultSyncStatus replaces which meetings are in Obsidian with the notes in the vault,
+// once per run: a meeting is synced when its note has the current synced_version. A note
+// without the property is adopted (stamped) on this sync; one with an older version is
+// regenerated.
+func loadVaultSyncStatus(state *SyncState, vaultPath string) error {
+	if !propertiesState || vaultNoteVersions != nil {
+		return nil
+	}
+	versions, err := scanSyncedVersions(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	state.mu.Lock()
+	state.ObsidianSyncedMeetings = make(map[string]bool, len(versions))
+	outdated := 0
+	for id, version := range versions {
+		if version >= currentSyncedVersion {
+			state.ObsidianSyncedMeetings[id] = true
+		} else if version > 0 {
+			outdated++
+		}
+	}
+	state.pending++
+	state.mu.Unlock()
+	vaultNoteVersions = versions
+
+	fmt.Printf("🗂  State from vault properties: %d downloaded, %d summarized, %d in Obsidian", len(state.SyncedMeetings), len(state.SummarizedMeetings), len(state.ObsidianSyncedMeetings))
+	if outdated > 0 {
+		fmt.Printf(", %d to regenerate", outdated)
+	}
+	fmt.Println()
+	return nil
+}
+
+// noteOutdated reports whether a meeting's note was written by an older synced_version and
+// has to be regenerated
+func noteOutdated(meetingID string) bool {
+	if !propertiesState {
+		return false
+	}
+	version, ok := vaultNoteVersions[meetingID]
+	return ok && version > 0 && version < currentSyncedVersion
+}
+
+// withSyncedVersion stamps a note's content with the current synced_version in the
+// properties store; otherwise it is returned as is
+func withSyncedVersion(content []byte) []byte {
+	if !propertiesState {
+		return content
+	}
+	stamped, err := editFrontmatter(content, map[string]interface{}{syncedVersionKey: currentSyncedVersion}, nil)
+	if err != nil {
+		return content
+	}
+	return stamped
+}
+
+// stampSyncedVersion adopts an existing note in the properties store, so it counts as synced
+func stampSyncedVersion(path string) error {
+	if !propertiesState {
+		return nil
+	}
+	return editFrontmatterFile(path, map[string]interface{}{syncedVersionKey: currentSyncedVersion}, nil)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// version is the krisp-sync version recorded in generated notes (set with -ldflags "-X main.version=...")
+var version = "dev"
+
+// provenanceMarker starts the provenance comment at the bottom of generated notes
+const provenanceMarker = "<!-- krisp-sync:provenance "
+
+// checkedBoxRegex matches a checked task checkbox, which doesn't count as an edit
+var checkedBoxRegex = regexp.MustCompile(`(?m)^(\s*- )\[[xX]\]`)
+
+// Provenance records how a note was generated. Everything above it was written by
+// krisp-sync; anything below it is the user's and survives regeneration.
+type Provenance struct {
+	Tool        string    `json:"tool"`
+	Prompt      string    `json:"prompt,omitempty"` // version (hash) of the summary prompt
+	Model       string    `json:"model,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+	MeetingID   string    `json:"meeting_id"`
+	ContentHash string    `json:"content_hash"`         // hash of the generated body, to detect edits
+	Properties  []string  `json:"properties,omitempty"` // frontmatter properties the note was generated with
+	Aliases     []string  `json:"aliases,omitempty"`    // aliases the note was generated with
+}
+
+// promptVersion identifies the summary prompt a note was generated with
+func promptVersion() string {
+	return contentHash([]byte(summaryPromptTemplate))[:12]
+}
+
+// generatedBodyHash hashes the body of a generated note, ignoring frontmatter (edited as
+// properties in Obsidian), surrounding whitespace and checked-off tasks
+func generatedBodyHash(content []byte) string {
+	body := string(content)
+	if _, b, err := splitFrontmatter(content); err == nil {
+		body = b
+	}
+	body = checkedBoxRegex.ReplaceAllString(strings.TrimSpace(blankUserSections(body)), "$1[ ]")
+	return contentHash([]byte(body))
+}
+
+// appendProvenance adds the provenance footer to a freshly generated note
+func appendProvenance(content []byte, p Provenance) []byte {
+	p.Tool = "krisp-sync " + version
+	p.ContentHash = generatedBodyHash(content)
+	if frontmatter, _, err := splitFrontmatter(content); err == nil {
+		p.Properties = make([]string, 0, len(frontmatter))
+		for key := range frontmatter {
+			p.Properties = append(p.Properties, key)
+		}
+		sort.Strings(p.Properties)
+		p.Aliases = frontmatterList(frontmatter["aliases"])
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return content
+	}
+	return []byte(fmt.Sprintf("%s\n\n%s%s -->\n", strings.TrimRight(string(content), "\n"), provenanceMarker, data))
+}
+
+// splitProvenance splits a note at its provenance footer into the generated part, the
+// provenance and the user content below it. Returns a nil provenance if there is no footer.
+func splitProvenance(content []byte) ([]byte, *Provenance, string) {
+	text := string(content)
+	start := strings.LastIndex(text, provenanceMarker)
+	if start < 0 {
+		return content, nil, ""
+	}
+	end := strings.Index(text[start:], "-->")
+	if end < 0 {
+		return content, nil, ""
+	}
+	end += start
+
+	var p Provenance
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text[start+len(provenanceMarker):end])), &p); err != nil {
+		return content, nil, ""
+	}
+	generated := strings.TrimRight(text[:start], "\n") + "\n"
+	tail := strings.TrimLeft(text[end+len("-->"):], "\n")
+	return []byte(generated), &p, tail
+}
+
+// regenerateNote combines a freshly rendered note with the existing one it replaces.
+// Notes with a provenance footer keep the user content below it and the properties and
+// aliases added by hand, while ones they were generated with are replaced; if the generated
+// part was edited since it was written, edits are merged as with resync instead of being
+// overwritten. Other notes are merged only when
+// mergeOnOverwrite is set.
+func regenerateNote(path string, existing, rendered []byte) []byte {
+	generated, p, tail := splitProvenance(existing)
+	if p == nil {
+		if mergeOnOverwrite {
+			return preserveUserSections(existing, mergeWithExisting(existing, rendered, nil))
+		}
+		return preserveUserSections(existing, rendered)
+	}
+
+	var content []byte
+	if generatedBodyHash(generated) != p.ContentHash {
+		// Notes without frontmatter (transcripts) have no sections to merge by - leave them be
+		if _, _, err := splitFrontmatter(generated); err != nil {
+			fmt.Printf("  ⚠ %s was edited since it was generated - not regenerating it\n", filepath.Base(path))
+			return existing
+		}
+		fmt.Printf("  ⚠ %s was edited since it was generated - keeping your changes\n", filepath.Base(path))
+		content = mergeWithExisting(generated, rendered, p)
+	} else {
+		content = mergeFrontmatterOnly(generated, rendered, p)
+	}
+	content = preserveUserSections(generated, content)
+	if strings.TrimSpace(tail) != "" {
+		content = []byte(strings.TrimRight(string(content), "\n") + "\n\n" + tail)
+	}
+	return content
+}
+
+// summaryModel returns the model a summary was generated with, if known
+func summaryModel(summaryData *SummaryData) string {
+	if summaryData == nil {
+		return ""
+	}
+	return summaryData.Model
+}
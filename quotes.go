@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxQuoteWords is how many leading words of a quote are used to find it in the transcript
+const maxQuoteWords = 8
+
+// Quote is a notable verbatim quote from a meeting
+type Quote struct {
+	Text      string  `json:"text"`
+	Speaker   string  `json:"speaker"`
+	SegmentID *int    `json:"segment_id,omitempty"` // transcript segment the quote was found in
+	Start     float64 `json:"start,omitempty"`      // seconds from meeting start
+}
+
+// notableQuotesEnabled reports whether quotes are extracted and rendered (NOTABLE_QUOTES, default on)
+func notableQuotesEnabled() bool {
+	return envBoolDefault("NOTABLE_QUOTES", true)
+}
+
+// transcriptBlockID is the Obsidian block ID of a transcript line, used to link quotes to it
+func transcriptBlockID(segmentID int) string {
+	return fmt.Sprintf("seg-%d", segmentID)
+}
+
+// normalizeForMatch lowercases text and reduces it to words, so quotes match despite
+// punctuation and spacing differences
+func normalizeForMatch(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	return strings.Join(words, " ")
+}
+
+// locateQuotes finds each quote's transcript segment so it can link to the exact line.
+// Quotes that can't be found (e.g. paraphrased by the model) are kept without a location.
+func locateQuotes(meeting *Meeting, quotes []Quote, inferred map[string]string) []Quote {
+	if len(quotes) == 0 {
+		return quotes
+	}
+
+	var segments []Segment
+	if err := json.Unmarshal([]byte(meeting.Resources.Transcript.Content), &segments); err != nil {
+		return quotes
+	}
+	normalized := make([]string, len(segments))
+	for i, seg := range segments {
+		normalized[i] = normalizeForMatch(seg.Speech.Text)
+	}
+
+	for qi := range quotes {
+		words := strings.Fields(normalizeForMatch(quotes[qi].Text))
+		if len(words) > maxQuoteWords {
+			words = words[:maxQuoteWords]
+		}
+		needle := strings.Join(words, " ")
+		if needle == "" {
+			continue
+		}
+
+		for i, text := range normalized {
+			if strings.Contains(text, needle) {
+				id := segments[i].ID
+				quotes[qi].SegmentID = &id
+				quotes[qi].Start = segments[i].Speech.Start
+				if quotes[qi].Speaker == "" {
+					quotes[qi].Speaker = speakerDisplayName(meeting, segments[i].SpeakerIndex, inferred)
+				}
+				break
+			}
+		}
+	}
+	return quotes
+}
+
+// renderNotableQuotes renders the "Notable Quotes" section of a summary note, or "" if disabled or empty
+func renderNotableQuotes(summaryData *SummaryData, meetingID string, offset float64) string {
+	if !notableQuotesEnabled() || summaryData == nil || len(summaryData.Quotes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Notable Quotes\n")
+	for _, q := range summaryData.Quotes {
+		sb.WriteString(fmt.Sprintf("> \"%s\"\n", q.Text))
+		attribution := q.Speaker
+		if q.SegmentID != nil {
+			attribution += fmt.Sprintf(", [[%s-transcript#^%s|%s]]", meetingID, transcriptBlockID(*q.SegmentID), formatTimestamp(q.Start+offset))
+		}
+		if attribution != "" {
+			sb.WriteString(fmt.Sprintf("> — %s\n", strings.TrimPrefix(attribution, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
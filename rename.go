@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// personRename is a registered person whose name in the vault differs from the registry
+type personRename struct {
+	Person *Person
+	From   []string // names the person may appear under in synced notes
+}
+
+// findRenames compares the people registry with the names already synced: the name last
+// written to the vault and the names Krisp used for the same email
+func findRenames(cache *Cache) ([]personRename, error) {
+	krispNames := make(map[string][]string) // lowercase email -> names Krisp used
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		for _, speakerInfo := range m.Speakers.Data {
+			email := strings.ToLower(speakerInfo.Person.Email)
+			krispNames[email] = append(krispNames[email], strings.TrimSpace(speakerInfo.Person.FirstName+" "+speakerInfo.Person.LastName))
+		}
+		for _, p := range m.Participants {
+			email := strings.ToLower(p.Email)
+			krispNames[email] = append(krispNames[email], strings.TrimSpace(p.FirstName+" "+p.LastName))
+		}
+	}
+
+	var renames []personRename
+	for email, p := range people.People {
+		if p.Name == "" {
+			continue
+		}
+		var from []string
+		for _, name := range append([]string{p.SyncedName}, krispNames[email]...) {
+			if name != "" && name != p.Name {
+				from = append(from, name)
+			}
+		}
+		if from = uniqueStrings(from); len(from) > 0 {
+			sort.Strings(from)
+			renames = append(renames, personRename{Person: p, From: from})
+		}
+	}
+	sort.Slice(renames, func(i, j int) bool { return renames[i].Person.Name < renames[j].Person.Name })
+	return renames, nil
+}
+
+// renameSpeakerLabels renames a speaker in transcript lines ("**[01:23] Name**: ...",
+// with the timestamp optionally linked)
+func renameSpeakerLabels(content, from, to string) string {
+	re := regexp.MustCompile(`(\*\*(?:\[\[[^\]]*\]\]|\[[^\]]*\](?:\([^)]*\))?) )` + regexp.QuoteMeta(from) + `(\*\*)`)
+	return re.ReplaceAllString(content, "${1}"+strings.ReplaceAll(to, "$", "$$")+"${2}")
+}
+
+// renameParticipant renames a person in a note's participants frontmatter, written either
+// inline ("participants: A, B") or as a list
+func renameParticipant(content, from, to string) string {
+	lines := strings.Split(content, "\n")
+	inFrontmatter, inList := false, false
+	for i, line := range lines {
+		if line == "---" {
+			if inFrontmatter {
+				break
+			}
+			inFrontmatter = i == 0
+			continue
+		}
+		if !inFrontmatter {
+			break
+		}
+
+		if inList {
+			item := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+			if !strings.HasPrefix(strings.TrimSpace(line), "-") {
+				inList = false
+			} else if strings.Trim(item, `"'`) == from {
+				lines[i] = strings.Replace(line, from, to, 1)
+				continue
+			} else {
+				continue
+			}
+		}
+
+		value, ok := strings.CutPrefix(line, "participants:")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			inList = true
+			continue
+		}
+		quoted := strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && len(value) > 1
+		names := strings.Split(strings.Trim(value, `"`), ", ")
+		for j, name := range names {
+			if name == from {
+				names[j] = to
+			}
+		}
+		joined := strings.Join(names, ", ")
+		if quoted {
+			joined = `"` + joined + `"`
+		}
+		lines[i] = "participants: " + joined
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printLineDiff prints the lines that differ between two versions of a note
+func printLineDiff(label, before, after string) {
+	fmt.Printf("--- %s\n+++ %s\n", label, label)
+	b := strings.Split(before, "\n")
+	a := strings.Split(after, "\n")
+	if len(a) != len(b) {
+		for _, line := range b {
+			fmt.Printf("- %s\n", line)
+		}
+		for _, line := range a {
+			fmt.Printf("+ %s\n", line)
+		}
+		return
+	}
+	for i := range b {
+		if b[i] != a[i] {
+			fmt.Printf("@@ line %d @@\n- %s\n+ %s\n", i+1, b[i], a[i])
+		}
+	}
+}
+
+// runRenamePeople rewrites the names of renamed people across synced transcripts,
+// participant frontmatter and People notes, printing a diff of every change
+func runRenamePeople(vaultPath string, cache *Cache, dryRun bool) error {
+	fmt.Println("\n=== Propagating people renames ===")
+	if dryRun {
+		fmt.Println("🔍 Dry run: no files will be changed")
+	}
+
+	renames, err := findRenames(cache)
+	if err != nil {
+		return err
+	}
+	if len(renames) == 0 {
+		fmt.Println("✅ Nobody has been renamed")
+		return nil
+	}
+	for _, r := range renames {
+		fmt.Printf("👤 %s → %s\n", strings.Join(r.From, ", "), r.Person.Name)
+	}
+
+	summaries, err := findSummaryNotes(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	changedFiles := 0
+	rewrite := func(path string, apply func(string) string) error {
+		if !vaultWriter.Exists(path) {
+			return nil
+		}
+		content, err := vaultWriter.ReadNote(path)
+		if err != nil {
+			return err
+		}
+		updated := apply(string(content))
+		if updated == string(content) {
+			return nil
+		}
+		printLineDiff(vaultRelative(vaultPath, path), string(content), updated)
+		changedFiles++
+		if dryRun {
+			return nil
+		}
+		return vaultWriter.CreateNote(path, []byte(updated))
+	}
+
+	for _, summaryPath := range summaries {
+		if err := rewrite(summaryPath, func(content string) string {
+			for _, r := range renames {
+				for _, from := range r.From {
+					content = renameParticipant(content, from, r.Person.Name)
+				}
+			}
+			return content
+		}); err != nil {
+			fmt.Printf("  ⚠ Error updating %s: %v\n", summaryPath, err)
+		}
+
+		transcriptPath := strings.TrimSuffix(summaryPath, "-summary.md") + "-transcript.md"
+		if err := rewrite(transcriptPath, func(content string) string {
+			for _, r := range renames {
+				for _, from := range r.From {
+					content = renameSpeakerLabels(content, from, r.Person.Name)
+				}
+			}
+			return content
+		}); err != nil {
+			fmt.Printf("  ⚠ Error updating %s: %v\n", transcriptPath, err)
+		}
+	}
+
+	// People notes move to the new name, with their heading and query updated
+	for _, r := range renames {
+		newPath := peopleNotePath(vaultPath, r.Person.Name)
+		for _, from := range r.From {
+			oldPath := peopleNotePath(vaultPath, from)
+			if oldPath == newPath || !vaultWriter.Exists(oldPath) {
+				continue
+			}
+			if vaultWriter.Exists(newPath) {
+				fmt.Printf("  ⚠ Both %s and %s exist - merge them by hand\n", vaultRelative(vaultPath, oldPath), vaultRelative(vaultPath, newPath))
+				continue
+			}
+			content, err := vaultWriter.ReadNote(oldPath)
+			if err != nil {
+				fmt.Printf("  ⚠ Error reading %s: %v\n", oldPath, err)
+				continue
+			}
+			updated := strings.ReplaceAll(string(content), "# "+from+"\n", "# "+r.Person.Name+"\n")
+			updated = strings.ReplaceAll(updated, fmt.Sprintf("contains(participants, %q)", from), fmt.Sprintf("contains(participants, %q)", r.Person.Name))
+			fmt.Printf("rename %s → %s\n", vaultRelative(vaultPath, oldPath), vaultRelative(vaultPath, newPath))
+			printLineDiff(vaultRelative(vaultPath, newPath), string(content), updated)
+			changedFiles++
+			if dryRun {
+				continue
+			}
+			if err := vaultWriter.CreateNote(newPath, []byte(updated)); err != nil {
+				fmt.Printf("  ⚠ Error writing %s: %v\n", newPath, err)
+				continue
+			}
+			if err := vaultWriter.DeleteNote(oldPath); err != nil {
+				fmt.Printf("  ⚠ Error removing %s: %v\n", oldPath, err)
+			}
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\n🔍 %d file(s) would change; run without --dry-run to apply\n", changedFiles)
+		return nil
+	}
+
+	for _, r := range renames {
+		r.Person.SyncedName = r.Person.Name
+	}
+	if err := people.Save(dataPath(peopleFile)); err != nil {
+		return fmt.Errorf("failed to save people registry: %w", err)
+	}
+	fmt.Printf("\n✅ Updated %d file(s)\n", changedFiles)
+	return nil
+}
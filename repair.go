@@ -11,7 +11,7 @@ func runRepair(syncState *SyncState, cache *Cache) error {
 	fmt.Println("\n=== Repair: Syncing state with filesystem ===")
 
 	// Get all meeting files from filesystem
-	files, err := filepath.Glob(filepath.Join(meetingsCacheDir, "*.json"))
+	files, err := filepath.Glob(filepath.Join(cache.dir, "*.json"))
 	if err != nil {
 		return fmt.Errorf("error reading cache directory: %w", err)
 	}
@@ -27,7 +27,7 @@ func runRepair(syncState *SyncState, cache *Cache) error {
 			// Summary file
 			meetingID := strings.TrimSuffix(filename, "-summary.json")
 			actualSummaries[meetingID] = true
-		} else {
+		} else if !isDerivedCacheFile(filename) {
 			// Meeting file
 			meetingID := strings.TrimSuffix(filename, ".json")
 			actualMeetings[meetingID] = true
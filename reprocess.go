@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// reprocessPollInterval is how often a reprocessing meeting is polled
+	reprocessPollInterval = 15 * time.Second
+	// reprocessTimeout is how long to wait for Krisp to finish reprocessing
+	reprocessTimeout = 30 * time.Minute
+)
+
+// requestReprocess asks Krisp to re-run transcription and speaker diarization for a meeting
+func requestReprocess(ctx context.Context, api *KrispClient, meetingID string) error {
+	err := api.Reprocess(ctx, meetingID)
+	var apiErr *KrispAPIError
+	if errors.As(err, &apiErr) && (errors.Is(err, errKrispNotFound) || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		return fmt.Errorf("Krisp does not support reprocessing this meeting (status %d)", apiErr.StatusCode)
+	}
+	return err
+}
+
+// waitForReprocess polls a meeting until its transcript has been regenerated
+func waitForReprocess(ctx context.Context, api *KrispClient, meetingID, previousContent string) (*Meeting, error) {
+	deadline := time.Now().Add(reprocessTimeout)
+	sawProcessing := false
+
+	for {
+		meeting, err := api.GetMeeting(ctx, meetingID)
+		if err != nil {
+			return nil, err
+		}
+
+		status := meeting.Resources.Transcript.Status
+		if status != "uploaded" {
+			sawProcessing = true
+		} else if sawProcessing || meeting.Resources.Transcript.Content != previousContent {
+			return meeting, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for reprocessing (status: %s)", reprocessTimeout, status)
+		}
+		fmt.Printf("  ⏳ Transcript status: %s, checking again in %s\n", status, reprocessPollInterval)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(reprocessPollInterval):
+		}
+	}
+}
+
+// runReprocess triggers Krisp reprocessing for each meeting, waits for it to finish and
+// re-downloads the result. Returns the IDs that were reprocessed so later stages can
+// re-summarize and re-sync them.
+func runReprocess(ctx context.Context, meetingIDs []string, syncState *SyncState, cache *Cache) ([]string, error) {
+	fmt.Println("\n=== Reprocess: Re-running Krisp transcription ===")
+
+	if len(meetingIDs) == 0 {
+		return nil, fmt.Errorf("reprocess requires --meeting <id>")
+	}
+
+	var reprocessed []string
+	for _, id := range meetingIDs {
+		if ctx.Err() != nil {
+			return reprocessed, ctx.Err()
+		}
+
+		fmt.Printf("\n🔁 Reprocessing %s\n", id)
+		if isMergedMeetingID(id) {
+			fmt.Printf("  ⚠ %s was made by merge and isn't in Krisp - reprocess the original meetings and merge them again\n", id)
+			continue
+		}
+
+		previousContent := ""
+		if cached, err := cache.LoadMeeting(id); err == nil {
+			previousContent = cached.Resources.Transcript.Content
+		}
+		api, ok := meetingClient(cache, id)
+		if !ok {
+			fmt.Printf("  ⚠ Unknown Krisp account for %s - download it first\n", id)
+			continue
+		}
+
+		if err := requestReprocess(ctx, api, id); err != nil {
+			fmt.Printf("  ⚠ %v\n", err)
+			continue
+		}
+		fmt.Println("  ✓ Reprocessing requested")
+
+		meeting, err := waitForReprocess(ctx, api, id, previousContent)
+		if err != nil {
+			fmt.Printf("  ⚠ %v\n", err)
+			continue
+		}
+
+		meeting.Account = api.account
+		if err := saveDownloadedMeeting(cache, meeting); err != nil {
+			fmt.Printf("  ⚠ Error caching meeting: %v\n", err)
+			continue
+		}
+		syncState.MarkDownloaded(id)
+		reprocessed = append(reprocessed, id)
+		fmt.Printf("  ✓ Re-downloaded: %s\n", meeting.Title)
+	}
+
+	fmt.Printf("\n✅ Reprocessed %d of %d meeting(s)\n", len(reprocessed), len(meetingIDs))
+	return reprocessed, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mergeOnOverwrite keeps user edits (properties, tags and aliases set by hand, and added
+// sections) when an existing summary note is re-rendered. Enabled by the resync step.
+var mergeOnOverwrite bool
+
+// findResyncMeetings returns synced meetings in the given month (YYYY-MM) and/or with the
+// given tag, oldest first
+func findResyncMeetings(syncState *SyncState, cache *Cache, month, tag string) ([]string, error) {
+	if month == "" && tag == "" {
+		return nil, fmt.Errorf("resync requires --month YYYY-MM and/or --tag <tag>")
+	}
+	if month != "" {
+		if _, err := parseMonth(month); err != nil {
+			return nil, err
+		}
+	}
+
+	type match struct {
+		id      string
+		meeting *Meeting
+	}
+	var matches []match
+	for id := range syncState.ObsidianSyncedMeetings {
+		meeting, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if month != "" && meeting.CreatedAt.Local().Format("2006-01") != month {
+			continue
+		}
+		if tag != "" {
+			summaryData, err := cache.LoadSummary(id)
+			if err != nil || !contains(splitTags(summaryData.Tags), tag) {
+				continue
+			}
+		}
+		matches = append(matches, match{id, meeting})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].meeting.CreatedAt.Before(matches[j].meeting.CreatedAt)
+	})
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.id
+	}
+	return ids, nil
+}
+
+// splitSections splits a note body into its preamble and "## " sections, keyed by heading line
+func splitSections(body string) (string, []string, map[string]string) {
+	var order []string
+	sections := make(map[string]string)
+	var preamble strings.Builder
+	current := ""
+
+	for _, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if headingLevel(trimmed) == 2 {
+			current = trimmed
+			if _, ok := sections[current]; !ok {
+				order = append(order, current)
+			}
+		}
+		if current == "" {
+			preamble.WriteString(line)
+		} else {
+			sections[current] += line
+		}
+	}
+	return preamble.String(), order, sections
+}
+
+// mergeWithExisting re-applies user edits from an existing note onto a freshly rendered one:
+// frontmatter properties and aliases the user set by hand are kept (see mergeFrontmatter),
+// and sections whose heading the new render doesn't have are appended. p is the provenance
+// the existing note was generated with, or nil if it has none.
+func mergeWithExisting(existing, rendered []byte, p *Provenance) []byte {
+	oldFrontmatter, oldBody, err := splitFrontmatter(existing)
+	if err != nil {
+		return rendered
+	}
+	newFrontmatter, newBody, err := splitFrontmatter(rendered)
+	if err != nil {
+		return rendered
+	}
+
+	mergeFrontmatter(oldFrontmatter, newFrontmatter, p)
+
+	_, _, newSections := splitSections(newBody)
+	_, oldOrder, oldSections := splitSections(oldBody)
+	var extra bytes.Buffer
+	for _, heading := range oldOrder {
+		if _, ok := newSections[heading]; !ok {
+			extra.WriteString(strings.TrimRight(oldSections[heading], "\n") + "\n\n")
+		}
+	}
+	if extra.Len() > 0 {
+		newBody = strings.TrimRight(newBody, "\n") + "\n\n" + strings.TrimRight(extra.String(), "\n") + "\n"
+	}
+
+	return renderFrontmatterNote(newFrontmatter, newBody)
+}
+
+// mergeFrontmatterOnly keeps the existing note's hand-set properties and aliases but
+// takes the body of the freshly rendered note as is. p is the provenance the existing note
+// was generated with.
+func mergeFrontmatterOnly(existing, rendered []byte, p *Provenance) []byte {
+	oldFrontmatter, _, err := splitFrontmatter(existing)
+	if err != nil {
+		return rendered
+	}
+	newFrontmatter, newBody, err := splitFrontmatter(rendered)
+	if err != nil {
+		return rendered
+	}
+	mergeFrontmatter(oldFrontmatter, newFrontmatter, p)
+	return renderFrontmatterNote(newFrontmatter, newBody)
+}
+
+// mergeFrontmatter copies the properties the user set by hand from oldFrontmatter into
+// newFrontmatter. The render's own properties win, tags included: tags added by hand are
+// recorded with the summary and rendered again (see claimUserTags). When the provenance
+// of the old note records what it was generated with, properties and aliases it was
+// generated with are left out, so ones the template stopped rendering go away; without
+// it every property the render doesn't set, and every alias, is kept.
+func mergeFrontmatter(oldFrontmatter, newFrontmatter map[string]interface{}, p *Provenance) {
+	generated := make(map[string]bool)
+	var generatedAliases []string
+	if p != nil && p.Properties != nil {
+		for _, key := range p.Properties {
+			generated[key] = true
+		}
+		generatedAliases = p.Aliases
+	}
+
+	for key, value := range oldFrontmatter {
+		if _, ok := newFrontmatter[key]; ok || generated[key] {
+			continue
+		}
+		newFrontmatter[key] = value
+	}
+
+	var userAliases []string
+	for _, alias := range frontmatterList(oldFrontmatter["aliases"]) {
+		if !contains(generatedAliases, alias) {
+			userAliases = append(userAliases, alias)
+		}
+	}
+	if merged := uniqueStrings(append(frontmatterList(newFrontmatter["aliases"]), userAliases...)); len(merged) > 0 {
+		newFrontmatter["aliases"] = merged
+	}
+}
+
+// frontmatterList returns a list-valued frontmatter property as strings
+func frontmatterList(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+	case []string:
+		items = append(items, v...)
+	case string:
+		if v != "" {
+			items = append(items, v)
+		}
+	}
+	return items
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// resyncFlag is the frontmatter property that asks for a meeting to be regenerated
+const resyncFlag = "krisp_resync"
+
+const defaultWatchInterval = 30 * time.Second
+
+// flagSet reports whether a frontmatter value is true
+func flagSet(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "true")
+	}
+	return false
+}
+
+// noteMeetingID returns the meeting a synced note belongs to, from its frontmatter or file name
+func noteMeetingID(path string, frontmatter map[string]interface{}) string {
+	for _, key := range []string{"meeting_id", "krisp_meeting_id"} {
+		if id, ok := frontmatter[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(path), ".md")
+	for _, suffix := range []string{"-summary", "-transcript"} {
+		if id, ok := strings.CutSuffix(name, suffix); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// unflaggedNotes remembers the modification time of meeting notes found without the flag,
+// so watch mode only re-reads notes changed since its last look
+var unflaggedNotes = make(map[string]time.Time)
+
+// findResyncFlags returns the meeting notes flagged with krisp_resync: true, by meeting ID.
+// Only the meeting folders (YYYY/MM-MonthName/meetings) of the vault and archive are scanned.
+func findResyncFlags(vaultPath string) (map[string][]string, error) {
+	flagged := make(map[string][]string)
+	for _, root := range []string{vaultPath, archiveDir} {
+		if root == "" {
+			continue
+		}
+		paths, err := filepath.Glob(filepath.Join(root, "*", "*", "meetings", "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("error scanning vault for %s flags: %w", resyncFlag, err)
+		}
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if modTime, ok := unflaggedNotes[path]; ok && modTime.Equal(info.ModTime()) {
+				continue
+			}
+			fm, _, err := parseFrontmatter(path)
+			if err != nil || !flagSet(fm[resyncFlag]) {
+				unflaggedNotes[path] = info.ModTime()
+				continue
+			}
+			delete(unflaggedNotes, path)
+			if id := noteMeetingID(path, fm); id != "" {
+				flagged[id] = append(flagged[id], path)
+			} else {
+				fmt.Printf("⚠ %s has %s set but isn't a synced meeting note\n", path, resyncFlag)
+			}
+		}
+	}
+	return flagged, nil
+}
+
+// clearResyncFlag removes the flag from a note, if regenerating it didn't already
+func clearResyncFlag(path string) error {
+	if !vaultWriter.Exists(path) {
+		return nil
+	}
+	fm, _, err := parseFrontmatter(path)
+	if err != nil || fm[resyncFlag] == nil {
+		return nil
+	}
+	return editFrontmatterFile(path, nil, []string{resyncFlag})
+}
+
+// runFlaggedResync re-summarizes and re-syncs meetings whose notes were flagged in Obsidian,
+// keeping edits like resync does, then clears the flags. Returns how many meetings were regenerated.
+func runFlaggedResync(ctx context.Context, vaultPath string, syncState *SyncState, cache *Cache) (int, error) {
+	flagged, err := findResyncFlags(vaultPath)
+	if err != nil || len(flagged) == 0 {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(flagged))
+	for id := range flagged {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	fmt.Printf("\n🔁 Regenerating %d meeting(s) flagged with %s\n", len(ids), resyncFlag)
+
+	// Flags stay set until the meeting is re-summarized, so offline runs and failures retry it
+	resummarized := false
+	if !skipOffline("summarize", serviceLLM) {
+		if err := runSummarize(ctx, 0, syncState, true, ids, cache); err != nil {
+			return 0, err
+		}
+		resummarized = true
+	}
+
+	previous := mergeOnOverwrite
+	mergeOnOverwrite = true
+	_, err = runSync(ctx, vaultPath, 0, syncState, true, false, false, ids, nil, cache)
+	mergeOnOverwrite = previous
+	if err != nil {
+		return 0, err
+	}
+
+	for id, paths := range flagged {
+		if !resummarized || !syncState.SummarizedMeetings[id] || !syncState.ObsidianSyncedMeetings[id] {
+			fmt.Printf("  ⚠ %s wasn't regenerated, leaving %s set\n", id, resyncFlag)
+			continue
+		}
+		for _, path := range paths {
+			if err := clearResyncFlag(path); err != nil {
+				fmt.Printf("  ⚠ Error clearing %s in %s: %v\n", resyncFlag, path, err)
+			}
+		}
+	}
+	return len(ids), nil
+}
+
+// runWatch polls the vault for krisp_resync flags until interrupted (WATCH_INTERVAL, default 30s)
+func runWatch(ctx context.Context, vaultPath string, syncState *SyncState, cache *Cache) error {
+	interval := defaultWatchInterval
+	if v := os.Getenv("WATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid WATCH_INTERVAL %q: must be a duration like 30s", v)
+		}
+		interval = d
+	}
+
+	quiet, err := quietScheduleFromEnv()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Watching vault for %s flags (every %s, Ctrl+C to stop) ===\n", resyncFlag, interval)
+	if quiet.enabled() {
+		fmt.Printf("🌙 Quiet hours: %s\n", quiet)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Flags set during quiet hours wait in the vault until they're over
+		if quiet.quiet(time.Now()) {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				continue
+			}
+		}
+
+		n, err := runFlaggedResync(ctx, vaultPath, syncState, cache)
+		if err != nil {
+			fmt.Printf("❌ Error regenerating flagged meetings: %v\n", err)
+		} else if n > 0 {
+			if err := syncState.Flush(); err != nil {
+				fmt.Printf("⚠ Warning: Could not save sync state: %v\n", err)
+			}
+			fmt.Printf("✅ Regenerated %d meeting(s)\n", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultSandboxDir is where `sync --test` writes, in the data directory
+const defaultSandboxDir = "sandbox"
+
+// sandboxDir returns the test-mode sandbox (KRISP_SYNC_SANDBOX_DIR, default
+// <data-dir>/sandbox). It must be outside the vault, or Obsidian would index the
+// experiments alongside the real notes.
+func sandboxDir(vaultPath string) (string, error) {
+	dir := dataPath(defaultSandboxDir)
+	if v := strings.TrimSpace(os.Getenv("KRISP_SYNC_SANDBOX_DIR")); v != "" {
+		resolved, err := resolvePath(v, vaultPath)
+		if err != nil {
+			return "", err
+		}
+		dir = resolved
+	}
+	if rel, err := filepath.Rel(vaultPath, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("test sandbox %s is inside the vault; set KRISP_SYNC_SANDBOX_DIR to a folder outside it", dir)
+	}
+	return dir, nil
+}
+
+// SandboxVaultWriter redirects vault writes to a sandbox folder that mirrors the vault's
+// layout, for --test. Notes are read from the sandbox when written there, and from the
+// vault otherwise, so regenerated notes keep what the real ones have. The vault itself
+// is never written; paths outside it (e.g. the archive) are written as usual.
+type SandboxVaultWriter struct {
+	inner      VaultWriter
+	vaultPath  string
+	sandboxDir string
+	mu         sync.Mutex
+	deleted    map[string]bool // vault notes the run deleted, hidden for the rest of it
+}
+
+// newSandboxVaultWriter wraps a writer, sending writes inside vaultPath to sandboxDir
+func newSandboxVaultWriter(inner VaultWriter, vaultPath, sandboxDir string) *SandboxVaultWriter {
+	return &SandboxVaultWriter{inner: inner, vaultPath: vaultPath, sandboxDir: sandboxDir, deleted: make(map[string]bool)}
+}
+
+// sandboxPath returns where a vault path lives in the sandbox, or false for paths
+// outside the vault
+func (w *SandboxVaultWriter) sandboxPath(path string) (string, bool) {
+	rel, err := filepath.Rel(w.vaultPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(w.sandboxDir, rel), true
+}
+
+func (w *SandboxVaultWriter) Exists(path string) bool {
+	sandboxed, ok := w.sandboxPath(path)
+	if !ok {
+		return w.inner.Exists(path)
+	}
+	if fileExists(sandboxed) {
+		return true
+	}
+	w.mu.Lock()
+	deleted := w.deleted[filepath.Clean(path)]
+	w.mu.Unlock()
+	return !deleted && w.inner.Exists(path)
+}
+
+func (w *SandboxVaultWriter) ReadNote(path string) ([]byte, error) {
+	sandboxed, ok := w.sandboxPath(path)
+	if !ok {
+		return w.inner.ReadNote(path)
+	}
+	if content, err := os.ReadFile(sandboxed); err == nil {
+		return content, nil
+	}
+	w.mu.Lock()
+	deleted := w.deleted[filepath.Clean(path)]
+	w.mu.Unlock()
+	if deleted {
+		return nil, fmt.Errorf("open %s: %w", path, os.ErrNotExist)
+	}
+	return w.inner.ReadNote(path)
+}
+
+func (w *SandboxVaultWriter) CreateNote(path string, content []byte) error {
+	sandboxed, ok := w.sandboxPath(path)
+	if !ok {
+		return w.inner.CreateNote(path, content)
+	}
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(sandboxed), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(sandboxed, content, 0644)
+}
+
+func (w *SandboxVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *SandboxVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *SandboxVaultWriter) DeleteNote(path string) error {
+	sandboxed, ok := w.sandboxPath(path)
+	if !ok {
+		return w.inner.DeleteNote(path)
+	}
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	if !w.Exists(path) {
+		return os.ErrNotExist
+	}
+	if err := os.Remove(sandboxed); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	w.mu.Lock()
+	w.deleted[filepath.Clean(path)] = true
+	w.mu.Unlock()
+	return nil
+}
+
+// MoveFolder moves a vault folder within the sandbox: its files are written to dest (in the
+// sandbox when dest is in the vault) and hidden from the vault, which is left as it was
+func (w *SandboxVaultWriter) MoveFolder(src, dest string) error {
+	sandboxed, ok := w.sandboxPath(src)
+	if !ok {
+		return w.inner.MoveFolder(src, dest)
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, dir := range []string{src, sandboxed} {
+		found, err := folderFiles(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, path := range found {
+			path = movedPath(path, dir, src)
+			if !seen[path] && w.Exists(path) {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return os.ErrNotExist
+	}
+	return moveFolderFiles(w, files, src, dest)
+}
+
+// sandboxFile is a file in the sandbox and how it compares with the vault
+type sandboxFile struct {
+	Rel    string // path relative to the sandbox and the vault
+	Status string // "new", "changed" or "same"
+}
+
+// listSandbox returns the files in the sandbox under the given sandbox-relative paths
+// (all of them when none are given), sorted
+func listSandbox(vaultPath, dir string, paths []string) ([]sandboxFile, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	seen := make(map[string]bool)
+	var files []sandboxFile
+	for _, p := range paths {
+		root := filepath.Join(dir, filepath.FromSlash(p))
+		if rel, err := filepath.Rel(dir, root); err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is outside the sandbox", p)
+		}
+		if _, err := os.Stat(root); err != nil {
+			return nil, fmt.Errorf("%s is not in the sandbox %s", p, dir)
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, path)
+			if seen[rel] {
+				return nil
+			}
+			seen[rel] = true
+			status := "new"
+			if existing, err := os.ReadFile(filepath.Join(vaultPath, rel)); err == nil {
+				status = "changed"
+				if content, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+					status = "same"
+				}
+			}
+			files = append(files, sandboxFile{Rel: filepath.ToSlash(rel), Status: status})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Rel < files[j].Rel })
+	return files, nil
+}
+
+// runPromote copies approved files from the test sandbox into the vault, through the
+// vault writer so the copies are in the audit log and can be rolled back, and removes
+// them from the sandbox. Without paths it lists what the sandbox holds.
+func runPromote(vaultPath, dir string, paths []string) error {
+	fmt.Printf("\n=== Promote: Copy files from the test sandbox (%s) into the vault ===\n", dir)
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Println("✅ The sandbox is empty; `krisp-sync sync --test` writes there")
+		return nil
+	}
+	files, err := listSandbox(vaultPath, dir, paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("✅ The sandbox is empty; `krisp-sync sync --test` writes there")
+		return nil
+	}
+
+	if len(paths) == 0 {
+		for _, f := range files {
+			fmt.Printf("  %-8s %s\n", f.Status, f.Rel)
+		}
+		fmt.Printf("\n%d file(s) in the sandbox. Review them (e.g. open %s as a vault), then promote files or folders with `krisp-sync promote <path>...` (`.` for all)\n", len(files), dir)
+		return nil
+	}
+
+	promoted := 0
+	for _, f := range files {
+		sandboxed := filepath.Join(dir, filepath.FromSlash(f.Rel))
+		content, err := os.ReadFile(sandboxed)
+		if err != nil {
+			return err
+		}
+		if f.Status != "same" {
+			if err := vaultWriter.CreateNote(filepath.Join(vaultPath, filepath.FromSlash(f.Rel)), content); err != nil {
+				fmt.Printf("  ⚠ Error promoting %s: %v\n", f.Rel, err)
+				continue
+			}
+			fmt.Printf("  ✓ Promoted (%s): %s\n", f.Status, f.Rel)
+			promoted++
+		}
+		if !dryRun {
+			if err := os.Remove(sandboxed); err != nil {
+				fmt.Printf("  ⚠ Could not remove %s from the sandbox: %v\n", f.Rel, err)
+			}
+		}
+	}
+	if !dryRun {
+		removeEmptyDirs(dir)
+	}
+	fmt.Printf("✅ Promoted %d file(s) into the vault\n", promoted)
+	return nil
+}
+
+// removeEmptyDirs removes the empty folders under dir, deepest first
+func removeEmptyDirs(dir string) {
+	var dirs []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != dir {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i]) // fails, harmlessly, on folders that still have files
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Search index defaults
+const (
+	defaultSearchIndexNote  = "Transcript Index.md"
+	defaultSearchIndexTerms = 25
+	// minIndexTermLength is the shortest word indexed; shorter ones are mostly filler
+	minIndexTermLength = 4
+)
+
+// indexStopwords are common spoken words that say nothing about what a meeting was about
+var indexStopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		about above actually after again against all also always another anything anyway around
+		away back basically because been before being below between both bring called came cannot
+		could didn doesn doing done down during each else even ever every everything exactly
+		fine first from going gonna good got gotta great guess guys happen have having hear here
+		hmm however just keep kind know last later least left less let like likely little look
+		looking made make makes making many maybe mean means might more most much must need
+		needs never next nice none nothing okay once only other others ours over pretty probably
+		quite rather really right said same saying says second seems seen should since some
+		someone something sometimes somewhere sorry sort still stuff such sure take talk talking
+		tell than thank thanks that thats their them then there these they thing things think
+		thinking this those though thought through time today together tomorrow told totally
+		took true trying under until very want wanted wanna well went were what whatever when
+		where whether which while will with within without wonder word work would yeah year
+		years yesterday your yours yourself`) {
+		indexStopwords[w] = true
+	}
+}
+
+// searchIndexMode reads SEARCH_INDEX: "auto" (default) keeps the index only when Obsidian's
+// excluded files cover the transcript notes; true and false force it on or off
+func searchIndexMode() (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(os.Getenv("SEARCH_INDEX"))); v {
+	case "", "auto":
+		return "auto", nil
+	case "1", "true", "yes":
+		return "on", nil
+	case "0", "false", "no":
+		return "off", nil
+	default:
+		return "", fmt.Errorf("invalid SEARCH_INDEX %q: expected auto, true or false", v)
+	}
+}
+
+// searchIndexTerms reads SEARCH_INDEX_TERMS, how many key terms are indexed per meeting
+func searchIndexTerms() int {
+	v := strings.TrimSpace(os.Getenv("SEARCH_INDEX_TERMS"))
+	if v == "" {
+		return defaultSearchIndexTerms
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		fmt.Printf("⚠ Ignoring invalid SEARCH_INDEX_TERMS %q\n", v)
+		return defaultSearchIndexTerms
+	}
+	return n
+}
+
+// searchIndexPath returns the path of the index note (SEARCH_INDEX_NOTE, vault-relative)
+func searchIndexPath(vaultPath string) string {
+	name := firstNonEmpty(strings.TrimSpace(os.Getenv("SEARCH_INDEX_NOTE")), defaultSearchIndexNote)
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+	return filepath.Join(vaultPath, name)
+}
+
+// obsidianExcludedFiles returns the "Excluded files" of Obsidian's settings (userIgnoreFilters
+// in .obsidian/app.json): vault-relative path prefixes, or regular expressions in slashes
+func obsidianExcludedFiles(vaultPath string) []string {
+	data, err := os.ReadFile(filepath.Join(vaultPath, ".obsidian", "app.json"))
+	if err != nil {
+		return nil
+	}
+	var settings struct {
+		UserIgnoreFilters []string `json:"userIgnoreFilters"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil
+	}
+	return settings.UserIgnoreFilters
+}
+
+// excludedFromSearch reports whether a vault-relative path matches one of Obsidian's
+// excluded files filters
+func excludedFromSearch(relPath string, filters []string) bool {
+	for _, filter := range filters {
+		if len(filter) > 2 && strings.HasPrefix(filter, "/") && strings.HasSuffix(filter, "/") {
+			if re, err := regexp.Compile(filter[1 : len(filter)-1]); err == nil && re.MatchString(relPath) {
+				return true
+			}
+			continue
+		}
+		if filter != "" && strings.HasPrefix(relPath, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchIndexWanted reports whether "all" runs keep the index note: SEARCH_INDEX is on, or
+// it is auto and Obsidian's search skips the transcript note of any synced meeting
+func searchIndexWanted(vaultPath string, syncState *SyncState, cache *Cache) bool {
+	mode, err := searchIndexMode()
+	if err != nil {
+		fmt.Printf("⚠ %v\n", err)
+		return false
+	}
+	if mode != "auto" {
+		return mode == "on"
+	}
+	filters := obsidianExcludedFiles(vaultPath)
+	if len(filters) == 0 {
+		return false
+	}
+	for id := range syncState.ObsidianSyncedMeetings {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		if excludedFromSearch(vaultRelative(vaultPath, transcriptNotePath(vaultPath, m)), filters) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchTerms are the indexed word counts of a transcript, cached with the hash of the
+// transcript they were counted from so the index isn't recounted from every transcript
+type SearchTerms struct {
+	TranscriptHash string         `json:"transcript_hash"`
+	Counts         map[string]int `json:"counts"`
+}
+
+// meetingIndexTerms returns the indexed word counts of a meeting's transcript, from the
+// cache while the transcript is unchanged
+func meetingIndexTerms(cache *Cache, m *Meeting, summaryData *SummaryData) (map[string]int, error) {
+	hash := contentHash([]byte(m.Resources.Transcript.Content))
+	if cached, err := cache.LoadSearchTerms(m.ID); err == nil && cached.TranscriptHash == hash {
+		return cached.Counts, nil
+	}
+	transcript, _, err := prepareTranscript(m, inferredSpeakers(summaryData))
+	if err != nil {
+		return nil, err
+	}
+	counts := indexTerms(transcript)
+	if err := cache.SaveSearchTerms(m.ID, &SearchTerms{TranscriptHash: hash, Counts: counts}); err != nil {
+		fmt.Printf("  ⚠ Error caching search terms for %s: %v\n", m.ID, err)
+	}
+	return counts, nil
+}
+
+// indexTerms splits the speech of "Speaker: text" transcript lines into the words worth
+// indexing, counting each
+func indexTerms(transcript string) map[string]int {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(transcript, "\n") {
+		if _, speech, ok := strings.Cut(line, ": "); ok {
+			line = speech
+		}
+		for _, word := range strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+		}) {
+			word = strings.Trim(word, "-")
+			if len([]rune(word)) < minIndexTermLength || indexStopwords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+				continue
+			}
+			counts[word]++
+		}
+	}
+	return counts
+}
+
+// keyTerms picks a meeting's most distinctive terms: frequent in its transcript and rare
+// in the others (tf-idf). Terms said only once are left out.
+func keyTerms(counts map[string]int, docFreq map[string]int, docs, limit int) []string {
+	type scored struct {
+		term  string
+		score float64
+	}
+	var terms []scored
+	for term, n := range counts {
+		if n < 2 {
+			continue
+		}
+		idf := math.Log(float64(docs+1) / float64(docFreq[term]))
+		terms = append(terms, scored{term, float64(n) * idf})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].score != terms[j].score {
+			return terms[i].score > terms[j].score
+		}
+		return terms[i].term < terms[j].term
+	})
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	out := make([]string, len(terms))
+	for i, t := range terms {
+		out[i] = t.term
+	}
+	sort.Strings(out)
+	return out
+}
+
+// runSearchIndex writes a note listing the key terms of every synced meeting's transcript,
+// each linking to its transcript note, so the meetings can be found with Obsidian search
+// even though it skips the transcripts. Confidential meetings are left out.
+func runSearchIndex(vaultPath string, syncState *SyncState, cache *Cache) error {
+	fmt.Println("\n=== Search index: Key terms of transcripts ===")
+
+	path := searchIndexPath(vaultPath)
+	filters := obsidianExcludedFiles(vaultPath)
+	if excludedFromSearch(vaultRelative(vaultPath, path), filters) {
+		fmt.Printf("⚠ %s is itself excluded from Obsidian search; set SEARCH_INDEX_NOTE to a path outside the excluded files\n", vaultRelative(vaultPath, path))
+	}
+
+	type entry struct {
+		meeting *Meeting
+		counts  map[string]int
+	}
+	var entries []entry
+	docFreq := make(map[string]int)
+	confidential := 0
+	for id := range syncState.ObsidianSyncedMeetings {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		summaryData, _ := cache.LoadSummary(id)
+		if isConfidential(m, summaryData) {
+			confidential++
+			continue
+		}
+		counts, err := meetingIndexTerms(cache, m, summaryData)
+		if err != nil {
+			continue
+		}
+		for term := range counts {
+			docFreq[term]++
+		}
+		entries = append(entries, entry{meeting: m, counts: counts})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].meeting.CreatedAt.After(entries[j].meeting.CreatedAt) })
+
+	limit := searchIndexTerms()
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("type: transcript-index\n")
+	sb.WriteString(fmt.Sprintf("meetings: %d\n", len(entries)))
+	sb.WriteString("---\n\n")
+	sb.WriteString("# Transcript Index\n\n")
+	sb.WriteString("Key terms of each meeting's transcript, for finding meetings with Obsidian search when it skips the transcripts. Regenerated by krisp-sync - edits will be overwritten.\n")
+	month := ""
+	for _, e := range entries {
+		m := e.meeting
+		created := m.CreatedAt.Local()
+		if created.Format("2006-01") != month {
+			month = created.Format("2006-01")
+			sb.WriteString(fmt.Sprintf("\n## %s\n\n", month))
+		}
+		target := strings.TrimSuffix(filepath.Base(transcriptNotePath(vaultPath, m)), ".md")
+		if !vaultWriter.Exists(transcriptNotePath(vaultPath, m)) {
+			target = strings.TrimSuffix(filepath.Base(summaryNotePath(vaultPath, m)), ".md")
+		}
+		label := strings.NewReplacer("[", "(", "]", ")", "|", "-").Replace(m.Title)
+		terms := keyTerms(e.counts, docFreq, len(entries), limit)
+		sb.WriteString(fmt.Sprintf("- [[%s|%s %s]]: %s\n", target, created.Format("2006-01-02"), label, strings.Join(terms, ", ")))
+	}
+	content := []byte(sb.String())
+
+	if vaultWriter.Exists(path) {
+		if existing, err := vaultWriter.ReadNote(path); err == nil && bytes.Equal(existing, content) {
+			fmt.Printf("✅ Search index up to date: %d meeting(s)\n", len(entries))
+			return nil
+		}
+	}
+	if err := vaultWriter.CreateNote(path, content); err != nil {
+		return fmt.Errorf("error writing search index: %w", err)
+	}
+	if confidential > 0 {
+		fmt.Printf("🔒 Left out %d confidential meeting(s)\n", confidential)
+	}
+	fmt.Printf("✅ Indexed %d meeting(s) in %s\n", len(entries), vaultRelative(vaultPath, path))
+	return nil
+}
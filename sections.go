@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TopicDetail is one topic of a meeting with its paragraph summary
+type TopicDetail struct {
+	Topic   string `json:"topic"`
+	Summary string `json:"summary"`
+}
+
+// summarySection is a block of the summary note body that can be reordered or turned off
+// with SUMMARY_SECTIONS. Var is the template variable holding the rendered block.
+type summarySection struct {
+	Key string
+	Var string
+}
+
+// summarySections lists the body sections in their default order
+var summarySections = []summarySection{
+	{"description", "DescriptionBlock"},
+	{"transcript", "TranscriptLink"},
+	{"omissions", "Omissions"},
+	{"since-last-time", "SinceLastTime"},
+	{"agenda", "Agenda"},
+	{"topics", "Topics"},
+	{"topic-details", "TopicDetails"},
+	{"decisions", "Decisions"},
+	{"quotes", "NotableQuotes"},
+	{"action-items", "ActionItems"},
+	{"chat", "ChatAndAttachments"},
+	{userSectionsKey, "UserSections"},
+}
+
+// userSectionsKey is the section key of the user sections (see usersections.go)
+const userSectionsKey = "user-sections"
+
+// summarySectionsFromEnv returns the sections to render, in order. SUMMARY_SECTIONS is a
+// comma-separated list of section keys; sections left out are not rendered.
+func summarySectionsFromEnv() ([]summarySection, error) {
+	value := strings.TrimSpace(os.Getenv("SUMMARY_SECTIONS"))
+	if value == "" {
+		return summarySections, nil
+	}
+
+	byKey := make(map[string]summarySection, len(summarySections))
+	keys := make([]string, 0, len(summarySections))
+	for _, s := range summarySections {
+		byKey[s.Key] = s
+		keys = append(keys, s.Key)
+	}
+
+	var sections []summarySection
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(value, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" || seen[key] {
+			continue
+		}
+		s, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid SUMMARY_SECTIONS %q: unknown section %q (available: %s)", value, key, strings.Join(keys, ", "))
+		}
+		seen[key] = true
+		sections = append(sections, s)
+	}
+	return sections, nil
+}
+
+// renderSections joins the rendered sections in order
+func renderSections(sections []summarySection, templateData map[string]interface{}) string {
+	var sb strings.Builder
+	for _, s := range sections {
+		if text, ok := templateData[s.Var].(string); ok {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+// renderDescriptionBlock renders the description as a quote under the title
+func renderDescriptionBlock(description string) string {
+	if description == "" {
+		return ""
+	}
+	return fmt.Sprintf("> %s\n\n", description)
+}
+
+// renderTranscriptLink renders the link to a meeting's transcript note
+func renderTranscriptLink(meetingID string) string {
+	return fmt.Sprintf("**Transcript**: [[meetings/%s-transcript|View Transcript]]\n\n", meetingID)
+}
+
+// renderTopics renders the "Topics Discussed" list. Summaries cached before topics were
+// stored separately render their whole summary here instead.
+func renderTopics(summaryData *SummaryData) string {
+	if summaryData == nil {
+		return ""
+	}
+	if len(summaryData.Topics) == 0 && len(summaryData.TopicDetails) == 0 {
+		return summaryData.Summary
+	}
+
+	var sb strings.Builder
+	if nameGuardMode() == nameGuardFlag && len(summaryData.UnknownNames) > 0 {
+		sb.WriteString(unverifiedNamesCallout(summaryData.UnknownNames))
+	}
+	sb.WriteString("## Topics Discussed\n")
+	for _, topic := range summaryData.Topics {
+		sb.WriteString(fmt.Sprintf("- %s\n", topic))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// renderTopicDetails renders a section per topic
+func renderTopicDetails(summaryData *SummaryData) string {
+	if summaryData == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, detail := range summaryData.TopicDetails {
+		sb.WriteString(fmt.Sprintf("## %s\n", detail.Topic))
+		sb.WriteString(detail.Summary)
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// renderDecisions renders the decisions made in the meeting
+func renderDecisions(summaryData *SummaryData) string {
+	if summaryData == nil || len(summaryData.Decisions) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Decisions\n")
+	for _, decision := range summaryData.Decisions {
+		sb.WriteString(fmt.Sprintf("- %s\n", decision))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"google.golang.org/genai"
+)
+
+//go:embed series-diff-prompt.md
+var seriesDiffPromptTemplate string
+
+var (
+	seriesDateRegex   = regexp.MustCompile(`\d{1,4}[-/.]\d{1,2}([-/.]\d{1,4})?`)
+	seriesNumberRegex = regexp.MustCompile(`#?\d+`)
+	seriesPunctRegex  = regexp.MustCompile(`[^\p{L}\s]+`)
+)
+
+// seriesKey normalizes a meeting title so occurrences of a recurring meeting share a key
+// (e.g. "Weekly Sync #12 - 2024/03/01" and "weekly sync" both become "weekly sync")
+func seriesKey(title string) string {
+	key := strings.ToLower(title)
+	key = seriesDateRegex.ReplaceAllString(key, " ")
+	key = seriesNumberRegex.ReplaceAllString(key, " ")
+	key = seriesPunctRegex.ReplaceAllString(key, " ")
+	return strings.Join(strings.Fields(key), " ")
+}
+
+// sameSeries reports whether two meetings look like occurrences of the same recurring meeting:
+// same normalized title and at least one participant in common (when both have named speakers)
+func sameSeries(a, b *Meeting) bool {
+	keyA := seriesKey(a.Title)
+	if keyA == "" || keyA != seriesKey(b.Title) {
+		return false
+	}
+
+	pa, pb := meetingParticipants(a), meetingParticipants(b)
+	if len(pa) == 0 || len(pb) == 0 {
+		return true
+	}
+	for _, p := range pa {
+		if contains(pb, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPreviousOccurrence returns the most recent earlier meeting in the same series that has a summary
+func findPreviousOccurrence(m *Meeting, candidates []*Meeting, cache *Cache) *Meeting {
+	var previous *Meeting
+	for _, c := range candidates {
+		if c.ID == m.ID || !c.CreatedAt.Before(m.CreatedAt) || !sameSeries(m, c) {
+			continue
+		}
+		if !cache.SummaryExists(c.ID) {
+			continue
+		}
+		if previous == nil || c.CreatedAt.After(previous.CreatedAt) {
+			previous = c
+		}
+	}
+	return previous
+}
+
+// seriesDiffEnabled reports whether "what changed since last time" sections are generated (SERIES_DIFF, default on)
+func seriesDiffEnabled() bool {
+	return envBoolDefault("SERIES_DIFF", true)
+}
+
+// generateSeriesDiffs adds a "what changed since last time" section to newly summarized
+// meetings that belong to a recurring series. Runs oldest first so a series summarized in
+// one batch diffs each occurrence against the one before it.
+func generateSeriesDiffs(ctx context.Context, meetingIDs []string, cache *Cache) {
+	if !seriesDiffEnabled() || len(meetingIDs) == 0 {
+		return
+	}
+
+	allIDs, err := cache.MeetingIDs()
+	if err != nil {
+		fmt.Printf("⚠ Error listing cached meetings for series diffs: %v\n", err)
+		return
+	}
+	var candidates []*Meeting
+	for _, id := range allIDs {
+		if m, err := cache.LoadMeeting(id); err == nil {
+			candidates = append(candidates, m)
+		}
+	}
+
+	var meetings []*Meeting
+	for _, id := range meetingIDs {
+		if m, err := cache.LoadMeeting(id); err == nil {
+			meetings = append(meetings, m)
+		}
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].CreatedAt.Before(meetings[j].CreatedAt)
+	})
+
+	for _, m := range meetings {
+		if ctx.Err() != nil || budgetStop("series diffs") {
+			return
+		}
+
+		previous := findPreviousOccurrence(m, candidates, cache)
+		if previous == nil {
+			continue
+		}
+
+		current, err := cache.LoadSummary(m.ID)
+		if err != nil {
+			continue
+		}
+		prior, err := cache.LoadSummary(previous.ID)
+		if err != nil {
+			continue
+		}
+
+		fmt.Printf("🔁 Comparing %s with previous occurrence %s\n", m.ID, previous.CreatedAt.Local().Format("2006-01-02"))
+		changes, err := compareWithPrevious(ctx, m, previous, current, prior)
+		if err != nil {
+			fmt.Printf("  ⚠ Error generating series diff: %v\n", err)
+			continue
+		}
+
+		current.PreviousMeetingID = previous.ID
+		current.SinceLastTime = changes
+		if err := cache.SaveSummary(m.ID, current); err != nil {
+			fmt.Printf("  ⚠ Error saving series diff: %v\n", err)
+			continue
+		}
+		fmt.Printf("  ✓ %d change(s) since last time\n", len(changes))
+	}
+}
+
+// compareWithPrevious asks the LLM what changed between two occurrences of a meeting
+func compareWithPrevious(ctx context.Context, m, previous *Meeting, current, prior *SummaryData) ([]string, error) {
+	tmpl, err := template.New("series-diff").Parse(seriesDiffPromptTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse series diff template: %w", err)
+	}
+
+	var promptBuf bytes.Buffer
+	if err := tmpl.Execute(&promptBuf, map[string]string{
+		"Title":        m.Title,
+		"PreviousDate": previous.CreatedAt.Local().Format("2006-01-02"),
+		"Previous":     prior.Description + "\n\n" + prior.Summary,
+		"CurrentDate":  m.CreatedAt.Local().Format("2006-01-02"),
+		"Current":      current.Description + "\n\n" + current.Summary,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to execute series diff template: %w", err)
+	}
+
+	schema := &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"changes": {
+				Type:        genai.TypeArray,
+				Description: "What changed since the previous meeting, one short sentence each",
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+		},
+		Required: []string{"changes"},
+	}
+
+	response, err := generateStructured(ctx, promptBuf.String(), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Changes []string `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(repairJSON(response)), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse series diff: %w", err)
+	}
+	return data.Changes, nil
+}
+
+// renderSinceLastTime renders the "What Changed Since Last Time" section, or "" if there is nothing to show
+func renderSinceLastTime(summaryData *SummaryData, cache *Cache) string {
+	if summaryData == nil || len(summaryData.SinceLastTime) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## What Changed Since Last Time\n")
+	if previous, err := cache.LoadMeeting(summaryData.PreviousMeetingID); err == nil {
+		sb.WriteString(fmt.Sprintf("_Compared with [[%s-summary|%s]]_\n\n", previous.ID, previous.CreatedAt.Local().Format("2006-01-02")))
+	}
+	for _, change := range summaryData.SinceLastTime {
+		sb.WriteString(fmt.Sprintf("- %s\n", change))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
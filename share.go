@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed share-template.md
+var shareNoteTemplate string
+
+// shareSectionKeys are the summary sections a shareable note keeps. The transcript link,
+// quotes, agenda, chat, omissions and user sections stay in the private note.
+var shareSectionKeys = map[string]bool{
+	"description":   true,
+	"topics":        true,
+	"topic-details": true,
+	"decisions":     true,
+	"action-items":  true,
+}
+
+// defaultShareExcludeTags are the tags left out of shareable notes unless
+// SHARE_EXCLUDE_TAGS says otherwise
+const defaultShareExcludeTags = "internal,private,confidential"
+
+// shareDir returns where shareable notes are exported (SHARE_DIR, resolved like other
+// paths), or "" when sharing isn't set up
+func shareDir(obsidianVaultPath string) (string, error) {
+	configured := strings.TrimSpace(os.Getenv("SHARE_DIR"))
+	if configured == "" {
+		return "", nil
+	}
+	return resolvePath(configured, obsidianVaultPath)
+}
+
+// shareSections keeps the shareable sections, in the configured order
+func shareSections(sections []summarySection) []summarySection {
+	var shared []summarySection
+	for _, s := range sections {
+		if shareSectionKeys[s.Key] {
+			shared = append(shared, s)
+		}
+	}
+	return shared
+}
+
+// shareTags drops the tags in SHARE_EXCLUDE_TAGS (default internal, private and
+// confidential) from a meeting's tags, along with tags nested under them
+func shareTags(tags []string) []string {
+	excludeList := defaultShareExcludeTags
+	if v, ok := os.LookupEnv("SHARE_EXCLUDE_TAGS"); ok {
+		excludeList = v
+	}
+	excluded := splitTags(strings.ToLower(excludeList))
+
+	var shared []string
+	for _, tag := range tags {
+		lower := strings.ToLower(tag)
+		keep := true
+		for _, ex := range excluded {
+			if lower == ex || strings.HasPrefix(lower, ex+"/") {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			shared = append(shared, tag)
+		}
+	}
+	return shared
+}
+
+// shareNoteName returns the file name of a meeting's shareable note, e.g.
+// "2024-03-05 1400 Roadmap review (abc123).md". The meeting ID keeps two meetings with the
+// same title and start apart.
+func shareNoteName(m *Meeting) string {
+	return fmt.Sprintf("%s %s (%s).md", m.CreatedAt.Local().Format("2006-01-02 1504"), sanitizeNoteName(m.Title), m.ID)
+}
+
+// renderShareNote renders the shareable version of a meeting's note: the summary without
+// the transcript, quotes, internal tags or participants' emails
+func renderShareNote(m *Meeting, summaryData *SummaryData, tags []string, sections []summarySection) ([]byte, error) {
+	tmpl, err := template.New("share").Parse(shareNoteTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing share template: %w", err)
+	}
+
+	// Names the summary couldn't verify are for the private note's reviewer
+	summary := *summaryData
+	summary.UnknownNames = nil
+
+	var participants []string
+	for _, p := range meetingParticipants(m) {
+		participants = append(participants, string(redactEmails([]byte(p), m)))
+	}
+
+	templateData := map[string]interface{}{
+		"Date":         m.CreatedAt.Local().Format("2006-01-02"),
+		"Time":         m.CreatedAt.Local().Format("15:04"),
+		"Title":        m.Title,
+		"Description":  summary.Description,
+		"Tags":         shareTags(tags),
+		"Participants": participants,
+
+		"DescriptionBlock": renderDescriptionBlock(summary.Description),
+		"Topics":           renderTopics(&summary),
+		"TopicDetails":     renderTopicDetails(&summary),
+		"Decisions":        renderDecisions(&summary),
+		"ActionItems":      renderActionItems(&summary),
+	}
+	templateData["Sections"] = renderSections(shareSections(sections), templateData)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return nil, fmt.Errorf("error rendering share template: %w", err)
+	}
+	return redactEmails(buf.Bytes(), m), nil
+}
+
+// exportShareNote writes a meeting's shareable note to dir, reporting whether it changed
+func exportShareNote(dir string, m *Meeting, summaryData *SummaryData, tags []string, sections []summarySection) (string, bool, error) {
+	content, err := renderShareNote(m, summaryData, tags, sections)
+	if err != nil {
+		return "", false, err
+	}
+	path := filepath.Join(dir, shareNoteName(m))
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return path, false, nil
+	}
+	if dryRun {
+		return path, true, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("failed to create share directory: %w", err)
+	}
+	if err := writeFileAtomic(path, content); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// runShare exports shareable notes of the given meetings, or of every synced meeting, to
+// SHARE_DIR (default: <data-dir>/share). Confidential and unsummarized meetings are skipped.
+func runShare(obsidianVaultPath string, syncState *SyncState, cache *Cache, meetingIDs []string) error {
+	fmt.Println("\n=== Share: Exporting shareable meeting notes ===")
+
+	dir, err := shareDir(obsidianVaultPath)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		dir = filepath.Join(dataDir, "share")
+	}
+	sections, err := summarySectionsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	ids := meetingIDs
+	if len(ids) == 0 {
+		for id := range syncState.ObsidianSyncedMeetings {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	}
+
+	retiredTags, err := loadRetiredTags()
+	if err != nil {
+		return err
+	}
+
+	exported, confidential := 0, 0
+	for _, id := range ids {
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			fmt.Printf("⚠ Error loading meeting %s: %v\n", id, err)
+			continue
+		}
+		if !cache.SummaryExists(id) {
+			fmt.Printf("⏭  Not summarized yet, skipping: %s\n", m.Title)
+			continue
+		}
+		summaryData, err := cache.LoadSummary(id)
+		if err != nil {
+			fmt.Printf("⚠ Error loading summary for %s: %v\n", id, err)
+			continue
+		}
+		if isConfidential(m, summaryData) {
+			confidential++
+			continue
+		}
+
+		// The vault note's tags include normalized and hand-added ones
+		tags := existingNoteTags(summaryNotePath(obsidianVaultPath, m))
+		if len(tags) == 0 {
+			tags = dropRetiredTags(splitTags(summaryData.Tags), retiredTags)
+		}
+		path, changed, err := exportShareNote(dir, m, summaryData, tags, sections)
+		if err != nil {
+			fmt.Printf("⚠ Error exporting %s: %v\n", m.Title, err)
+			continue
+		}
+		if changed {
+			exported++
+			if dryRun {
+				fmt.Printf("📤 Would export: %s\n", filepath.Base(path))
+			} else {
+				fmt.Printf("📤 Exported: %s\n", filepath.Base(path))
+			}
+		}
+	}
+
+	if confidential > 0 {
+		fmt.Printf("🔒 Left out %d confidential meeting(s)\n", confidential)
+	}
+	fmt.Printf("✅ %d shareable note(s) exported to %s\n", exported, dir)
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Speaker label fallbacks (SPEAKER_FALLBACK): how a speaker Krisp has no name for is
+// labelled instead of "Unknown Speaker A"
+const (
+	speakerFallbackEmail    = "email"    // the local part of the speaker's email
+	speakerFallbackInitials = "initials" // initials of the one attendee not matched to a named speaker
+	speakerFallbackLLM      = "llm"      // name inferred by the summary LLM from self-introductions
+)
+
+// InferredSpeaker is a name the LLM found for an unnamed speaker in the transcript
+type InferredSpeaker struct {
+	Speaker string `json:"speaker"` // label in the transcript, e.g. "Unknown Speaker A"
+	Name    string `json:"name"`
+}
+
+// speakerFallbackList is SPEAKER_FALLBACK, read once
+var (
+	speakerFallbackOnce sync.Once
+	speakerFallbackList []string
+)
+
+// speakerFallbacks returns the SPEAKER_FALLBACK methods, in the order they are tried
+func speakerFallbacks() []string {
+	speakerFallbackOnce.Do(func() {
+		for _, f := range strings.Split(os.Getenv("SPEAKER_FALLBACK"), ",") {
+			switch f = strings.ToLower(strings.TrimSpace(f)); f {
+			case "":
+			case speakerFallbackEmail, speakerFallbackInitials, speakerFallbackLLM:
+				speakerFallbackList = append(speakerFallbackList, f)
+			default:
+				fmt.Printf("⚠ Ignoring unknown SPEAKER_FALLBACK %q\n", f)
+			}
+		}
+	})
+	return speakerFallbackList
+}
+
+// speakerFallbackEnabled reports whether a fallback method is configured
+func speakerFallbackEnabled(method string) bool {
+	return contains(speakerFallbacks(), method)
+}
+
+// fallbackSpeakerName labels a speaker Krisp has no name for with the first configured
+// fallback that finds one, or "" if none does. inferred holds the names the summary's LLM
+// inferred, by speaker index.
+func fallbackSpeakerName(meeting *Meeting, speakerIndex int, inferred map[string]string) string {
+	for _, method := range speakerFallbacks() {
+		var name string
+		switch method {
+		case speakerFallbackEmail:
+			if speakerInfo, ok := meeting.Speakers.Data[strconv.Itoa(speakerIndex)]; ok {
+				name = emailLocalPart(speakerInfo.Person.Email)
+			}
+		case speakerFallbackInitials:
+			name = attendeeInitials(meeting, speakerIndex)
+		case speakerFallbackLLM:
+			name = inferred[strconv.Itoa(speakerIndex)]
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// emailLocalPart returns the part of an email before the "@"
+func emailLocalPart(email string) string {
+	local, _, ok := strings.Cut(strings.TrimSpace(email), "@")
+	if !ok {
+		return ""
+	}
+	return local
+}
+
+// initials returns the uppercase initials of a name ("Jane van Doe" -> "JVD"), or of an
+// email's local part split on dots, dashes and underscores
+func initials(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.' || r == '-' || r == '_'
+	}) {
+		sb.WriteRune(unicode.ToUpper([]rune(part)[0]))
+	}
+	return sb.String()
+}
+
+// attendeeInitials returns the initials of the attendee an unnamed speaker must be: only
+// when the meeting has a single unnamed speaker and a single attendee who isn't one of
+// the named speakers
+func attendeeInitials(meeting *Meeting, speakerIndex int) string {
+	if _, unnamed := unknownSpeakerOrder(meeting)[speakerIndex]; !unnamed || len(unknownSpeakerOrder(meeting)) != 1 {
+		return ""
+	}
+
+	spoke := make(map[string]bool)
+	for _, speakerInfo := range meeting.Speakers.Data {
+		if email := strings.ToLower(speakerInfo.Person.Email); email != "" {
+			spoke[email] = true
+		}
+		if name := strings.ToLower(strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)); name != "" {
+			spoke[name] = true
+		}
+	}
+	var unmatched []Speaker
+	for _, p := range meeting.Participants {
+		name := strings.ToLower(strings.TrimSpace(p.FirstName + " " + p.LastName))
+		if spoke[strings.ToLower(p.Email)] || (name != "" && spoke[name]) {
+			continue
+		}
+		unmatched = append(unmatched, p)
+	}
+	if len(unmatched) != 1 {
+		return ""
+	}
+	if name := strings.TrimSpace(unmatched[0].FirstName + " " + unmatched[0].LastName); name != "" {
+		return initials(name)
+	}
+	return initials(emailLocalPart(unmatched[0].Email))
+}
+
+// resolveInferredSpeakers maps the names the LLM found for "Unknown Speaker X" labels to
+// the speakers' indexes, as stored with the summary
+func resolveInferredSpeakers(meeting *Meeting, inferred []InferredSpeaker) map[string]string {
+	byLabel := make(map[string]int)
+	for index, n := range unknownSpeakerOrder(meeting) {
+		byLabel[unknownSpeakerLabel(n)] = index
+	}
+
+	names := make(map[string]string)
+	for _, s := range inferred {
+		name := strings.TrimSpace(s.Name)
+		index, ok := byLabel[strings.TrimSpace(s.Speaker)]
+		if !ok || name == "" || strings.HasPrefix(name, "Unknown Speaker") {
+			continue
+		}
+		names[strconv.Itoa(index)] = name
+	}
+	return names
+}
+
+// inferredSpeakers returns the speaker names inferred for a meeting's summary, by speaker
+// index, for speaker labels
+func inferredSpeakers(summaryData *SummaryData) map[string]string {
+	if summaryData == nil {
+		return nil
+	}
+	return summaryData.InferredSpeakers
+}
+
+// carryInferredSpeakers keeps the names inferred for a meeting's speakers by an earlier
+// summary when re-summarizing: once known they are shown in the transcript, so the LLM
+// can't find them again
+func carryInferredSpeakers(previous, summaryData *SummaryData) {
+	for index, name := range previous.InferredSpeakers {
+		if _, ok := summaryData.InferredSpeakers[index]; ok {
+			continue
+		}
+		if summaryData.InferredSpeakers == nil {
+			summaryData.InferredSpeakers = make(map[string]string)
+		}
+		summaryData.InferredSpeakers[index] = name
+	}
+}
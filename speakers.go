@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// unknownSpeakerOrder memoizes, per meeting, the order in which unnamed speakers first speak
+var (
+	unknownSpeakerMu    sync.Mutex
+	unknownSpeakerCache = make(map[string]map[int]int)
+)
+
+// unknownSpeakerLabel returns "Unknown Speaker A", "Unknown Speaker B", ... for the nth unnamed speaker
+func unknownSpeakerLabel(n int) string {
+	if n < 26 {
+		return fmt.Sprintf("Unknown Speaker %c", 'A'+n)
+	}
+	return fmt.Sprintf("Unknown Speaker %d", n+1)
+}
+
+// namedSpeaker returns the name for a speaker index, if any: the people registry's
+// preferred name for their email, or the name Krisp has
+func namedSpeaker(meeting *Meeting, speakerIndex int) string {
+	if speakerInfo, ok := meeting.Speakers.Data[fmt.Sprintf("%d", speakerIndex)]; ok {
+		name := strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
+		return preferredName(speakerInfo.Person.Email, name)
+	}
+	return ""
+}
+
+// unknownSpeakerOrder numbers a meeting's unnamed speakers by first appearance in the transcript
+func unknownSpeakerOrder(meeting *Meeting) map[int]int {
+	unknownSpeakerMu.Lock()
+	defer unknownSpeakerMu.Unlock()
+
+	if order, ok := unknownSpeakerCache[meeting.ID]; ok {
+		return order
+	}
+
+	order := make(map[int]int)
+	var segments []Segment
+	if err := json.Unmarshal([]byte(meeting.Resources.Transcript.Content), &segments); err == nil {
+		for _, seg := range segments {
+			if _, seen := order[seg.SpeakerIndex]; seen || namedSpeaker(meeting, seg.SpeakerIndex) != "" {
+				continue
+			}
+			order[seg.SpeakerIndex] = len(order)
+		}
+	}
+	unknownSpeakerCache[meeting.ID] = order
+	return order
+}
+
+// speakerDisplayName returns the name for a speaker index, then a SPEAKER_FALLBACK label,
+// or "Unknown Speaker A/B/..." (in order of first appearance) when Krisp has no name for them
+func speakerDisplayName(meeting *Meeting, speakerIndex int, inferred map[string]string) string {
+	if name := namedSpeaker(meeting, speakerIndex); name != "" {
+		return name
+	}
+	if name := fallbackSpeakerName(meeting, speakerIndex, inferred); name != "" {
+		return name
+	}
+	if n, ok := unknownSpeakerOrder(meeting)[speakerIndex]; ok {
+		return unknownSpeakerLabel(n)
+	}
+	return fmt.Sprintf("Speaker %d", speakerIndex)
+}
+
+// meetingParticipants returns the people in a meeting: named speakers, falling back to
+// Krisp's participant list when the transcript has no speaker names. Registered people
+// appear under their preferred name.
+func meetingParticipants(m *Meeting) []string {
+	var participants []string
+	for _, speakerInfo := range m.Speakers.Data {
+		name := strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
+		name = preferredName(speakerInfo.Person.Email, name)
+		if name != "" {
+			participants = append(participants, name)
+		}
+	}
+
+	if len(participants) == 0 {
+		for _, p := range m.Participants {
+			name := preferredName(p.Email, strings.TrimSpace(p.FirstName+" "+p.LastName))
+			if name == "" {
+				name = p.Email
+			}
+			if name != "" {
+				participants = append(participants, name)
+			}
+		}
+	}
+
+	participants = uniqueStrings(participants)
+	sort.Strings(participants)
+	return participants
+}
+
+// estimatedParticipants labels the speakers of a meeting nobody could be named for,
+// using the speaker count estimated by the LLM when the transcript isn't diarized
+func estimatedParticipants(m *Meeting, summaryData *SummaryData) []string {
+	count := len(unknownSpeakerOrder(m))
+	if summaryData != nil && summaryData.EstimatedSpeakers > count {
+		count = summaryData.EstimatedSpeakers
+	}
+
+	labels := make([]string, count)
+	for i := range labels {
+		labels[i] = unknownSpeakerLabel(i)
+	}
+	return labels
+}
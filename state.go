@@ -1,21 +1,96 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 )
 
+// Default debounce settings for batched state saves
+const (
+	defaultSaveEvery    = 25
+	defaultSaveInterval = 30 * time.Second
+)
+
+// Journal stages recorded for crash protection between batched saves
+const (
+	journalDownloaded       = "downloaded"
+	journalSummarized       = "summarized"
+	journalObsidianSynced   = "obsidian_synced"
+	journalObsidianUnsynced = "obsidian_unsynced"
+	journalReviewed         = "reviewed"
+	journalForgotten        = "forgotten"
+)
+
+// journalEntry is one line of the append-only crash journal
+type journalEntry struct {
+	Stage string `json:"stage"`
+	ID    string `json:"id"`
+}
+
 // Sync state to track last sync
 type SyncState struct {
-	LastSyncTime           time.Time       `json:"last_sync_time"`
-	SyncedMeetings         map[string]bool `json:"synced_meetings"`          // meeting ID -> downloaded from Krisp
-	SummarizedMeetings     map[string]bool `json:"summarized_meetings"`      // meeting ID -> summarized with Gemini
-	ObsidianSyncedMeetings map[string]bool `json:"obsidian_synced_meetings"` // meeting ID -> synced to Obsidian vault
+	LastSyncTime           time.Time                      `json:"last_sync_time"`
+	SyncedMeetings         map[string]bool                `json:"synced_meetings"`                // meeting ID -> downloaded from Krisp
+	SummarizedMeetings     map[string]bool                `json:"summarized_meetings"`            // meeting ID -> summarized with Gemini
+	ObsidianSyncedMeetings map[string]bool                `json:"obsidian_synced_meetings"`       // meeting ID -> synced to Obsidian vault
+	ReviewedMeetings       map[string]bool                `json:"reviewed_meetings,omitempty"`    // meeting ID -> checked off in the inbox note
+	ListCursor             *ListCursor                    `json:"list_cursor,omitempty"`          // resume point for the Krisp meetings listing
+	AccountCursors         map[string]*ListCursor         `json:"account_cursors,omitempty"`      // per-account resume points (KRISP_ACCOUNTS)
+	ListingFingerprints    map[string]*ListingFingerprint `json:"listing_fingerprints,omitempty"` // account -> last complete listing, to skip unchanged ones
+
+	TranscriptQueue      map[string]*QueuedTranscript    `json:"transcript_queue,omitempty"`      // meeting ID -> transcript not ready yet
+	TruncatedTranscripts map[string]*TruncatedTranscript `json:"truncated_transcripts,omitempty"` // meeting ID -> transcript ends well before the meeting
+	AudioOnlyNotes       map[string]string               `json:"audio_only_notes,omitempty"`      // meeting ID -> transcript status its note was written with
+	TagLastUsed          map[string]time.Time            `json:"tag_last_used,omitempty"`         // tag -> latest meeting it was synced with
+	FailedMeetings       map[string]*MeetingFailure      `json:"failed_meetings,omitempty"`       // meeting ID -> last failure, for retry-failed
+	Backfill             *BackfillState                  `json:"backfill,omitempty"`              // daily quota usage of the backfill step
 
 	// Internal field to remember the file path (not serialized to JSON)
 	path string `json:"-"`
+
+	// mu guards changes recorded by parallel workers and the saves they trigger
+	mu sync.Mutex
+
+	// Debounced saving: save after SaveEvery changes or SaveInterval, whichever comes first
+	SaveEvery    int           `json:"-"`
+	SaveInterval time.Duration `json:"-"`
+	pending      int
+	lastSave     time.Time
+	journal      *os.File
+
+	// Vault state store (see statestore.go); dir is empty for the single-file store
+	dir       string
+	machine   string
+	persisted map[string]meetingStateFile
+	conflicts []string
+}
+
+// ListCursor remembers the last fully listed (and fully downloaded) page of the
+// Krisp meetings listing so later runs only list newer meetings
+type ListCursor struct {
+	Page      int       `json:"page"`       // last full page whose meetings are all cached
+	CreatedAt time.Time `json:"created_at"` // created_at of the last meeting on that page
+}
+
+// advanceListCursor moves the cursor past every full page whose meetings are all cached.
+// Stops at the first page with an uncached meeting so limited runs don't skip anything.
+func advanceListCursor(cursor *ListCursor, pages []MeetingsPage, cache *Cache) *ListCursor {
+	for _, p := range pages {
+		if !p.Full() {
+			break
+		}
+		for _, m := range p.Rows {
+			if !cache.MeetingExists(m.ID) {
+				return cursor
+			}
+		}
+		cursor = &ListCursor{Page: p.Page, CreatedAt: p.Rows[len(p.Rows)-1].CreatedAt}
+	}
+	return cursor
 }
 
 func loadSyncState(path string) *SyncState {
@@ -24,6 +99,7 @@ func loadSyncState(path string) *SyncState {
 		SummarizedMeetings:     make(map[string]bool),
 		ObsidianSyncedMeetings: make(map[string]bool),
 		path:                   path,
+		lastSave:               time.Now(),
 	}
 
 	// Check for orphaned temp file from crashed save
@@ -55,45 +131,245 @@ func loadSyncState(path string) *SyncState {
 			SummarizedMeetings:     make(map[string]bool),
 			ObsidianSyncedMeetings: make(map[string]bool),
 			path:                   path,
+			lastSave:               time.Now(),
 		}
 	}
 
-	// Ensure maps are initialized (for backwards compatibility)
-	if state.SyncedMeetings == nil {
-		state.SyncedMeetings = make(map[string]bool)
-	}
-	if state.SummarizedMeetings == nil {
-		state.SummarizedMeetings = make(map[string]bool)
-	}
-	if state.ObsidianSyncedMeetings == nil {
-		state.ObsidianSyncedMeetings = make(map[string]bool)
-	}
+	// A file without the tracking maps (or with null ones) would leave them nil; early
+	// versions' files are also upgraded on startup (see migrations.go)
+	state.initMaps()
 
 	// Remember the path
 	state.path = path
 
+	state.replayJournal()
+
 	return state
 }
 
-// Save saves the sync state to disk atomically
-func (s *SyncState) Save() error {
-	data, err := json.MarshalIndent(s, "", "  ")
+// initMaps makes the tracking maps a loaded state file left nil
+func (s *SyncState) initMaps() {
+	if s.SyncedMeetings == nil {
+		s.SyncedMeetings = make(map[string]bool)
+	}
+	if s.SummarizedMeetings == nil {
+		s.SummarizedMeetings = make(map[string]bool)
+	}
+	if s.ObsidianSyncedMeetings == nil {
+		s.ObsidianSyncedMeetings = make(map[string]bool)
+	}
+}
+
+// journalPath returns the path of the crash journal for this state file
+func (s *SyncState) journalPath() string {
+	return s.path + ".journal"
+}
+
+// replayJournal applies changes recorded since the last full save (e.g. before a crash)
+func (s *SyncState) replayJournal() {
+	f, err := os.Open(s.journalPath())
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	replayed := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A torn final line from a crash mid-write - ignore it
+			continue
+		}
+		s.apply(entry)
+		replayed++
+	}
+
+	if replayed > 0 {
+		fmt.Printf("⚠ Recovered %d state change(s) from journal: %s\n", replayed, s.journalPath())
+		s.pending = replayed
+	}
+}
+
+// apply records a journal entry in the in-memory state
+func (s *SyncState) apply(entry journalEntry) {
+	switch entry.Stage {
+	case journalDownloaded:
+		s.SyncedMeetings[entry.ID] = true
+	case journalSummarized:
+		s.SummarizedMeetings[entry.ID] = true
+	case journalObsidianSynced:
+		s.ObsidianSyncedMeetings[entry.ID] = true
+	case journalObsidianUnsynced:
+		delete(s.ObsidianSyncedMeetings, entry.ID)
+	case journalReviewed:
+		if s.ReviewedMeetings == nil {
+			s.ReviewedMeetings = make(map[string]bool)
+		}
+		s.ReviewedMeetings[entry.ID] = true
+	case journalForgotten:
+		delete(s.SyncedMeetings, entry.ID)
+		delete(s.SummarizedMeetings, entry.ID)
+		delete(s.ObsidianSyncedMeetings, entry.ID)
+		delete(s.AudioOnlyNotes, entry.ID)
+		delete(s.FailedMeetings, entry.ID)
+	}
+}
+
+// MarkDownloaded records that a meeting was downloaded from Krisp
+func (s *SyncState) MarkDownloaded(meetingID string) {
+	s.record(journalEntry{Stage: journalDownloaded, ID: meetingID}, stageDownload)
+}
+
+// MarkSummarized records that a meeting was summarized
+func (s *SyncState) MarkSummarized(meetingID string) {
+	s.record(journalEntry{Stage: journalSummarized, ID: meetingID}, stageSummarize)
+}
+
+// MarkObsidianSynced records that a meeting was synced to the Obsidian vault
+func (s *SyncState) MarkObsidianSynced(meetingID string) {
+	s.record(journalEntry{Stage: journalObsidianSynced, ID: meetingID}, stageSync)
+}
+
+// UnmarkObsidianSynced records that a meeting's notes have to be synced again
+func (s *SyncState) UnmarkObsidianSynced(meetingID string) {
+	s.record(journalEntry{Stage: journalObsidianUnsynced, ID: meetingID}, "")
+}
+
+// MarkReviewed records that a meeting was checked off in the inbox note
+func (s *SyncState) MarkReviewed(meetingID string) {
+	s.record(journalEntry{Stage: journalReviewed, ID: meetingID}, "")
+}
+
+// Forget records that a meeting no longer exists on its own (it was merged into another),
+// dropping it from every stage, its audio-only note and its failures
+func (s *SyncState) Forget(meetingID string) {
+	s.record(journalEntry{Stage: journalForgotten, ID: meetingID}, "")
+}
+
+// record applies a change, clears the meeting's failure in the stage it completes (if
+// any), appends it to the crash journal and saves if the debounce threshold is reached.
+// Safe to call from parallel workers.
+func (s *SyncState) record(entry journalEntry, stage string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stage != "" {
+		s.clearFailure(entry.ID, stage)
+	}
+	s.apply(entry)
+
+	if err := s.appendJournal(entry); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not write state journal: %v\n", err)
+	}
+
+	s.pending++
+	if err := s.checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+}
+
+// appendJournal appends an entry to the crash journal
+func (s *SyncState) appendJournal(entry journalEntry) error {
+	if s.path == "" || dryRun {
+		return nil
+	}
+	if s.journal == nil {
+		f, err := os.OpenFile(s.journalPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		s.journal = f
+	}
+
+	line, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
+	_, err = s.journal.Write(append(line, '\n'))
+	return err
+}
+
+// Checkpoint saves the state if enough changes have accumulated or enough time has passed
+func (s *SyncState) Checkpoint() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoint()
+}
+
+func (s *SyncState) checkpoint() error {
+	if s.pending == 0 {
+		return nil
+	}
+
+	every := s.SaveEvery
+	if every <= 0 {
+		every = defaultSaveEvery
+	}
+	interval := s.SaveInterval
+	if interval <= 0 {
+		interval = defaultSaveInterval
+	}
 
-	// Atomic write: write to temp file, then rename
-	tempPath := s.path + ".new"
+	if s.pending < every && time.Since(s.lastSave) < interval {
+		return nil
+	}
+	return s.save()
+}
 
-	// Write to temporary file
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
+// Flush saves any pending changes and closes the journal. Call before exiting.
+func (s *SyncState) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending > 0 {
+		if err := s.save(); err != nil {
+			return err
+		}
 	}
+	if s.journal != nil {
+		s.journal.Close()
+		s.journal = nil
+	}
+	return nil
+}
 
-	// Rename temp file to actual file (atomic on POSIX filesystems)
-	if err := os.Rename(tempPath, s.path); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+// Save saves the sync state to disk atomically (not in a dry run)
+func (s *SyncState) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save()
+}
+
+func (s *SyncState) save() error {
+	if dryRun {
+		return nil
+	}
+	if s.dir != "" {
+		if err := s.saveDir(); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		// Atomic write: write to temp file, then rename
+		if err := writeFileAtomic(s.path, data); err != nil {
+			return err
+		}
+	}
+
+	// Everything in the journal is now part of the saved state
+	if s.journal != nil {
+		s.journal.Close()
+		s.journal = nil
+	}
+	if err := os.Remove(s.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear state journal: %w", err)
 	}
 
+	s.pending = 0
+	s.lastSave = time.Now()
 	return nil
 }
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// State stores: "file" keeps everything in one JSON file (default); "vault" keeps
+// merge-friendly per-meeting files in the vault so several machines syncing the same
+// vault (e.g. via iCloud) share one state
+const (
+	stateStoreFile  = "file"
+	stateStoreVault = "vault"
+
+	defaultVaultStateDir = "vault:.krisp-sync/state"
+)
+
+// meetingStateFile is the per-meeting state file in a vault state store
+type meetingStateFile struct {
+	ID             string    `json:"id"`
+	Downloaded     bool      `json:"downloaded"`
+	Summarized     bool      `json:"summarized"`
+	ObsidianSynced bool      `json:"obsidian_synced"`
+	Reviewed       bool      `json:"reviewed,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Machine        string    `json:"machine"`
+}
+
+// sameFlags reports whether two meeting states record the same progress
+func (m meetingStateFile) sameFlags(other meetingStateFile) bool {
+	return m.Downloaded == other.Downloaded && m.Summarized == other.Summarized && m.ObsidianSynced == other.ObsidianSynced && m.Reviewed == other.Reviewed
+}
+
+// machineStateFile holds the state that is specific to one machine
+type machineStateFile struct {
+	LastSyncTime         time.Time                       `json:"last_sync_time"`
+	ListCursor           *ListCursor                     `json:"list_cursor,omitempty"`
+	AccountCursors       map[string]*ListCursor          `json:"account_cursors,omitempty"`
+	ListingFingerprints  map[string]*ListingFingerprint  `json:"listing_fingerprints,omitempty"`
+	TranscriptQueue      map[string]*QueuedTranscript    `json:"transcript_queue,omitempty"`
+	TruncatedTranscripts map[string]*TruncatedTranscript `json:"truncated_transcripts,omitempty"`
+	AudioOnlyNotes       map[string]string               `json:"audio_only_notes,omitempty"`
+	TagLastUsed          map[string]time.Time            `json:"tag_last_used,omitempty"`
+	FailedMeetings       map[string]*MeetingFailure      `json:"failed_meetings,omitempty"`
+	Backfill             *BackfillState                  `json:"backfill,omitempty"`
+}
+
+var machineNameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// machineName identifies this machine in a shared state store (KRISP_SYNC_MACHINE or the hostname)
+func machineName() string {
+	name := os.Getenv("KRISP_SYNC_MACHINE")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	name = machineNameRegex.ReplaceAllString(name, "-")
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// writeFileAtomic writes data to path via a temp file and rename
+func writeFileAtomic(path string, data []byte) error {
+	tempPath := path + ".new"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// loadSyncStateDir loads state from a vault state store. Meeting files are merged by the
+// meeting ID inside them, so conflict copies made by file sync tools (e.g. "abc 2.json")
+// are folded in and cleaned up on the next save. An existing single-file state at
+// legacyPath is imported the first time the store is used.
+func loadSyncStateDir(dir, legacyPath string) *SyncState {
+	machine := machineName()
+	state := &SyncState{
+		SyncedMeetings:         make(map[string]bool),
+		SummarizedMeetings:     make(map[string]bool),
+		ObsidianSyncedMeetings: make(map[string]bool),
+		path:                   filepath.Join(dir, "machines", machine+".json"),
+		dir:                    dir,
+		machine:                machine,
+		persisted:              make(map[string]meetingStateFile),
+		lastSave:               time.Now(),
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "meetings", "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var m meetingStateFile
+		if err := json.Unmarshal(data, &m); err != nil || m.ID == "" {
+			fmt.Printf("⚠ Skipping unreadable state file: %s\n", file)
+			continue
+		}
+
+		if m.Downloaded {
+			state.SyncedMeetings[m.ID] = true
+		}
+		if m.Summarized {
+			state.SummarizedMeetings[m.ID] = true
+		}
+		if m.ObsidianSynced {
+			state.ObsidianSyncedMeetings[m.ID] = true
+		}
+		if m.Reviewed {
+			if state.ReviewedMeetings == nil {
+				state.ReviewedMeetings = make(map[string]bool)
+			}
+			state.ReviewedMeetings[m.ID] = true
+		}
+
+		if filepath.Base(file) == m.ID+".json" {
+			state.persisted[m.ID] = m
+		} else {
+			state.conflicts = append(state.conflicts, file)
+		}
+	}
+
+	if data, err := os.ReadFile(state.path); err == nil {
+		var ms machineStateFile
+		if err := json.Unmarshal(data, &ms); err != nil {
+			fmt.Printf("⚠ Warning: Could not parse machine state %s: %v\n", state.path, err)
+		} else {
+			state.LastSyncTime = ms.LastSyncTime
+			state.ListCursor = ms.ListCursor
+			state.AccountCursors = ms.AccountCursors
+			state.ListingFingerprints = ms.ListingFingerprints
+			state.TranscriptQueue = ms.TranscriptQueue
+			state.TruncatedTranscripts = ms.TruncatedTranscripts
+			state.AudioOnlyNotes = ms.AudioOnlyNotes
+			state.TagLastUsed = ms.TagLastUsed
+			state.FailedMeetings = ms.FailedMeetings
+			state.Backfill = ms.Backfill
+		}
+	} else if len(files) == 0 && fileExists(legacyPath) {
+		legacy := loadSyncState(legacyPath)
+		legacy.Flush()
+		state.LastSyncTime = legacy.LastSyncTime
+		state.SyncedMeetings = legacy.SyncedMeetings
+		state.SummarizedMeetings = legacy.SummarizedMeetings
+		state.ObsidianSyncedMeetings = legacy.ObsidianSyncedMeetings
+		state.ReviewedMeetings = legacy.ReviewedMeetings
+		state.ListCursor = legacy.ListCursor
+		state.AccountCursors = legacy.AccountCursors
+		state.ListingFingerprints = legacy.ListingFingerprints
+		state.TranscriptQueue = legacy.TranscriptQueue
+		state.TruncatedTranscripts = legacy.TruncatedTranscripts
+		state.AudioOnlyNotes = legacy.AudioOnlyNotes
+		state.TagLastUsed = legacy.TagLastUsed
+		state.FailedMeetings = legacy.FailedMeetings
+		state.Backfill = legacy.Backfill
+		state.pending = 1
+		state.initMaps()
+		fmt.Printf("📦 Importing state from %s into %s\n", legacyPath, dir)
+	}
+
+	state.replayJournal()
+	return state
+}
+
+// saveDir writes changed meeting files and this machine's state file
+func (s *SyncState) saveDir() error {
+	meetingsDir := filepath.Join(s.dir, "meetings")
+	if err := os.MkdirAll(meetingsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, set := range []map[string]bool{s.SyncedMeetings, s.SummarizedMeetings, s.ObsidianSyncedMeetings, s.ReviewedMeetings} {
+		for id := range set {
+			ids[id] = true
+		}
+	}
+	for id := range s.persisted {
+		ids[id] = true
+	}
+
+	now := time.Now()
+	for id := range ids {
+		current := meetingStateFile{
+			ID:             id,
+			Downloaded:     s.SyncedMeetings[id],
+			Summarized:     s.SummarizedMeetings[id],
+			ObsidianSynced: s.ObsidianSyncedMeetings[id],
+			Reviewed:       s.ReviewedMeetings[id],
+			UpdatedAt:      now,
+			Machine:        s.machine,
+		}
+		if previous, ok := s.persisted[id]; ok && previous.sameFlags(current) {
+			continue
+		}
+
+		path := filepath.Join(meetingsDir, id+".json")
+		if !current.Downloaded && !current.Summarized && !current.ObsidianSynced && !current.Reviewed {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			delete(s.persisted, id)
+			continue
+		}
+
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+		s.persisted[id] = current
+	}
+
+	// Conflict copies have been merged into the canonical files above
+	for _, file := range s.conflicts {
+		if strings.HasSuffix(file, ".json") {
+			os.Remove(file)
+		}
+	}
+	s.conflicts = nil
+
+	data, err := json.MarshalIndent(machineStateFile{
+		LastSyncTime:         s.LastSyncTime,
+		ListCursor:           s.ListCursor,
+		AccountCursors:       s.AccountCursors,
+		ListingFingerprints:  s.ListingFingerprints,
+		TranscriptQueue:      s.TranscriptQueue,
+		TruncatedTranscripts: s.TruncatedTranscripts,
+		AudioOnlyNotes:       s.AudioOnlyNotes,
+		TagLastUsed:          s.TagLastUsed,
+		FailedMeetings:       s.FailedMeetings,
+		Backfill:             s.Backfill,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TranscriptStats holds size and complexity metrics for a meeting transcript
+type TranscriptStats struct {
+	MeetingID       string         `json:"meeting_id"`
+	Title           string         `json:"title"`
+	CreatedAt       time.Time      `json:"created_at"`
+	DurationSeconds int            `json:"duration_seconds"`
+	Segments        int            `json:"segments"`
+	Words           int            `json:"words"`
+	Characters      int            `json:"characters"`
+	TokenEstimate   int            `json:"token_estimate"`
+	SpeakerCount    int            `json:"speaker_count"`
+	SpeakerWords    map[string]int `json:"speaker_words"` // speaker name -> words spoken
+}
+
+// estimateTokens approximates LLM tokens for a text (~4 characters per token)
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// computeTranscriptStats measures a rendered transcript
+func computeTranscriptStats(meeting *Meeting, segments []Segment, transcriptText string, inferred map[string]string) *TranscriptStats {
+	stats := &TranscriptStats{
+		MeetingID:       meeting.ID,
+		Title:           meeting.Title,
+		CreatedAt:       meeting.CreatedAt,
+		DurationSeconds: meeting.Duration,
+		Segments:        len(segments),
+		Characters:      len(transcriptText),
+		TokenEstimate:   estimateTokens(transcriptText),
+		SpeakerWords:    make(map[string]int),
+	}
+
+	var lastEnd float64
+	for _, seg := range segments {
+		words := len(strings.Fields(seg.Speech.Text))
+		stats.Words += words
+		stats.SpeakerWords[speakerDisplayName(meeting, seg.SpeakerIndex, inferred)] += words
+		if seg.Speech.End > lastEnd {
+			lastEnd = seg.Speech.End
+		}
+	}
+	stats.SpeakerCount = len(stats.SpeakerWords)
+
+	// Fall back to transcript length when Krisp didn't report a duration
+	if stats.DurationSeconds == 0 {
+		stats.DurationSeconds = int(lastEnd)
+	}
+
+	return stats
+}
+
+// runStats aggregates transcript metrics across all cached meetings
+func runStats(cache *Cache) error {
+	fmt.Println("\n=== Transcript Stats ===")
+
+	meetingIDs, err := cache.MeetingIDs()
+	if err != nil {
+		return err
+	}
+
+	var all []*TranscriptStats
+	computed := 0
+	for _, meetingID := range meetingIDs {
+		stats, err := cache.LoadStats(meetingID)
+		if err != nil {
+			// Not summarized yet - measure it now and remember the result
+			meeting, err := cache.LoadMeeting(meetingID)
+			if err != nil {
+				fmt.Printf("⚠ Error loading meeting %s: %v\n", meetingID, err)
+				continue
+			}
+			_, stats, err = prepareTranscript(meeting, nil)
+			if err != nil {
+				continue
+			}
+			if err := cache.SaveStats(meetingID, stats); err != nil {
+				fmt.Printf("⚠ Error saving transcript stats for %s: %v\n", meetingID, err)
+			}
+			computed++
+		}
+		all = append(all, stats)
+	}
+
+	if len(all) == 0 {
+		fmt.Println("⚠ No cached transcripts found. Run download step first.")
+		return nil
+	}
+	if computed > 0 {
+		fmt.Printf("📏 Measured %d transcript(s) not yet in the stats cache\n", computed)
+	}
+
+	// Totals
+	totalTokens, totalSeconds, totalWords := 0, 0, 0
+	speakerWords := make(map[string]int)
+	speakerMeetings := make(map[string]int)
+	for _, s := range all {
+		totalTokens += s.TokenEstimate
+		totalSeconds += s.DurationSeconds
+		totalWords += s.Words
+		for speaker, words := range s.SpeakerWords {
+			speakerWords[speaker] += words
+			speakerMeetings[speaker]++
+		}
+	}
+
+	fmt.Printf("\nMeetings:        %d\n", len(all))
+	fmt.Printf("Total duration:  %s\n", formatTimestamp(float64(totalSeconds)))
+	fmt.Printf("Total words:     %d\n", totalWords)
+	fmt.Printf("Total tokens:    ~%d (avg ~%d per meeting)\n", totalTokens, totalTokens/len(all))
+
+	// Longest meetings
+	sort.Slice(all, func(i, j int) bool { return all[i].DurationSeconds > all[j].DurationSeconds })
+	fmt.Printf("\nLongest meetings:\n")
+	for i := 0; i < 10 && i < len(all); i++ {
+		s := all[i]
+		fmt.Printf("  %2d. %-9s %s  %s\n", i+1, formatTimestamp(float64(s.DurationSeconds)), s.CreatedAt.Local().Format("2006-01-02"), s.Title)
+	}
+
+	// Token spend drivers
+	sort.Slice(all, func(i, j int) bool { return all[i].TokenEstimate > all[j].TokenEstimate })
+	fmt.Printf("\nToken spend drivers:\n")
+	for i := 0; i < 10 && i < len(all); i++ {
+		s := all[i]
+		share := float64(s.TokenEstimate) / float64(maxInt(totalTokens, 1)) * 100
+		fmt.Printf("  %2d. ~%-8d (%4.1f%%) %s  %s\n", i+1, s.TokenEstimate, share, s.CreatedAt.Local().Format("2006-01-02"), s.Title)
+	}
+
+	// Chattiest speakers
+	type speakerTotal struct {
+		Name     string
+		Words    int
+		Meetings int
+	}
+	var speakers []speakerTotal
+	for name, words := range speakerWords {
+		speakers = append(speakers, speakerTotal{Name: name, Words: words, Meetings: speakerMeetings[name]})
+	}
+	sort.Slice(speakers, func(i, j int) bool {
+		if speakers[i].Words != speakers[j].Words {
+			return speakers[i].Words > speakers[j].Words
+		}
+		return speakers[i].Name < speakers[j].Name
+	})
+	fmt.Printf("\nChattiest speakers:\n")
+	for i := 0; i < 10 && i < len(speakers); i++ {
+		s := speakers[i]
+		fmt.Printf("  %2d. %-30s %8d words in %d meeting(s)\n", i+1, s.Name, s.Words, s.Meetings)
+	}
+
+	return nil
+}
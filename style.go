@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SummaryStyle describes the user's preferred writing language and style for summaries
+type SummaryStyle struct {
+	Language string // e.g. "German", "en-GB"
+	Tone     string // e.g. "concise", "formal", "casual"
+	Format   string // "bullets" or "prose"
+	Person   string // "first" or "third"
+	Length   string // "short", "medium" or "long"
+	Words    int    // explicit word budget for the whole summary, instead of a length preset
+
+	// LengthRules pick a length for meetings by title (not part of the style key)
+	LengthRules []LengthRule
+}
+
+// LengthRule sets the summary length for meetings whose title contains Match
+type LengthRule struct {
+	Match  string
+	Length string
+	Words  int
+}
+
+// Summary length presets
+var summaryLengths = map[string]struct {
+	directive, topics, details string
+}{
+	"short": {
+		directive: "Keep the summary brief - about 150 words in total. Cover at most 3 topics, each in one or two sentences, and leave out small talk and status details nobody acted on.",
+		topics:    "The main topics discussed (at most 3)",
+		details:   "One or two sentences with the key point and its outcome",
+	},
+	"medium": {
+		directive: "Aim for a summary of about 350 words in total.",
+		topics:    "List of topics discussed",
+		details:   "One paragraph summary including key points, decisions, and action items",
+	},
+	"long": {
+		directive: "Be thorough - up to about 900 words in total. Cover every distinct topic, and for each give the discussion, the reasoning and alternatives considered, numbers and dates mentioned, decisions, and who owns the follow-ups.",
+		topics:    "Every distinct topic discussed, in order",
+		details:   "A detailed paragraph of three to five sentences: what was discussed, why, the alternatives considered, decisions, and owners of follow-ups",
+	},
+}
+
+// parseSummaryLength parses a length preset or a word budget (e.g. "short", "400")
+func parseSummaryLength(value string) (string, int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if _, ok := summaryLengths[value]; ok {
+		return value, 0, nil
+	}
+	if words, err := strconv.Atoi(value); err == nil && words > 0 {
+		return "", words, nil
+	}
+	return "", 0, fmt.Errorf("must be short, medium, long or a word count, got %q", value)
+}
+
+// summaryStyle is the configured style applied to every summarization prompt
+var summaryStyle SummaryStyle
+
+// loadSummaryStyle reads the style directive from SUMMARY_LANGUAGE, SUMMARY_TONE,
+// SUMMARY_FORMAT, SUMMARY_PERSON, SUMMARY_LENGTH, SUMMARY_WORDS and SUMMARY_LENGTH_RULES
+func loadSummaryStyle() (SummaryStyle, error) {
+	style := SummaryStyle{
+		Language: strings.TrimSpace(os.Getenv("SUMMARY_LANGUAGE")),
+		Tone:     strings.TrimSpace(os.Getenv("SUMMARY_TONE")),
+		Format:   strings.ToLower(strings.TrimSpace(os.Getenv("SUMMARY_FORMAT"))),
+		Person:   strings.ToLower(strings.TrimSpace(os.Getenv("SUMMARY_PERSON"))),
+	}
+
+	if style.Format != "" && style.Format != "bullets" && style.Format != "prose" {
+		return style, fmt.Errorf("SUMMARY_FORMAT must be 'bullets' or 'prose', got %q", style.Format)
+	}
+	if style.Person != "" && style.Person != "first" && style.Person != "third" {
+		return style, fmt.Errorf("SUMMARY_PERSON must be 'first' or 'third', got %q", style.Person)
+	}
+
+	if v := os.Getenv("SUMMARY_LENGTH"); strings.TrimSpace(v) != "" {
+		length, words, err := parseSummaryLength(v)
+		if err != nil {
+			return style, fmt.Errorf("SUMMARY_LENGTH %w", err)
+		}
+		style.Length, style.Words = length, words
+	}
+	if v := strings.TrimSpace(os.Getenv("SUMMARY_WORDS")); v != "" {
+		words, err := strconv.Atoi(v)
+		if err != nil || words <= 0 {
+			return style, fmt.Errorf("invalid SUMMARY_WORDS %q: must be a positive number", v)
+		}
+		style.Words = words
+	}
+
+	// "standup=short,workshop=long,board=600"
+	for _, rule := range strings.Split(os.Getenv("SUMMARY_LENGTH_RULES"), ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		match, value, ok := strings.Cut(rule, "=")
+		if !ok || strings.TrimSpace(match) == "" {
+			return style, fmt.Errorf("invalid SUMMARY_LENGTH_RULES entry %q: expected title=length", rule)
+		}
+		length, words, err := parseSummaryLength(value)
+		if err != nil {
+			return style, fmt.Errorf("invalid SUMMARY_LENGTH_RULES entry %q: length %w", rule, err)
+		}
+		style.LengthRules = append(style.LengthRules, LengthRule{
+			Match:  strings.ToLower(strings.TrimSpace(match)),
+			Length: length,
+			Words:  words,
+		})
+	}
+
+	return style, nil
+}
+
+// ForMeeting returns the style for a meeting, applying the first length rule matching its title
+func (s SummaryStyle) ForMeeting(title string) SummaryStyle {
+	title = strings.ToLower(title)
+	for _, rule := range s.LengthRules {
+		if strings.Contains(title, rule.Match) {
+			s.Length, s.Words = rule.Length, rule.Words
+			break
+		}
+	}
+	return s
+}
+
+// lengthDirective returns the prompt line setting the summary length, or ""
+func (s SummaryStyle) lengthDirective() string {
+	if s.Words > 0 {
+		return fmt.Sprintf("Keep the whole summary (description, topics and topic details) within about %d words, spreading them over the topics by importance.", s.Words)
+	}
+	if preset, ok := summaryLengths[s.Length]; ok {
+		return preset.directive
+	}
+	return ""
+}
+
+// TopicsDescription returns the schema description of the topics list for the length
+func (s SummaryStyle) TopicsDescription() string {
+	if preset, ok := summaryLengths[s.Length]; ok && s.Words == 0 {
+		return preset.topics
+	}
+	return summaryLengths["medium"].topics
+}
+
+// TopicDetailDescription returns the schema description of a topic detail for the length
+func (s SummaryStyle) TopicDetailDescription() string {
+	if s.Words > 0 {
+		return fmt.Sprintf("Summary of the topic, including key points, decisions, and action items, sized so all topic details together stay within about %d words", s.Words)
+	}
+	if preset, ok := summaryLengths[s.Length]; ok {
+		return preset.details
+	}
+	return summaryLengths["medium"].details
+}
+
+// Directive returns the prompt text describing the style, or "" if no style is configured
+func (s SummaryStyle) Directive() string {
+	var lines []string
+	if s.Language != "" {
+		lines = append(lines, fmt.Sprintf("- Write the description, topics and topic details in %s. Keep tags in English kebab-case.", s.Language))
+	}
+	if s.Tone != "" {
+		lines = append(lines, fmt.Sprintf("- Use a %s tone.", s.Tone))
+	}
+	switch s.Format {
+	case "bullets":
+		lines = append(lines, "- Write each topic detail as a short bulleted list (one point per line, starting with \"- \") instead of a paragraph.")
+	case "prose":
+		lines = append(lines, "- Write each topic detail as flowing prose paragraphs, without bullet points.")
+	}
+	switch s.Person {
+	case "first":
+		lines = append(lines, "- Write from my point of view in the first person (\"we decided\", \"I will follow up\"), as if I took the notes myself.")
+	case "third":
+		lines = append(lines, "- Write in the third person, referring to participants by name.")
+	}
+	if directive := s.lengthDirective(); directive != "" {
+		lines = append(lines, "- "+directive)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Writing style:\n" + strings.Join(lines, "\n")
+}
+
+// Key returns a stable fingerprint of the style, stored with each summary so
+// summaries written in an older style can be found and regenerated
+func (s SummaryStyle) Key() string {
+	parts := map[string]string{
+		"language": s.Language,
+		"tone":     s.Tone,
+		"format":   s.Format,
+		"person":   s.Person,
+		"length":   s.Length,
+	}
+	if s.Words > 0 {
+		parts["words"] = strconv.Itoa(s.Words)
+	}
+
+	var keys []string
+	for k, v := range parts {
+		if v != "" {
+			keys = append(keys, k+"="+v)
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ";")
+}
+
+// findRestyleMeetings returns summarized meetings whose summary was written in a different style
+func findRestyleMeetings(syncState *SyncState, cache *Cache) []string {
+	var stale []string
+	for meetingID := range syncState.SummarizedMeetings {
+		summaryData, err := cache.LoadSummary(meetingID)
+		if err != nil {
+			continue
+		}
+		current := summaryStyle
+		if meeting, err := cache.LoadMeeting(meetingID); err == nil {
+			current = summaryStyle.ForMeeting(meeting.Title)
+		}
+		if summaryData.Style != current.Key() {
+			stale = append(stale, meetingID)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
@@ -5,19 +5,26 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 )
 
 //go:embed summary-prompt.md
 var summaryPromptTemplate string
 
-// Stage 2: Summarize cached meetings with Gemini
+// Stage 2: Summarize cached meetings with the LLM
 func runSummarize(ctx context.Context, limit int, syncState *SyncState, overwrite bool, meetingIDs []string, cache *Cache) error {
 	fmt.Println("\n=== Stage 2: Summarizing meetings ===")
 
@@ -37,140 +44,11 @@ func runSummarize(ctx context.Context, limit int, syncState *SyncState, overwrit
 		if err != nil {
 			fmt.Printf("⚠ Warning: Error loading obsidian-tags.json: %v\n", err)
 		} else if obsidianTags != nil && len(obsidianTags) > 0 {
-			existingTags = obsidianTags
+			existingTags = withoutRetiredTags(obsidianTags)
 			fmt.Printf("📚 Loaded %d tags from Obsidian vault\n", len(existingTags))
 		}
 
-		// Load all meetings and their transcripts
-		type meetingWithTranscript struct {
-			ID         string
-			Transcript string
-		}
-		var meetingsToProcess []meetingWithTranscript
-
-		for _, meetingID := range meetingIDs {
-			meeting, err := cache.LoadMeeting(meetingID)
-			if err != nil {
-				fmt.Printf("⚠ Error loading meeting %s: %v\n", meetingID, err)
-				continue
-			}
-
-			// Parse transcript
-			if meeting.Resources.Transcript.Status != "uploaded" {
-				fmt.Printf("⚠ Transcript not uploaded for %s (status: %s)\n", meetingID, meeting.Resources.Transcript.Status)
-				continue
-			}
-			if meeting.Resources.Transcript.Content == "" {
-				fmt.Printf("⚠ Transcript content empty for %s\n", meetingID)
-				continue
-			}
-
-			var segments []Segment
-			if err := json.Unmarshal([]byte(meeting.Resources.Transcript.Content), &segments); err != nil {
-				fmt.Printf("⚠ Error parsing transcript JSON for %s: %v\n", meetingID, err)
-				continue
-			}
-
-			if len(segments) == 0 {
-				fmt.Printf("⚠ Transcript has no segments for %s\n", meetingID)
-				continue
-			}
-
-			var sb strings.Builder
-			for _, seg := range segments {
-				speakerName := fmt.Sprintf("Speaker %d", seg.SpeakerIndex)
-				if speakerInfo, ok := meeting.Speakers.Data[fmt.Sprintf("%d", seg.SpeakerIndex)]; ok {
-					speakerName = strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
-					if speakerName == "" {
-						speakerName = fmt.Sprintf("Speaker %d", seg.SpeakerIndex)
-					}
-				}
-				sb.WriteString(fmt.Sprintf("%s: %s\n", speakerName, seg.Speech.Text))
-			}
-			transcriptText := sb.String()
-
-			if transcriptText == "" {
-				fmt.Printf("⚠ Generated transcript text is empty for %s\n", meetingID)
-				continue
-			}
-
-			meetingsToProcess = append(meetingsToProcess, meetingWithTranscript{
-				ID:         meetingID,
-				Transcript: transcriptText,
-			})
-		}
-
-		if len(meetingsToProcess) == 0 {
-			fmt.Println("⚠ No meetings with transcripts to process")
-			return nil
-		}
-
-		// Process summaries in parallel with concurrency limit
-		const maxConcurrency = 10
-		semaphore := make(chan struct{}, maxConcurrency)
-
-		type result struct {
-			index int
-			id    string
-			data  *SummaryData
-			err   error
-		}
-		results := make(chan result, len(meetingsToProcess))
-
-		// Process each meeting in parallel
-		for i, m := range meetingsToProcess {
-			// Check if context was cancelled
-			if ctx.Err() != nil {
-				fmt.Printf("\n⚠ Summarization cancelled\n")
-				return ctx.Err()
-			}
-
-			semaphore <- struct{}{} // Acquire semaphore
-
-			go func(index int, meetingID string, transcript string) {
-				defer func() { <-semaphore }() // Release semaphore
-
-				fmt.Printf("[%d/%d] Summarizing meeting: %s\n", index+1, len(meetingsToProcess), meetingID)
-
-				// Generate summary with Gemini
-				summaryResponse, err := summarizeWithGemini(ctx, transcript, existingTags)
-				if err != nil {
-					fmt.Printf("  ⚠ Error generating summary: %v\n", err)
-					results <- result{index: index, id: meetingID, err: err}
-					return
-				}
-
-				// Parse the summary response to SummaryData
-				summaryData := parseSummaryResponse(summaryResponse)
-
-				fmt.Printf("  ✓ Summary generated: %s\n", meetingID)
-				results <- result{index: index, id: meetingID, data: summaryData, err: nil}
-			}(i, m.ID, m.Transcript)
-		}
-
-		// Wait for all goroutines to complete and save results
-		successCount := 0
-		for i := 0; i < len(meetingsToProcess); i++ {
-			res := <-results
-			if res.err == nil {
-				// Save summary to cache
-				if err := cache.SaveSummary(res.id, res.data); err != nil {
-					fmt.Printf("  ⚠ Error saving summary for %s: %v\n", res.id, err)
-					continue
-				}
-				fmt.Printf("  ✓ Summary saved: meetings/%s-summary.json\n", res.id)
-
-				syncState.SummarizedMeetings[res.id] = true
-				successCount++
-				// Save state after each successful summary
-				if err := syncState.Save(); err != nil {
-					fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
-				}
-			}
-		}
-
-		fmt.Printf("\n✅ Summarized %d meeting(s)\n", successCount)
-		return nil
+		return summarizeMeetings(ctx, loadTranscripts(meetingIDs, syncState, cache), existingTags, syncState, cache)
 	}
 
 	if overwrite {
@@ -184,11 +62,11 @@ func runSummarize(ctx context.Context, limit int, syncState *SyncState, overwrit
 	if err != nil {
 		fmt.Printf("⚠ Warning: Error loading obsidian-tags.json: %v\n", err)
 	} else if obsidianTags != nil && len(obsidianTags) > 0 {
-		existingTags = obsidianTags
+		existingTags = withoutRetiredTags(obsidianTags)
 		fmt.Printf("📚 Loaded %d tags from Obsidian vault\n", len(existingTags))
 	} else {
 		fmt.Println("📝 No Obsidian tags found - tags will be generated freely")
-		fmt.Println("   Tip: Run --step extract-tags first to use existing vault tags")
+		fmt.Println("   Tip: Run krisp-sync extract-tags first to use existing vault tags")
 	}
 
 	// Get meetings from sync state that need summarization
@@ -237,75 +115,199 @@ func runSummarize(ctx context.Context, limit int, syncState *SyncState, overwrit
 		toSummarize = toSummarize[:limit]
 	}
 
-	// Load all meetings first (cache is not thread-safe)
-	type meetingWithTranscript struct {
-		ID         string
-		Transcript string
+	ids := make([]string, len(toSummarize))
+	for i, m := range toSummarize {
+		ids[i] = m.ID
 	}
-	var meetingsToProcess []meetingWithTranscript
 
-	for _, m := range toSummarize {
-		meeting, err := cache.LoadMeeting(m.ID)
-		if err != nil {
-			fmt.Printf("⚠ Error loading meeting %s: %v\n", m.ID, err)
-			continue
-		}
+	return summarizeMeetings(ctx, loadTranscripts(ids, syncState, cache), existingTags, syncState, cache)
+}
 
-		// Parse transcript
-		var transcriptText string
-		if meeting.Resources.Transcript.Status != "uploaded" {
-			fmt.Printf("⚠ Transcript not uploaded for %s (status: %s)\n", m.ID, meeting.Resources.Transcript.Status)
+// printSummarizePlan lists the LLM calls summarizing meetings would make, with their
+// estimated prompt sizes, for --dry-run
+func printSummarizePlan(meetingsToProcess []meetingWithTranscript, existingTags []string, compression CompressionConfig, chunkTokens int, model string, cache *Cache) {
+	calls, tokens := 0, 0
+	fmt.Println("🔍 Dry run: LLM calls that would be made")
+	for _, m := range meetingsToProcess {
+		title := m.ID
+		if meeting, err := cache.LoadMeeting(m.ID); err == nil {
+			title = fmt.Sprintf("%s (%s)", meeting.Title, m.ID)
+		}
+		if compression.Applies(m.Stats) {
+			prompt, err := buildCompressPrompt(m.Transcript)
+			if err != nil {
+				fmt.Printf("  ⚠ %s: %v\n", title, err)
+				continue
+			}
+			n := estimateTokens(prompt)
+			fmt.Printf("  %s\n    compress with %s: ~%d prompt tokens\n    summarize the minutes with %s\n", title, compression.Model, n, model)
+			calls += 2
+			tokens += n
 			continue
 		}
-		if meeting.Resources.Transcript.Content == "" {
-			fmt.Printf("⚠ Transcript content empty for %s\n", m.ID)
+		if chunkTokens > 0 && estimateTokens(m.Transcript) > chunkTokens {
+			n := len(splitTranscript(m.Transcript, chunkTokens))
+			fmt.Printf("  %s\n    summarize in %d parts with %s: ~%d transcript tokens\n    merge the part summaries with %s\n", title, n, model, estimateTokens(m.Transcript), model)
+			calls += n + 1
+			tokens += estimateTokens(m.Transcript)
 			continue
 		}
-
-		var segments []Segment
-		if err := json.Unmarshal([]byte(meeting.Resources.Transcript.Content), &segments); err != nil {
-			fmt.Printf("⚠ Error parsing transcript JSON for %s: %v\n", m.ID, err)
+		prompt, err := buildSummaryPrompt(m.Transcript, selectPromptTags(existingTags, m.Transcript), m.Names, m.Style)
+		if err != nil {
+			fmt.Printf("  ⚠ %s: %v\n", title, err)
 			continue
 		}
+		n := estimateTokens(prompt)
+		fmt.Printf("  %s\n    summarize with %s: ~%d prompt tokens\n", title, model, n)
+		calls++
+		tokens += n
+	}
+	fmt.Printf("🔍 %d LLM call(s) for %d meeting(s), ~%d prompt tokens", calls, len(meetingsToProcess), tokens)
+	if compression.Enabled {
+		fmt.Print(" (plus the prompts of compressed meetings' minutes)")
+	}
+	fmt.Println()
+	outputPerCall := averageOutputTokens(cache)
+	if cost, ok := estimateCost(model, calls, tokens, outputPerCall); ok {
+		fmt.Printf("💰 Estimated cost with %s: ~%s (assuming ~%d output tokens per call)\n", model, formatCost(cost), outputPerCall)
+	} else {
+		fmt.Printf("💰 No price known for %s; set LLM_PRICES to estimate the cost\n", model)
+	}
+}
+
+// meetingWithTranscript is a meeting ready to be sent to the LLM
+type meetingWithTranscript struct {
+	ID         string
+	Transcript string
+	Stats      *TranscriptStats
+	Names      []string     // names the summary may mention
+	Style      SummaryStyle // writing style and length for this meeting
+}
+
+// loadTranscripts loads meetings and renders their transcripts as speaker-labelled text.
+// Meetings are loaded up front because the cache is not thread-safe.
+func loadTranscripts(meetingIDs []string, syncState *SyncState, cache *Cache) []meetingWithTranscript {
+	var meetingsToProcess []meetingWithTranscript
 
-		if len(segments) == 0 {
-			fmt.Printf("⚠ Transcript has no segments for %s\n", m.ID)
+	for _, meetingID := range meetingIDs {
+		meeting, err := cache.LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("⚠ Error loading meeting %s: %v\n", meetingID, err)
+			recordFailure(syncState, stageSummarize, meetingID, err)
 			continue
 		}
 
-		var sb strings.Builder
-		for _, seg := range segments {
-			// Get speaker name from the speakers map
-			speakerName := fmt.Sprintf("Speaker %d", seg.SpeakerIndex)
-			if speakerInfo, ok := meeting.Speakers.Data[fmt.Sprintf("%d", seg.SpeakerIndex)]; ok {
-				speakerName = strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
-				if speakerName == "" {
-					speakerName = fmt.Sprintf("Speaker %d", seg.SpeakerIndex)
-				}
-			}
-			sb.WriteString(fmt.Sprintf("%s: %s\n", speakerName, seg.Speech.Text))
+		// Speaker names inferred by an earlier summary are shown in the transcript
+		var previous *SummaryData
+		if cache.SummaryExists(meetingID) {
+			previous, _ = cache.LoadSummary(meetingID)
 		}
-		transcriptText = sb.String()
-
-		if transcriptText == "" {
-			fmt.Printf("⚠ Generated transcript text is empty for %s\n", m.ID)
+		transcriptText, stats, err := prepareTranscript(meeting, inferredSpeakers(previous))
+		if err != nil {
+			fmt.Printf("⚠ %v for %s\n", err, meetingID)
 			continue
 		}
 
+		// Report and remember the size of what we're about to send
+		fmt.Printf("📏 %s: ~%d tokens, %d speaker(s), %s\n", meetingID, stats.TokenEstimate, stats.SpeakerCount, formatTimestamp(float64(stats.DurationSeconds)))
+		if err := cache.SaveStats(meetingID, stats); err != nil {
+			fmt.Printf("  ⚠ Error saving transcript stats for %s: %v\n", meetingID, err)
+		}
+
 		meetingsToProcess = append(meetingsToProcess, meetingWithTranscript{
-			ID:         m.ID,
+			ID:         meetingID,
 			Transcript: transcriptText,
+			Stats:      stats,
+			Names:      allowedNames(meeting),
+			Style:      summaryStyle.ForMeeting(meeting.Title),
 		})
 	}
 
+	return meetingsToProcess
+}
+
+// prepareTranscript renders a meeting's transcript as "Speaker: text" lines and measures it
+func prepareTranscript(meeting *Meeting, inferred map[string]string) (string, *TranscriptStats, error) {
+	if meeting.Resources.Transcript.Status != "uploaded" {
+		return "", nil, fmt.Errorf("transcript not uploaded (status: %s)", meeting.Resources.Transcript.Status)
+	}
+	if meeting.Resources.Transcript.Content == "" {
+		return "", nil, fmt.Errorf("transcript content empty")
+	}
+
+	var segments []Segment
+	if err := json.Unmarshal([]byte(meeting.Resources.Transcript.Content), &segments); err != nil {
+		return "", nil, fmt.Errorf("error parsing transcript JSON: %w", err)
+	}
+
+	if len(segments) == 0 {
+		return "", nil, fmt.Errorf("transcript has no segments")
+	}
+
+	var sb strings.Builder
+	for _, seg := range segments {
+		speakerName := speakerDisplayName(meeting, seg.SpeakerIndex, inferred)
+		sb.WriteString(fmt.Sprintf("%s: %s\n", speakerName, seg.Speech.Text))
+	}
+	transcriptText := sb.String()
+
+	if transcriptText == "" {
+		return "", nil, fmt.Errorf("generated transcript text is empty")
+	}
+
+	return transcriptText, computeTranscriptStats(meeting, segments, transcriptText, inferred), nil
+}
+
+// summarizeMeetings summarizes meetings in parallel and saves the results to the cache
+func summarizeMeetings(ctx context.Context, meetingsToProcess []meetingWithTranscript, existingTags []string, syncState *SyncState, cache *Cache) error {
 	if len(meetingsToProcess) == 0 {
 		fmt.Println("⚠ No meetings with transcripts to process")
 		return nil
 	}
 
+	// Build the tag co-occurrence model before new summaries are added
+	tagModel, err := buildTagCooccurrence(cache)
+	if err != nil {
+		return err
+	}
+	autoThreshold, reviewThreshold, err := tagThresholdsFromEnv()
+	if err != nil {
+		return err
+	}
+	pendingReview := make(map[string][]TagSuggestion)
+
+	compression, err := compressionFromEnv()
+	if err != nil {
+		return err
+	}
+	models, err := summaryModelsFromEnv()
+	if err != nil {
+		return err
+	}
+	chunkTokens, err := chunkTokensFromEnv()
+	if err != nil {
+		return err
+	}
+	ctx, runUsage := withUsageRecorder(ctx)
+	if dryRun {
+		printSummarizePlan(meetingsToProcess, existingTags, compression, chunkTokens, models[0], cache)
+		return nil
+	}
+	if err := checkLLMReady(models); err != nil {
+		return err
+	}
+	if compression.Enabled {
+		if err := checkLLMReady([]string{compression.Model}); err != nil {
+			return err
+		}
+	}
+	alertRules, err := alertRulesFromEnv()
+	if err != nil {
+		return err
+	}
+
 	// Process summaries in parallel with concurrency limit
-	const maxConcurrency = 10
-	semaphore := make(chan struct{}, maxConcurrency)
+	semaphore := make(chan struct{}, summarizeConcurrency())
 
 	type result struct {
 		index int
@@ -316,71 +318,263 @@ func runSummarize(ctx context.Context, limit int, syncState *SyncState, overwrit
 	results := make(chan result, len(meetingsToProcess))
 
 	// Process each meeting in parallel
+	dispatched := 0
 	for i, m := range meetingsToProcess {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
 			fmt.Printf("\n⚠ Summarization cancelled\n")
 			return ctx.Err()
 		}
+		if budgetStop("summarize") {
+			break
+		}
 
 		semaphore <- struct{}{} // Acquire semaphore
+		dispatched++
 
-		go func(index int, meetingID string, transcript string) {
+		go func(index int, meetingID string, transcript string, stats *TranscriptStats, names []string, style SummaryStyle) {
 			defer func() { <-semaphore }() // Release semaphore
+			ctx, span := startMeetingSpan(ctx, "summarize", meetingID)
+			defer span.End()
+			ctx, meetingUsage := withUsageRecorder(ctx)
 
 			fmt.Printf("[%d/%d] Summarizing meeting: %s\n", index+1, len(meetingsToProcess), meetingID)
 
-			// Generate summary with Gemini
-			summaryResponse, err := summarizeWithGemini(ctx, transcript, existingTags)
+			// Long transcripts are condensed into minutes by a cheaper model first
+			fullTranscript := transcript
+			if compression.Applies(stats) {
+				transcript = compressTranscript(ctx, compression, meetingID, transcript)
+			}
+
+			// Offer the most used tags and those the meeting talks about, not the whole dictionary
+			promptTags := selectPromptTags(existingTags, transcript)
+
+			// Generate summary, falling through the model chain on failures. Transcripts
+			// still too long for one call are summarized in parts.
+			var summaryData *SummaryData
+			var model string
+			var err error
+			if chunkTokens > 0 && estimateTokens(transcript) > chunkTokens {
+				summaryData, model, err = summarizeInChunks(ctx, models, transcript, promptTags, names, style, chunkTokens)
+			} else {
+				summaryData, model, err = summarizeWithFallback(ctx, models, transcript, promptTags, names, style)
+			}
 			if err != nil {
 				fmt.Printf("  ⚠ Error generating summary: %v\n", err)
+				if isTransientLLMError(err) {
+					fmt.Printf("  ⏳ %s will be summarized again on the next run (or `krisp-sync retry-failed`)\n", meetingID)
+				}
+				failSpan(span, err)
 				results <- result{index: index, id: meetingID, err: err}
 				return
 			}
-
-			// Parse the summary response to SummaryData
-			summaryData := parseSummaryResponse(summaryResponse)
+			if unknown := guardNames(summaryData, names); len(unknown) > 0 {
+				fmt.Printf("  ⚠ Summary of %s names people who aren't speakers: %s\n", meetingID, strings.Join(unknown, ", "))
+			}
+			for _, tag := range splitTags(summaryData.Tags) {
+				recordTagOrigin(summaryData, tag, tagOriginLLM)
+			}
+			summaryData.Style = style.Key()
+			summaryData.Model = model
+			if meeting, err := cache.LoadMeeting(meetingID); err == nil {
+				if names := resolveInferredSpeakers(meeting, summaryData.speakerNames); len(names) > 0 {
+					summaryData.InferredSpeakers = names
+				}
+				summaryData.Quotes = locateQuotes(meeting, summaryData.Quotes, inferredSpeakers(summaryData))
+				summaryData.Alerts = matchKeywordAlerts(alertRules, meeting, fullTranscript, summaryData)
+			}
+			usage := meetingUsage.Usage()
+			summaryData.Usage = &usage
 
 			fmt.Printf("  ✓ Summary generated: %s\n", meetingID)
 			results <- result{index: index, id: meetingID, data: summaryData, err: nil}
-		}(i, m.ID, m.Transcript)
+		}(i, m.ID, m.Transcript, m.Stats, m.Names, m.Style)
 	}
 
 	// Wait for all goroutines to complete and save results
 	successCount := 0
-	for i := 0; i < len(meetingsToProcess); i++ {
+	var summarizedIDs []string
+	for i := 0; i < dispatched; i++ {
 		res := <-results
-		if res.err == nil {
+		if res.err != nil {
+			recordFailure(syncState, stageSummarize, res.id, res.err)
+		} else {
+			// Keep tags added by hand to the previous summary's note, and its reviewed visibility
+			if previous, err := cache.LoadSummary(res.id); err == nil {
+				carryUserTags(previous, res.data)
+				res.data.Visibility = previous.Visibility
+				carryInferredSpeakers(previous, res.data)
+				// Alerts are for newly summarized meetings, not re-summaries
+				res.data.Alerts = previous.Alerts
+			}
+
+			// Add tags that usually accompany the generated ones
+			applied, review := applyTagSuggestions(tagModel, res.data, autoThreshold, reviewThreshold)
+			for _, t := range applied {
+				fmt.Printf("  🏷  Added tag %s (%.0f%% of %s meetings)\n", t.Tag, t.Confidence*100, t.Because)
+			}
+			if len(review) > 0 {
+				pendingReview[res.id] = review
+			}
+
+			// Let plugins adjust the summary last, e.g. with their own tag rules
+			if len(stagePlugins) > 0 {
+				meeting, err := cache.LoadMeeting(res.id)
+				if err == nil {
+					err = runAfterSummarize(meeting, res.data)
+				}
+				if err != nil {
+					fmt.Printf("  ⚠ Error running plugins on %s: %v\n", res.id, err)
+					recordFailure(syncState, stageSummarize, res.id, err)
+					continue
+				}
+			}
+
 			// Save summary to cache
 			if err := cache.SaveSummary(res.id, res.data); err != nil {
 				fmt.Printf("  ⚠ Error saving summary for %s: %v\n", res.id, err)
+				recordFailure(syncState, stageSummarize, res.id, err)
 				continue
 			}
-			fmt.Printf("  ✓ Summary saved: meetings/%s-summary.json\n", res.id)
+			fmt.Printf("  ✓ Summary saved: %s\n", filepath.Join(cache.dir, res.id+"-summary.json"))
 
-			syncState.SummarizedMeetings[res.id] = true
+			syncState.MarkSummarized(res.id)
+			summarizedIDs = append(summarizedIDs, res.id)
 			successCount++
-			// Save state after each successful summary
-			if err := syncState.Save(); err != nil {
-				fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
-			}
 		}
 	}
 
+	// Compare recurring meetings with their previous occurrence
+	generateSeriesDiffs(ctx, summarizedIDs, cache)
+
+	// Split meetings with a calendar agenda by agenda item
+	generateAgendaSlices(ctx, summarizedIDs, cache)
+
+	if err := saveTagReviewQueue(pendingReview); err != nil {
+		fmt.Printf("⚠ Warning: Could not save tag review queue: %v\n", err)
+	} else if len(pendingReview) > 0 {
+		fmt.Printf("🏷  %d meeting(s) have tag suggestions to review in %s\n", len(pendingReview), dataPath(tagSuggestionsFile))
+	}
+
 	fmt.Printf("\n✅ Summarized %d meeting(s)\n", successCount)
+	if usage := runUsage.Usage(); usage.Calls > 0 {
+		if err := appendUsageLog(UsageLogEntry{Time: time.Now(), Meetings: successCount, Usage: usage}); err != nil {
+			fmt.Printf("⚠ Warning: Could not record LLM usage: %v\n", err)
+		}
+		fmt.Printf("💰 LLM usage: %s\n", usage)
+		if successCount > 0 {
+			fmt.Printf("   ~%s per summarized meeting; `krisp-sync costs` shows the totals so far\n", formatCost(usage.CostUSD/float64(successCount)))
+		}
+	}
 	return nil
 }
 
-func summarizeWithGemini(ctx context.Context, transcript string, existingTags []string) (string, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		Project:  gcpProject,
-		Location: gcpLocation,
-		Backend:  genai.BackendVertexAI,
-	})
+// geminiModel is the Gemini model (on Vertex AI or the Gemini API) used for LLM calls when
+// no model is configured
+const geminiModel = "gemini-2.0-flash-lite"
+
+// generateStructured sends a prompt to the default model and returns the JSON text
+// conforming to schema
+func generateStructured(ctx context.Context, prompt string, schema *genai.Schema) (string, error) {
+	return generateStructuredWith(ctx, defaultModel(), prompt, schema)
+}
+
+// generateStructuredWith is generateStructured with an explicit model
+func generateStructuredWith(ctx context.Context, model string, prompt string, schema *genai.Schema) (string, error) {
+	return generateContent(ctx, model, prompt, generateOptions{Temperature: 0.3, Schema: schema})
+}
+
+// generateText sends a prompt to the given model and returns its plain-text response
+func generateText(ctx context.Context, model string, prompt string) (string, error) {
+	return generateContent(ctx, model, prompt, generateOptions{Temperature: 0.2})
+}
+
+// defaultSummarizeConcurrency is how many meetings are summarized at once by default
+const defaultSummarizeConcurrency = 10
+
+// summarizeConcurrency returns how many meetings are summarized at once (SUMMARIZE_CONCURRENCY)
+func summarizeConcurrency() int {
+	v := strings.TrimSpace(os.Getenv("SUMMARIZE_CONCURRENCY"))
+	if v == "" {
+		return defaultSummarizeConcurrency
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		fmt.Printf("⚠ Ignoring invalid SUMMARIZE_CONCURRENCY %q\n", v)
+		return defaultSummarizeConcurrency
+	}
+	return n
+}
+
+// LLM endpoint overrides, used by the bench step to talk to a local fake instead of Vertex AI
+var (
+	llmHTTPClient *http.Client
+	llmBaseURL    string
+)
+
+// generateContent runs a single-prompt request on the model's backend (see llm.go) and
+// returns the response text
+func generateContent(ctx context.Context, model string, prompt string, opts generateOptions) (text string, err error) {
+	backend, name, err := resolveModel(model)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Vertex AI client: %w", err)
+		return "", err
 	}
+	ctx, span := tracer.Start(ctx, "llm "+model, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("gen_ai.system", backend.Name()),
+		attribute.String("gen_ai.request.model", name),
+		attribute.Int("llm.prompt_chars", len(prompt)),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("llm.response_chars", len(text)))
+		endSpan(span, err)
+	}()
+
+	// Transient failures (rate limits, overloaded servers) are retried with backoff. Each
+	// attempt's usage is recorded: a failed one still costs the tokens the API reported
+	// (e.g. for a blocked or cut-off response).
+	return withLLMRetries(ctx, model, func() (string, error) {
+		call := &llmCallTokens{}
+		text, err := backend.Generate(context.WithValue(ctx, llmCallKey{}, call), name, prompt, opts)
+		if err == nil || call.reported() {
+			recordLLMCall(ctx, backend.Name(), name, call, prompt, text)
+		}
+		return text, err
+	})
+}
 
+// summarizeWithFallback summarizes a transcript with the first model in the chain that
+// succeeds, falling through on quota errors, content-filter blocks and schema failures.
+// A transcript every model blocks is retried around the block (see contentfilter.go).
+// Returns the summary and the model that produced it.
+func summarizeWithFallback(ctx context.Context, models []string, transcript string, existingTags []string, names []string, style SummaryStyle) (*SummaryData, string, error) {
+	for i, model := range models {
+		response, err := summarizeTranscript(ctx, model, transcript, existingTags, names, style)
+		if err == nil && !validSummaryResponse(response) {
+			err = errSchemaFailure
+		}
+		if err == nil {
+			return parseSummaryResponse(response), model, nil
+		}
+
+		last := i == len(models)-1
+		if last || !shouldFallBack(err) {
+			if errors.Is(err, errSchemaFailure) {
+				// Nothing left to try - keep whatever can be salvaged from the response
+				return parseSummaryResponse(response), model, nil
+			}
+			if errors.Is(err, errContentBlocked) && contentFilterRetryEnabled() {
+				summaryData, err := summarizeAroundBlocks(ctx, model, transcript, existingTags, names, style, err)
+				return summaryData, model, err
+			}
+			return nil, model, err
+		}
+		fmt.Printf("  ⚠ %s failed (%v), falling back to %s\n", model, err, models[i+1])
+	}
+	return nil, "", fmt.Errorf("no summary models configured")
+}
+
+// buildSummaryPrompt renders the summary prompt for a transcript
+func buildSummaryPrompt(transcript string, existingTags []string, names []string, style SummaryStyle) (string, error) {
 	// Parse the summary prompt template
 	tmpl, err := template.New("prompt").Parse(summaryPromptTemplate)
 	if err != nil {
@@ -399,10 +593,39 @@ func summarizeWithGemini(ctx context.Context, transcript string, existingTags []
 		prompt += fmt.Sprintf("\n\nPrefer using these existing tags when appropriate:\n%s\n\nYou may suggest new tags if none of these fit well.", strings.Join(existingTags, ", "))
 	}
 
+	// Keep the model from inventing people
+	if directive := nameGuardDirective(names); directive != "" {
+		prompt += "\n\n" + directive
+	}
+
+	// Say who the notes are for, so their commitments are assigned to them
+	if directive := myCommitmentsDirective(); directive != "" {
+		prompt += "\n\n" + directive
+	}
+
+	// Add the user's writing style directive if configured
+	if directive := style.Directive(); directive != "" {
+		prompt += "\n\n" + directive
+	}
+	return prompt, nil
+}
+
+// summarizeTranscript asks a model for a transcript's summary, as JSON following the
+// summary schema
+func summarizeTranscript(ctx context.Context, model string, transcript string, existingTags []string, names []string, style SummaryStyle) (string, error) {
+	prompt, err := buildSummaryPrompt(transcript, existingTags, names, style)
+	if err != nil {
+		return "", err
+	}
+
 	// Define JSON schema for structured output
 	schema := &genai.Schema{
 		Type: genai.TypeObject,
 		Properties: map[string]*genai.Schema{
+			"title": {
+				Type:        genai.TypeString,
+				Description: "Short descriptive meeting title",
+			},
 			"description": {
 				Type:        genai.TypeString,
 				Description: "One-line description of the meeting",
@@ -416,7 +639,7 @@ func summarizeWithGemini(ctx context.Context, transcript string, existingTags []
 			},
 			"topics": {
 				Type:        genai.TypeArray,
-				Description: "List of topics discussed",
+				Description: style.TopicsDescription(),
 				Items: &genai.Schema{
 					Type: genai.TypeString,
 				},
@@ -433,60 +656,130 @@ func summarizeWithGemini(ctx context.Context, transcript string, existingTags []
 						},
 						"summary": {
 							Type:        genai.TypeString,
-							Description: "One paragraph summary including key points, decisions, and action items",
+							Description: style.TopicDetailDescription(),
 						},
 					},
 					Required: []string{"topic", "summary"},
 				},
 			},
+			"decisions": {
+				Type:        genai.TypeArray,
+				Description: "Decisions the meeting reached, one sentence each (empty if none)",
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+			"people_mentioned": {
+				Type:        genai.TypeArray,
+				Description: "Every person named anywhere in the summary or action items",
+				Items:       &genai.Schema{Type: genai.TypeString},
+			},
+			"speaker_count": {
+				Type:        genai.TypeInteger,
+				Description: "Estimated number of distinct people speaking, judged from the conversation",
+			},
+			"action_items": {
+				Type:        genai.TypeArray,
+				Description: "Concrete follow-up tasks agreed in the meeting",
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"text": {
+							Type:        genai.TypeString,
+							Description: "The task, starting with a verb",
+						},
+						"owner": {
+							Type:        genai.TypeString,
+							Description: "Person responsible, if mentioned",
+						},
+					},
+					Required: []string{"text"},
+				},
+			},
 		},
 		Required: []string{"description", "tags", "topics", "topic_details"},
 	}
-
-	resp, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash-lite", []*genai.Content{
-		{
-			Role: "user",
-			Parts: []*genai.Part{
-				genai.NewPartFromText(prompt),
+	if speakerFallbackEnabled(speakerFallbackLLM) {
+		schema.Properties["speaker_names"] = &genai.Schema{
+			Type:        genai.TypeArray,
+			Description: "Names of speakers labelled \"Unknown Speaker X\" who introduce themselves or are clearly addressed by name (empty if none)",
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"speaker": {
+						Type:        genai.TypeString,
+						Description: "Speaker label exactly as it appears in the transcript",
+					},
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The speaker's name as introduced",
+					},
+				},
+				Required: []string{"speaker", "name"},
 			},
-		},
-	}, &genai.GenerateContentConfig{
-		Temperature:      func() *float32 { v := float32(0.3); return &v }(),
-		ResponseMIMEType: "application/json",
-		ResponseSchema:   schema,
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to generate summary: %w", err)
+		}
 	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no summary generated")
+	if notableQuotesEnabled() {
+		schema.Properties["quotes"] = &genai.Schema{
+			Type:        genai.TypeArray,
+			Description: "3-5 notable quotes copied word for word from the transcript",
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"text": {
+						Type:        genai.TypeString,
+						Description: "The exact words spoken, verbatim",
+					},
+					"speaker": {
+						Type:        genai.TypeString,
+						Description: "Speaker name as it appears in the transcript",
+					},
+				},
+				Required: []string{"text", "speaker"},
+			},
+		}
 	}
 
-	summary := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0].Text)
+	summary, err := generateStructuredWith(ctx, model, prompt, schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate summary: %w", err)
+	}
 	return summary, nil
 }
 
 // parseSummaryResponse parses the JSON response from the LLM
 func parseSummaryResponse(response string) *SummaryData {
 	var data struct {
-		Description  string   `json:"description"`
-		Tags         []string `json:"tags"`
-		Topics       []string `json:"topics"`
-		TopicDetails []struct {
-			Topic   string `json:"topic"`
-			Summary string `json:"summary"`
-		} `json:"topic_details"`
+		Title        string        `json:"title"`
+		Description  string        `json:"description"`
+		Tags         []string      `json:"tags"`
+		Topics       []string      `json:"topics"`
+		TopicDetails []TopicDetail `json:"topic_details"`
+		Decisions    []string      `json:"decisions"`
+		ActionItems  []ActionItem  `json:"action_items"`
+		Quotes       []Quote       `json:"quotes"`
+		SpeakerCount int           `json:"speaker_count"`
+
+		PeopleMentioned []string          `json:"people_mentioned"`
+		SpeakerNames    []InferredSpeaker `json:"speaker_names"`
 	}
 
 	if err := json.Unmarshal([]byte(response), &data); err != nil {
-		fmt.Printf("  ⚠ Error parsing JSON response: %v\n", err)
-		// Fallback to raw response
-		return &SummaryData{
-			Description: "",
-			Tags:        "",
-			Summary:     response,
+		// Retry with fences stripped, the largest object extracted and trailing commas removed
+		repaired := repairJSON(response)
+		if repairErr := json.Unmarshal([]byte(repaired), &data); repairErr != nil {
+			fmt.Printf("  ⚠ Error parsing JSON response: %v\n", err)
+
+			// Salvage what we can before falling back to the raw response
+			description, tags := salvageSummaryFields(response)
+			if description != "" || len(tags) > 0 {
+				fmt.Printf("  ⚠ Salvaged description and %d tag(s) from malformed response\n", len(tags))
+			}
+			return &SummaryData{
+				Description: description,
+				Tags:        strings.Join(tags, ", "),
+				Summary:     stripCodeFences(response),
+			}
 		}
+		fmt.Printf("  ⚠ Repaired malformed JSON response\n")
 	}
 
 	// Build the formatted summary
@@ -507,8 +800,20 @@ func parseSummaryResponse(response string) *SummaryData {
 	}
 
 	return &SummaryData{
+		Title:       data.Title,
 		Description: data.Description,
 		Tags:        strings.Join(data.Tags, ", "),
 		Summary:     sb.String(),
+
+		Topics:       data.Topics,
+		TopicDetails: data.TopicDetails,
+		Decisions:    data.Decisions,
+
+		ActionItems: data.ActionItems,
+		Quotes:      data.Quotes,
+
+		EstimatedSpeakers: data.SpeakerCount,
+		PeopleMentioned:   data.PeopleMentioned,
+		speakerNames:      data.SpeakerNames,
 	}
 }
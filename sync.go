@@ -13,7 +13,7 @@ import (
 	"text/template"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:embed summary-template.md
@@ -26,32 +26,58 @@ var dailyNoteTemplate string
 type MeetingWithSummary struct {
 	Meeting     *Meeting
 	SummaryData *SummaryData
+	Rewrite     bool // replace the audio-only note written before the transcript was in
+}
+
+// SyncResult lists the vault notes written by a sync run
+type SyncResult struct {
+	SummaryNotes []string // summary notes created or overwritten, oldest meeting first
+	DailyNotes   []string // daily notes created or updated
+}
+
+// merge appends another result's notes
+func (r *SyncResult) merge(other *SyncResult) {
+	if other == nil {
+		return
+	}
+	r.SummaryNotes = append(r.SummaryNotes, other.SummaryNotes...)
+	r.DailyNotes = append(r.DailyNotes, other.DailyNotes...)
 }
 
 // Stage 3: Sync cached meetings and summaries to Obsidian
-func runSync(ctx context.Context, obsidianVaultPath string, limit int, syncState *SyncState, overwrite bool, testMode bool, applyNormalization bool, meetingIDs []string, updateFields []string, cache *Cache) error {
+func runSync(ctx context.Context, obsidianVaultPath string, limit int, syncState *SyncState, overwrite bool, testMode bool, applyNormalization bool, meetingIDs []string, updateFields []string, cache *Cache) (*SyncResult, error) {
 	fmt.Println("\n=== Stage 3: Syncing to Obsidian ===")
 
+	// In the properties store, the notes in the vault say which meetings are in Obsidian
+	if err := loadVaultSyncStatus(syncState, obsidianVaultPath); err != nil {
+		return nil, err
+	}
+
 	// Handle specific meeting IDs mode
 	if len(meetingIDs) > 0 {
 		fmt.Printf("🎯 Processing %d specific meeting(s)\n", len(meetingIDs))
 		if overwrite {
 			fmt.Println("🔄 Forcing re-sync of specified meetings")
 			for _, id := range meetingIDs {
-				delete(syncState.ObsidianSyncedMeetings, id)
+				syncState.UnmarkObsidianSynced(id)
 			}
 		}
 		// Process each meeting
+		result := &SyncResult{}
 		for _, meetingID := range meetingIDs {
-			if err := syncSingleMeeting(ctx, meetingID, obsidianVaultPath, syncState, applyNormalization, updateFields, cache); err != nil {
+			single, err := syncSingleMeeting(ctx, meetingID, obsidianVaultPath, syncState, testMode, applyNormalization, updateFields, cache)
+			if err != nil {
 				fmt.Printf("❌ Error syncing meeting %s: %v\n", meetingID, err)
+				recordFailure(syncState, stageSync, meetingID, err)
 				// Continue with other meetings
+				continue
 			}
+			result.merge(single)
 		}
-		return nil
+		return result, nil
 	}
 
-	return runSyncInternal(ctx, obsidianVaultPath, limit, syncState, overwrite, testMode, applyNormalization, updateFields, cache)
+	return runSyncInternal(ctx, obsidianVaultPath, limit, syncState, overwrite, testMode, testMode, applyNormalization, updateFields, cache)
 }
 
 // fileExists checks if a file exists
@@ -62,41 +88,18 @@ func fileExists(path string) bool {
 
 // parseFrontmatter extracts YAML frontmatter and body from a markdown file
 func parseFrontmatter(filePath string) (map[string]interface{}, string, error) {
-	content, err := os.ReadFile(filePath)
+	content, err := vaultWriter.ReadNote(filePath)
 	if err != nil {
 		return nil, "", err
 	}
 
-	// Check for frontmatter delimiters
-	if !bytes.HasPrefix(content, []byte("---\n")) {
-		return nil, "", fmt.Errorf("file does not have YAML frontmatter")
-	}
-
-	// Find the end of frontmatter
-	parts := bytes.SplitN(content[4:], []byte("\n---\n"), 2)
-	if len(parts) != 2 {
-		return nil, "", fmt.Errorf("malformed YAML frontmatter")
-	}
-
-	// Parse YAML
-	var frontmatter map[string]interface{}
-	if err := yaml.Unmarshal(parts[0], &frontmatter); err != nil {
-		return nil, "", fmt.Errorf("failed to parse frontmatter: %w", err)
-	}
-
-	body := string(parts[1])
-	return frontmatter, body, nil
+	return splitFrontmatter(content)
 }
 
-// updateFrontmatterFields updates specific fields in existing frontmatter
-func updateFrontmatterFields(existingFrontmatter map[string]interface{}, newData map[string]interface{}, fieldsToUpdate []string) map[string]interface{} {
+// updateFrontmatterFields picks the new values of the fields to update from the template data
+func updateFrontmatterFields(newData map[string]interface{}, fieldsToUpdate []string) map[string]interface{} {
 	updated := make(map[string]interface{})
 
-	// Copy existing frontmatter
-	for k, v := range existingFrontmatter {
-		updated[k] = v
-	}
-
 	// Update only specified fields (case-insensitive match)
 	for _, field := range fieldsToUpdate {
 		fieldLower := strings.ToLower(field)
@@ -113,14 +116,37 @@ func updateFrontmatterFields(existingFrontmatter map[string]interface{}, newData
 	return updated
 }
 
+// vaultAttachmentsDir returns the vault-relative folder for attachments and recordings
+func vaultAttachmentsDir() string {
+	return firstNonEmpty(os.Getenv("OBSIDIAN_ATTACHMENTS_DIR"), defaultAttachmentsDir)
+}
+
+// renderTranscriptNote renders a meeting's transcript note, copying its recording into the
+// vault so the transcript can link to it
+func renderTranscriptNote(vaultPath, attachmentsDir string, m *Meeting, summaryData *SummaryData, cache *Cache) []byte {
+	audio, err := copyRecordingToVault(vaultPath, attachmentsDir, m, cache)
+	if err != nil {
+		fmt.Printf("  ⚠ Error copying recording: %v\n", err)
+	}
+	return appendProvenance([]byte(generateTranscriptContent(m, audio, summaryData)), Provenance{
+		GeneratedAt: time.Now(),
+		MeetingID:   m.ID,
+	})
+}
+
 // writeFrontmatterFile writes a markdown file with YAML frontmatter
 func writeFrontmatterFile(filePath string, frontmatter map[string]interface{}, body string) error {
+	return vaultWriter.CreateNote(filePath, renderFrontmatterNote(frontmatter, body))
+}
+
+// renderFrontmatterNote renders YAML frontmatter followed by the note body
+func renderFrontmatterNote(frontmatter map[string]interface{}, body string) []byte {
 	var buf bytes.Buffer
 
 	buf.WriteString("---\n")
 
 	// Write frontmatter fields in a consistent order
-	orderedKeys := []string{"date", "time", "type", "title", "description", "tags", "participants", "meeting_id"}
+	orderedKeys := []string{"date", "time", "type", "title", "aliases", "description", "tags", "participants", "teams", "meeting_id", "account", "transcript_truncated", "transcript_coverage", transcriptStatusKey, visibilityKey}
 	for _, key := range orderedKeys {
 		if value, ok := frontmatter[key]; ok {
 			writeFrontmatterField(&buf, key, value)
@@ -137,7 +163,7 @@ func writeFrontmatterFile(filePath string, frontmatter map[string]interface{}, b
 	buf.WriteString("---\n")
 	buf.WriteString(body)
 
-	return os.WriteFile(filePath, buf.Bytes(), 0644)
+	return buf.Bytes()
 }
 
 // writeFrontmatterField writes a single frontmatter field
@@ -211,10 +237,79 @@ func uniqueStrings(slice []string) []string {
 	return result
 }
 
+// meetingAliases returns the titles a meeting note should be findable by in Obsidian's quick switcher
+func meetingAliases(m *Meeting, summaryData *SummaryData) []string {
+	var aliases []string
+	if title := strings.TrimSpace(m.Title); title != "" {
+		aliases = append(aliases, title)
+	}
+	if summaryData != nil {
+		if title := strings.TrimSpace(summaryData.Title); title != "" {
+			aliases = append(aliases, title)
+		}
+	}
+	return uniqueStrings(aliases)
+}
+
+// refreshAliases adds any missing aliases to an existing note's frontmatter,
+// preserving aliases the user added by hand. Returns true if the file was rewritten.
+func refreshAliases(filePath string, aliases []string) (bool, error) {
+	if len(aliases) == 0 {
+		return false, nil
+	}
+
+	frontmatter, _, err := parseFrontmatter(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	var existing []string
+	switch v := frontmatter["aliases"].(type) {
+	case []interface{}:
+		for _, item := range v {
+			existing = append(existing, fmt.Sprintf("%v", item))
+		}
+	case string:
+		existing = append(existing, v)
+	}
+
+	merged := uniqueStrings(append(existing, aliases...))
+	if len(merged) == len(existing) {
+		return false, nil
+	}
+
+	if err := vaultWriter.UpdateFrontmatter(filePath, map[string]interface{}{"aliases": merged}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeDailyNote creates a day's daily note, or updates the Dataview query of an existing
+// one, and writes its My Action Items section, through w
+func writeDailyNote(w VaultWriter, vaultPath, path string, t time.Time, cache *Cache) error {
+	if w.Exists(path) {
+		if err := updateDailyNoteDataview(w, path, dailyNoteData(vaultPath, t)); err != nil {
+			return fmt.Errorf("error updating daily note Dataview: %w", err)
+		}
+	} else {
+		content, err := renderDailyNote(vaultPath, t)
+		if err != nil {
+			return err
+		}
+		if err := w.CreateNote(path, content); err != nil {
+			return err
+		}
+	}
+	if err := updateDailyMyActionItems(w, path, t, cache); err != nil {
+		return fmt.Errorf("error updating My Action Items: %w", err)
+	}
+	return nil
+}
+
 // updateDailyNoteDataview updates the Dataview query in an existing daily note
-func updateDailyNoteDataview(filePath string, data map[string]string) error {
+func updateDailyNoteDataview(w VaultWriter, filePath string, data map[string]string) error {
 	// Read existing daily note
-	content, err := os.ReadFile(filePath)
+	content, err := w.ReadNote(filePath)
 	if err != nil {
 		return err
 	}
@@ -267,10 +362,10 @@ func updateDailyNoteDataview(filePath string, data map[string]string) error {
 	}
 
 	// Write updated content back
-	return os.WriteFile(filePath, []byte(contentStr), 0644)
+	return w.CreateNote(filePath, []byte(contentStr))
 }
 
-func generateTranscriptContent(m *Meeting) string {
+func generateTranscriptContent(m *Meeting, audio *audioTarget, summaryData *SummaryData) string {
 	var sb strings.Builder
 
 	// Transcript header
@@ -279,41 +374,39 @@ func generateTranscriptContent(m *Meeting) string {
 	sb.WriteString(fmt.Sprintf("# %s - %s (Transcript)\n\n", timeStr, m.Title))
 	sb.WriteString(fmt.Sprintf("**Date**: %s\n", dateStr))
 	sb.WriteString(fmt.Sprintf("**Meeting ID**: `%s`\n\n", m.ID))
+	if audio != nil {
+		sb.WriteString(fmt.Sprintf("**Recording**: ![[%s]]\n\n", audio.VaultPath))
+	}
 
 	// Full transcript
 	if m.Resources.Transcript.Status == "uploaded" && m.Resources.Transcript.Content != "" {
 		var segments []Segment
 		if err := json.Unmarshal([]byte(m.Resources.Transcript.Content), &segments); err == nil && len(segments) > 0 {
 			sb.WriteString("## Transcript\n\n")
-
-			for _, segment := range segments {
-				timestamp := formatTimestamp(segment.Speech.Start)
-
-				// Get speaker name from the speakers map
-				speakerName := fmt.Sprintf("Speaker %d", segment.SpeakerIndex)
-				if speakerInfo, ok := m.Speakers.Data[fmt.Sprintf("%d", segment.SpeakerIndex)]; ok {
-					if speakerInfo.Person.FirstName != "" || speakerInfo.Person.LastName != "" {
-						speakerName = strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
-					}
-				}
-
-				sb.WriteString(fmt.Sprintf("**[%s] %s**: %s\n\n", timestamp, speakerName, segment.Speech.Text))
-			}
+			sb.WriteString(renderTranscriptSegments(m, segments, audio, summaryData))
 		}
 	}
 
 	return sb.String()
 }
 
-// syncSingleMeeting syncs a single meeting by ID to Obsidian
-func syncSingleMeeting(ctx context.Context, meetingID string, obsidianVaultPath string, syncState *SyncState, applyNormalization bool, updateFields []string, cache *Cache) error {
+// syncSingleMeeting syncs a single meeting by ID to Obsidian, overwriting its notes.
+// testMode is set for test runs, which write to the sandbox.
+func syncSingleMeeting(ctx context.Context, meetingID string, obsidianVaultPath string, syncState *SyncState, testMode bool, applyNormalization bool, updateFields []string, cache *Cache) (*SyncResult, error) {
 	// Temporarily add meeting to synced list if not there
 	if !syncState.SyncedMeetings[meetingID] {
-		return fmt.Errorf("meeting %s not found in sync state (run download first)", meetingID)
+		return nil, fmt.Errorf("meeting %s not found in sync state (run download first)", meetingID)
+	}
+
+	// Failures recorded during the run land in the real state
+	if syncState.FailedMeetings == nil {
+		syncState.FailedMeetings = make(map[string]*MeetingFailure)
 	}
 
 	// Temporarily create a new sync state with just this meeting
 	tempState := &SyncState{
+		FailedMeetings:         syncState.FailedMeetings,
+		TranscriptQueue:        syncState.TranscriptQueue,
 		path:                   syncState.path,
 		SyncedMeetings:         map[string]bool{meetingID: true},
 		SummarizedMeetings:     syncState.SummarizedMeetings,
@@ -321,22 +414,40 @@ func syncSingleMeeting(ctx context.Context, meetingID string, obsidianVaultPath
 		LastSyncTime:           syncState.LastSyncTime,
 	}
 
-	// Run the sync with limit 1 and test mode true to force overwrite
-	if err := runSyncInternal(ctx, obsidianVaultPath, 1, tempState, false, true, applyNormalization, updateFields, cache); err != nil {
-		return err
+	// Run the sync with limit 1, forcing the overwrite
+	failuresBefore := len(runFailures)
+	result, err := runSyncInternal(ctx, obsidianVaultPath, 1, tempState, false, true, testMode, applyNormalization, updateFields, cache)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the real sync state (we do this manually since test mode doesn't update state)
-	syncState.ObsidianSyncedMeetings[meetingID] = true
-	if err := syncState.Save(); err != nil {
-		return fmt.Errorf("failed to save sync state: %w", err)
+	// Tags recorded as used while syncing land in the real state
+	for tag, at := range tempState.TagLastUsed {
+		syncState.RecordTagUse([]string{tag}, at)
 	}
 
-	return nil
+	// Update the real sync state (we do this manually since forced syncs don't update state),
+	// unless writing one of its notes failed
+	if len(runFailures) == failuresBefore {
+		syncState.MarkObsidianSynced(meetingID)
+		if m, err := cache.LoadMeeting(meetingID); err == nil {
+			if status := transcriptStatus(m, syncState); status != "" {
+				syncState.MarkAudioOnly(meetingID, status)
+			} else {
+				syncState.ClearAudioOnly(meetingID)
+			}
+		}
+	}
+
+	return result, nil
 }
 
-// runSyncInternal is the internal sync logic extracted for reuse
-func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, syncState *SyncState, overwrite bool, testMode bool, applyNormalization bool, updateFields []string, cache *Cache) error {
+// runSyncInternal is the internal sync logic extracted for reuse. force overwrites the
+// notes of the first meeting without updating state; testMode is set for test runs,
+// which write to the sandbox and don't notify, export or log anything outside it.
+func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, syncState *SyncState, overwrite bool, force bool, testMode bool, applyNormalization bool, updateFields []string, cache *Cache) (*SyncResult, error) {
+	result := &SyncResult{}
+
 	if testMode {
 		fmt.Println("🧪 Test mode: will overwrite files without updating state")
 	}
@@ -385,7 +496,7 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 	}
 
 	// If overwrite flag is set, clear the Obsidian sync state
-	if overwrite && !testMode {
+	if overwrite && !force {
 		fmt.Println("🔄 Overwrite mode: clearing Obsidian sync state")
 		syncState.ObsidianSyncedMeetings = make(map[string]bool)
 	}
@@ -394,18 +505,28 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 	var toSync []*MeetingWithSummary
 	for id := range syncState.SyncedMeetings {
 		// Determine if we should process this meeting:
-		// - testMode: process all meetings
+		// - force: process all meetings
 		// - updateFields: process already-synced meetings (to update existing files)
+		// - audio-only notes: rewrite them once the transcript is in
 		// - otherwise: only process unsynced meetings
-		shouldProcess := testMode ||
+		_, audioOnly := syncState.AudioOnlyNotes[id]
+		shouldProcess := force ||
 			(len(updateFields) > 0 && syncState.ObsidianSyncedMeetings[id]) ||
-			(!syncState.ObsidianSyncedMeetings[id])
+			(!syncState.ObsidianSyncedMeetings[id]) ||
+			audioOnly
 
 		if shouldProcess {
 			// Load the meeting once
 			meeting, err := cache.LoadMeeting(id)
 			if err != nil {
 				fmt.Printf("⚠ Error loading meeting %s: %v\n", id, err)
+				recordFailure(syncState, stageSync, id, err)
+				continue
+			}
+
+			// Audio-only notes that are already synced wait for their transcript
+			rewrite := syncState.audioOnlyOutdated(meeting, cache)
+			if audioOnly && syncState.ObsidianSyncedMeetings[id] && !force && len(updateFields) == 0 && !rewrite {
 				continue
 			}
 
@@ -421,13 +542,14 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 			toSync = append(toSync, &MeetingWithSummary{
 				Meeting:     meeting,
 				SummaryData: summaryData,
+				Rewrite:     rewrite,
 			})
 		}
 	}
 
 	if len(toSync) == 0 {
 		fmt.Println("✅ All downloaded meetings already synced to Obsidian!")
-		return nil
+		return result, nil
 	}
 
 	// Sort by creation time (oldest first)
@@ -435,11 +557,13 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 		return toSync[i].Meeting.CreatedAt.Before(toSync[j].Meeting.CreatedAt)
 	})
 
-	// In test mode, only take the first meeting
-	if testMode && len(toSync) > 0 {
+	// Forced syncs only take the first meeting
+	if force && len(toSync) > 0 {
 		toSync = toSync[:1]
 		limit = 1
-		fmt.Printf("🧪 Test mode: processing first meeting only\n")
+		if testMode {
+			fmt.Printf("🧪 Test mode: processing first meeting only\n")
+		}
 	}
 
 	fmt.Printf("Found %d meeting(s) to sync to Obsidian (oldest to newest)\n", len(toSync))
@@ -452,7 +576,7 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 		// Check if context was cancelled
 		if ctx.Err() != nil {
 			fmt.Printf("\n⚠ Sync cancelled\n")
-			return ctx.Err()
+			return result, ctx.Err()
 		}
 
 		if limit > 0 && processedCount >= limit {
@@ -466,15 +590,63 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 		processedCount++
 	}
 
+	// Vault folder for meeting attachments
+	attachmentsDir := vaultAttachmentsDir()
+
+	// Existing notes from other tools that Krisp meetings should be merged into
+	var dedupeIndex *DedupeIndex
+	if envBool("VAULT_DEDUPE") {
+		index, err := buildDedupeIndex(obsidianVaultPath)
+		if err != nil {
+			return nil, err
+		}
+		dedupeIndex = index
+	}
+
+	// Thresholds a day has to meet before a new daily note is created
+	dailyThresholds, err := dailyNoteThresholdsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Retired tags are left out of the notes, even when cached summaries still have them
+	retiredTags, err := loadRetiredTags()
+	if err != nil {
+		return nil, err
+	}
+	earlierSynced := &syncedDays{state: syncState, cache: cache}
+
+	// Body sections, in the order configured by SUMMARY_SECTIONS
+	sections, err := summarySectionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Shareable versions of the notes, when SHARE_DIR is set
+	shareTo, err := shareDir(obsidianVaultPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse the summary template
 	tmpl, err := template.New("summary").Parse(obsidianSummaryTemplate)
 	if err != nil {
-		return fmt.Errorf("error parsing template: %w", err)
+		return nil, fmt.Errorf("error parsing template: %w", err)
 	}
 
 	// Process each day
 	successCount := 0
-	for date, dayMeetings := range meetingsByDate {
+	dates := make([]string, 0, len(meetingsByDate))
+	for date := range meetingsByDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if budgetStop("sync") {
+			break
+		}
+		dayMeetings := meetingsByDate[date]
 		fmt.Printf("\n📅 Processing %s (%d meeting(s))\n", date, len(dayMeetings))
 
 		// Sort meetings by time
@@ -484,44 +656,76 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 
 		// Generate path: YYYY/MM-MonthName/YYYY-MM-DD-DayName.md
 		t := dayMeetings[0].Meeting.CreatedAt.Local()
-		year := t.Format("2006")
-		monthNum := t.Format("01")
-		monthName := t.Format("January")
-		dayName := t.Format("Monday")
 
-		// Create directory structure: YYYY/MM-MonthName
-		dailyNotesPath := filepath.Join(obsidianVaultPath, year, monthNum+"-"+monthName)
-		if err := os.MkdirAll(dailyNotesPath, 0755); err != nil {
-			fmt.Printf("  ⚠ Error creating directory: %v\n", err)
-			continue
+		// Folders (YYYY/MM-MonthName/meetings) are created by the vault writer as notes are written
+		meetingsPath := meetingsDir(obsidianVaultPath, t)
+
+		// The daily note is created once the day's meetings meet the thresholds, and kept up
+		// to date once it exists
+		dailyFileName := dailyNoteFileName(t)
+		dailyPath := dailyNotePath(obsidianVaultPath, t)
+		dailyExisted := vaultWriter.Exists(dailyPath)
+		writeDaily, reason := dailyThresholds.Allows(dayMeetings, cache)
+		if !writeDaily && !dailyExisted && !dailyThresholds.Disabled {
+			// Meetings synced earlier that day count towards the thresholds too
+			writeDaily, reason = dailyThresholds.Allows(earlierSynced.withEarlier(date, dayMeetings), cache)
 		}
-
-		// Create meetings subdirectory
-		meetingsPath := filepath.Join(dailyNotesPath, "meetings")
-		if err := os.MkdirAll(meetingsPath, 0755); err != nil {
-			fmt.Printf("  ⚠ Error creating meetings directory: %v\n", err)
-			continue
+		writeDaily = writeDaily || dailyExisted
+		if !writeDaily {
+			fmt.Printf("  ⏭ Skipping daily note (%s)\n", reason)
 		}
 
-		// Create individual meeting files
+		// Create individual meeting files. Each meeting's notes, and its update of the daily
+		// note, are staged and committed together, and the meeting is only marked synced
+		// once they are all in place, so a failure part-way leaves none of them behind.
+		var batch *VaultBatch
+		daySynced := 0
+		meetingSpan := trace.SpanFromContext(context.Background()) // no-op until the first meeting
 		for _, mws := range dayMeetings {
+			batch.Discard()
+			meetingSpan.End()
+
 			// Check if context was cancelled
 			if ctx.Err() != nil {
 				fmt.Printf("\n⚠ Sync cancelled\n")
-				return ctx.Err()
+				return result, ctx.Err()
+			}
+			if budgetExhausted() {
+				// Finish this day's daily note; the day loop reports the stop
+				break
 			}
 
 			m := mws.Meeting
+			batch = newVaultBatch(vaultWriter)
+			_, meetingSpan = startMeetingSpan(ctx, "sync", m.ID)
 
-			// Get participants from speakers
-			var participants []string
-			for _, speakerInfo := range m.Speakers.Data {
-				name := strings.TrimSpace(speakerInfo.Person.FirstName + " " + speakerInfo.Person.LastName)
-				if name != "" {
-					participants = append(participants, name)
-				}
+			// Meetings without a transcript get an audio-only note until it comes in
+			status := transcriptStatus(m, syncState)
+
+			// Sensitivity levels changed in the note since it was written
+			if err := reviewVisibility(batch, filepath.Join(meetingsPath, m.ID+"-summary.md"), m, mws.SummaryData, cache); err != nil {
+				fmt.Printf("  ⚠ Error applying %s change: %v\n", visibilityKey, err)
+				recordFailure(syncState, stageSync, m.ID, err)
+				continue
+			}
+
+			// Confidential meetings get no transcript note and have emails redacted
+			confidential := isConfidential(m, mws.SummaryData)
+			visibility, transcriptLink := "", renderTranscriptLink(m.ID)
+			if confidential {
+				visibility, transcriptLink = visibilityConfidential, ""
+			}
+
+			// Get participants from speakers (or Krisp's participant list), labelling
+			// unidentified speakers when nobody could be named
+			participants := meetingParticipants(m)
+			if len(participants) == 0 {
+				participants = estimatedParticipants(m, mws.SummaryData)
 			}
 			participantsStr := strings.Join(participants, ", ")
+			if confidential {
+				participantsStr = string(redactEmails([]byte(participantsStr), m))
+			}
 			if participantsStr == "" {
 				participantsStr = "[]"
 			}
@@ -530,157 +734,286 @@ func runSyncInternal(ctx context.Context, obsidianVaultPath string, limit int, s
 			description := ""
 			var tags []string
 			summary := ""
+			aliases := meetingAliases(m, mws.SummaryData)
 			if mws.SummaryData != nil {
 				description = mws.SummaryData.Description
 				// Split comma-separated tags into array and apply mappings
-				if mws.SummaryData.Tags != "" {
-					for _, tag := range strings.Split(mws.SummaryData.Tags, ",") {
-						tag = strings.TrimSpace(tag)
-						// Apply mapping if dictionary exists
-						if tagMappings != nil {
-							if canonical, ok := tagMappings[tag]; ok {
-								tag = canonical
-							}
+				tags = meetingTags(mws.SummaryData, tagMappings, retiredTags)
+				summary = mws.SummaryData.Summary
+
+				// Tags added by hand to an existing note are kept on every re-sync
+				if noteTags := existingNoteTags(filepath.Join(meetingsPath, m.ID+"-summary.md")); len(noteTags) > 0 {
+					if claimUserTags(mws.SummaryData, noteTags, tags, tagMappings) {
+						if err := cache.SaveSummary(m.ID, mws.SummaryData); err != nil {
+							fmt.Printf("  ⚠ Error saving tag origins: %v\n", err)
 						}
-						tags = append(tags, tag)
 					}
-					// Remove duplicates after mapping
-					tags = uniqueStrings(tags)
-					sort.Strings(tags)
 				}
-				summary = mws.SummaryData.Summary
+				tags = withUserTags(tags, mws.SummaryData)
 			}
 
-			templateData := map[string]interface{}{
-				"Date":         m.CreatedAt.Local().Format("2006-01-02"),
-				"Time":         m.CreatedAt.Local().Format("15:04"),
-				"Title":        m.Title,
-				"Description":  description,
-				"Tags":         tags,
-				"Participants": participantsStr,
-				"MeetingID":    m.ID,
-				"Summary":      summary,
-			}
-
-			// Write summary file
-			summaryFileName := fmt.Sprintf("%s-summary.md", m.ID)
-			summaryFilePath := filepath.Join(meetingsPath, summaryFileName)
-
-			// Handle selective field updates if --update-fields is specified
-			if len(updateFields) > 0 && fileExists(summaryFilePath) {
-				// Read existing file and update only specified fields
-				existingFrontmatter, body, err := parseFrontmatter(summaryFilePath)
-				if err != nil {
-					fmt.Printf("  ⚠ Error parsing existing file %s: %v\n", summaryFileName, err)
-					continue
-				}
+			// Copy chat attachments into the vault
+			attachmentLinks, err := copyAttachmentsToVault(obsidianVaultPath, attachmentsDir, m, cache)
+			if err != nil {
+				fmt.Printf("  ⚠ Error copying attachments: %v\n", err)
+			}
 
-				// Update only specified fields
-				updatedFrontmatter := updateFrontmatterFields(existingFrontmatter, templateData, updateFields)
+			// Transcript timestamps counted from the recording start are shifted to the meeting start
+			offset := transcriptTimeOffset(m)
+			if offset != 0 {
+				fmt.Printf("  ⏱  Shifting transcript timestamps by %+.0fs to the meeting start\n", offset)
+			}
 
-				// Write back with updated fields
-				if err := writeFrontmatterFile(summaryFilePath, updatedFrontmatter, body); err != nil {
-					fmt.Printf("  ⚠ Error updating summary file: %v\n", err)
-					continue
+			templateData := map[string]interface{}{
+				"Date":               m.CreatedAt.Local().Format("2006-01-02"),
+				"Time":               m.CreatedAt.Local().Format("15:04"),
+				"Title":              m.Title,
+				"Aliases":            aliases,
+				"Description":        description,
+				"Tags":               tags,
+				"Participants":       participantsStr,
+				"Teams":              meetingTeams(m),
+				"MeetingID":          m.ID,
+				"Account":            m.Account,
+				"TranscriptCoverage": formatCoverage(m),
+				"TranscriptStatus":   status,
+				"Visibility":         visibility,
+				"Summary":            summary,
+
+				"DescriptionBlock":   renderDescriptionBlock(description),
+				"TranscriptLink":     transcriptLink,
+				"Omissions":          renderOmissions(mws.SummaryData),
+				"SinceLastTime":      renderSinceLastTime(mws.SummaryData, cache),
+				"Agenda":             renderAgenda(mws.SummaryData, m.ID, offset),
+				"Topics":             renderTopics(mws.SummaryData),
+				"TopicDetails":       renderTopicDetails(mws.SummaryData),
+				"Decisions":          renderDecisions(mws.SummaryData),
+				"NotableQuotes":      renderNotableQuotes(mws.SummaryData, m.ID, offset),
+				"ActionItems":        renderActionItems(mws.SummaryData),
+				"ChatAndAttachments": renderChatAndAttachments(m, attachmentLinks),
+				"UserSections":       renderUserSections(),
+			}
+			templateData["Sections"] = renderSections(sections, templateData)
+			if status != "" {
+				var audio *audioTarget
+				if !confidential {
+					if audio, err = copyRecordingToVault(obsidianVaultPath, attachmentsDir, m, cache); err != nil {
+						fmt.Printf("  ⚠ Error copying recording: %v\n", err)
+					}
 				}
+				templateData["Sections"] = renderAudioOnlyBody(status, audio)
+			}
 
-				fmt.Printf("  ✓ Updated fields %v in: %s\n", updateFields, summaryFileName)
-			} else {
-				// Standard sync: render and write full file
-				var summaryBuf bytes.Buffer
-				if err := tmpl.Execute(&summaryBuf, templateData); err != nil {
-					fmt.Printf("  ⚠ Error rendering template for %s: %v\n", m.ID, err)
+			// Meetings already noted by hand (or by another tool) get the summary added to that
+			// note, once there is one
+			var notePath string
+			if existingPath := dedupeIndex.Match(m, aliases...); existingPath != "" && status == "" {
+				if err := mergeIntoExistingNote(existingPath, m, sections, templateData); err != nil {
+					fmt.Printf("  ⚠ Error adding summary to existing note: %v\n", err)
+					recordFailure(syncState, stageSync, m.ID, err)
 					continue
 				}
+				dedupeIndex.Claim(m, existingPath)
+				notePath = existingPath
+
+				// The audio-only note written while waiting for the transcript isn't needed anymore
+				stubPath := filepath.Join(meetingsPath, m.ID+"-summary.md")
+				if mws.Rewrite && vaultWriter.Exists(stubPath) {
+					if err := vaultWriter.DeleteNote(stubPath); err != nil {
+						fmt.Printf("  ⚠ Error removing audio-only note: %v\n", err)
+					}
+				}
+				fmt.Printf("  🔗 Added summary to existing note: %s\n", vaultRelative(obsidianVaultPath, existingPath))
+				result.SummaryNotes = append(result.SummaryNotes, existingPath)
+			} else {
+				// Write summary file
+				summaryFileName := fmt.Sprintf("%s-summary.md", m.ID)
+				summaryFilePath := filepath.Join(meetingsPath, summaryFileName)
+				notePath = summaryFilePath
+
+				// Handle selective field updates if --update-fields is specified
+				if len(updateFields) > 0 && vaultWriter.Exists(summaryFilePath) {
+					// Rewrite only the specified fields, leaving the rest of the file as it was
+					if err := editFrontmatterFile(summaryFilePath, updateFrontmatterFields(templateData, updateFields), nil); err != nil {
+						fmt.Printf("  ⚠ Error updating summary file %s: %v\n", summaryFileName, err)
+						recordFailure(syncState, stageSync, m.ID, err)
+						continue
+					}
 
-				if !testMode && fileExists(summaryFilePath) {
-					fmt.Printf("  ⏭  Summary exists, skipping: %s\n", summaryFileName)
+					fmt.Printf("  ✓ Updated fields %v in: %s\n", updateFields, summaryFileName)
 				} else {
-					if err := os.WriteFile(summaryFilePath, summaryBuf.Bytes(), 0644); err != nil {
-						fmt.Printf("  ⚠ Error writing summary file: %v\n", err)
+					// Standard sync: render and write full file
+					var summaryBuf bytes.Buffer
+					if err := tmpl.Execute(&summaryBuf, templateData); err != nil {
+						fmt.Printf("  ⚠ Error rendering template for %s: %v\n", m.ID, err)
+						recordFailure(syncState, stageSync, m.ID, err)
 						continue
 					}
-					if testMode {
-						fmt.Printf("  ✓ Overwrote summary: %s\n", summaryFileName)
+
+					if !force && !mws.Rewrite && !noteOutdated(m.ID) && vaultWriter.Exists(summaryFilePath) {
+						fmt.Printf("  ⏭  Summary exists, skipping: %s\n", summaryFileName)
+						if err := stampSyncedVersion(summaryFilePath); err != nil {
+							fmt.Printf("  ⚠ Error adding %s: %v\n", syncedVersionKey, err)
+						}
+
+						// Titles may have been improved since the note was written - keep aliases current
+						if updated, err := refreshAliases(summaryFilePath, aliases); err != nil {
+							fmt.Printf("  ⚠ Error refreshing aliases: %v\n", err)
+						} else if updated {
+							fmt.Printf("  ✓ Updated aliases in: %s\n", summaryFileName)
+						}
 					} else {
-						fmt.Printf("  ✓ Created summary: %s\n", summaryFileName)
+						rendered := summaryBuf.Bytes()
+						if confidential {
+							rendered = redactEmails(rendered, m)
+						}
+						if !force {
+							rendered = withSyncedVersion(rendered)
+						}
+						content := appendProvenance(rendered, Provenance{
+							Prompt:      promptVersion(),
+							Model:       summaryModel(mws.SummaryData),
+							GeneratedAt: time.Now(),
+							MeetingID:   m.ID,
+						})
+						if batch.Exists(summaryFilePath) {
+							if existing, err := batch.ReadNote(summaryFilePath); err == nil {
+								content = regenerateNote(summaryFilePath, withoutTranscriptStatus(existing), content)
+							}
+						}
+						if err := batch.CreateNote(summaryFilePath, content); err != nil {
+							fmt.Printf("  ⚠ Error writing summary file: %v\n", err)
+							recordFailure(syncState, stageSync, m.ID, err)
+							continue
+						}
+						if status != "" {
+							fmt.Printf("  🎧 Wrote audio-only note (transcript %s): %s\n", status, summaryFileName)
+						} else if mws.Rewrite {
+							fmt.Printf("  ✓ Replaced audio-only note with summary: %s\n", summaryFileName)
+						} else if force {
+							fmt.Printf("  ✓ Overwrote summary: %s\n", summaryFileName)
+						} else {
+							fmt.Printf("  ✓ Created summary: %s\n", summaryFileName)
+						}
+						result.SummaryNotes = append(result.SummaryNotes, summaryFilePath)
 					}
 				}
+
 			}
 
-			// Generate transcript file (skip if exists unless in test mode)
-			transcriptFileName := fmt.Sprintf("%s-transcript.md", m.ID)
-			transcriptFilePath := filepath.Join(meetingsPath, transcriptFileName)
-			if !testMode && fileExists(transcriptFilePath) {
-				fmt.Printf("  ⏭  Transcript exists, skipping: %s\n", transcriptFileName)
-			} else {
-				transcriptContent := generateTranscriptContent(m)
-				if err := os.WriteFile(transcriptFilePath, []byte(transcriptContent), 0644); err != nil {
-					fmt.Printf("  ⚠ Error writing transcript file: %v\n", err)
-					continue
-				}
-				if testMode {
-					fmt.Printf("  ✓ Overwrote transcript: %s\n", transcriptFileName)
-				} else {
-					fmt.Printf("  ✓ Created transcript: %s\n", transcriptFileName)
+			// Shareable version of the note, without the transcript or internal tags (a test
+			// run only writes to the sandbox)
+			if shareTo != "" && !testMode && status == "" && !confidential && mws.SummaryData != nil {
+				if path, changed, err := exportShareNote(shareTo, m, mws.SummaryData, tags, sections); err != nil {
+					fmt.Printf("  ⚠ Error exporting shareable note: %v\n", err)
+				} else if changed && dryRun {
+					fmt.Printf("  📤 Would export shareable note: %s\n", filepath.Base(path))
+				} else if changed {
+					fmt.Printf("  📤 Exported shareable note: %s\n", filepath.Base(path))
 				}
 			}
 
-			// Mark meeting as synced to Obsidian (skip in test mode)
-			if !testMode {
-				syncState.ObsidianSyncedMeetings[m.ID] = true
-
-				// Save state after each meeting sync
-				if err := syncState.Save(); err != nil {
-					fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+			// Notify about ALERT_KEYWORDS found when the meeting was summarized (not from a test run)
+			if status == "" && !testMode && mws.SummaryData != nil && sendKeywordAlerts(obsidianVaultPath, notePath, m, mws.SummaryData, confidential) {
+				if err := cache.SaveSummary(m.ID, mws.SummaryData); err != nil {
+					fmt.Printf("  ⚠ Error recording sent alerts: %v\n", err)
 				}
 			}
 
-			successCount++
-		}
-
-		// Create or update daily note with Dataview query
-		filename := fmt.Sprintf("%s-%s.md", date, dayName)
-		filePath := filepath.Join(dailyNotesPath, filename)
+			// People notes for participants from the people directory
+			writePeopleNotes(obsidianVaultPath, m)
 
-		dailyNoteData := map[string]string{
-			"Date":      date,
-			"YearPath":  year,
-			"MonthPath": monthNum + "-" + monthName,
-		}
+			// Running per-person log of 1:1s
+			if status == "" {
+				if err := writeOneOnOneLog(obsidianVaultPath, m, mws.SummaryData, testMode); err != nil {
+					fmt.Printf("  ⚠ Error updating 1:1 log: %v\n", err)
+				}
+			}
 
-		if fileExists(filePath) {
-			// Update existing daily note's Dataview query
-			if err := updateDailyNoteDataview(filePath, dailyNoteData); err != nil {
-				fmt.Printf("  ⚠ Error updating daily note Dataview: %v\n", err)
-			} else {
-				fmt.Printf("  ✓ Updated daily note Dataview: %s\n", filename)
+			// Formal minutes for board and steering meetings
+			if path, written, err := writeMinutesNote(obsidianVaultPath, m, mws.SummaryData, rewriteMinutes); err != nil {
+				fmt.Printf("  ⚠ Error writing minutes: %v\n", err)
+			} else if written {
+				fmt.Printf("  ✓ Wrote minutes: %s\n", filepath.Base(path))
 			}
-		} else {
-			// Create new daily note with Dataview query
-			dailyNoteTmpl, err := template.New("dailynote").Parse(dailyNoteTemplate)
-			if err != nil {
-				fmt.Printf("  ⚠ Error parsing daily note template: %v\n", err)
-				continue
+
+			// Generate transcript file once there is a transcript, unless the meeting is
+			// confidential (skip if exists unless in test mode). Confidential meetings lose
+			// the transcript note written before a rule or review made them confidential.
+			transcriptFileName := fmt.Sprintf("%s-transcript.md", m.ID)
+			transcriptFilePath := filepath.Join(meetingsPath, transcriptFileName)
+			if confidential && batch.Exists(transcriptFilePath) {
+				if err := batch.DeleteNote(transcriptFilePath); err != nil {
+					fmt.Printf("  ⚠ Error removing transcript of confidential meeting: %v\n", err)
+					recordFailure(syncState, stageSync, m.ID, err)
+					continue
+				}
+				fmt.Printf("  🔒 Removed transcript of confidential meeting: %s\n", transcriptFileName)
+			} else if status == "" && !confidential {
+				if !force && vaultWriter.Exists(transcriptFilePath) {
+					fmt.Printf("  ⏭  Transcript exists, skipping: %s\n", transcriptFileName)
+				} else {
+					transcriptContent := renderTranscriptNote(obsidianVaultPath, attachmentsDir, m, mws.SummaryData, cache)
+					if batch.Exists(transcriptFilePath) {
+						if existing, err := batch.ReadNote(transcriptFilePath); err == nil {
+							transcriptContent = regenerateNote(transcriptFilePath, existing, transcriptContent)
+						}
+					}
+					if err := batch.CreateNote(transcriptFilePath, transcriptContent); err != nil {
+						fmt.Printf("  ⚠ Error writing transcript file: %v\n", err)
+						recordFailure(syncState, stageSync, m.ID, err)
+						continue
+					}
+					if force {
+						fmt.Printf("  ✓ Overwrote transcript: %s\n", transcriptFileName)
+					} else {
+						fmt.Printf("  ✓ Created transcript: %s\n", transcriptFileName)
+					}
+				}
 			}
 
-			var dailyNoteBuf bytes.Buffer
-			if err := dailyNoteTmpl.Execute(&dailyNoteBuf, dailyNoteData); err != nil {
-				fmt.Printf("  ⚠ Error rendering daily note template: %v\n", err)
-				continue
+			if writeDaily {
+				if err := writeDailyNote(batch, obsidianVaultPath, dailyPath, t, cache); err != nil {
+					fmt.Printf("  ⚠ Error writing daily note: %v\n", err)
+					recordFailure(syncState, stageSync, m.ID, err)
+					continue
+				}
 			}
 
-			if err := os.WriteFile(filePath, dailyNoteBuf.Bytes(), 0644); err != nil {
-				fmt.Printf("  ⚠ Error writing daily note: %v\n", err)
+			if err := batch.Commit(); err != nil {
+				fmt.Printf("  ⚠ Error writing meeting notes: %v\n", err)
+				recordFailure(syncState, stageSync, m.ID, err)
 				continue
 			}
-
-			fmt.Printf("  ✓ Created daily note: %s (with Dataview query)\n", filename)
+			// The note carries these tags now (test runs only write to the sandbox)
+			if !testMode {
+				syncState.RecordTagUse(tags, m.CreatedAt)
+			}
+			if !force {
+				syncState.MarkObsidianSynced(m.ID)
+				if status != "" {
+					syncState.MarkAudioOnly(m.ID, status)
+				} else {
+					syncState.ClearAudioOnly(m.ID)
+				}
+			}
+			daySynced++
+			successCount++
 		}
+		batch.Discard()
+		meetingSpan.End()
 
-		fmt.Printf("  ✓ Synced %d meeting file(s)\n", len(dayMeetings))
+		if writeDaily && daySynced > 0 {
+			if dailyExisted {
+				fmt.Printf("  ✓ Updated daily note Dataview: %s\n", dailyFileName)
+			} else {
+				fmt.Printf("  ✓ Created daily note: %s (with Dataview query)\n", dailyFileName)
+			}
+			result.DailyNotes = append(result.DailyNotes, dailyPath)
+		}
+		fmt.Printf("  ✓ Synced %d meeting file(s)\n", daySynced)
 	}
 
 	fmt.Printf("\n✅ Synced %d meeting(s) to %d daily note(s)\n", successCount, len(meetingsByDate))
-	return nil
+	return result, nil
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testVault runs sync against an in-memory vault, with the cache and state in a
+// temporary directory
+type testVault struct {
+	t      *testing.T
+	path   string
+	writer *MemoryVaultWriter
+	cache  *Cache
+	state  *SyncState
+}
+
+// newTestVault installs an in-memory vault writer for the duration of a test
+func newTestVault(t *testing.T) *testVault {
+	t.Helper()
+	dir := t.TempDir()
+	previousWriter, previousDataDir := vaultWriter, dataDir
+	t.Cleanup(func() { vaultWriter, dataDir, runFailures = previousWriter, previousDataDir, nil })
+
+	dataDir = filepath.Join(dir, "data")
+	writer := NewMemoryVaultWriter()
+	vaultWriter = writer
+	return &testVault{
+		t:      t,
+		path:   filepath.Join(dir, "vault"), // never created: every note lives in writer
+		writer: writer,
+		cache:  NewCache(filepath.Join(dir, "meetings")),
+		state:  loadSyncState(filepath.Join(dir, "state.json")),
+	}
+}
+
+// addMeeting caches a downloaded and summarized meeting with a two-speaker transcript
+func (v *testVault) addMeeting(id, title string, createdAt time.Time, summary *SummaryData) *Meeting {
+	v.t.Helper()
+	m := &Meeting{ID: id, Title: title, CreatedAt: createdAt, Duration: 600}
+	m.Speakers.Data = map[string]SpeakerInfo{}
+	for index, name := range map[string]string{"1": "Ada Lovelace", "2": "Alan Turing"} {
+		var info SpeakerInfo
+		info.Person.FirstName, info.Person.LastName, _ = strings.Cut(name, " ")
+		m.Speakers.Data[index] = info
+	}
+	segments := []Segment{
+		{SpeakerIndex: 1, ID: 1, Speech: Speech{Start: 0, End: 5, Text: "Let's go over the launch plan."}},
+		{SpeakerIndex: 2, ID: 2, Speech: Speech{Start: 5, End: 12, Text: "The beta ships on Friday."}},
+	}
+	content, err := json.Marshal(segments)
+	if err != nil {
+		v.t.Fatal(err)
+	}
+	m.Resources.Transcript.Status = "uploaded"
+	m.Resources.Transcript.Content = string(content)
+
+	if err := v.cache.SaveMeeting(m); err != nil {
+		v.t.Fatal(err)
+	}
+	if err := v.cache.SaveSummary(id, summary); err != nil {
+		v.t.Fatal(err)
+	}
+	v.state.SyncedMeetings[id] = true
+	v.state.SummarizedMeetings[id] = true
+	return m
+}
+
+// sync runs the sync stage, failing the test on errors
+func (v *testVault) sync(overwrite bool, meetingIDs ...string) *SyncResult {
+	v.t.Helper()
+	result, err := runSync(context.Background(), v.path, 0, v.state, overwrite, false, false, meetingIDs, nil, v.cache)
+	if err != nil {
+		v.t.Fatalf("sync failed: %v", err)
+	}
+	if len(runFailures) > 0 {
+		v.t.Fatalf("sync recorded failures: %v", runFailures[0].Err)
+	}
+	return result
+}
+
+// text returns a note's content, failing the test if it doesn't exist
+func (v *testVault) text(path string) string {
+	v.t.Helper()
+	content, err := v.writer.ReadNote(path)
+	if err != nil {
+		v.t.Fatalf("note %s: %v\nnotes: %v", path, err, v.writer.Files())
+	}
+	return string(content)
+}
+
+// note returns a note's frontmatter and body, failing the test if it doesn't exist
+func (v *testVault) note(path string) (map[string]interface{}, string) {
+	v.t.Helper()
+	fm, body, err := splitFrontmatter([]byte(v.text(path)))
+	if err != nil {
+		v.t.Fatalf("note %s: %v", path, err)
+	}
+	return fm, body
+}
+
+func TestSyncWritesSummaryAndTranscriptNotes(t *testing.T) {
+	v := newTestVault(t)
+	m := v.addMeeting("m1", "Launch planning", time.Date(2025, 3, 14, 10, 0, 0, 0, time.Local), &SummaryData{
+		Description: "Planning the beta launch",
+		Tags:        "launch, planning",
+		Summary:     "The beta ships on Friday.",
+		ActionItems: []ActionItem{{Text: "Send the beta invites", Owner: "Ada Lovelace"}},
+	})
+
+	v.sync(false)
+
+	if !v.state.ObsidianSyncedMeetings[m.ID] {
+		t.Errorf("meeting not marked synced")
+	}
+	fm, body := v.note(summaryNotePath(v.path, m))
+	if fm["title"] == nil || !strings.Contains(body, "The beta ships on Friday.") {
+		t.Errorf("summary note is missing its title or summary:\n%v\n%s", fm, body)
+	}
+	if !strings.Contains(body, "Send the beta invites") {
+		t.Errorf("summary note is missing the action item:\n%s", body)
+	}
+	transcript := v.text(transcriptNotePath(v.path, m))
+	if !strings.Contains(transcript, "Ada Lovelace") || !strings.Contains(transcript, "The beta ships on Friday.") {
+		t.Errorf("transcript note is missing speakers or text:\n%s", transcript)
+	}
+}
+
+func TestSyncSkipsSyncedMeetingsUnlessOverwritten(t *testing.T) {
+	v := newTestVault(t)
+	m := v.addMeeting("m1", "Weekly sync", time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local), &SummaryData{
+		Description: "Weekly team sync",
+		Tags:        "team",
+		Summary:     "First summary.",
+	})
+	v.sync(false)
+
+	// A hand edit survives a run with nothing new to sync
+	path := summaryNotePath(v.path, m)
+	if err := v.writer.CreateNote(path, []byte("---\ntitle: edited\n---\nMy notes\n")); err != nil {
+		t.Fatal(err)
+	}
+	v.sync(false)
+	if _, body := v.note(path); body != "My notes\n" {
+		t.Errorf("an up-to-date meeting was re-synced:\n%s", body)
+	}
+
+	// Overwriting re-renders the note from the cached summary
+	summary, err := v.cache.LoadSummary(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary.Summary = "Second summary."
+	if err := v.cache.SaveSummary(m.ID, summary); err != nil {
+		t.Fatal(err)
+	}
+	v.sync(true, m.ID)
+	if _, body := v.note(path); !strings.Contains(body, "Second summary.") {
+		t.Errorf("overwrite didn't re-render the note:\n%s", body)
+	}
+}
+
+func TestSyncOfSpecificMeetingsRecordsTagUse(t *testing.T) {
+	v := newTestVault(t)
+	created := time.Date(2025, 3, 12, 15, 0, 0, 0, time.Local)
+	m := v.addMeeting("m1", "Roadmap review", created, &SummaryData{
+		Description: "Reviewing the roadmap",
+		Tags:        "roadmap",
+		Summary:     "The roadmap was agreed.",
+	})
+
+	v.sync(true, m.ID)
+
+	if got := v.state.TagLastUsed["roadmap"]; !got.Equal(created) {
+		t.Errorf("tag use not recorded for a --meeting sync: got %v, want %v", got, created)
+	}
+}
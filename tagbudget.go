@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Defaults for how many existing tags go into a summary prompt
+const (
+	defaultPromptTagsMax = 150 // tags offered to the LLM per meeting
+	defaultPromptTagsTop = 50  // of which the vault's most used tags, always offered
+)
+
+// promptTagLimits reads PROMPT_TAGS_MAX and PROMPT_TAGS_TOP. A max of 0 offers every tag.
+func promptTagLimits() (max, top int) {
+	max, top = defaultPromptTagsMax, defaultPromptTagsTop
+	for _, setting := range []struct {
+		env   string
+		value *int
+	}{{"PROMPT_TAGS_MAX", &max}, {"PROMPT_TAGS_TOP", &top}} {
+		v := strings.TrimSpace(os.Getenv(setting.env))
+		if v == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			*setting.value = n
+		} else {
+			fmt.Printf("⚠ Ignoring invalid %s %q\n", setting.env, v)
+		}
+	}
+	if top > max && max > 0 {
+		top = max
+	}
+	return max, top
+}
+
+// transcriptWordCounts counts the lowercase words of a transcript
+func transcriptWordCounts(transcript string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(transcript), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		counts[word]++
+	}
+	return counts
+}
+
+// tagRelevance scores how much a transcript talks about a tag: how often the words of the
+// tag ("project/apollo-launch" -> project, apollo, launch) are mentioned, or 0 unless every
+// word of three letters or more is. Plurals count toward the singular and back.
+func tagRelevance(tag string, words map[string]int) int {
+	score := 0
+	for _, part := range strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(part) < 3 {
+			continue
+		}
+		n := words[part] + words[part+"s"]
+		if singular := strings.TrimSuffix(part, "s"); singular != part {
+			n += words[singular]
+		}
+		if n == 0 {
+			return 0
+		}
+		score += n
+	}
+	return score
+}
+
+// selectPromptTags picks the existing tags offered to the LLM for a transcript, instead of
+// the whole dictionary: the PROMPT_TAGS_TOP most used tags (tags are ordered by use), then
+// the tags the transcript mentions most, up to PROMPT_TAGS_MAX in total
+func selectPromptTags(tags []string, transcript string) []string {
+	max, top := promptTagLimits()
+	if max == 0 || len(tags) <= max {
+		return tags
+	}
+
+	selected := append([]string(nil), tags[:top]...)
+	words := transcriptWordCounts(transcript)
+	type candidate struct {
+		tag   string
+		score int
+		rank  int
+	}
+	var relevant []candidate
+	for i, tag := range tags[top:] {
+		if score := tagRelevance(tag, words); score > 0 {
+			relevant = append(relevant, candidate{tag: tag, score: score, rank: i})
+		}
+	}
+	sort.Slice(relevant, func(i, j int) bool {
+		if relevant[i].score != relevant[j].score {
+			return relevant[i].score > relevant[j].score
+		}
+		return relevant[i].rank < relevant[j].rank
+	})
+	for _, c := range relevant {
+		if len(selected) >= max {
+			break
+		}
+		selected = append(selected, c.tag)
+	}
+	return selected
+}
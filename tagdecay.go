@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag retirement defaults
+const (
+	defaultTagRetireMonths = 6
+	retiredTagsFile        = "retired-tags.json"
+)
+
+// RetiredTag is a tag taken out of the dictionary fed to the LLM
+type RetiredTag struct {
+	Tag       string    `json:"tag"`
+	RetiredAt time.Time `json:"retired_at"`
+	LastUsed  time.Time `json:"last_used,omitempty"` // zero if it was never applied to a meeting
+}
+
+// RecordTagUse notes that tags were applied to a meeting, keeping the latest meeting date per tag
+func (s *SyncState) RecordTagUse(tags []string, at time.Time) {
+	for _, tag := range tags {
+		if !s.TagLastUsed[tag].Before(at) {
+			continue
+		}
+		if s.TagLastUsed == nil {
+			s.TagLastUsed = make(map[string]time.Time)
+		}
+		s.TagLastUsed[tag] = at
+		s.pending++
+	}
+}
+
+// tagRetireMonths reads TAG_RETIRE_MONTHS
+func tagRetireMonths() (int, error) {
+	v := strings.TrimSpace(os.Getenv("TAG_RETIRE_MONTHS"))
+	if v == "" {
+		return defaultTagRetireMonths, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid TAG_RETIRE_MONTHS %q: must be a positive number of months", v)
+	}
+	return n, nil
+}
+
+// loadRetiredTags reads retired-tags.json, keyed by tag
+func loadRetiredTags() (map[string]RetiredTag, error) {
+	retired := make(map[string]RetiredTag)
+	data, err := os.ReadFile(dataPath(retiredTagsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return retired, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", retiredTagsFile, err)
+	}
+	var list []RetiredTag
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", retiredTagsFile, err)
+	}
+	for _, t := range list {
+		retired[t.Tag] = t
+	}
+	return retired, nil
+}
+
+// saveRetiredTags writes retired-tags.json, sorted by tag
+func saveRetiredTags(retired map[string]RetiredTag) error {
+	list := make([]RetiredTag, 0, len(retired))
+	for _, t := range retired {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Tag < list[j].Tag })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal retired tags: %w", err)
+	}
+	return os.WriteFile(dataPath(retiredTagsFile), data, 0644)
+}
+
+// withoutRetiredTags drops retired tags from the dictionary fed to the LLM
+func withoutRetiredTags(tags []string) []string {
+	retired, err := loadRetiredTags()
+	if err != nil {
+		fmt.Printf("⚠ Warning: %v\n", err)
+		return tags
+	}
+	return dropRetiredTags(tags, retired)
+}
+
+// dropRetiredTags returns tags without the retired ones
+func dropRetiredTags(tags []string, retired map[string]RetiredTag) []string {
+	if len(retired) == 0 {
+		return tags
+	}
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := retired[tag]; !ok {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// tagLastUses returns when each tag was last applied to a meeting: recorded by sync, and
+// for meetings summarized before that was tracked, found in the cached summaries
+func tagLastUses(syncState *SyncState, cache *Cache) (map[string]time.Time, error) {
+	lastUsed := make(map[string]time.Time, len(syncState.TagLastUsed))
+	for tag, at := range syncState.TagLastUsed {
+		lastUsed[tag] = at
+	}
+
+	ids, err := cache.MeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !cache.SummaryExists(id) {
+			continue
+		}
+		summary, err := cache.LoadSummary(id)
+		if err != nil {
+			continue
+		}
+		m, err := cache.LoadMeeting(id)
+		if err != nil {
+			continue
+		}
+		for _, tag := range splitTags(summary.Tags) {
+			if lastUsed[tag].Before(m.CreatedAt) {
+				lastUsed[tag] = m.CreatedAt
+			}
+		}
+	}
+	return lastUsed, nil
+}
+
+// staleTag is a dictionary tag proposed for retirement
+type staleTag struct {
+	Tag      string
+	LastUsed time.Time // zero if never applied to a meeting
+}
+
+// findStaleTags returns the dictionary's tags not applied to a meeting since cutoff, least
+// recently used first
+func findStaleTags(dictionary []string, lastUsed map[string]time.Time, retired map[string]RetiredTag, cutoff time.Time) []staleTag {
+	var stale []staleTag
+	for _, tag := range dictionary {
+		if _, ok := retired[tag]; ok {
+			continue
+		}
+		if at := lastUsed[tag]; at.Before(cutoff) {
+			stale = append(stale, staleTag{Tag: tag, LastUsed: at})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if !stale[i].LastUsed.Equal(stale[j].LastUsed) {
+			return stale[i].LastUsed.Before(stale[j].LastUsed)
+		}
+		return stale[i].Tag < stale[j].Tag
+	})
+	return stale
+}
+
+// describeLastUse formats when a tag was last applied
+func describeLastUse(at time.Time) string {
+	if at.IsZero() {
+		return "never used in a meeting"
+	}
+	return "last used " + at.Local().Format("2006-01-02")
+}
+
+// reviewStaleTags asks which stale tags to retire: retire, keep, retire all or quit
+func reviewStaleTags(reader *bufio.Reader, stale []staleTag) []staleTag {
+	var retire []staleTag
+	all := false
+
+	fmt.Println("Commands: [r]etire  [k]eep  [R]etire all  [q]uit (keep the rest)")
+	for i, t := range stale {
+		cmd := "r"
+		if !all {
+			fmt.Printf("\n[%d/%d] %s (%s)\n> ", i+1, len(stale), t.Tag, describeLastUse(t.LastUsed))
+			line, err := reader.ReadString('\n')
+			if err != nil && line == "" {
+				fmt.Println()
+				return retire
+			}
+			cmd = strings.TrimSpace(line)
+		}
+
+		switch cmd {
+		case "R":
+			all = true
+			fallthrough
+		case "r":
+			retire = append(retire, t)
+			fmt.Printf("  🪦 Retiring %s\n", t.Tag)
+		case "q":
+			return retire
+		default:
+			fmt.Printf("  ⏭  Keeping %s\n", t.Tag)
+		}
+	}
+	return retire
+}
+
+// removeTagsFromMeetingNotes removes tags from the frontmatter of the vault's meeting summary
+// notes, leaving other notes alone. Returns how many notes changed.
+func removeTagsFromMeetingNotes(vaultPath string, tags map[string]bool) (int, error) {
+	changed := 0
+	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != vaultPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(info.Name(), "-summary.md") || filepath.Base(filepath.Dir(path)) != "meetings" {
+			return nil
+		}
+		fm, _, err := parseFrontmatter(path)
+		if err != nil {
+			return nil
+		}
+		noteTags := frontmatterList(fm["tags"])
+		kept := make([]string, 0, len(noteTags))
+		for _, tag := range noteTags {
+			if !tags[tag] {
+				kept = append(kept, tag)
+			}
+		}
+		if len(kept) == len(noteTags) {
+			return nil
+		}
+		if err := editFrontmatterFile(path, map[string]interface{}{"tags": kept}, nil); err != nil {
+			fmt.Printf("  ⚠ Error updating %s: %v\n", vaultRelative(vaultPath, path), err)
+			return nil
+		}
+		changed++
+		return nil
+	})
+	return changed, err
+}
+
+// runRetireTags proposes retiring tags not applied to a meeting for TAG_RETIRE_MONTHS: retired
+// tags are no longer offered to the LLM, and can also be removed from meeting notes
+func runRetireTags(vaultPath string, syncState *SyncState, cache *Cache, dryRun bool) error {
+	fmt.Println("\n=== Retire tags: Finding tags that are no longer used ===")
+
+	months, err := tagRetireMonths()
+	if err != nil {
+		return err
+	}
+	dictionary, err := loadObsidianTags()
+	if err != nil {
+		return err
+	}
+	if len(dictionary) == 0 {
+		fmt.Println("⚠ No tag dictionary yet. Run krisp-sync extract-tags first.")
+		return nil
+	}
+	retired, err := loadRetiredTags()
+	if err != nil {
+		return err
+	}
+	lastUsed, err := tagLastUses(syncState, cache)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, -months, 0)
+	stale := findStaleTags(dictionary, lastUsed, retired, cutoff)
+	fmt.Printf("📊 %d of %d tag(s) not used in a meeting for %d month(s) (%d already retired)\n", len(stale), len(dictionary), months, len(retired))
+	if len(stale) == 0 {
+		return nil
+	}
+	if dryRun {
+		for _, t := range stale {
+			fmt.Printf("  🏷  %s (%s)\n", t.Tag, describeLastUse(t.LastUsed))
+		}
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	retire := reviewStaleTags(reader, stale)
+	if len(retire) == 0 {
+		fmt.Println("\n✅ No tags retired")
+		return nil
+	}
+
+	now := time.Now()
+	remove := make(map[string]bool, len(retire))
+	for _, t := range retire {
+		retired[t.Tag] = RetiredTag{Tag: t.Tag, RetiredAt: now, LastUsed: t.LastUsed}
+		remove[t.Tag] = true
+	}
+	if err := saveRetiredTags(retired); err != nil {
+		return err
+	}
+	fmt.Printf("\n✅ Retired %d tag(s); they are no longer suggested to the LLM (%s)\n", len(retire), dataPath(retiredTagsFile))
+
+	if askYesNo(reader, fmt.Sprintf("Also remove the %d retired tag(s) from meeting notes in the vault?", len(retire))) {
+		changed, err := removeTagsFromMeetingNotes(vaultPath, remove)
+		if err != nil {
+			return fmt.Errorf("error scanning vault: %w", err)
+		}
+		fmt.Printf("✅ Removed retired tags from %d meeting note(s)\n", changed)
+	}
+	return nil
+}
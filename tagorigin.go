@@ -0,0 +1,157 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Where a meeting's tag came from
+const (
+	tagOriginLLM        = "llm"        // generated with the summary
+	tagOriginMapping    = "mapping"    // canonical tag from the normalization mappings
+	tagOriginSuggestion = "suggestion" // added by co-occurrence suggestions
+	tagOriginUser       = "user"       // added by hand to the note in the vault
+)
+
+// recordTagOrigin notes where a tag came from, keeping an origin recorded earlier
+func recordTagOrigin(summaryData *SummaryData, tag, origin string) {
+	if summaryData.TagOrigins == nil {
+		summaryData.TagOrigins = make(map[string]string)
+	}
+	if _, ok := summaryData.TagOrigins[tag]; !ok {
+		summaryData.TagOrigins[tag] = origin
+	}
+}
+
+// carryUserTags copies the hand-added tags of a previous summary into a regenerated one,
+// so re-summarizing never loses them. The previous summary's generated tags are remembered
+// with their origin (without being added), so claimUserTags doesn't mistake them for the
+// user's when they are still on the note.
+func carryUserTags(previous, summaryData *SummaryData) {
+	if previous == nil {
+		return
+	}
+	for _, tag := range splitTags(previous.Tags) {
+		if origin := previous.TagOrigins[tag]; origin != tagOriginUser {
+			recordTagOrigin(summaryData, tag, firstNonEmpty(origin, tagOriginLLM))
+		}
+	}
+	for tag, origin := range previous.TagOrigins {
+		if origin != tagOriginUser {
+			recordTagOrigin(summaryData, tag, origin)
+		}
+	}
+
+	tags := splitTags(summaryData.Tags)
+	for _, tag := range sortedUserTags(previous.TagOrigins) {
+		if summaryData.TagOrigins == nil {
+			summaryData.TagOrigins = make(map[string]string)
+		}
+		summaryData.TagOrigins[tag] = tagOriginUser
+		tags = append(tags, tag)
+	}
+	summaryData.Tags = strings.Join(uniqueStrings(tags), ", ")
+}
+
+// meetingTags returns a summary's tags with the normalization mappings applied and retired
+// tags left out, recording the origin of each
+func meetingTags(summaryData *SummaryData, tagMappings map[string]string, retired map[string]RetiredTag) []string {
+	if summaryData == nil || summaryData.Tags == "" {
+		return nil
+	}
+
+	var tags []string
+	for _, tag := range splitTags(summaryData.Tags) {
+		if canonical, ok := tagMappings[tag]; ok && canonical != tag {
+			tag = canonical
+			recordTagOrigin(summaryData, tag, tagOriginMapping)
+		} else {
+			recordTagOrigin(summaryData, tag, tagOriginLLM)
+		}
+		tags = append(tags, tag)
+	}
+
+	// Remove duplicates after mapping, and tags retired since the meeting was summarized
+	tags = dropRetiredTags(uniqueStrings(tags), retired)
+	sort.Strings(tags)
+	return tags
+}
+
+// claimUserTags finds tags on an existing note that were added by hand - ones the sync
+// didn't generate, that no earlier summary of the meeting generated (see carryUserTags)
+// and that aren't an old name of a mapped tag - and records them as user tags. Returns
+// whether any new user tags were found.
+func claimUserTags(summaryData *SummaryData, noteTags, generated []string, tagMappings map[string]string) bool {
+	if summaryData == nil {
+		return false
+	}
+
+	isGenerated := make(map[string]bool, len(generated))
+	for _, tag := range generated {
+		isGenerated[tag] = true
+	}
+
+	changed := false
+	for _, tag := range noteTags {
+		if isGenerated[tag] {
+			continue
+		}
+		if _, mapped := tagMappings[tag]; mapped {
+			continue
+		}
+		if origin, ok := summaryData.TagOrigins[tag]; ok && origin != tagOriginUser {
+			// Generated by an earlier summary; the user kept it but didn't add it
+			continue
+		}
+		if summaryData.TagOrigins[tag] != tagOriginUser {
+			if summaryData.TagOrigins == nil {
+				summaryData.TagOrigins = make(map[string]string)
+			}
+			summaryData.TagOrigins[tag] = tagOriginUser
+			changed = true
+		}
+	}
+	return changed
+}
+
+// withUserTags adds the recorded user tags to generated tags, so a re-sync never drops them
+func withUserTags(tags []string, summaryData *SummaryData) []string {
+	if summaryData == nil {
+		return tags
+	}
+	user := sortedUserTags(summaryData.TagOrigins)
+	if len(user) == 0 {
+		return tags
+	}
+	merged := uniqueStrings(append(append([]string(nil), tags...), user...))
+	sort.Strings(merged)
+	return merged
+}
+
+// sortedUserTags returns the tags recorded as added by hand
+func sortedUserTags(origins map[string]string) []string {
+	var tags []string
+	for tag, origin := range origins {
+		if origin == tagOriginUser {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// existingNoteTags returns the tags in a vault note's frontmatter, or nil if there is no note
+func existingNoteTags(path string) []string {
+	if !vaultWriter.Exists(path) {
+		return nil
+	}
+	content, err := vaultWriter.ReadNote(path)
+	if err != nil {
+		return nil
+	}
+	frontmatter, _, err := splitFrontmatter(content)
+	if err != nil {
+		return nil
+	}
+	return frontmatterList(frontmatter["tags"])
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultTagsReport is the vault-relative path of the tags report note
+const defaultTagsReport = "Tags Report.md"
+
+// maxTagExamples is how many example notes are linked per tag
+const maxTagExamples = 3
+
+// tagCount is a tag with its number of uses across the vault
+type tagCount struct {
+	Tag   string
+	Count int
+}
+
+// tagsReportEnabled reports whether the tags report note is written (TAGS_REPORT, default on)
+func tagsReportEnabled() bool {
+	return envBoolDefault("TAGS_REPORT", true)
+}
+
+// tagsReportPath returns the absolute path of the tags report note (TAGS_REPORT_PATH, vault-relative)
+func tagsReportPath(vaultPath string) string {
+	return filepath.Join(vaultPath, firstNonEmpty(os.Getenv("TAGS_REPORT_PATH"), defaultTagsReport))
+}
+
+// writeTagsReport writes a note listing vault tags by frequency with links to example notes.
+// Tags are written as code spans so the report doesn't count towards its own tags.
+func writeTagsReport(vaultPath string, tags []tagCount, examples map[string][]string) error {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("type: tags-report\n")
+	sb.WriteString(fmt.Sprintf("updated: %s\n", time.Now().Format("2006-01-02 15:04")))
+	sb.WriteString("---\n\n")
+	sb.WriteString("# Tags Report\n\n")
+	sb.WriteString(fmt.Sprintf("%d tags in use, most frequent first. Regenerated on every run - edits will be overwritten.\n\n", len(tags)))
+	sb.WriteString("| Tag | Uses | Examples |\n")
+	sb.WriteString("|-----|-----:|----------|\n")
+
+	for _, t := range tags {
+		var links []string
+		for _, rel := range examples[t.Tag] {
+			target := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+			links = append(links, fmt.Sprintf("[[%s\\|%s]]", target, filepath.Base(target)))
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %s |\n", t.Tag, t.Count, strings.Join(links, ", ")))
+	}
+
+	// Keep the report (and its updated date) as it is when the tags haven't changed
+	path := tagsReportPath(vaultPath)
+	if existing, err := vaultWriter.ReadNote(path); err == nil {
+		_, oldBody, oldErr := splitFrontmatter(existing)
+		_, newBody, newErr := splitFrontmatter([]byte(sb.String()))
+		if oldErr == nil && newErr == nil && oldBody == newBody {
+			return nil
+		}
+	}
+	return vaultWriter.CreateNote(path, []byte(sb.String()))
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tag suggestion defaults
+const (
+	defaultTagAutoApplyThreshold = 0.8 // confidence at which suggested tags are added automatically
+	defaultTagReviewThreshold    = 0.5 // confidence at which suggestions go to the review queue
+	tagSuggestionMinSupport      = 3   // minimum meetings a tag must appear in to drive suggestions
+	tagSuggestionsFile           = "tag-suggestions.json"
+)
+
+// TagSuggestion is an additional tag suggested from co-occurrence with existing tags
+type TagSuggestion struct {
+	Tag        string  `json:"tag"`
+	Confidence float64 `json:"confidence"` // P(tag | because) across cached summaries
+	Because    string  `json:"because"`    // existing tag that drove the suggestion
+}
+
+// TagCooccurrence counts how often tags appear together across summaries
+type TagCooccurrence struct {
+	counts map[string]int
+	pairs  map[string]map[string]int
+}
+
+// splitTags splits a comma-separated tag string
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return uniqueStrings(result)
+}
+
+// buildTagCooccurrence builds the co-occurrence model from all cached summaries
+func buildTagCooccurrence(cache *Cache) (*TagCooccurrence, error) {
+	files, err := filepath.Glob(filepath.Join(cache.dir, "*-summary.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache directory: %w", err)
+	}
+
+	model := &TagCooccurrence{
+		counts: make(map[string]int),
+		pairs:  make(map[string]map[string]int),
+	}
+
+	for _, file := range files {
+		meetingID := strings.TrimSuffix(filepath.Base(file), "-summary.json")
+		summaryData, err := cache.LoadSummary(meetingID)
+		if err != nil {
+			continue
+		}
+		model.add(splitTags(summaryData.Tags))
+	}
+
+	return model, nil
+}
+
+// add records one meeting's tags
+func (m *TagCooccurrence) add(tags []string) {
+	for _, a := range tags {
+		m.counts[a]++
+		if m.pairs[a] == nil {
+			m.pairs[a] = make(map[string]int)
+		}
+		for _, b := range tags {
+			if a != b {
+				m.pairs[a][b]++
+			}
+		}
+	}
+}
+
+// Suggest returns tags that usually accompany the given tags, highest confidence first
+func (m *TagCooccurrence) Suggest(tags []string, minConfidence float64) []TagSuggestion {
+	present := make(map[string]bool)
+	for _, tag := range tags {
+		present[tag] = true
+	}
+
+	best := make(map[string]TagSuggestion)
+	for _, a := range tags {
+		if m.counts[a] < tagSuggestionMinSupport {
+			continue
+		}
+		for b, together := range m.pairs[a] {
+			if present[b] {
+				continue
+			}
+			confidence := float64(together) / float64(m.counts[a])
+			if confidence >= minConfidence && confidence > best[b].Confidence {
+				best[b] = TagSuggestion{Tag: b, Confidence: confidence, Because: a}
+			}
+		}
+	}
+
+	suggestions := make([]TagSuggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Tag < suggestions[j].Tag
+	})
+	return suggestions
+}
+
+// tagThresholdsFromEnv reads TAG_SUGGEST_AUTO_THRESHOLD and TAG_SUGGEST_REVIEW_THRESHOLD
+func tagThresholdsFromEnv() (float64, float64, error) {
+	auto, review := defaultTagAutoApplyThreshold, defaultTagReviewThreshold
+	if v := os.Getenv("TAG_SUGGEST_AUTO_THRESHOLD"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid TAG_SUGGEST_AUTO_THRESHOLD %q: %w", v, err)
+		}
+		auto = f
+	}
+	if v := os.Getenv("TAG_SUGGEST_REVIEW_THRESHOLD"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid TAG_SUGGEST_REVIEW_THRESHOLD %q: %w", v, err)
+		}
+		review = f
+	}
+	return auto, review, nil
+}
+
+// applyTagSuggestions adds high-confidence suggestions to a summary's tags and
+// returns the lower-confidence ones for review
+func applyTagSuggestions(model *TagCooccurrence, summaryData *SummaryData, autoThreshold, reviewThreshold float64) (applied, review []TagSuggestion) {
+	tags := splitTags(summaryData.Tags)
+	for _, s := range model.Suggest(tags, reviewThreshold) {
+		if s.Confidence >= autoThreshold {
+			applied = append(applied, s)
+			tags = append(tags, s.Tag)
+			recordTagOrigin(summaryData, s.Tag, tagOriginSuggestion)
+		} else {
+			review = append(review, s)
+		}
+	}
+
+	if len(applied) > 0 {
+		summaryData.Tags = strings.Join(tags, ", ")
+	}
+	summaryData.SuggestedTags = review
+	return applied, review
+}
+
+// saveTagReviewQueue merges suggestions awaiting review into tag-suggestions.json
+func saveTagReviewQueue(pending map[string][]TagSuggestion) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	queue := make(map[string][]TagSuggestion)
+	if data, err := os.ReadFile(dataPath(tagSuggestionsFile)); err == nil {
+		if err := json.Unmarshal(data, &queue); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", tagSuggestionsFile, err)
+		}
+	}
+
+	for meetingID, suggestions := range pending {
+		queue[meetingID] = suggestions
+	}
+
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag suggestions: %w", err)
+	}
+	return os.WriteFile(dataPath(tagSuggestionsFile), data, 0644)
+}
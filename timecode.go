@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseTranscriptTimeOffset reads TRANSCRIPT_TIME_OFFSET: "auto" (or unset), "off", or a
+// duration. For auto there is no fixed offset.
+func parseTranscriptTimeOffset(v string) (offset time.Duration, auto bool, err error) {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case "", "auto":
+		return 0, true, nil
+	case "off":
+		return 0, false, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid TRANSCRIPT_TIME_OFFSET %q: expected auto, off or a duration like -45s", v)
+	}
+	return d, false, nil
+}
+
+// transcriptTimeOffset returns the seconds to add to a meeting's transcript timestamps so
+// they count from the meeting start instead of the recording start (TRANSCRIPT_TIME_OFFSET):
+//   - "auto" (default): the gap between the recording's and the meeting's start, when Krisp
+//     reports when the recording started; otherwise no correction
+//   - a duration like "-45s" or "2m": the same offset for every meeting
+//   - "off" or "0": no correction
+//
+// main rejects invalid values at startup; here they mean no correction. Audio links keep
+// the original timestamps, since the recording starts at the recording start.
+func transcriptTimeOffset(m *Meeting) float64 {
+	offset, auto, err := parseTranscriptTimeOffset(os.Getenv("TRANSCRIPT_TIME_OFFSET"))
+	if err != nil {
+		return 0
+	}
+	if !auto {
+		return offset.Seconds()
+	}
+	if started := m.Resources.Recording.StartedAt; started != nil && !started.IsZero() && !m.CreatedAt.IsZero() {
+		return math.Round(started.Sub(m.CreatedAt).Seconds())
+	}
+	return 0
+}
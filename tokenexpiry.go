@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTokenWarningDays is how long before a Krisp token expires the warnings start
+const defaultTokenWarningDays = 7
+
+// tokenExpiry decodes the expiry of a JWT bearer token. Returns false for tokens that
+// aren't JWTs or carry no "exp" claim.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}, false
+	}
+	sec, frac := math.Modf(claims.Exp)
+	return time.Unix(int64(sec), int64(frac*1e9)), true
+}
+
+// tokenWarningWindow reads TOKEN_EXPIRY_WARNING_DAYS
+func tokenWarningWindow() time.Duration {
+	days := defaultTokenWarningDays
+	if v := strings.TrimSpace(os.Getenv("TOKEN_EXPIRY_WARNING_DAYS")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			days = n
+		} else {
+			fmt.Printf("⚠ Ignoring invalid TOKEN_EXPIRY_WARNING_DAYS %q\n", v)
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// krispTokens returns the configured Krisp tokens by account name ("" for KRISP_BEARER_TOKEN)
+func krispTokens() []KrispAccount {
+	if len(krispAccounts) > 0 {
+		return krispAccounts
+	}
+	return []KrispAccount{{Token: bearerToken}}
+}
+
+// tokenLabel names a token in messages
+func tokenLabel(a KrispAccount) string {
+	if a.Name == "" {
+		return "KRISP_BEARER_TOKEN"
+	}
+	return accountTokenEnv(a.Name)
+}
+
+// describeExpiry says when a token expires (or expired), relative to now
+func describeExpiry(expiry time.Time) string {
+	left := time.Until(expiry)
+	date := expiry.Local().Format("2006-01-02 15:04")
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("expired on %s", date)
+	case left < 24*time.Hour:
+		return fmt.Sprintf("expires in %s (%s)", left.Round(time.Minute), date)
+	default:
+		return fmt.Sprintf("expires in %d day(s) (%s)", int(math.Round(left.Hours()/24)), date)
+	}
+}
+
+// warnTokenExpiry warns about Krisp tokens that expired or expire within the warning
+// window, so an expired token shows up before a cron run fails with a 401
+func warnTokenExpiry() {
+	window := tokenWarningWindow()
+	for _, a := range krispTokens() {
+		expiry, ok := tokenExpiry(a.Token)
+		if !ok {
+			continue
+		}
+		if left := time.Until(expiry); left <= 0 {
+			fmt.Printf("❌ %s %s - replace it in .env\n", tokenLabel(a), describeExpiry(expiry))
+		} else if left <= window {
+			fmt.Printf("⚠ %s %s - replace it in .env before then\n", tokenLabel(a), describeExpiry(expiry))
+		}
+	}
+}
+
+// printTokenExpiry lists every Krisp token's expiry for the status step
+func printTokenExpiry() {
+	for _, a := range krispTokens() {
+		if a.Token == "" {
+			continue
+		}
+		status := "no expiry in token"
+		if expiry, ok := tokenExpiry(a.Token); ok {
+			status = describeExpiry(expiry)
+			if time.Until(expiry) <= tokenWarningWindow() {
+				status = "⚠ " + status
+			}
+		}
+		fmt.Printf("Token:       %s %s\n", tokenLabel(a), status)
+	}
+}
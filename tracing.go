@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the service and instrumentation scope of exported spans
+const tracerName = "krisp-sync"
+
+// tracer starts the pipeline's spans. Until setupTracing installs a provider it is a no-op.
+var tracer = otel.Tracer(tracerName)
+
+// setupTracing exports spans to the OTLP/HTTP collector at OTEL_EXPORTER_OTLP_ENDPOINT
+// and/or appends them as JSON lines to KRISP_SYNC_TRACE_FILE. The returned function
+// flushes and closes the exporters; it is a no-op when tracing isn't configured.
+func setupTracing() (func(), error) {
+	var exporters []sdktrace.SpanExporter
+	var targets []string
+
+	if path := strings.TrimSpace(os.Getenv("KRISP_SYNC_TRACE_FILE")); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to create trace file exporter: %w", err)
+		}
+		exporters = append(exporters, &closingExporter{SpanExporter: exporter, closer: f})
+		targets = append(targets, path)
+	}
+	if endpoint := strings.TrimSpace(firstNonEmpty(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"), os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))); endpoint != "" {
+		// The exporter reads the endpoint, headers and the rest of its OTEL_EXPORTER_OTLP_*
+		// settings itself
+		exporter, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporters = append(exporters, exporter)
+		targets = append(targets, endpoint)
+	}
+	if len(exporters) == 0 {
+		return func() {}, nil
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", tracerName))),
+	}
+	for _, exporter := range exporters {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+
+	// Krisp API calls get a client span each; LLM calls get their own spans
+	withKrispTransport(&tracingTransport{inner: krispAPI.baseTransport()})(krispAPI)
+
+	fmt.Printf("🔭 Tracing to %s\n", strings.Join(targets, ", "))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			fmt.Printf("⚠ Warning: Could not export traces: %v\n", err)
+		}
+	}, nil
+}
+
+// startMeetingSpan starts the span of one meeting's work in a stage
+func startMeetingSpan(ctx context.Context, stage, meetingID string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, stage+" meeting", trace.WithAttributes(attribute.String("meeting.id", meetingID)))
+}
+
+// startStageSpan starts the span of a pipeline stage. The returned function ends it,
+// marking the stage failed when it returned an error.
+func startStageSpan(ctx context.Context, stage string) (context.Context, func(error)) {
+	ctx, span := tracer.Start(ctx, stage)
+	return ctx, func(err error) { endSpan(span, err) }
+}
+
+// endSpan ends a span, recording err as its failure
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		failSpan(span, err)
+	}
+	span.End()
+}
+
+// failSpan marks a span failed with err
+func failSpan(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// tracingTransport records a client span for every Krisp API request. Query strings are left
+// out of span names and attributes, since attachment URLs carry signatures.
+type tracingTransport struct {
+	inner http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, span := tracer.Start(req.Context(), req.Method+" "+req.URL.Host+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		))
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
+
+// closingExporter closes the trace file once its exporter has shut down
+type closingExporter struct {
+	sdktrace.SpanExporter
+	closer io.Closer
+}
+
+func (e *closingExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if cerr := e.closer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
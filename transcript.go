@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// overlapTolerance ignores overlaps shorter than this (seconds), which are usually timing jitter
+const overlapTolerance = 0.3
+
+// defaultLowConfidence is the segment confidence below which a transcript line is flagged
+const defaultLowConfidence = 0.6
+
+// lowConfidenceThreshold reads TRANSCRIPT_LOW_CONFIDENCE (0-1)
+func lowConfidenceThreshold() float64 {
+	if v := os.Getenv("TRANSCRIPT_LOW_CONFIDENCE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return defaultLowConfidence
+}
+
+// segmentInterruption is where another speaker cut into a segment
+type segmentInterruption struct {
+	At      float64
+	Speaker int
+}
+
+// findInterruptions returns, for each segment, the first later segment from another
+// speaker that starts before it ends, and whether each segment overlaps an earlier one
+func findInterruptions(segments []Segment) (map[int]segmentInterruption, map[int]bool) {
+	interrupted := make(map[int]segmentInterruption)
+	overlapping := make(map[int]bool)
+
+	for i, seg := range segments {
+		for j := i + 1; j < len(segments); j++ {
+			next := segments[j]
+			if next.Speech.Start >= seg.Speech.End-overlapTolerance {
+				break
+			}
+			if next.SpeakerIndex == seg.SpeakerIndex {
+				continue
+			}
+			overlapping[j] = true
+			if _, ok := interrupted[i]; !ok {
+				interrupted[i] = segmentInterruption{At: next.Speech.Start, Speaker: next.SpeakerIndex}
+			}
+		}
+	}
+	return interrupted, overlapping
+}
+
+// segmentConfidence returns the segment's confidence, averaging word confidences when
+// the segment has none of its own
+func segmentConfidence(speech Speech) (float64, bool) {
+	if speech.Confidence != nil {
+		return *speech.Confidence, true
+	}
+	total, count := 0.0, 0
+	for _, w := range speech.Words {
+		if w.Confidence != nil {
+			total += *w.Confidence
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / float64(count), true
+}
+
+// joinWords joins word texts with spaces, keeping punctuation attached
+func joinWords(words []Word) string {
+	var sb strings.Builder
+	for _, w := range words {
+		text := strings.TrimSpace(w.Text)
+		if text == "" {
+			continue
+		}
+		if sb.Len() > 0 && !strings.ContainsAny(text[:1], ".,!?;:") {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// segmentText renders a segment's text, marking where it was interrupted when word timing allows
+func segmentText(m *Meeting, seg Segment, interruption *segmentInterruption, inferred map[string]string) string {
+	if interruption == nil {
+		return seg.Speech.Text
+	}
+
+	marker := fmt.Sprintf("*(interrupted by %s)*", speakerDisplayName(m, interruption.Speaker, inferred))
+	if len(seg.Speech.Words) == 0 {
+		return seg.Speech.Text + " " + marker
+	}
+
+	split := len(seg.Speech.Words)
+	for i, w := range seg.Speech.Words {
+		if w.Start >= interruption.At {
+			split = i
+			break
+		}
+	}
+	before := joinWords(seg.Speech.Words[:split])
+	after := joinWords(seg.Speech.Words[split:])
+	if after == "" {
+		return before + " " + marker
+	}
+	return fmt.Sprintf("%s — %s … %s", before, marker, after)
+}
+
+// renderTranscriptSegments renders transcript segments as markdown, showing overlapping
+// speech and interruptions instead of flattening them, and flagging low-confidence lines.
+// Each agenda item of the summary that was discussed gets a heading where its discussion
+// starts, and unnamed speakers get the names the summary inferred (SPEAKER_FALLBACK=llm).
+func renderTranscriptSegments(m *Meeting, segments []Segment, audio *audioTarget, summaryData *SummaryData) string {
+	var sb strings.Builder
+	interrupted, overlapping := findInterruptions(segments)
+	threshold := lowConfidenceThreshold()
+	inferred := inferredSpeakers(summaryData)
+	var agenda []AgendaSlice
+	if summaryData != nil {
+		agenda = summaryData.Agenda
+	}
+	headings := agendaHeadings(agenda)
+	offset := transcriptTimeOffset(m)
+
+	for i, segment := range segments {
+		if item, ok := headings[segment.ID]; ok {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", item))
+		}
+		// Shown from the meeting start; the recording plays from its own start
+		label := formatTimestamp(segment.Speech.Start + offset)
+		timestamp := "[" + label + "]"
+		if audio != nil {
+			timestamp = audioTimestampLink(audio, segment.Speech.Start, label)
+		}
+		speakerName := speakerDisplayName(m, segment.SpeakerIndex, inferred)
+
+		var interruption *segmentInterruption
+		if in, ok := interrupted[i]; ok {
+			interruption = &in
+		}
+		text := segmentText(m, segment, interruption, inferred)
+
+		if confidence, ok := segmentConfidence(segment.Speech); ok && confidence < threshold {
+			text += fmt.Sprintf(" *(low confidence: %.0f%%)*", confidence*100)
+		}
+
+		// Block IDs let other notes (e.g. notable quotes) link to this exact line
+		blockID := transcriptBlockID(segment.ID)
+		if overlapping[i] {
+			sb.WriteString(fmt.Sprintf("> **%s %s** *(overlapping)*: %s ^%s\n\n", timestamp, speakerName, text, blockID))
+		} else {
+			sb.WriteString(fmt.Sprintf("**%s %s**: %s ^%s\n\n", timestamp, speakerName, text, blockID))
+		}
+	}
+
+	return sb.String()
+}
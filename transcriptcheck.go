@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transcripts ending well before the meeting did are treated as truncated
+const (
+	defaultTranscriptMinCoverage = 0.8
+	transcriptTruncationSlack    = 2 * time.Minute // trailing gap that is never a truncation (e.g. goodbyes, silence)
+)
+
+// TruncatedTranscript records a meeting whose transcript covers much less than the meeting
+type TruncatedTranscript struct {
+	Title       string    `json:"title"`
+	Duration    int       `json:"duration"` // meeting length in seconds
+	Covered     float64   `json:"covered"`  // end of the last transcript segment, in seconds
+	DetectedAt  time.Time `json:"detected_at"`
+	Redownloads int       `json:"redownloads,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+}
+
+// Coverage returns the fraction of the meeting the transcript covers
+func (t *TruncatedTranscript) Coverage() float64 {
+	if t.Duration <= 0 {
+		return 1
+	}
+	return t.Covered / float64(t.Duration)
+}
+
+// transcriptMinCoverage reads TRANSCRIPT_MIN_COVERAGE (a fraction, e.g. "0.8")
+func transcriptMinCoverage() float64 {
+	if v := os.Getenv("TRANSCRIPT_MIN_COVERAGE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f <= 1 {
+			return f
+		}
+		fmt.Printf("⚠ Ignoring invalid TRANSCRIPT_MIN_COVERAGE %q\n", v)
+	}
+	return defaultTranscriptMinCoverage
+}
+
+// transcriptEnd returns the end time of a meeting's last transcript segment, in seconds
+func transcriptEnd(m *Meeting) (float64, bool) {
+	var segments []Segment
+	if err := json.Unmarshal([]byte(m.Resources.Transcript.Content), &segments); err != nil || len(segments) == 0 {
+		return 0, false
+	}
+	end := 0.0
+	for _, seg := range segments {
+		if seg.Speech.End > end {
+			end = seg.Speech.End
+		}
+	}
+	return end, true
+}
+
+// transcriptTruncated reports whether a downloaded transcript stops well short of the
+// meeting's duration, returning how far it gets
+func transcriptTruncated(m *Meeting) (float64, bool) {
+	if m.Duration <= 0 || !transcriptReady(m) {
+		return 0, false
+	}
+	end, ok := transcriptEnd(m)
+	if !ok {
+		return 0, false
+	}
+	gap := float64(m.Duration) - end
+	if gap <= transcriptTruncationSlack.Seconds() {
+		return end, false
+	}
+	return end, end/float64(m.Duration) < transcriptMinCoverage()
+}
+
+// formatCoverage formats a coverage fraction for frontmatter, or "" when the transcript is complete
+func formatCoverage(m *Meeting) string {
+	end, truncated := transcriptTruncated(m)
+	if !truncated {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", end/float64(m.Duration))
+}
+
+// redownloadTruncated reports whether truncated transcripts are fetched again on later runs
+// (TRANSCRIPT_REDOWNLOAD_TRUNCATED=true)
+func redownloadTruncated() bool {
+	return envBool("TRANSCRIPT_REDOWNLOAD_TRUNCATED")
+}
+
+// CheckTranscript records a downloaded meeting as truncated, or clears an earlier flag once
+// its transcript is complete. Returns whether it is truncated.
+func (s *SyncState) CheckTranscript(m *Meeting) bool {
+	end, truncated := transcriptTruncated(m)
+	item, flagged := s.TruncatedTranscripts[m.ID]
+
+	if !truncated {
+		if flagged {
+			delete(s.TruncatedTranscripts, m.ID)
+			s.pending++
+			if err := s.Checkpoint(); err != nil {
+				fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+			}
+		}
+		return false
+	}
+
+	if s.TruncatedTranscripts == nil {
+		s.TruncatedTranscripts = make(map[string]*TruncatedTranscript)
+	}
+	now := time.Now()
+	if !flagged {
+		item = &TruncatedTranscript{Title: m.Title, DetectedAt: now}
+		s.TruncatedTranscripts[m.ID] = item
+	} else {
+		item.Redownloads++
+	}
+	item.Duration = m.Duration
+	item.Covered = end
+	item.NextAttempt = now.Add(transcriptBackoff(item.Redownloads + 1))
+
+	s.pending++
+	if err := s.Checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+	fmt.Printf("  ⚠ Transcript looks truncated: covers %s of %s (%.0f%%)\n", formatTimestamp(end), formatTimestamp(float64(m.Duration)), item.Coverage()*100)
+	return true
+}
+
+// truncatedTranscriptIDs returns truncated meeting IDs, oldest first
+func (s *SyncState) truncatedTranscriptIDs() []string {
+	ids := make([]string, 0, len(s.TruncatedTranscripts))
+	for id := range s.TruncatedTranscripts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return s.TruncatedTranscripts[ids[i]].DetectedAt.Before(s.TruncatedTranscripts[ids[j]].DetectedAt)
+	})
+	return ids
+}
+
+// retryTruncatedTranscripts re-downloads truncated meetings whose retry time has come, until
+// TRANSCRIPT_MAX_WAIT after they were first seen
+func retryTruncatedTranscripts(ctx context.Context, syncState *SyncState, cache *Cache) {
+	if !redownloadTruncated() {
+		return
+	}
+
+	now := time.Now()
+	var due []string
+	for _, id := range syncState.truncatedTranscriptIDs() {
+		item := syncState.TruncatedTranscripts[id]
+		if !now.Before(item.NextAttempt) && now.Sub(item.DetectedAt) <= transcriptMaxWait() {
+			due = append(due, id)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("🔍 Would re-download %d meeting(s) with truncated transcripts: %s\n", len(due), strings.Join(due, ", "))
+		return
+	}
+	fmt.Printf("✂️  Re-downloading %d meeting(s) with truncated transcripts\n", len(due))
+	for _, id := range due {
+		if ctx.Err() != nil || budgetStop("download") {
+			return
+		}
+
+		meeting, err := fetchAccountMeeting(ctx, cache, id)
+		if err != nil {
+			fmt.Printf("  ⚠ Error fetching meeting %s: %v\n", id, err)
+			continue
+		}
+		if !transcriptReady(meeting) {
+			continue
+		}
+		if syncState.CheckTranscript(meeting) {
+			continue
+		}
+
+		if err := saveDownloadedMeeting(cache, meeting); err != nil {
+			fmt.Printf("  ⚠ Error saving to cache: %v\n", err)
+			continue
+		}
+		fmt.Printf("  ✓ Transcript now complete: %s (set %s: true on its note to regenerate it)\n", meeting.Title, resyncFlag)
+	}
+}
+
+// printTruncatedTranscripts lists meetings whose transcripts look truncated
+func printTruncatedTranscripts(syncState *SyncState) {
+	if len(syncState.TruncatedTranscripts) == 0 {
+		return
+	}
+	fmt.Printf("\n✂️  Truncated transcripts: %d\n", len(syncState.TruncatedTranscripts))
+	for _, id := range syncState.truncatedTranscriptIDs() {
+		item := syncState.TruncatedTranscripts[id]
+		fmt.Printf("  ✂️  %s (%s) - covers %.0f%% of %s\n", item.Title, id, item.Coverage()*100, formatTimestamp(float64(item.Duration)))
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Retry schedule for meetings whose transcript wasn't ready when downloaded
+const (
+	transcriptRetryBase   = 15 * time.Minute
+	transcriptRetryMax    = 12 * time.Hour
+	defaultTranscriptWait = 7 * 24 * time.Hour // after this the transcript is flagged as missing
+)
+
+// QueuedTranscript tracks a meeting whose transcript Krisp hadn't finished yet
+type QueuedTranscript struct {
+	Title       string    `json:"title"`
+	Status      string    `json:"status"` // last transcript status seen (e.g. "processing")
+	FirstSeen   time.Time `json:"first_seen"`
+	LastAttempt time.Time `json:"last_attempt"`
+	NextAttempt time.Time `json:"next_attempt"`
+	Attempts    int       `json:"attempts"`
+	Missing     bool      `json:"missing,omitempty"` // gave up after the maximum wait
+}
+
+// transcriptReady reports whether a downloaded meeting has a usable transcript
+func transcriptReady(m *Meeting) bool {
+	return m.Resources.Transcript.Status == "uploaded" && m.Resources.Transcript.Content != ""
+}
+
+// transcriptMaxWait reads TRANSCRIPT_MAX_WAIT (e.g. "72h")
+func transcriptMaxWait() time.Duration {
+	if v := os.Getenv("TRANSCRIPT_MAX_WAIT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		fmt.Printf("⚠ Ignoring invalid TRANSCRIPT_MAX_WAIT %q\n", v)
+	}
+	return defaultTranscriptWait
+}
+
+// transcriptBackoff returns the wait before the given retry attempt (doubling, capped)
+func transcriptBackoff(attempts int) time.Duration {
+	wait := transcriptRetryBase
+	for i := 1; i < attempts && wait < transcriptRetryMax; i++ {
+		wait *= 2
+	}
+	if wait > transcriptRetryMax {
+		wait = transcriptRetryMax
+	}
+	return wait
+}
+
+// QueueTranscript records (or updates) a meeting whose transcript isn't ready yet
+func (s *SyncState) QueueTranscript(m *Meeting) {
+	if s.TranscriptQueue == nil {
+		s.TranscriptQueue = make(map[string]*QueuedTranscript)
+	}
+
+	now := time.Now()
+	item, ok := s.TranscriptQueue[m.ID]
+	if !ok {
+		item = &QueuedTranscript{Title: m.Title, FirstSeen: now}
+		s.TranscriptQueue[m.ID] = item
+	}
+	item.Status = m.Resources.Transcript.Status
+	item.Attempts++
+	item.LastAttempt = now
+	item.NextAttempt = now.Add(transcriptBackoff(item.Attempts))
+	if now.Sub(item.FirstSeen) > transcriptMaxWait() {
+		item.Missing = true
+	}
+
+	s.pending++
+	if err := s.Checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+}
+
+// DequeueTranscript removes a meeting from the transcript queue once its transcript arrived
+func (s *SyncState) DequeueTranscript(meetingID string) {
+	if _, ok := s.TranscriptQueue[meetingID]; !ok {
+		return
+	}
+	delete(s.TranscriptQueue, meetingID)
+	s.pending++
+	if err := s.Checkpoint(); err != nil {
+		fmt.Printf("  ⚠ Warning: Could not save sync state: %v\n", err)
+	}
+}
+
+// queuedTranscriptIDs returns queued meeting IDs, oldest first
+func (s *SyncState) queuedTranscriptIDs() []string {
+	ids := make([]string, 0, len(s.TranscriptQueue))
+	for id := range s.TranscriptQueue {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return s.TranscriptQueue[ids[i]].FirstSeen.Before(s.TranscriptQueue[ids[j]].FirstSeen)
+	})
+	return ids
+}
+
+// retryQueuedTranscripts re-downloads queued meetings whose retry time has come
+func retryQueuedTranscripts(ctx context.Context, syncState *SyncState, cache *Cache) {
+	now := time.Now()
+	var due []string
+	for _, id := range syncState.queuedTranscriptIDs() {
+		item := syncState.TranscriptQueue[id]
+		if !item.Missing && !now.Before(item.NextAttempt) {
+			due = append(due, id)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("🔍 Would re-fetch %d meeting(s) waiting for transcripts: %s\n", len(due), strings.Join(due, ", "))
+		return
+	}
+	fmt.Printf("⏳ Retrying %d meeting(s) waiting for transcripts\n", len(due))
+	for _, id := range due {
+		if ctx.Err() != nil || budgetStop("download") {
+			return
+		}
+
+		meeting, err := fetchAccountMeeting(ctx, cache, id)
+		if err != nil {
+			fmt.Printf("  ⚠ Error fetching meeting %s: %v\n", id, err)
+			continue
+		}
+
+		if !transcriptReady(meeting) {
+			syncState.QueueTranscript(meeting)
+			item := syncState.TranscriptQueue[id]
+			if item.Missing {
+				fmt.Printf("  ❌ Giving up on transcript for %s (%s): still %q after %s\n", meeting.Title, id, item.Status, transcriptMaxWait())
+			} else {
+				fmt.Printf("  ⏳ Still %s: %s (next try %s)\n", item.Status, meeting.Title, item.NextAttempt.Local().Format("Jan 2 15:04"))
+			}
+			continue
+		}
+
+		if err := saveDownloadedMeeting(cache, meeting); err != nil {
+			fmt.Printf("  ⚠ Error saving to cache: %v\n", err)
+			continue
+		}
+		downloadAttachments(ctx, meeting, cache)
+		syncState.MarkDownloaded(id)
+		syncState.DequeueTranscript(id)
+		syncState.CheckTranscript(meeting)
+		fmt.Printf("  ✓ Transcript ready: %s\n", meeting.Title)
+	}
+}
+
+// printTranscriptQueue lists meetings still waiting for (or missing) transcripts
+func printTranscriptQueue(syncState *SyncState) {
+	if len(syncState.TranscriptQueue) == 0 {
+		return
+	}
+
+	waiting, missing := 0, 0
+	for _, item := range syncState.TranscriptQueue {
+		if item.Missing {
+			missing++
+		} else {
+			waiting++
+		}
+	}
+	fmt.Printf("\n⏳ Transcripts: %d waiting, %d missing\n", waiting, missing)
+
+	for _, id := range syncState.queuedTranscriptIDs() {
+		item := syncState.TranscriptQueue[id]
+		if item.Missing {
+			fmt.Printf("  ❌ %s (%s) - missing since %s, %d attempt(s)\n", item.Title, id, item.FirstSeen.Local().Format("2006-01-02"), item.Attempts)
+		} else {
+			fmt.Printf("  ⏳ %s (%s) - %s, next try %s\n", item.Title, id, item.Status, item.NextAttempt.Local().Format("Jan 2 15:04"))
+		}
+	}
+}
+
+// runStatus prints pipeline progress from the sync state
+func runStatus(syncState *SyncState) error {
+	fmt.Println("\n=== Status ===")
+	if syncState.LastSyncTime.IsZero() {
+		fmt.Println("Last sync:   never")
+	} else {
+		fmt.Printf("Last sync:   %s\n", syncState.LastSyncTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("Downloaded:  %d\n", len(syncState.SyncedMeetings))
+	fmt.Printf("Summarized:  %d\n", len(syncState.SummarizedMeetings))
+	fmt.Printf("In Obsidian: %d\n", len(syncState.ObsidianSyncedMeetings))
+	printTokenExpiry()
+	if syncState.ListCursor != nil {
+		fmt.Printf("Listing:     resumes after page %d (%s)\n", syncState.ListCursor.Page, syncState.ListCursor.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if bf := syncState.Backfill; bf != nil {
+		if bf.Completed {
+			fmt.Printf("Backfill:    complete (started %s)\n", bf.StartedAt.Local().Format("2006-01-02"))
+		} else {
+			fmt.Printf("Backfill:    in progress since %s (%s: %d downloaded, %d summarized)\n", bf.StartedAt.Local().Format("2006-01-02"), bf.Day, bf.Downloaded, bf.Summarized)
+		}
+	}
+
+	if len(syncState.TranscriptQueue) == 0 {
+		fmt.Println("Transcripts: none waiting")
+	}
+	printTranscriptQueue(syncState)
+	printTruncatedTranscripts(syncState)
+	return nil
+}
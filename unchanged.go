@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// UnchangedVaultWriter skips writes that wouldn't change a note, so re-running sync on
+// an up-to-date vault modifies no files (and git or cloud sync sees nothing to do)
+type UnchangedVaultWriter struct {
+	inner   VaultWriter
+	mu      sync.Mutex
+	skipped int
+}
+
+// newUnchangedVaultWriter wraps a writer, dropping writes of content already on disk
+func newUnchangedVaultWriter(inner VaultWriter) *UnchangedVaultWriter {
+	return &UnchangedVaultWriter{inner: inner}
+}
+
+// sameNoteContent reports whether writing content over existing would change nothing but
+// the time in the provenance footer
+func sameNoteContent(existing, content []byte) bool {
+	if bytes.Equal(existing, content) {
+		return true
+	}
+	oldGenerated, oldProvenance, oldTail := splitProvenance(existing)
+	newGenerated, newProvenance, newTail := splitProvenance(content)
+	if oldProvenance == nil || newProvenance == nil || oldTail != newTail || !bytes.Equal(oldGenerated, newGenerated) {
+		return false
+	}
+	newProvenance.GeneratedAt = oldProvenance.GeneratedAt
+	return reflect.DeepEqual(oldProvenance, newProvenance)
+}
+
+// unchanged reports whether a note already has the content, counting it as skipped
+func (w *UnchangedVaultWriter) unchanged(path string, content []byte) bool {
+	if !w.inner.Exists(path) {
+		return false
+	}
+	existing, err := w.inner.ReadNote(path)
+	if err != nil || !sameNoteContent(existing, content) {
+		return false
+	}
+	w.mu.Lock()
+	w.skipped++
+	w.mu.Unlock()
+	return true
+}
+
+func (w *UnchangedVaultWriter) Exists(path string) bool {
+	return w.inner.Exists(path)
+}
+
+func (w *UnchangedVaultWriter) ReadNote(path string) ([]byte, error) {
+	return w.inner.ReadNote(path)
+}
+
+func (w *UnchangedVaultWriter) CreateNote(path string, content []byte) error {
+	if w.unchanged(path, content) {
+		return nil
+	}
+	return w.inner.CreateNote(path, content)
+}
+
+func (w *UnchangedVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	var changed []string
+	for _, path := range paths {
+		if !w.unchanged(path, contents[path]) {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return createNotes(w.inner, changed, contents)
+}
+
+func (w *UnchangedVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *UnchangedVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *UnchangedVaultWriter) DeleteNote(path string) error {
+	return w.inner.DeleteNote(path)
+}
+
+func (w *UnchangedVaultWriter) MoveFolder(src, dest string) error {
+	return w.inner.MoveFolder(src, dest)
+}
+
+// Report prints how many writes were skipped because the note was already up to date
+func (w *UnchangedVaultWriter) Report() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.skipped > 0 {
+		fmt.Printf("⏭  %d note write(s) skipped: already up to date\n", w.skipped)
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LLMUsage counts the tokens of LLM calls and what they cost
+type LLMUsage struct {
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+	Unpriced     int     `json:"unpriced_calls,omitempty"`  // calls to models with no known price, not in CostUSD
+	Estimated    int     `json:"estimated_calls,omitempty"` // calls whose backend reported no token counts
+}
+
+// Add adds another usage to u
+func (u *LLMUsage) Add(other LLMUsage) {
+	u.Calls += other.Calls
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CostUSD += other.CostUSD
+	u.Unpriced += other.Unpriced
+	u.Estimated += other.Estimated
+}
+
+// String describes the usage, e.g. "3 call(s), 41,200 input + 2,310 output tokens, ~$0.0038"
+func (u LLMUsage) String() string {
+	s := fmt.Sprintf("%d call(s), %s input + %s output tokens, ~%s", u.Calls, formatCount(u.InputTokens), formatCount(u.OutputTokens), formatCost(u.CostUSD))
+	if u.Unpriced > 0 {
+		s += fmt.Sprintf(" (plus %d call(s) to models without a price; see LLM_PRICES)", u.Unpriced)
+	}
+	return s
+}
+
+// modelPrice is what a model costs in USD per million tokens
+type modelPrice struct {
+	Input, Output float64
+}
+
+// knownPrices are list prices per million tokens, matched by model name prefix (the
+// longest matching prefix wins). LLM_PRICES overrides and extends them.
+var knownPrices = map[string]modelPrice{
+	"gemini-2.0-flash-lite": {0.075, 0.30},
+	"gemini-2.0-flash":      {0.10, 0.40},
+	"gemini-1.5-flash":      {0.075, 0.30},
+	"gemini-1.5-pro":        {1.25, 5.00},
+	"gemini-2.5-flash-lite": {0.10, 0.40},
+	"gemini-2.5-flash":      {0.30, 2.50},
+	"gemini-2.5-pro":        {1.25, 10.00},
+	"gpt-4o-mini":           {0.15, 0.60},
+	"gpt-4o":                {2.50, 10.00},
+	"gpt-4.1-nano":          {0.10, 0.40},
+	"gpt-4.1-mini":          {0.40, 1.60},
+	"gpt-4.1":               {2.00, 8.00},
+	"claude-3-5-haiku":      {0.80, 4.00},
+	"claude-3-haiku":        {0.25, 1.25},
+	"claude-3-5-sonnet":     {3.00, 15.00},
+	"claude-3-7-sonnet":     {3.00, 15.00},
+	"claude-sonnet-4":       {3.00, 15.00},
+	"claude-opus-4":         {15.00, 75.00},
+}
+
+var (
+	llmPricesOnce sync.Once
+	llmPrices     map[string]modelPrice
+)
+
+// pricesFromEnv returns the known prices with LLM_PRICES applied: comma-separated
+// model=input/output entries in USD per million tokens, e.g. "gemini-2.0-flash=0.1/0.4"
+func pricesFromEnv() map[string]modelPrice {
+	llmPricesOnce.Do(func() {
+		llmPrices = make(map[string]modelPrice, len(knownPrices))
+		for model, price := range knownPrices {
+			llmPrices[model] = price
+		}
+		for _, entry := range strings.Split(os.Getenv("LLM_PRICES"), ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			model, prices, ok := strings.Cut(entry, "=")
+			in, out, ok2 := strings.Cut(prices, "/")
+			input, err1 := strconv.ParseFloat(strings.TrimSpace(in), 64)
+			output, err2 := strconv.ParseFloat(strings.TrimSpace(out), 64)
+			if !ok || !ok2 || err1 != nil || err2 != nil || input < 0 || output < 0 {
+				fmt.Printf("⚠ Ignoring invalid LLM_PRICES entry %q: expected model=input/output\n", entry)
+				continue
+			}
+			llmPrices[strings.TrimSpace(model)] = modelPrice{input, output}
+		}
+	})
+	return llmPrices
+}
+
+// priceOf returns the price of a model, as named by its backend. Local models are free.
+func priceOf(provider, model string) (modelPrice, bool) {
+	prices := pricesFromEnv()
+	if price, ok := prices[provider+":"+model]; ok {
+		return price, true
+	}
+	best := ""
+	for prefix := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		return prices[best], true
+	}
+	if provider == providerOllama {
+		return modelPrice{}, true
+	}
+	return modelPrice{}, false
+}
+
+// Cost returns what input and output tokens cost at this price
+func (p modelPrice) Cost(input, output int) float64 {
+	return (float64(input)*p.Input + float64(output)*p.Output) / 1e6
+}
+
+// llmCallKey marks the context of one LLM call, for its backend to report token counts
+type llmCallKey struct{}
+
+// llmCallTokens are the token counts a backend reported for a call
+type llmCallTokens struct {
+	mu             sync.Mutex
+	input, output  int
+	reportedTokens bool
+}
+
+// reported reports whether the API returned token counts for the call
+func (c *llmCallTokens) reported() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reportedTokens
+}
+
+// reportTokens records the token counts the API returned for a request. Backends that
+// send several requests for one call (e.g. to repair JSON) report each.
+func reportTokens(ctx context.Context, input, output int) {
+	call, ok := ctx.Value(llmCallKey{}).(*llmCallTokens)
+	if !ok || (input == 0 && output == 0) {
+		return
+	}
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	call.input += input
+	call.output += output
+	call.reportedTokens = true
+}
+
+// usageKey marks a context whose LLM calls are counted by a usageRecorder
+type usageKey struct{}
+
+// usageRecorder sums the usage of the LLM calls made with its context, and passes it on
+// to the recorder of the enclosing context, so a meeting's calls also count for the run
+type usageRecorder struct {
+	mu     sync.Mutex
+	usage  LLMUsage
+	parent *usageRecorder
+}
+
+// withUsageRecorder returns a context whose LLM calls are counted by the returned recorder
+func withUsageRecorder(ctx context.Context) (context.Context, *usageRecorder) {
+	parent, _ := ctx.Value(usageKey{}).(*usageRecorder)
+	r := &usageRecorder{parent: parent}
+	return context.WithValue(ctx, usageKey{}, r), r
+}
+
+// Usage returns the usage recorded so far
+func (r *usageRecorder) Usage() LLMUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.usage
+}
+
+// recordLLMCall adds a finished call to the recorders of ctx. Calls whose backend
+// reported no token counts are estimated from the prompt and response.
+func recordLLMCall(ctx context.Context, provider, model string, call *llmCallTokens, prompt, response string) {
+	r, ok := ctx.Value(usageKey{}).(*usageRecorder)
+	if !ok {
+		return
+	}
+	call.mu.Lock()
+	usage := LLMUsage{Calls: 1, InputTokens: call.input, OutputTokens: call.output}
+	if !call.reportedTokens {
+		usage.InputTokens, usage.OutputTokens = estimateTokens(prompt), estimateTokens(response)
+		usage.Estimated = 1
+	}
+	call.mu.Unlock()
+	if price, ok := priceOf(provider, model); ok {
+		usage.CostUSD = price.Cost(usage.InputTokens, usage.OutputTokens)
+	} else {
+		usage.Unpriced = 1
+	}
+	for ; r != nil; r = r.parent {
+		r.mu.Lock()
+		r.usage.Add(usage)
+		r.mu.Unlock()
+	}
+}
+
+// formatCost formats a USD amount with enough digits to show small amounts
+func formatCost(usd float64) string {
+	if usd != 0 && usd < 0.01 {
+		return fmt.Sprintf("$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatCount formats a count with thousands separators
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// usageLogFile records the LLM usage of every summarize run, including re-summaries
+// whose previous usage their summary no longer holds
+const usageLogFile = "llm-usage.jsonl"
+
+// UsageLogEntry is one summarize run in the usage log
+type UsageLogEntry struct {
+	Time     time.Time `json:"time"`
+	Meetings int       `json:"meetings"` // meetings summarized
+	Usage    LLMUsage  `json:"usage"`
+}
+
+// appendUsageLog appends a run's usage to the usage log
+func appendUsageLog(entry UsageLogEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dataPath(usageLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readUsageLog returns the usage log's entries, oldest first
+func readUsageLog() ([]UsageLogEntry, error) {
+	f, err := os.Open(dataPath(usageLogFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", usageLogFile, err)
+	}
+	defer f.Close()
+
+	var entries []UsageLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry UsageLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", usageLogFile, err)
+	}
+	return entries, nil
+}
+
+// defaultSummaryOutputTokens is the assumed response size of a summary call when there is
+// no usage history to average
+const defaultSummaryOutputTokens = 1500
+
+// averageOutputTokens returns the average output tokens per call of the summaries in the
+// cache that recorded usage
+func averageOutputTokens(cache *Cache) int {
+	meetingIDs, err := cache.MeetingIDs()
+	if err != nil {
+		return defaultSummaryOutputTokens
+	}
+	calls, tokens := 0, 0
+	for _, id := range meetingIDs {
+		if s, err := cache.LoadSummary(id); err == nil && s.Usage != nil {
+			calls += s.Usage.Calls
+			tokens += s.Usage.OutputTokens
+		}
+	}
+	if calls == 0 {
+		return defaultSummaryOutputTokens
+	}
+	return tokens / calls
+}
+
+// estimateCost returns what calls with the given input tokens would cost on a model, with
+// outputPerCall output tokens each
+func estimateCost(model string, calls, inputTokens, outputPerCall int) (float64, bool) {
+	backend, name, err := resolveModel(model)
+	if err != nil {
+		return 0, false
+	}
+	price, ok := priceOf(backend.Name(), name)
+	if !ok {
+		return 0, false
+	}
+	return price.Cost(inputTokens, calls*outputPerCall), true
+}
+
+// runCosts reports what summarize runs have spent by month, the usage of the current
+// summaries by model, and what re-summarizing every cached meeting would cost
+func runCosts(cache *Cache) error {
+	fmt.Println("\n=== LLM Costs ===")
+
+	entries, err := readUsageLog()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("⚠ No LLM usage recorded yet; it is recorded from the next summarize run on")
+	} else {
+		var total LLMUsage
+		var months []string
+		byMonth := make(map[string]*LLMUsage)
+		for _, e := range entries {
+			month := e.Time.Local().Format("2006-01")
+			if byMonth[month] == nil {
+				byMonth[month] = &LLMUsage{}
+				months = append(months, month)
+			}
+			byMonth[month].Add(e.Usage)
+			total.Add(e.Usage)
+		}
+		fmt.Printf("\nSpent by summarize runs since %s:\n", entries[0].Time.Local().Format("2006-01-02"))
+		for _, month := range months {
+			fmt.Printf("  %s  %s\n", month, byMonth[month])
+		}
+		fmt.Printf("  Total    %s\n", total)
+		if total.Estimated > 0 {
+			fmt.Printf("  (%d call(s) estimated from text length; their backend reported no token counts)\n", total.Estimated)
+		}
+	}
+
+	meetingIDs, err := cache.MeetingIDs()
+	if err != nil {
+		return err
+	}
+	var total LLMUsage
+	byModel := make(map[string]*LLMUsage)
+	meetingsByModel := make(map[string]int)
+	withUsage, transcriptTokens, transcripts := 0, 0, 0
+	for _, id := range meetingIDs {
+		if stats, err := cache.LoadStats(id); err == nil {
+			transcriptTokens += stats.TokenEstimate
+			transcripts++
+		}
+		summary, err := cache.LoadSummary(id)
+		if err != nil || summary.Usage == nil {
+			continue
+		}
+		withUsage++
+		total.Add(*summary.Usage)
+		model := firstNonEmpty(summary.Model, "unknown")
+		if byModel[model] == nil {
+			byModel[model] = &LLMUsage{}
+		}
+		byModel[model].Add(*summary.Usage)
+		meetingsByModel[model]++
+	}
+
+	outputPerCall := defaultSummaryOutputTokens
+	if withUsage > 0 {
+		fmt.Printf("\nCurrent summaries with recorded usage: %d\n", withUsage)
+		fmt.Printf("  Average per meeting: %s input + %s output tokens, ~%s\n",
+			formatCount(total.InputTokens/withUsage), formatCount(total.OutputTokens/withUsage), formatCost(total.CostUSD/float64(withUsage)))
+		models := make([]string, 0, len(byModel))
+		for model := range byModel {
+			models = append(models, model)
+		}
+		sort.Slice(models, func(i, j int) bool { return byModel[models[i]].CostUSD > byModel[models[j]].CostUSD })
+		for _, model := range models {
+			fmt.Printf("  %s: %d meeting(s), %s\n", model, meetingsByModel[model], byModel[model])
+		}
+		if total.Calls > 0 {
+			outputPerCall = total.OutputTokens / total.Calls
+		}
+	}
+
+	// What re-summarizing everything would cost, from the transcript sizes in the stats cache
+	if transcripts == 0 {
+		return nil
+	}
+	model := defaultModel()
+	if models, err := summaryModelsFromEnv(); err == nil && len(models) > 0 {
+		model = models[0]
+	}
+	fmt.Printf("\nRe-summarizing all %d measured transcript(s) with %s: ~%s input tokens", transcripts, model, formatCount(transcriptTokens))
+	if cost, ok := estimateCost(model, transcripts, transcriptTokens, outputPerCall); ok {
+		fmt.Printf(", ~%s\n", formatCost(cost))
+	} else {
+		fmt.Printf(" (no price known for %s; set LLM_PRICES)\n", model)
+	}
+	if transcripts < len(meetingIDs) {
+		fmt.Printf("  %d meeting(s) aren't measured yet; `krisp-sync stats` measures them\n", len(meetingIDs)-transcripts)
+	}
+	fmt.Println("  The prompt adds to each transcript; `krisp-sync summarize --overwrite --dry-run` lists every call")
+	return nil
+}
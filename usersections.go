@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultUserSections are the sections rendered empty into every summary note for your own notes
+const defaultUserSections = "My Notes"
+
+// userSectionHeadings returns the "## " headings of sections that belong to the user
+// (USER_SECTIONS, comma-separated; "none" turns them off)
+func userSectionHeadings() []string {
+	value := strings.TrimSpace(os.Getenv("USER_SECTIONS"))
+	if value == "" {
+		value = defaultUserSections
+	}
+	if strings.EqualFold(value, "none") {
+		return nil
+	}
+
+	var headings []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(name), "#"))
+		if name != "" {
+			headings = append(headings, "## "+name)
+		}
+	}
+	return headings
+}
+
+// isUserSection reports whether a "## " heading line starts a user section
+func isUserSection(heading string, headings []string) bool {
+	for _, h := range headings {
+		if strings.EqualFold(strings.TrimSpace(heading), h) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderUserSections renders the empty user sections for a new note
+func renderUserSections() string {
+	var sb strings.Builder
+	for _, heading := range userSectionHeadings() {
+		sb.WriteString(heading + "\n\n")
+	}
+	return sb.String()
+}
+
+// blankUserSections empties the user sections of a note body, keeping their headings, so
+// writing in them doesn't count as editing the generated note
+func blankUserSections(body string) string {
+	headings := userSectionHeadings()
+	if len(headings) == 0 {
+		return body
+	}
+	preamble, order, sections := splitSections(body)
+	var sb strings.Builder
+	sb.WriteString(preamble)
+	for _, heading := range order {
+		if isUserSection(heading, headings) {
+			sb.WriteString(heading + "\n\n")
+		} else {
+			sb.WriteString(sections[heading])
+		}
+	}
+	return sb.String()
+}
+
+// splitNoteBody splits a note into its frontmatter block (with delimiters) and body
+func splitNoteBody(content []byte) (string, string) {
+	if _, body, err := splitFrontmatter(content); err == nil {
+		return string(content[:len(content)-len(body)]), body
+	}
+	return "", string(content)
+}
+
+// preserveUserSections carries the user sections of an existing note over to its freshly
+// rendered replacement verbatim: they replace the empty ones in the render, and any the
+// render doesn't have are appended after the generated sections
+func preserveUserSections(existing, rendered []byte) []byte {
+	headings := userSectionHeadings()
+	if len(headings) == 0 {
+		return rendered
+	}
+
+	_, oldBody := splitNoteBody(existing)
+	if p := strings.LastIndex(oldBody, provenanceMarker); p >= 0 {
+		oldBody = oldBody[:p]
+	}
+	_, oldOrder, oldSections := splitSections(oldBody)
+	kept := make(map[string]string)
+	var keptOrder []string
+	for _, heading := range oldOrder {
+		if isUserSection(heading, headings) {
+			kept[strings.ToLower(heading)] = oldSections[heading]
+			keptOrder = append(keptOrder, heading)
+		}
+	}
+	if len(kept) == 0 {
+		return rendered
+	}
+
+	// Keep the provenance footer (and anything below it) out of the last section
+	text, footer := string(rendered), ""
+	if p := strings.LastIndex(text, provenanceMarker); p >= 0 {
+		text, footer = text[:p], text[p:]
+	}
+	frontmatter, body := splitNoteBody([]byte(text))
+
+	preamble, order, sections := splitSections(body)
+	var sb strings.Builder
+	sb.WriteString(preamble)
+	for _, heading := range order {
+		section := sections[heading]
+		if old, ok := kept[strings.ToLower(heading)]; ok && isUserSection(heading, headings) {
+			section = old
+			delete(kept, strings.ToLower(heading))
+		}
+		sb.WriteString(strings.TrimRight(section, "\n") + "\n\n")
+	}
+	for _, heading := range keptOrder {
+		if old, ok := kept[strings.ToLower(heading)]; ok {
+			sb.WriteString(strings.TrimRight(old, "\n") + "\n\n")
+		}
+	}
+
+	result := frontmatter + strings.TrimRight(sb.String(), "\n") + "\n"
+	if footer != "" {
+		result += "\n" + footer
+	}
+	return []byte(result)
+}
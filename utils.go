@@ -17,6 +17,9 @@ import (
 )
 
 func formatTimestamp(seconds float64) string {
+	if seconds < 0 {
+		return "-" + formatTimestamp(-seconds)
+	}
 	totalSeconds := int(seconds)
 	hours := totalSeconds / 3600
 	minutes := (totalSeconds % 3600) / 60
@@ -28,11 +31,56 @@ func formatTimestamp(seconds float64) string {
 	return fmt.Sprintf("%02d:%02d", minutes, secs)
 }
 
-// extractTagsFromObsidian scans the Obsidian vault and extracts all unique tags
-// Returns a map of tag -> count
-func extractTagsFromObsidian(vaultPath string) (map[string]int, error) {
+// tagScopeMeetings is the TAG_SCOPE entry for the synced meeting notes folders
+const tagScopeMeetings = "meetings"
+
+// tagScope returns the vault-relative folders tags are extracted from (TAG_SCOPE,
+// comma-separated; "meetings" means every meeting notes folder). Empty means the whole vault.
+func tagScope() []string {
+	var scope []string
+	for _, folder := range strings.Split(os.Getenv("TAG_SCOPE"), ",") {
+		folder = strings.Trim(filepath.ToSlash(strings.TrimSpace(folder)), "/")
+		if folder != "" {
+			scope = append(scope, folder)
+		}
+	}
+	return scope
+}
+
+// inTagScope reports whether a vault-relative note path is inside the tag scope
+func inTagScope(rel string, scope []string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+	for _, folder := range scope {
+		if folder == tagScopeMeetings && contains(strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/"), "meetings") {
+			return true
+		}
+		if strings.HasPrefix(rel, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTagsFromObsidian scans the Obsidian vault (limited to TAG_SCOPE, if set) and extracts all unique tags
+// Returns a map of tag -> count and tag -> up to maxTagExamples vault-relative notes using it
+func extractTagsFromObsidian(vaultPath string) (map[string]int, map[string][]string, error) {
 	tagCounts := make(map[string]int)
+	tagExamples := make(map[string][]string)
 	md := goldmark.New()
+	reportPath := tagsReportPath(vaultPath)
+	scope := tagScope()
+
+	countTag := func(tag, path string) {
+		tagCounts[tag]++
+		if len(tagExamples[tag]) < maxTagExamples {
+			if rel, err := filepath.Rel(vaultPath, path); err == nil && !contains(tagExamples[tag], rel) {
+				tagExamples[tag] = append(tagExamples[tag], rel)
+			}
+		}
+	}
 
 	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -44,8 +92,13 @@ func extractTagsFromObsidian(vaultPath string) (map[string]int, error) {
 			return nil
 		}
 
-		// Skip transcript files (they don't have frontmatter tags)
-		if strings.HasSuffix(info.Name(), "-transcript.md") {
+		// Skip transcript files (they don't have frontmatter tags) and our own tags report
+		if strings.HasSuffix(info.Name(), "-transcript.md") || path == reportPath {
+			return nil
+		}
+
+		// Journals, book notes etc. outside the tag scope would suggest unrelated tags
+		if rel, err := filepath.Rel(vaultPath, path); err != nil || !inTagScope(rel, scope) {
 			return nil
 		}
 
@@ -57,7 +110,7 @@ func extractTagsFromObsidian(vaultPath string) (map[string]int, error) {
 		// Extract frontmatter tags
 		tags := extractFrontmatterTags(content)
 		for _, tag := range tags {
-			tagCounts[tag]++
+			countTag(tag, path)
 		}
 
 		// Extract inline hashtags from markdown content (excluding frontmatter)
@@ -80,7 +133,7 @@ func extractTagsFromObsidian(vaultPath string) (map[string]int, error) {
 				// Find hashtags in this text segment
 				tags := extractHashtags(textContent)
 				for _, tag := range tags {
-					tagCounts[tag]++
+					countTag(tag, path)
 				}
 			}
 
@@ -91,10 +144,10 @@ func extractTagsFromObsidian(vaultPath string) (map[string]int, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("error scanning vault: %w", err)
+		return nil, nil, fmt.Errorf("error scanning vault: %w", err)
 	}
 
-	return tagCounts, nil
+	return tagCounts, tagExamples, nil
 }
 
 // stripFrontmatter removes YAML frontmatter from markdown content
@@ -185,8 +238,11 @@ func extractHashtags(text string) []string {
 func runExtractTags(vaultPath string) error {
 	fmt.Println("\n=== Extracting tags from Obsidian vault ===")
 	fmt.Printf("Scanning vault: %s\n", vaultPath)
+	if scope := tagScope(); len(scope) > 0 {
+		fmt.Printf("Limited to: %s\n", strings.Join(scope, ", "))
+	}
 
-	tagCounts, err := extractTagsFromObsidian(vaultPath)
+	tagCounts, tagExamples, err := extractTagsFromObsidian(vaultPath)
 	if err != nil {
 		return err
 	}
@@ -221,12 +277,24 @@ func runExtractTags(vaultPath string) error {
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
-	if err := os.WriteFile("obsidian-tags.json", data, 0644); err != nil {
+	if err := os.WriteFile(dataPath("obsidian-tags.json"), data, 0644); err != nil {
 		return fmt.Errorf("failed to write obsidian-tags.json: %w", err)
 	}
 
 	fmt.Printf("\n✅ Extracted %d unique tags from vault\n", len(tags))
-	fmt.Printf("📝 Saved to obsidian-tags.json\n")
+	fmt.Printf("📝 Saved to %s\n", dataPath("obsidian-tags.json"))
+
+	if tagsReportEnabled() {
+		counts := make([]tagCount, len(tags))
+		for i, t := range tags {
+			counts[i] = tagCount{Tag: t.Tag, Count: t.Count}
+		}
+		if err := writeTagsReport(vaultPath, counts, tagExamples); err != nil {
+			fmt.Printf("⚠ Warning: Could not write tags report: %v\n", err)
+		} else {
+			fmt.Printf("📝 Tags report: %s\n", tagsReportPath(vaultPath))
+		}
+	}
 	fmt.Printf("\nTop 10 tags:\n")
 	for i := 0; i < 10 && i < len(tags); i++ {
 		fmt.Printf("  %2d. %-30s (used %d times)\n", i+1, tags[i].Tag, tags[i].Count)
@@ -237,7 +305,7 @@ func runExtractTags(vaultPath string) error {
 
 // loadObsidianTags loads tags from obsidian-tags.json
 func loadObsidianTags() ([]string, error) {
-	data, err := os.ReadFile("obsidian-tags.json")
+	data, err := os.ReadFile(dataPath("obsidian-tags.json"))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No tags file yet
@@ -262,3 +330,49 @@ func loadObsidianTags() ([]string, error) {
 
 	return tagNames, nil
 }
+
+// wordPattern finds a term as a whole word. Unlike \b, which only knows ASCII letters,
+// any Unicode letter or digit counts as part of a word, so "Zoë" isn't found in "Zoëy",
+// and terms that start or end with punctuation ("C++", "@team") are found too.
+type wordPattern struct {
+	re *regexp.Regexp
+}
+
+// newWordPattern compiles a word pattern for a term
+func newWordPattern(term string, ignoreCase bool) *wordPattern {
+	flags := ""
+	if ignoreCase {
+		flags = "(?i)"
+	}
+	// The term with any word characters around it: a match is a whole word when there are none
+	return &wordPattern{re: regexp.MustCompile(flags + `([\p{L}\p{N}_]*)(` + regexp.QuoteMeta(term) + `)([\p{L}\p{N}_]*)`)}
+}
+
+// wholeWords returns the start and end of each whole-word occurrence of the term in text
+func (p *wordPattern) wholeWords(text string) [][2]int {
+	var found [][2]int
+	for _, m := range p.re.FindAllStringSubmatchIndex(text, -1) {
+		if m[2] == m[3] && m[6] == m[7] {
+			found = append(found, [2]int{m[4], m[5]})
+		}
+	}
+	return found
+}
+
+// MatchString reports whether text contains the term as a whole word
+func (p *wordPattern) MatchString(text string) bool {
+	return len(p.wholeWords(text)) > 0
+}
+
+// ReplaceAllString replaces every whole-word occurrence of the term with repl
+func (p *wordPattern) ReplaceAllString(text, repl string) string {
+	var b strings.Builder
+	last := 0
+	for _, w := range p.wholeWords(text) {
+		b.WriteString(text[last:w[0]])
+		b.WriteString(repl)
+		last = w[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stagedSuffix is appended to a note's path while it is staged on disk, so Obsidian
+// (which only indexes .md files) never shows a half-written set of notes
+const stagedSuffix = ".krisp-tmp"
+
+// batchVaultWriter is implemented by writers that can write several notes together,
+// so that either all of them are replaced or none are
+type batchVaultWriter interface {
+	CreateNotes(paths []string, contents map[string][]byte) error
+}
+
+// createNotes writes notes through w, all together when w supports it
+func createNotes(w VaultWriter, paths []string, contents map[string][]byte) error {
+	if bw, ok := w.(batchVaultWriter); ok {
+		return bw.CreateNotes(paths, contents)
+	}
+	for _, path := range paths {
+		if err := w.CreateNote(path, contents[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingBatchFile (in the data directory) lists the notes of a batch that is fully staged,
+// so a batch interrupted while its notes are renamed into place is completed by the next one
+const pendingBatchFile = "vault-batch.json"
+
+// CreateNotes writes every note to a temp file next to it, then renames them all into
+// place. A failure while writing the temps leaves the vault untouched; once they are all
+// written the batch counts as done, and renames a crash or error interrupted are finished
+// before the next batch is written.
+func (w *FSVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	if err := completePendingBatch(); err != nil {
+		return err
+	}
+
+	var staged []string
+	removeStaged := func() {
+		for _, path := range staged {
+			os.Remove(path + stagedSuffix)
+		}
+	}
+
+	for _, path := range paths {
+		if err := checkVaultWrite(path, false); err != nil {
+			removeStaged()
+			return err
+		}
+		if err := mkdirVault(filepath.Dir(path)); err != nil {
+			removeStaged()
+			return err
+		}
+		if err := os.WriteFile(path+stagedSuffix, contents[path], 0644); err != nil {
+			removeStaged()
+			return fmt.Errorf("failed to stage %s: %w", filepath.Base(path), err)
+		}
+		staged = append(staged, path)
+	}
+
+	data, err := json.Marshal(staged)
+	if err != nil {
+		removeStaged()
+		return err
+	}
+	if err := writeFileAtomic(dataPath(pendingBatchFile), data); err != nil {
+		removeStaged()
+		return err
+	}
+	return completePendingBatch()
+}
+
+// completePendingBatch renames the staged notes of a fully staged batch into place
+func completePendingBatch() error {
+	data, err := os.ReadFile(dataPath(pendingBatchFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", pendingBatchFile, err)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path + stagedSuffix); err != nil {
+			continue // already renamed
+		}
+		if err := os.Rename(path+stagedSuffix, path); err != nil {
+			return fmt.Errorf("failed to commit %s: %w", filepath.Base(path), err)
+		}
+	}
+	return os.Remove(dataPath(pendingBatchFile))
+}
+
+func (w *MemoryVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	for _, path := range paths {
+		if err := checkVaultWrite(path, false); err != nil {
+			return err
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, path := range paths {
+		w.files[filepath.Clean(path)] = append([]byte(nil), contents[path]...)
+	}
+	return nil
+}
+
+func (w *ManifestVaultWriter) CreateNotes(paths []string, contents map[string][]byte) error {
+	for _, path := range paths {
+		if err := w.track(path, manifestModified); err != nil {
+			return err
+		}
+	}
+	if err := createNotes(w.inner, paths, contents); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	for _, path := range paths {
+		w.byPath[filepath.Clean(path)].AfterHash = contentHash(contents[path])
+	}
+	w.mu.Unlock()
+	return nil
+}
+
+// VaultBatch holds back note writes until Commit, so the notes of one meeting land
+// together. Reads through the batch see the staged content.
+type VaultBatch struct {
+	inner    VaultWriter
+	paths    []string
+	contents map[string][]byte
+	done     bool
+}
+
+// newVaultBatch starts a batch of writes to inner. Only writes made through the batch are
+// held back; the vault writer everything else uses is left alone.
+func newVaultBatch(inner VaultWriter) *VaultBatch {
+	return &VaultBatch{inner: inner, contents: make(map[string][]byte)}
+}
+
+func (b *VaultBatch) Exists(path string) bool {
+	if _, ok := b.contents[filepath.Clean(path)]; ok {
+		return true
+	}
+	return b.inner.Exists(path)
+}
+
+func (b *VaultBatch) ReadNote(path string) ([]byte, error) {
+	if content, ok := b.contents[filepath.Clean(path)]; ok {
+		return append([]byte(nil), content...), nil
+	}
+	return b.inner.ReadNote(path)
+}
+
+func (b *VaultBatch) CreateNote(path string, content []byte) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	path = filepath.Clean(path)
+	if _, ok := b.contents[path]; !ok {
+		b.paths = append(b.paths, path)
+	}
+	b.contents[path] = append([]byte(nil), content...)
+	return nil
+}
+
+func (b *VaultBatch) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(b, path, fields)
+}
+
+func (b *VaultBatch) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(b, path, heading, content)
+}
+
+// DeleteNote drops any staged content and deletes the note straight away
+func (b *VaultBatch) DeleteNote(path string) error {
+	path = filepath.Clean(path)
+	if _, ok := b.contents[path]; ok {
+		delete(b.contents, path)
+		for i, p := range b.paths {
+			if p == path {
+				b.paths = append(b.paths[:i], b.paths[i+1:]...)
+				break
+			}
+		}
+		if !b.inner.Exists(path) {
+			return nil
+		}
+	}
+	return b.inner.DeleteNote(path)
+}
+
+// MoveFolder moves a folder straight away; staged notes are written where they were staged
+func (b *VaultBatch) MoveFolder(src, dest string) error {
+	return b.inner.MoveFolder(src, dest)
+}
+
+// Commit writes all staged notes together
+func (b *VaultBatch) Commit() error {
+	if b == nil || b.done {
+		return nil
+	}
+	b.done = true
+	if len(b.paths) == 0 {
+		return nil
+	}
+	return createNotes(b.inner, b.paths, b.contents)
+}
+
+// Discard drops all staged notes. Safe to call on a nil or finished batch.
+func (b *VaultBatch) Discard() {
+	if b == nil || b.done {
+		return
+	}
+	b.done = true
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// vaultIgnoreFileName is the ignore file in the vault root listing folders the tool must never write to
+const vaultIgnoreFileName = ".krisp-sync-ignore"
+
+// VaultIgnore holds gitignore-style exclude rules for vault writes
+type VaultIgnore struct {
+	root  string
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool // "!pattern" re-includes a path
+	anchored bool // pattern contains a "/" and matches from the vault root
+	dirOnly  bool // "pattern/" only matches directories (and everything below them)
+}
+
+// vaultIgnore guards every vault write; nil means nothing is excluded
+var vaultIgnore *VaultIgnore
+
+// loadVaultIgnore reads the ignore file from the vault root, if present
+func loadVaultIgnore(vaultPath string) (*VaultIgnore, error) {
+	ignore := &VaultIgnore{root: vaultPath}
+
+	f, err := os.Open(filepath.Join(vaultPath, vaultIgnoreFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ignore, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", vaultIgnoreFileName, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		rule.pattern = line
+		ignore.rules = append(ignore.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", vaultIgnoreFileName, err)
+	}
+
+	return ignore, nil
+}
+
+// Ignored reports whether writing to path is excluded. Paths outside the vault are never ignored.
+func (v *VaultIgnore) Ignored(path string, isDir bool) bool {
+	if v == nil || len(v.rules) == 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(v.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	// Last matching rule wins, as in .gitignore
+	ignored := false
+	for _, rule := range v.rules {
+		if rule.matches(parts, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matches checks a rule against a vault-relative path split into components
+func (r ignoreRule) matches(parts []string, isDir bool) bool {
+	// A rule matching a directory also excludes everything below it, so try every
+	// ancestor; a file path itself never matches a directory-only rule
+	for i := 1; i <= len(parts); i++ {
+		if r.dirOnly && i == len(parts) && !isDir {
+			continue
+		}
+
+		var candidate string
+		if r.anchored {
+			candidate = strings.Join(parts[:i], "/")
+		} else {
+			candidate = parts[i-1]
+		}
+
+		if ok, _ := filepath.Match(r.pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVaultWrite returns an error if the path is protected by the vault ignore file
+func checkVaultWrite(path string, isDir bool) error {
+	if vaultIgnore.Ignored(path, isDir) {
+		return fmt.Errorf("refusing to write %s: excluded by %s", path, vaultIgnoreFileName)
+	}
+	return nil
+}
+
+// writeVaultFile writes a file unless it is protected by the vault ignore file
+func writeVaultFile(path string, data []byte) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// mkdirVault creates a directory unless it is protected by the vault ignore file
+func mkdirVault(path string) error {
+	if err := checkVaultWrite(path, true); err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VaultWriter abstracts reading and writing notes in the Obsidian vault so sync can
+// target something other than the filesystem (e.g. an in-memory vault in tests)
+type VaultWriter interface {
+	// Exists reports whether a note exists
+	Exists(path string) bool
+	// ReadNote returns a note's raw content
+	ReadNote(path string) ([]byte, error)
+	// CreateNote writes a note, creating parent folders and replacing any existing content
+	CreateNote(path string, content []byte) error
+	// UpdateFrontmatter merges fields into a note's frontmatter, keeping the body unchanged
+	UpdateFrontmatter(path string, fields map[string]interface{}) error
+	// UpsertSection replaces the section under heading (e.g. "## Action Items") or appends it
+	UpsertSection(path, heading, content string) error
+	// DeleteNote removes a note
+	DeleteNote(path string) error
+	// MoveFolder moves a folder and everything in it to dest, creating dest's parent folders
+	MoveFolder(src, dest string) error
+}
+
+// vaultWriter is the writer used by all vault operations
+var vaultWriter VaultWriter = &FSVaultWriter{}
+
+// FSVaultWriter writes notes to the filesystem, honouring the vault ignore file
+type FSVaultWriter struct{}
+
+func (w *FSVaultWriter) Exists(path string) bool {
+	return fileExists(path)
+}
+
+func (w *FSVaultWriter) ReadNote(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (w *FSVaultWriter) CreateNote(path string, content []byte) error {
+	if err := mkdirVault(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return writeVaultFile(path, content)
+}
+
+func (w *FSVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *FSVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *FSVaultWriter) DeleteNote(path string) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (w *FSVaultWriter) MoveFolder(src, dest string) error {
+	if err := checkVaultWrite(src, true); err != nil {
+		return err
+	}
+	if err := mkdirVault(filepath.Dir(dest)); err != nil {
+		return err
+	}
+	return os.Rename(src, dest)
+}
+
+// folderFiles lists the files under a folder on disk
+func folderFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// movedPath returns where a file under src ends up when src is moved to dest
+func movedPath(path, src, dest string) string {
+	rel, err := filepath.Rel(src, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(dest, rel)
+}
+
+// moveFolderFiles moves files under src to dest one by one through w, for writers that
+// only stage their changes (dry runs, the test sandbox)
+func moveFolderFiles(w VaultWriter, files []string, src, dest string) error {
+	for _, path := range files {
+		content, err := w.ReadNote(path)
+		if err != nil {
+			return err
+		}
+		if err := w.CreateNote(movedPath(path, src, dest), content); err != nil {
+			return err
+		}
+		if err := w.DeleteNote(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryVaultWriter keeps notes in memory, for exercising sync without a real vault
+type MemoryVaultWriter struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryVaultWriter creates an empty in-memory vault
+func NewMemoryVaultWriter() *MemoryVaultWriter {
+	return &MemoryVaultWriter{files: make(map[string][]byte)}
+}
+
+func (w *MemoryVaultWriter) Exists(path string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.files[filepath.Clean(path)]
+	return ok
+}
+
+func (w *MemoryVaultWriter) ReadNote(path string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	content, ok := w.files[filepath.Clean(path)]
+	if !ok {
+		return nil, fmt.Errorf("open %s: %w", path, os.ErrNotExist)
+	}
+	return append([]byte(nil), content...), nil
+}
+
+func (w *MemoryVaultWriter) CreateNote(path string, content []byte) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files[filepath.Clean(path)] = append([]byte(nil), content...)
+	return nil
+}
+
+func (w *MemoryVaultWriter) UpdateFrontmatter(path string, fields map[string]interface{}) error {
+	return updateNoteFrontmatter(w, path, fields)
+}
+
+func (w *MemoryVaultWriter) UpsertSection(path, heading, content string) error {
+	return upsertNoteSection(w, path, heading, content)
+}
+
+func (w *MemoryVaultWriter) DeleteNote(path string) error {
+	if err := checkVaultWrite(path, false); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.files[filepath.Clean(path)]; !ok {
+		return os.ErrNotExist
+	}
+	delete(w.files, filepath.Clean(path))
+	return nil
+}
+
+func (w *MemoryVaultWriter) MoveFolder(src, dest string) error {
+	if err := checkVaultWrite(src, true); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prefix := filepath.Clean(src) + string(filepath.Separator)
+	var paths []string
+	for path := range w.files {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return os.ErrNotExist
+	}
+	for _, path := range paths {
+		w.files[movedPath(path, src, dest)] = w.files[path]
+		delete(w.files, path)
+	}
+	return nil
+}
+
+// Files returns the paths of all notes, sorted
+func (w *MemoryVaultWriter) Files() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	paths := make([]string, 0, len(w.files))
+	for p := range w.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// splitFrontmatter separates a note's YAML frontmatter from its body
+func splitFrontmatter(content []byte) (map[string]interface{}, string, error) {
+	// Check for frontmatter delimiters
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return nil, "", fmt.Errorf("file does not have YAML frontmatter")
+	}
+
+	// Find the end of frontmatter
+	parts := bytes.SplitN(content[4:], []byte("\n---\n"), 2)
+	if len(parts) != 2 {
+		return nil, "", fmt.Errorf("malformed YAML frontmatter")
+	}
+
+	// Parse YAML
+	var frontmatter map[string]interface{}
+	if err := yaml.Unmarshal(parts[0], &frontmatter); err != nil {
+		return nil, "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	return frontmatter, string(parts[1]), nil
+}
+
+// updateNoteFrontmatter implements UpdateFrontmatter on top of ReadNote/CreateNote
+func updateNoteFrontmatter(w VaultWriter, path string, fields map[string]interface{}) error {
+	content, err := w.ReadNote(path)
+	if err != nil {
+		return err
+	}
+	updated, err := editFrontmatter(content, fields, nil)
+	if err != nil {
+		return err
+	}
+	return w.CreateNote(path, updated)
+}
+
+// upsertNoteSection implements UpsertSection on top of ReadNote/CreateNote
+func upsertNoteSection(w VaultWriter, path, heading, content string) error {
+	existing, err := w.ReadNote(path)
+	if err != nil {
+		return err
+	}
+	return w.CreateNote(path, []byte(upsertSection(string(existing), heading, content)))
+}
+
+// headingLevel returns the markdown heading level of a line (0 if it is not a heading)
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// sectionBody returns the body of the section starting at heading, up to the next heading
+// of the same or a higher level, or "" if there is no such section
+func sectionBody(doc, heading string) string {
+	heading = strings.TrimSpace(heading)
+	level := headingLevel(heading)
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != heading {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if l := headingLevel(lines[j]); l > 0 && (level == 0 || l <= level) {
+				end = j
+				break
+			}
+		}
+		return strings.TrimSpace(strings.Join(lines[i+1:end], "\n"))
+	}
+	return ""
+}
+
+// upsertSection replaces the body of the section starting at heading, up to the next
+// heading of the same or a higher level, or appends the section if it is missing
+func upsertSection(doc, heading, content string) string {
+	heading = strings.TrimSpace(heading)
+	level := headingLevel(heading)
+	section := heading + "\n\n" + strings.TrimRight(content, "\n") + "\n"
+
+	lines := strings.Split(doc, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == heading {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		doc = strings.TrimRight(doc, "\n")
+		if doc == "" {
+			return section
+		}
+		return doc + "\n\n" + section
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if l := headingLevel(lines[i]); l > 0 && (level == 0 || l <= level) {
+			end = i
+			break
+		}
+	}
+
+	before := strings.Join(lines[:start], "\n")
+	if before != "" {
+		before += "\n"
+	}
+	after := strings.Join(lines[end:], "\n")
+	if after != "" {
+		section += "\n"
+	}
+	return before + section + after
+}
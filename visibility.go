@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Meeting sensitivity levels
+const (
+	visibilityNormal       = "normal"
+	visibilityConfidential = "confidential" // summary only: no transcript, emails redacted, left out of digests and exports
+)
+
+// visibilityKey is the frontmatter property holding a meeting's level; changing it in a
+// note reviews the meeting's level
+const visibilityKey = "visibility"
+
+// redactedEmail replaces the mailbox part of participant emails in confidential notes
+const redactedEmail = "[redacted]"
+
+// emailRegex matches email addresses
+var emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// parseVisibility validates a sensitivity level
+func parseVisibility(value string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case visibilityNormal, visibilityConfidential:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown visibility %q (expected %s or %s)", value, visibilityNormal, visibilityConfidential)
+}
+
+// confidentialRules returns the CONFIDENTIAL_MEETINGS rules: comma-separated title keywords,
+// or "tag:<tag>" for meetings with that tag (lowercase)
+func confidentialRules() []string {
+	var rules []string
+	for _, rule := range strings.Split(os.Getenv("CONFIDENTIAL_MEETINGS"), ",") {
+		if rule = strings.ToLower(strings.TrimSpace(rule)); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// meetingVisibility returns a meeting's sensitivity level: the one set on review, or else
+// confidential when a CONFIDENTIAL_MEETINGS rule matches its title or tags
+func meetingVisibility(m *Meeting, summaryData *SummaryData) string {
+	if summaryData != nil && summaryData.Visibility != "" {
+		return summaryData.Visibility
+	}
+
+	title := strings.ToLower(m.Title)
+	var tags []string
+	if summaryData != nil {
+		title += "\n" + strings.ToLower(summaryData.Title)
+		tags = splitTags(strings.ToLower(summaryData.Tags))
+	}
+	for _, rule := range confidentialRules() {
+		if tag, ok := strings.CutPrefix(rule, "tag:"); ok {
+			if contains(tags, strings.TrimSpace(tag)) {
+				return visibilityConfidential
+			}
+		} else if strings.Contains(title, rule) {
+			return visibilityConfidential
+		}
+	}
+	return visibilityNormal
+}
+
+// isConfidential reports whether a meeting is kept out of transcripts, digests and exports
+func isConfidential(m *Meeting, summaryData *SummaryData) bool {
+	return meetingVisibility(m, summaryData) == visibilityConfidential
+}
+
+// participantEmails returns the emails Krisp has for a meeting's speakers and participants
+func participantEmails(m *Meeting) []string {
+	var emails []string
+	for _, speakerInfo := range m.Speakers.Data {
+		emails = append(emails, speakerInfo.Person.Email)
+	}
+	for _, p := range m.Participants {
+		emails = append(emails, p.Email)
+	}
+	return emails
+}
+
+// redactEmails hides the participants' emails in a confidential meeting's note, and any
+// other address the summary picked up, keeping only their domain
+func redactEmails(content []byte, m *Meeting) []byte {
+	text := string(content)
+	for _, email := range participantEmails(m) {
+		if _, domain, ok := strings.Cut(email, "@"); ok {
+			text = strings.ReplaceAll(text, email, redactedEmail+"@"+domain)
+		}
+	}
+	text = emailRegex.ReplaceAllStringFunc(text, func(email string) string {
+		if strings.HasPrefix(email, redactedEmail) {
+			return email
+		}
+		return redactedEmail + "@" + emailRegex.FindStringSubmatch(email)[1]
+	})
+	return []byte(text)
+}
+
+// reviewVisibility picks up a level changed with the visibility property of a meeting's
+// summary note, as the meeting is synced, and records it with the meeting's summary. The
+// note of a meeting made confidential has emails redacted right away; the sync then leaves
+// out (and removes) its transcript note, or writes it back once the meeting is normal again.
+func reviewVisibility(w VaultWriter, notePath string, m *Meeting, summaryData *SummaryData, cache *Cache) error {
+	if summaryData == nil || !w.Exists(notePath) {
+		return nil
+	}
+	content, err := w.ReadNote(notePath)
+	if err != nil {
+		return err
+	}
+	fm, _, err := splitFrontmatter(content)
+	if err != nil || fm[visibilityKey] == nil {
+		return nil
+	}
+	level, err := parseVisibility(fmt.Sprintf("%v", fm[visibilityKey]))
+	if err != nil {
+		fmt.Printf("  ⚠ %s: %v\n", filepath.Base(notePath), err)
+		return nil
+	}
+	if meetingVisibility(m, summaryData) == level {
+		return nil
+	}
+
+	summaryData.Visibility = level
+	if err := cache.SaveSummary(m.ID, summaryData); err != nil {
+		return fmt.Errorf("error saving visibility: %w", err)
+	}
+	if level != visibilityConfidential {
+		fmt.Printf("  🔓 No longer confidential\n")
+		return nil
+	}
+	fmt.Printf("  🔒 Now confidential\n")
+	return w.CreateNote(notePath, redactEmails(content, m))
+}